@@ -0,0 +1,66 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/gif"
+)
+
+// Animation is a sequence of full color frames. Unlike gif.GIF the frames are
+// not restricted to a 256 color palette, so gradient and photo backgrounds
+// survive intact. It is written with EncodeAPNG or EncodeWebP.
+type Animation struct {
+	// Successive frames.
+	Image []image.Image
+
+	// The successive delay times, one per frame, in 100ths of a second.
+	Delay []int
+
+	// LoopCount controls the number of times an animation will be restarted
+	// during display, with the same meaning as gif.GIF.LoopCount: 0 loops
+	// forever, -1 shows each frame only once, n > 0 shows the frames n+1
+	// times.
+	LoopCount int
+}
+
+// AnimationGenerator can generate an animated artistic qr code, keeping the
+// full color of every frame.
+func AnimationGenerator(q *QRCode, a Animation, size int) *Animation {
+	na := Animation{Image: make([]image.Image, len(a.Image)), Delay: a.Delay, LoopCount: a.LoopCount}
+	for i, v := range a.Image {
+		na.Image[i] = ImageGenerator(q, v, size)
+	}
+	return &na
+}
+
+// AnimationFromGIF returns the frames of g as an Animation, for use with
+// AnimationGenerator.
+func AnimationFromGIF(g *gif.GIF) Animation {
+	a := Animation{Image: make([]image.Image, len(g.Image)), Delay: g.Delay, LoopCount: g.LoopCount}
+	for i, v := range g.Image {
+		a.Image[i] = v
+	}
+	return a
+}
+
+// numPlays converts LoopCount to the total number of plays used by APNG and
+// WebP, where 0 means forever.
+func (a *Animation) numPlays() int {
+	switch {
+	case a.LoopCount == 0:
+		return 0
+	case a.LoopCount < 0:
+		return 1
+	}
+	return a.LoopCount + 1
+}
+
+// delay returns the delay of frame i in 100ths of a second.
+func (a *Animation) delay(i int) int {
+	if i < len(a.Delay) && a.Delay[i] > 0 {
+		return a.Delay[i]
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+)
+
+// pngSignature is the 8 byte header of every PNG file.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// EncodeAPNG writes the Animation a to w in animated PNG (APNG) format.
+//
+// Every frame is stored as 8-bit RGBA, so no colors are lost to a palette.
+// Decoders without APNG support display the first frame. All frames must have
+// the same dimensions as the first frame.
+func EncodeAPNG(w io.Writer, a *Animation) error {
+	if len(a.Image) == 0 {
+		return errors.New("apng: no frames to encode")
+	}
+
+	bounds := a.Image[0].Bounds()
+	for _, m := range a.Image {
+		if m.Bounds().Dx() != bounds.Dx() || m.Bounds().Dy() != bounds.Dy() {
+			return errors.New("apng: frames have different sizes")
+		}
+	}
+
+	e := &pngChunkWriter{w: w}
+	if _, e.err = io.WriteString(w, pngSignature); e.err != nil {
+		return e.err
+	}
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(bounds.Dy()))
+	ihdr[8] = 8  // Bit depth.
+	ihdr[9] = 6  // Color type: truecolor with alpha.
+	ihdr[10] = 0 // Compression method.
+	ihdr[11] = 0 // Filter method.
+	ihdr[12] = 0 // Interlace method.
+	e.writeChunk("IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], uint32(len(a.Image)))
+	binary.BigEndian.PutUint32(actl[4:8], uint32(a.numPlays()))
+	e.writeChunk("acTL", actl)
+
+	var seq uint32
+	for i, m := range a.Image {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:4], seq)
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(bounds.Dy()))
+		// x_offset and y_offset are zero: every frame covers the canvas.
+		binary.BigEndian.PutUint16(fctl[20:22], uint16(a.delay(i)))
+		binary.BigEndian.PutUint16(fctl[22:24], 100)
+		fctl[24] = 0 // dispose_op: APNG_DISPOSE_OP_NONE.
+		fctl[25] = 0 // blend_op: APNG_BLEND_OP_SOURCE.
+		e.writeChunk("fcTL", fctl)
+		seq++
+
+		data, err := compressRGBA(m)
+		if err != nil {
+			return err
+		}
+
+		// The first frame doubles as the default image.
+		if i == 0 {
+			e.writeChunk("IDAT", data)
+			continue
+		}
+
+		fdat := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(fdat[0:4], seq)
+		copy(fdat[4:], data)
+		e.writeChunk("fdAT", fdat)
+		seq++
+	}
+
+	e.writeChunk("IEND", nil)
+
+	return e.err
+}
+
+// pngChunkWriter writes PNG chunks, remembering the first error encountered.
+type pngChunkWriter struct {
+	w   io.Writer
+	err error
+}
+
+// writeChunk writes a single length-prefixed, CRC protected chunk.
+func (e *pngChunkWriter) writeChunk(name string, data []byte) {
+	if e.err != nil {
+		return
+	}
+
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], name)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:8])
+	crc.Write(data)
+
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, b := range [][]byte{header, data, footer} {
+		if _, e.err = e.w.Write(b); e.err != nil {
+			return
+		}
+	}
+}
+
+// compressRGBA returns the zlib compressed, unfiltered 8-bit RGBA scanlines
+// of m.
+func compressRGBA(m image.Image) ([]byte, error) {
+	b := m.Bounds()
+
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]byte, 1+4*b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		// row[0] is the filter type, which is always None.
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+			i := 1 + 4*(x-b.Min.X)
+			row[i+0] = c.R
+			row[i+1] = c.G
+			row[i+2] = c.B
+			row[i+3] = c.A
+		}
+		if _, err = zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestEncodeAPNG(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bg := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			bg.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 0x80, 0xff})
+		}
+	}
+
+	a := AnimationGenerator(q, Animation{Image: []image.Image{bg, bg, bg}, Delay: []int{10, 20, 30}}, 256)
+
+	var buf bytes.Buffer
+	if err = EncodeAPNG(&buf, a); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, chunk := range []string{"acTL", "fcTL", "IDAT", "fdAT"} {
+		if !bytes.Contains(buf.Bytes(), []byte(chunk)) {
+			t.Errorf("APNG missing %s chunk", chunk)
+		}
+	}
+
+	// Decoders without APNG support see the first frame.
+	m, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if m.Bounds() != a.Image[0].Bounds() {
+		t.Fatalf("got bounds %v, expected %v", m.Bounds(), a.Image[0].Bounds())
+	}
+
+	for x := 0; x < m.Bounds().Dx(); x++ {
+		for y := 0; y < m.Bounds().Dy(); y++ {
+			got := color.NRGBAModel.Convert(m.At(x, y))
+			expected := color.NRGBAModel.Convert(a.Image[0].At(x, y))
+			if got != expected {
+				t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, got, expected)
+			}
+		}
+	}
+}
+
+func TestEncodeAPNGMismatchedFrames(t *testing.T) {
+	a := &Animation{Image: []image.Image{
+		image.NewRGBA(image.Rect(0, 0, 10, 10)),
+		image.NewRGBA(image.Rect(0, 0, 20, 20)),
+	}}
+
+	var buf bytes.Buffer
+	if err := EncodeAPNG(&buf, a); err == nil {
+		t.Error("EncodeAPNG with mismatched frames succeeded, expected error")
+	}
+}
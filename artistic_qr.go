@@ -1,3 +1,6 @@
+//go:build !tinygo
+// +build !tinygo
+
 package qrcode
 
 import (
@@ -24,10 +27,14 @@ func GifGenerator(q *QRCode, g gif.GIF, size int) *gif.GIF {
 
 // ImageGenerator can generate a artistic qr code
 func ImageGenerator(q *QRCode, g image.Image, size int) image.Image {
-	bg := scale(g, size)
 	// Minimum pixels (both width and height) required.
 	realSize := q.symbol.size
 
+	// Exact module size support.
+	if q.scale > 0 {
+		size = q.scale * realSize
+	}
+
 	// Variable size support.
 	if size < 0 {
 		size = size * -1 * realSize
@@ -41,22 +48,28 @@ func ImageGenerator(q *QRCode, g image.Image, size int) image.Image {
 	// Size of each module drawn.
 	pixelsPerModule := size / realSize
 
-	// Center the symbol within the image.
-	// offset := (size - realSize*pixelsPerModule) / 2
-	bgTmp := image.NewRGBA(image.Rect(0, 0, pixelsPerModule*realSize, pixelsPerModule*realSize))
+	// Shrink the image to a whole number of modules, or center the symbol
+	// within the image.
+	if q.snapToModule {
+		size = realSize * pixelsPerModule
+	}
+	offset := (size - realSize*pixelsPerModule) / 2
+
+	bg := scale(g, size, !q.noSmoothing)
+	bgTmp := image.NewRGBA(image.Rect(0, 0, size, size))
 	bitmap := q.symbol.bitmap()
 	for y, row := range bitmap {
 		for x, v := range row {
 			//if the point is belong to FinderPatterns,AlignmentPatterns,TimingPatterns,dont scale it
 			var startX, startY, lenX, lenY int
-			if q.getPointType(x, y) <= 0 {
-				startX = x*pixelsPerModule + pixelsPerModule/4
-				startY = y*pixelsPerModule + pixelsPerModule/4
+			if t := q.pointType(x, y); t == DataPoint || t == QuietZonePoint {
+				startX = x*pixelsPerModule + pixelsPerModule/4 + offset
+				startY = y*pixelsPerModule + pixelsPerModule/4 + offset
 				lenX = startX + pixelsPerModule - pixelsPerModule/2
 				lenY = startY + pixelsPerModule - pixelsPerModule/2
 			} else {
-				startX = x * pixelsPerModule
-				startY = y * pixelsPerModule
+				startX = x*pixelsPerModule + offset
+				startY = y*pixelsPerModule + offset
 				lenX = startX + pixelsPerModule
 				lenY = startY + pixelsPerModule
 			}
@@ -77,14 +90,17 @@ func ImageGenerator(q *QRCode, g image.Image, size int) image.Image {
 			}
 		}
 	}
-	if float64(size)/float64(bgTmp.Bounds().Dx()) > 1 {
-		tmp := scale(bgTmp, size)
-		draw.Draw(&bg, bg.Bounds(), &tmp, image.ZP, draw.Over)
-	}
+	draw.Draw(&bg, bg.Bounds(), bgTmp, image.ZP, draw.Over)
 	return &bg
 }
 
-func scale(g image.Image, size int) image.RGBA {
+// scale returns g stretched to size x size pixels, smoothed with CatmullRom
+// if smooth is true.
+func scale(g image.Image, size int, smooth bool) image.RGBA {
+	if !smooth {
+		return *scaleNearest(g, size, size)
+	}
+
 	bg := image.NewRGBA(image.Rect(0, 0, size, size))
 	transform := draw.CatmullRom
 	tmp := newunits()
@@ -162,7 +178,7 @@ func (p *Point) translate(mx, my float64) {
 	}
 	for i := 0; i < len(p.Y); i++ {
 		if p.Y[i].Variable == "" {
-			p.Y[i].Coefficient += mx
+			p.Y[i].Coefficient += my
 			break
 		}
 		if i == len(p.Y)-1 {
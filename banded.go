@@ -0,0 +1,164 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/yougg/go-qrcode/styles"
+)
+
+// idatChunkSize is the largest IDAT chunk written by EncodeBandedPNG.
+const idatChunkSize = 1 << 16
+
+// EncodeBandedPNG writes the QR Code to w as a 1-bit palette PNG image,
+// generating it a scanline at a time. Memory use is independent of the image
+// size, so poster size renders (e.g. Width(16384), Height(16384)) need only a
+// few kilobytes, where Image() would need hundreds of megabytes.
+//
+// The image is identical to PNG() for codes drawn with square modules in
+// ForegroundColor and BackgroundColor. Options needing the whole image, such
+// as module shapes, quiet zone styling, captions, stamps, orientation markers,
+// transforms and Downscale thumbnails, are an error.
+func (q *QRCode) EncodeBandedPNG(w io.Writer) error {
+	start := time.Now()
+
+	if !q.isPlain() {
+		return errors.New("png: banded encoding supports plain square codes only")
+	}
+
+	chunk, err := q.colorProfileChunk()
+	if err != nil {
+		return err
+	}
+
+	pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY, width, height := q.layout()
+	bitmap := q.symbol.bitmap()
+
+	cw := &colorProfileWriter{w: w, chunk: append(chunk, q.metadataChunks()...)}
+	e := &pngChunkWriter{w: cw}
+	if _, e.err = io.WriteString(cw, pngSignature); e.err != nil {
+		return e.err
+	}
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(height))
+	ihdr[8] = 1 // Bit depth.
+	ihdr[9] = 3 // Color type: palette.
+	e.writeChunk("IHDR", ihdr)
+
+	var plte, trns []byte
+	for _, c := range []color.Color{q.BackgroundColor, q.ForegroundColor} {
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		plte = append(plte, n.R, n.G, n.B)
+		trns = append(trns, n.A)
+	}
+	e.writeChunk("PLTE", plte)
+	if trns[0] != 0xff || trns[1] != 0xff {
+		e.writeChunk("tRNS", trns)
+	}
+
+	idat := &idatWriter{e: e}
+	zw, err := zlib.NewWriterLevel(idat, zlib.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	// A row of a module band is the same for every scanline in the band.
+	row := make([]byte, 1+(width+7)/8)
+	lastModuleY := -1
+	for y := 0; y < height; y++ {
+		moduleY := -1
+		if y >= offsetY && y < offsetY+len(bitmap)*pixelsPerModuleY {
+			moduleY = (y - offsetY) / pixelsPerModuleY
+		}
+
+		if y == 0 || moduleY != lastModuleY {
+			for i := range row {
+				row[i] = 0 // Also filter type None.
+			}
+			if moduleY >= 0 {
+				for x, v := range bitmap[moduleY] {
+					if !v {
+						continue
+					}
+					startX := x*pixelsPerModuleX + offsetX
+					for px := startX; px < startX+pixelsPerModuleX; px++ {
+						row[1+px/8] |= 0x80 >> uint(px%8)
+					}
+				}
+			}
+			lastModuleY = moduleY
+		}
+
+		if _, err := zw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	idat.flush()
+	e.writeChunk("IEND", nil)
+
+	if e.err != nil {
+		return e.err
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("png", time.Since(start), cw.n)
+	}
+
+	return nil
+}
+
+// isPlain reports whether Image() draws only square modules in the
+// foreground and background colors.
+func (q *QRCode) isPlain() bool {
+	return q.moduleShape == styles.Square && q.eyeShape == styles.Square &&
+		q.quietZoneColor == nil && q.quietZoneRadius <= 0 &&
+		(q.outlineWidth <= 0 || q.outlineColor == nil) &&
+		q.captionText() == "" && q.stamp == "" && q.transform == nil &&
+		q.quietZoneEdges == nil && q.customPalette == nil &&
+		q.orientationMarker == NoEdge && !q.thumbnailed()
+}
+
+// thumbnailed reports whether Image() returns a Downscale thumbnail.
+func (q *QRCode) thumbnailed() bool {
+	_, _, ok := q.thumbnailSize()
+	return ok
+}
+
+// idatWriter splits compressed image data into IDAT chunks.
+type idatWriter struct {
+	e   *pngChunkWriter
+	buf []byte
+}
+
+func (w *idatWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= idatChunkSize {
+		w.e.writeChunk("IDAT", w.buf[:idatChunkSize])
+		w.buf = append(w.buf[:0], w.buf[idatChunkSize:]...)
+	}
+	if w.e.err != nil {
+		return 0, w.e.err
+	}
+	return len(p), nil
+}
+
+// flush writes any remaining data as a final IDAT chunk.
+func (w *idatWriter) flush() {
+	if len(w.buf) > 0 {
+		w.e.writeChunk("IDAT", w.buf)
+		w.buf = nil
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestEncodeBandedPNG(t *testing.T) {
+	tests := [][]Option{
+		{Width(256), Height(256)},
+		{Width(301), Height(257), Margin(4)},
+		{Width(-3), Height(-3), ForegroundColor(color.NRGBA{0x1a, 0x73, 0xe8, 0xff}), BackgroundColor(color.Transparent)},
+	}
+
+	for i, opts := range tests {
+		q, err := New("https://example.org", opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		var b bytes.Buffer
+		if err := q.EncodeBandedPNG(&b); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		got, err := png.Decode(&b)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		expected := q.Image()
+		if got.Bounds() != expected.Bounds() {
+			t.Fatalf("test %d: image is %v, expected %v", i, got.Bounds(), expected.Bounds())
+		}
+
+		if n := countDifferentPixels(got, expected); n != 0 {
+			t.Errorf("test %d: %d pixels differ from Image()", i, n)
+		}
+	}
+}
+
+func TestEncodeBandedPNGLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping TestEncodeBandedPNGLarge")
+	}
+
+	q, err := New("https://example.org", Width(8192), Height(8192))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var b bytes.Buffer
+	if err := q.EncodeBandedPNG(&b); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	config, err := png.DecodeConfig(&b)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if config.Width != 8192 || config.Height != 8192 {
+		t.Errorf("image is %dx%d, expected 8192x8192", config.Width, config.Height)
+	}
+}
+
+func TestEncodeBandedPNGNotPlain(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"caption", []Option{Caption("Scan me")}},
+		{"orientation marker", []Option{OrientationMarker(TopEdge)}},
+		{"thumbnail", []Option{Width(10), Height(10), Downscale(0)}},
+	}
+
+	for _, test := range tests {
+		q, err := New("https://example.org", test.opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		var b bytes.Buffer
+		if err := q.EncodeBandedPNG(&b); err == nil {
+			t.Errorf("EncodeBandedPNG with a %s succeeded, expected error", test.name)
+		}
+	}
+}
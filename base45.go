@@ -0,0 +1,65 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// base45Alphabet is the RFC 9285 Base45 alphabet: the QR Code alphanumeric
+// mode character set.
+const base45Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// base45Encode returns data encoded as Base45 (RFC 9285).
+func base45Encode(data []byte) string {
+	var b strings.Builder
+	b.Grow((len(data)*3 + 1) / 2)
+
+	for i := 0; i+1 < len(data); i += 2 {
+		n := int(data[i])<<8 | int(data[i+1])
+		b.WriteByte(base45Alphabet[n%45])
+		b.WriteByte(base45Alphabet[n/45%45])
+		b.WriteByte(base45Alphabet[n/(45*45)])
+	}
+	if len(data)%2 == 1 {
+		n := int(data[len(data)-1])
+		b.WriteByte(base45Alphabet[n%45])
+		b.WriteByte(base45Alphabet[n/45])
+	}
+
+	return b.String()
+}
+
+// base45Decode returns the data encoded as Base45 (RFC 9285) in s.
+func base45Decode(s string) ([]byte, error) {
+	if len(s)%3 == 1 {
+		return nil, fmt.Errorf("invalid base45 length %d", len(s))
+	}
+
+	data := make([]byte, 0, len(s)/3*2+1)
+	for i := 0; i < len(s); i += 3 {
+		n, scale := 0, 1
+		end := min(i+3, len(s))
+		for j := i; j < end; j++ {
+			v := strings.IndexByte(base45Alphabet, s[j])
+			if v < 0 {
+				return nil, fmt.Errorf("invalid base45 character %q at %d", s[j], j)
+			}
+			n += v * scale
+			scale *= 45
+		}
+
+		if end-i == 3 {
+			if n > 0xffff {
+				return nil, fmt.Errorf("invalid base45 group %q", s[i:end])
+			}
+			data = append(data, byte(n>>8), byte(n))
+		} else {
+			if n > 0xff {
+				return nil, fmt.Errorf("invalid base45 group %q", s[i:end])
+			}
+			data = append(data, byte(n))
+		}
+	}
+
+	return data, nil
+}
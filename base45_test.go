@@ -0,0 +1,39 @@
+package qrcode
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBase45(t *testing.T) {
+	// RFC 9285 examples.
+	tests := []struct {
+		data    string
+		encoded string
+	}{
+		{"", ""},
+		{"AB", "BB8"},
+		{"Hello!!", "%69 VD92EX0"},
+		{"base-45", "UJCLQE7W581"},
+		{"ietf!", "QED8WEX0"},
+		{"\xff\xff", "FGW"},
+		{"\x00", "00"},
+	}
+
+	for _, test := range tests {
+		if got := base45Encode([]byte(test.data)); got != test.encoded {
+			t.Errorf("base45Encode(%q) = %q, expected %q", test.data, got, test.encoded)
+		}
+
+		got, err := base45Decode(test.encoded)
+		if err != nil || !bytes.Equal(got, []byte(test.data)) {
+			t.Errorf("base45Decode(%q) = %q, %v, expected %q", test.encoded, got, err, test.data)
+		}
+	}
+
+	for _, invalid := range []string{"GGW", "A", "BB8A", "bb8", "ZZ"} {
+		if _, err := base45Decode(invalid); err == nil {
+			t.Errorf("base45Decode(%q) succeeded, expected error", invalid)
+		}
+	}
+}
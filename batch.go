@@ -0,0 +1,66 @@
+package qrcode
+
+import (
+	"fmt"
+)
+
+// NewBatch constructs a QRCode for each of contents, all sharing one common
+// version, so every code in a sheet has the same module count and physical
+// size.
+//
+// The common version is the smallest version able to fit every payload (and
+// any MinVersion given in opts). An error occurs if any content is too long.
+//
+// With the StampSequence option, each code is stamped with its position in
+// the batch.
+func NewBatch(contents []string, opts ...Option) ([]*QRCode, error) {
+	version := 0
+
+	for i, content := range contents {
+		q, err := New(content, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("content %d: %s", i, err.Error())
+		}
+
+		version = max(version, q.VersionNumber)
+	}
+
+	// A payload may need a larger version than version, if version is the
+	// first to use longer character count fields. Repeat until every code
+	// agrees.
+	for {
+		codes := make([]*QRCode, len(contents))
+		batchOpts := append(append([]Option{}, opts...), MinVersion(version))
+
+		agreed := true
+		for i, content := range contents {
+			q, err := New(content, batchOpts...)
+			if err != nil {
+				return nil, fmt.Errorf("content %d: %s", i, err.Error())
+			}
+
+			if q.VersionNumber > version {
+				version = q.VersionNumber
+				agreed = false
+				break
+			}
+
+			codes[i] = q
+		}
+
+		if agreed {
+			for i, q := range codes {
+				if q.stampSequence {
+					q.stamp = sequenceStamp(i, len(codes))
+				}
+			}
+			return codes, nil
+		}
+	}
+}
+
+// sequenceStamp returns the stamp for code i of n, e.g. "042/500".
+func sequenceStamp(i, n int) string {
+	digits := len(fmt.Sprint(n))
+	return fmt.Sprintf("%0*d/%d", digits, i+1, n)
+}
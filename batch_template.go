@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+)
+
+// TemplateItems returns one Item for each element of rows, a slice of structs
+// or maps, for use with GenerateAll. The content and filename of each item are
+// produced by executing the text/template strings content and filename with
+// the element, e.g.
+//
+//	TemplateItems("https://ex.com/a/{{.ID}}?t={{.Token}}", "out/{{.ID}}.png", skus)
+//
+// Every item is given opts.
+func TemplateItems(content, filename string, rows interface{}, opts ...Option) ([]Item, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("template: rows must be a slice")
+	}
+
+	contentTmpl, err := template.New("content").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	filenameTmpl, err := template.New("filename").Option("missingkey=error").Parse(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, v.Len())
+	for i := range items {
+		row := v.Index(i).Interface()
+
+		var c, f bytes.Buffer
+		if err := contentTmpl.Execute(&c, row); err != nil {
+			return nil, fmt.Errorf("row %d: %s", i, err.Error())
+		}
+		if err := filenameTmpl.Execute(&f, row); err != nil {
+			return nil, fmt.Errorf("row %d: %s", i, err.Error())
+		}
+
+		items[i] = Item{Content: c.String(), Filename: f.String(), Options: opts}
+	}
+
+	return items, nil
+}
+
+// CSVTemplateItems is like TemplateItems, with rows read from CSV data. The
+// first record names the columns, which templates refer to as {{.Name}}.
+func CSVTemplateItems(content, filename string, r io.Reader, opts ...Option) ([]Item, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("template: no CSV header")
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, len(records)-1)
+	for i, record := range records[1:] {
+		rows[i] = make(map[string]string, len(header))
+		for j, name := range header {
+			rows[i][name] = record[j]
+		}
+	}
+
+	return TemplateItems(content, filename, rows, opts...)
+}
@@ -0,0 +1,76 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplateItems(t *testing.T) {
+	type sku struct {
+		ID    int
+		Token string
+	}
+
+	items, err := TemplateItems("https://ex.com/a/{{.ID}}?t={{.Token}}", "out/{{.ID}}.png",
+		[]sku{{1, "abc"}, {22, "xyz"}}, Level(Low))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []Item{
+		{Content: "https://ex.com/a/1?t=abc", Filename: "out/1.png"},
+		{Content: "https://ex.com/a/22?t=xyz", Filename: "out/22.png"},
+	}
+
+	if len(items) != len(expected) {
+		t.Fatalf("got %d items, expected %d", len(items), len(expected))
+	}
+	for i, item := range items {
+		if item.Content != expected[i].Content || item.Filename != expected[i].Filename {
+			t.Errorf("item %d is %q %q, expected %q %q", i, item.Content, item.Filename,
+				expected[i].Content, expected[i].Filename)
+		}
+		if len(item.Options) != 1 {
+			t.Errorf("item %d has %d options, expected 1", i, len(item.Options))
+		}
+	}
+}
+
+func TestCSVTemplateItems(t *testing.T) {
+	const data = "ID,Token\n1,abc\n22,\"x,z\"\n"
+
+	items, err := CSVTemplateItems("{{.ID}}:{{.Token}}", "{{.ID}}.png", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(items) != 2 || items[1].Content != "22:x,z" || items[1].Filename != "22.png" {
+		t.Errorf("got %+v", items)
+	}
+}
+
+func TestTemplateItemsErrors(t *testing.T) {
+	rows := []map[string]string{{"ID": "1"}}
+
+	tests := []struct {
+		content string
+		rows    interface{}
+	}{
+		{"{{.ID}}", "not a slice"},
+		{"{{.ID", rows},
+		{"{{.Missing}}", rows},
+	}
+
+	for _, test := range tests {
+		if _, err := TemplateItems(test.content, "{{.ID}}.png", test.rows); err == nil {
+			t.Errorf("TemplateItems(%q) succeeded, expected error", test.content)
+		}
+	}
+
+	if _, err := CSVTemplateItems("{{.ID}}", "{{.ID}}.png", strings.NewReader("")); err == nil {
+		t.Error("CSVTemplateItems with no header succeeded, expected error")
+	}
+}
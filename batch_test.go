@@ -0,0 +1,60 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewBatch(t *testing.T) {
+	contents := []string{
+		"A",
+		"https://example.org",
+		strings.Repeat("#", 100),
+		"01234567",
+	}
+
+	codes, err := NewBatch(contents, Level(Medium))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(codes) != len(contents) {
+		t.Fatalf("got %d codes, expected %d", len(codes), len(contents))
+	}
+
+	// 100 bytes need version 6 at level M.
+	for i, q := range codes {
+		if q.Content != contents[i] {
+			t.Errorf("code %d has content %q, expected %q", i, q.Content, contents[i])
+		}
+		if q.VersionNumber != 6 {
+			t.Errorf("code %d has version %d, expected 6", i, q.VersionNumber)
+		}
+		if q.RecoveryLevel() != Medium {
+			t.Errorf("code %d has level %s, expected M", i, q.RecoveryLevel())
+		}
+		if len(q.Bitmap()) != len(codes[0].Bitmap()) {
+			t.Errorf("code %d has %d modules, expected %d", i, len(q.Bitmap()), len(codes[0].Bitmap()))
+		}
+	}
+}
+
+func TestNewBatchMinVersion(t *testing.T) {
+	codes, err := NewBatch([]string{"A", "B"}, MinVersion(12))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for i, q := range codes {
+		if q.VersionNumber != 12 {
+			t.Errorf("code %d has version %d, expected 12", i, q.VersionNumber)
+		}
+	}
+}
+
+func TestNewBatchTooLong(t *testing.T) {
+	_, err := NewBatch([]string{"A", strings.Repeat("#", 3000)})
+	if err == nil {
+		t.Error("NewBatch with too long content succeeded, expected error")
+	}
+}
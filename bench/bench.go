@@ -0,0 +1,159 @@
+//go:build !tinygo
+// +build !tinygo
+
+// Package bench provides reproducible benchmarks of the QR Code encoder and
+// renderer across versions 1-40, every recovery level and several image
+// sizes, so performance regressions can be tracked over time.
+//
+// Run them with go test:
+//
+//	go test -bench . -benchmem github.com/yougg/go-qrcode/bench
+//
+// or with the qrcode command, which can also write a CPU profile:
+//
+//	qrcode bench -cpuprofile cpu.out -run 'v40-H'
+package bench
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yougg/go-qrcode"
+)
+
+// Versions, Levels and Sizes span the benchmark cases.
+var (
+	Versions = versions()
+	Levels   = []qrcode.RecoveryLevel{qrcode.Low, qrcode.Medium, qrcode.High, qrcode.Highest}
+	Sizes    = []int{256, 1024}
+)
+
+// Case is a single benchmark: encoding content that fills a QR Code of the
+// version and level, then, if Size is non-zero, rendering it as a Size x Size
+// PNG image.
+type Case struct {
+	Version int
+	Level   qrcode.RecoveryLevel
+	Size    int
+}
+
+// Name returns the name of the case, e.g. "v10-M" or "v10-M-256px".
+func (c Case) Name() string {
+	name := fmt.Sprintf("v%02d-%s", c.Version, c.Level)
+	if c.Size > 0 {
+		name += fmt.Sprintf("-%dpx", c.Size)
+	}
+	return name
+}
+
+// EncodeCases returns the encoding only cases, one per version and level.
+func EncodeCases() []Case {
+	var cases []Case
+	for _, v := range Versions {
+		for _, l := range Levels {
+			cases = append(cases, Case{Version: v, Level: l})
+		}
+	}
+	return cases
+}
+
+// RenderCases returns the encoding and rendering cases, one per version,
+// level and size.
+func RenderCases() []Case {
+	var cases []Case
+	for _, c := range EncodeCases() {
+		for _, size := range Sizes {
+			c.Size = size
+			cases = append(cases, c)
+		}
+	}
+	return cases
+}
+
+// Run encodes, and renders if c.Size is non-zero, once.
+func (c Case) Run() error {
+	opts := []qrcode.Option{qrcode.Level(c.Level), qrcode.MinVersion(c.Version)}
+	if c.Size > 0 {
+		opts = append(opts, qrcode.Width(c.Size), qrcode.Height(c.Size))
+	}
+
+	q, err := qrcode.New(Content(c.Version, c.Level), opts...)
+	if err != nil {
+		return err
+	}
+	if q.Version() != c.Version {
+		return fmt.Errorf("%s: encoded as version %d", c.Name(), q.Version())
+	}
+
+	if c.Size > 0 {
+		_, err = q.PNG()
+	}
+
+	return err
+}
+
+// Benchmark runs the case repeatedly and returns the result, as go test
+// -bench would.
+func (c Case) Benchmark() testing.BenchmarkResult {
+	Content(c.Version, c.Level)
+
+	return testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := c.Run(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+var (
+	contentMu sync.Mutex
+	contents  = map[Case]string{}
+)
+
+// Content returns the longest content of the form "abcd..." that encodes as
+// exactly version at level. It is the same on every run, so results are
+// comparable.
+func Content(version int, level qrcode.RecoveryLevel) string {
+	key := Case{Version: version, Level: level}
+
+	contentMu.Lock()
+	defer contentMu.Unlock()
+
+	if s, ok := contents[key]; ok {
+		return s
+	}
+
+	const maxBytes = 2953
+	full := strings.Repeat("abcdefghijklmnopqrstuvwxyz", maxBytes/26+1)[:maxBytes]
+
+	fits := func(n int) bool {
+		q, err := qrcode.New(full[:n], qrcode.Level(level), qrcode.MinVersion(version))
+		return err == nil && q.Version() == version
+	}
+
+	// Binary search for the longest prefix that fits the version.
+	lo, hi := 1, maxBytes
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	contents[key] = full[:lo]
+	return contents[key]
+}
+
+func versions() []int {
+	v := make([]int, 40)
+	for i := range v {
+		v[i] = i + 1
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+//go:build !tinygo
+// +build !tinygo
+
+package bench
+
+import (
+	"testing"
+
+	"github.com/yougg/go-qrcode"
+)
+
+func TestCases(t *testing.T) {
+	if got, want := len(EncodeCases()), 40*4; got != want {
+		t.Errorf("got %d encode cases, expected %d", got, want)
+	}
+	if got, want := len(RenderCases()), 40*4*len(Sizes); got != want {
+		t.Errorf("got %d render cases, expected %d", got, want)
+	}
+
+	for _, c := range []Case{{1, qrcode.Low, 0}, {10, qrcode.Highest, 256}, {40, qrcode.Medium, 0}} {
+		if err := c.Run(); err != nil {
+			t.Errorf("%s: %v", c.Name(), err)
+		}
+
+		// The content fills the version: one more byte needs the next.
+		s := Content(c.Version, c.Level)
+		if c.Version < 40 {
+			q, err := qrcode.New(s+"a", qrcode.Level(c.Level), qrcode.MinVersion(c.Version))
+			if err != nil || q.Version() == c.Version {
+				t.Errorf("%s: content of %d bytes does not fill the version", c.Name(), len(s))
+			}
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, c := range EncodeCases() {
+		Content(c.Version, c.Level)
+		b.Run(c.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.Run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, c := range RenderCases() {
+		Content(c.Version, c.Level)
+		b.Run(c.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.Run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
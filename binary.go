@@ -0,0 +1,76 @@
+package qrcode
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// BinaryEncoding is a text encoding of binary data, e.g. keys, tokens or
+// compressed payloads, for QR Code content. See NewBinary.
+type BinaryEncoding int
+
+const (
+	// Base45 (RFC 9285) uses only alphanumeric mode characters, encoding 2
+	// bytes in 3 characters of 11 bits: about 20% shorter than Base64 in
+	// byte mode.
+	Base45 BinaryEncoding = iota
+
+	// Base64URL is unpadded Base64 with the URL and filename safe alphabet
+	// (RFC 4648), encoded in byte mode. Use it for scanners which expect
+	// Base64, e.g. in URLs.
+	Base64URL
+)
+
+// String returns the name of the encoding.
+func (e BinaryEncoding) String() string {
+	switch e {
+	case Base45:
+		return "Base45"
+	case Base64URL:
+		return "Base64URL"
+	}
+
+	return fmt.Sprintf("BinaryEncoding(%d)", int(e))
+}
+
+// EncodeBinary returns data encoded as text with e.
+func EncodeBinary(data []byte, e BinaryEncoding) (string, error) {
+	switch e {
+	case Base45:
+		return base45Encode(data), nil
+	case Base64URL:
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	}
+
+	return "", fmt.Errorf("unknown binary encoding %s", e)
+}
+
+// DecodeBinary returns the data encoded as text with e in s, e.g. the
+// content read by a scanner.
+func DecodeBinary(s string, e BinaryEncoding) ([]byte, error) {
+	switch e {
+	case Base45:
+		return base45Decode(s)
+	case Base64URL:
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+
+	return nil, fmt.Errorf("unknown binary encoding %s", e)
+}
+
+// NewBinary returns a QR Code of data encoded as text with e, in the mode
+// best suited to the encoding: alphanumeric mode for Base45, byte mode for
+// Base64URL.
+func NewBinary(data []byte, e BinaryEncoding, opts ...Option) (*QRCode, error) {
+	s, err := EncodeBinary(data, e)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := Byte
+	if e == Base45 {
+		mode = Alphanumeric
+	}
+
+	return NewSegments([]Segment{{Mode: mode, Data: s}}, opts...)
+}
@@ -0,0 +1,45 @@
+package qrcode
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewBinary(t *testing.T) {
+	data := make([]byte, 200)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	contentBits := map[BinaryEncoding]int{}
+	for _, e := range []BinaryEncoding{Base45, Base64URL} {
+		q, err := NewBinary(data, e, Level(Medium))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := DecodeBinary(string(info.Content), e)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: decoded %x, %v", e, got, err)
+		}
+
+		contentBits[e] = q.contentBits
+	}
+
+	// Base45 in alphanumeric mode is about 20% smaller.
+	if ratio := float64(contentBits[Base45]) / float64(contentBits[Base64URL]); ratio > 0.85 {
+		t.Errorf("Base45 is %d bits, Base64URL %d bits, expected Base45 to be smaller",
+			contentBits[Base45], contentBits[Base64URL])
+	}
+
+	if _, err := NewBinary(data, BinaryEncoding(9)); err == nil {
+		t.Error("NewBinary succeeded with an unknown encoding")
+	}
+	if _, err := DecodeBinary("!!", Base45); err == nil {
+		t.Error("DecodeBinary succeeded on invalid Base45")
+	}
+}
@@ -17,12 +17,21 @@
 //	v = b.At(1)                       // 1
 //	v = b.At(2)                       // 0
 //	v = b.At(8)                       // 0
+//
+// Or, to consume bits in order, use a Reader:
+//
+//	r := bitset.NewReader(b)
+//	v, err := r.ReadUint32(4)          // 0xd, nil
+//
+// The exported API is stable, and is intended for building new encoding modes
+// (e.g. ECI or Kanji) and test harnesses outside this module. Bits are always
+// stored and read most significant bit first, matching the QR Code bit stream.
 package bitset
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
-	"log"
 )
 
 const (
@@ -47,15 +56,18 @@ func New(v ...bool) *Bitset {
 	return b
 }
 
-// Clone returns a copy.
+// Clone returns a copy. Appending to the copy does not modify from.
 func Clone(from *Bitset) *Bitset {
-	return &Bitset{numBits: from.numBits, bits: from.bits[:]}
+	bits := make([]byte, len(from.bits))
+	copy(bits, from.bits)
+
+	return &Bitset{numBits: from.numBits, bits: bits}
 }
 
 // Substr returns a substring, consisting of the bits from indexes start to end.
 func (b *Bitset) Substr(start int, end int) *Bitset {
 	if start > end || end > b.numBits {
-		log.Panicf("Out of range start=%d end=%d numBits=%d", start, end, b.numBits)
+		panic(fmt.Sprintf("Out of range start=%d end=%d numBits=%d", start, end, b.numBits))
 	}
 
 	result := New()
@@ -88,7 +100,7 @@ func NewFromBase2String(b2string string) *Bitset {
 			b.AppendBools(false)
 		case ' ':
 		default:
-			log.Panicf("Invalid char %c in NewFromBase2String", c)
+			panic(fmt.Sprintf("Invalid char %c in NewFromBase2String", c))
 		}
 	}
 
@@ -107,7 +119,7 @@ func (b *Bitset) AppendByte(value byte, numBits int) {
 	b.ensureCapacity(numBits)
 
 	if numBits > 8 {
-		log.Panicf("numBits %d out of range 0-8", numBits)
+		panic(fmt.Sprintf("numBits %d out of range 0-8", numBits))
 	}
 
 	for i := numBits - 1; i >= 0; i-- {
@@ -124,7 +136,7 @@ func (b *Bitset) AppendUint32(value uint32, numBits int) {
 	b.ensureCapacity(numBits)
 
 	if numBits > 32 {
-		log.Panicf("numBits %d out of range 0-32", numBits)
+		panic(fmt.Sprintf("numBits %d out of range 0-32", numBits))
 	}
 
 	for i := numBits - 1; i >= 0; i-- {
@@ -226,12 +238,25 @@ func (b *Bitset) Bits() []bool {
 // At returns the value of the bit at |index|.
 func (b *Bitset) At(index int) bool {
 	if index >= b.numBits {
-		log.Panicf("Index %d out of range", index)
+		panic(fmt.Sprintf("Index %d out of range", index))
 	}
 
 	return (b.bits[index/8] & (0x80 >> byte(index%8))) != 0
 }
 
+// Equal returns true if a and b contain the same bits. Two nil Bitsets are
+// equal, and a nil Bitset equals an empty one.
+func Equal(a, b *Bitset) bool {
+	if a == nil {
+		a = New()
+	}
+	if b == nil {
+		b = New()
+	}
+
+	return a.Equals(b)
+}
+
 // Equals returns true if the Bitset equals other.
 func (b *Bitset) Equals(other *Bitset) bool {
 	if b.numBits != other.numBits {
@@ -257,7 +282,7 @@ func (b *Bitset) Equals(other *Bitset) bool {
 // ByteAt returns a byte consisting of upto 8 bits starting at index.
 func (b *Bitset) ByteAt(index int) byte {
 	if index < 0 || index >= b.numBits {
-		log.Panicf("Index %d out of range", index)
+		panic(fmt.Sprintf("Index %d out of range", index))
 	}
 
 	var result byte
@@ -271,3 +296,40 @@ func (b *Bitset) ByteAt(index int) byte {
 
 	return result
 }
+
+// ForEach calls f with the index and value of each bit in order, stopping
+// early if f returns false.
+func (b *Bitset) ForEach(f func(index int, value bool) bool) {
+	for i := 0; i < b.numBits; i++ {
+		if !f(i, b.At(i)) {
+			return
+		}
+	}
+}
+
+// Bytes returns the contents of the Bitset packed into bytes, most
+// significant bit first. The final byte is padded with 0 bits.
+func (b *Bitset) Bytes() []byte {
+	numBytes := (b.numBits + 7) / 8
+
+	result := make([]byte, numBytes)
+	copy(result, b.bits[:numBytes])
+
+	// Clear any bits past the end.
+	if b.numBits%8 != 0 {
+		result[numBytes-1] &= 0xff << uint(8-b.numBits%8)
+	}
+
+	return result
+}
+
+// Hex returns the contents of the Bitset as a hexadecimal string, see Bytes.
+func (b *Bitset) Hex() string {
+	return hex.EncodeToString(b.Bytes())
+}
+
+// Dump returns a hex dump of the Bitset, in the format of hex.Dump, preceded
+// by the number of bits.
+func (b *Bitset) Dump() string {
+	return fmt.Sprintf("numBits=%d\n%s", b.numBits, hex.Dump(b.Bytes()))
+}
@@ -319,3 +319,89 @@ func TestSubstr(t *testing.T) {
 		}
 	}
 }
+
+func TestClone(t *testing.T) {
+	a := New(b1, b0, b1)
+	c := Clone(a)
+	c.AppendBools(b1, b1)
+
+	if !a.Equals(New(b1, b0, b1)) {
+		t.Errorf("appending to a clone modified the original: %s", a.String())
+	}
+	if !c.Equals(New(b1, b0, b1, b1, b1)) {
+		t.Errorf("got %s, expected 10111", c.String())
+	}
+
+	// The original can still be appended to.
+	a.AppendBools(b0)
+	if !a.Equals(New(b1, b0, b1, b0)) {
+		t.Errorf("got %s, expected 1010", a.String())
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b     *Bitset
+		expected bool
+	}{
+		{nil, nil, true},
+		{nil, New(), true},
+		{New(b1), nil, false},
+		{New(b1, b0), New(b1, b0), true},
+		{New(b1, b0), New(b1, b0, b0), false},
+		{New(b1, b0), New(b1, b1), false},
+	}
+
+	for i, test := range tests {
+		if got := Equal(test.a, test.b); got != test.expected {
+			t.Errorf("test %d: got %t, expected %t", i, got, test.expected)
+		}
+	}
+}
+
+func TestForEach(t *testing.T) {
+	b := New(b1, b0, b1, b1)
+
+	var got []bool
+	b.ForEach(func(i int, v bool) bool {
+		if i != len(got) {
+			t.Errorf("got index %d, expected %d", i, len(got))
+		}
+		got = append(got, v)
+		return i < 2
+	})
+
+	if !equal(got, []bool{b1, b0, b1}) {
+		t.Errorf("got %v, expected the first 3 bits", got)
+	}
+}
+
+func TestBytesAndHex(t *testing.T) {
+	tests := []struct {
+		b        *Bitset
+		expected string
+	}{
+		{New(), ""},
+		{NewFromBase2String("1"), "80"},
+		{NewFromBase2String("1010 1011 1100 1101"), "abcd"},
+		{NewFromBase2String("1111 1111 101"), "ffa0"},
+	}
+
+	for _, test := range tests {
+		if got := test.b.Hex(); got != test.expected {
+			t.Errorf("%s: Hex() = %q, expected %q", test.b.String(), got, test.expected)
+		}
+	}
+
+	// Bits past the end are never exposed, even if a clone wrote to them.
+	b := NewFromBase2String("1111")
+	c := Clone(b)
+	c.AppendBools(b1, b1)
+	if got := b.Hex(); got != "f0" {
+		t.Errorf("Hex() = %q, expected f0", got)
+	}
+
+	if got, expected := NewFromBase2String("1010 1011").Dump(), "numBits=8\n00000000  ab                                                |.|\n"; got != expected {
+		t.Errorf("Dump() = %q, expected %q", got, expected)
+	}
+}
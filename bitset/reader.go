@@ -0,0 +1,108 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package bitset
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrShortRead is returned when a Reader has too few bits remaining.
+var ErrShortRead = errors.New("bitset: not enough bits remaining")
+
+// Reader reads bits from a Bitset in order, most significant bit first.
+type Reader struct {
+	b   *Bitset
+	pos int
+}
+
+// NewReader returns a Reader positioned at the first bit of b.
+func NewReader(b *Bitset) *Reader {
+	return &Reader{b: b}
+}
+
+// Offset returns the index of the next bit to be read.
+func (r *Reader) Offset() int {
+	return r.pos
+}
+
+// Remaining returns the number of bits left to read.
+func (r *Reader) Remaining() int {
+	return r.b.numBits - r.pos
+}
+
+// ReadBool reads a single bit.
+func (r *Reader) ReadBool() (bool, error) {
+	if r.Remaining() < 1 {
+		return false, ErrShortRead
+	}
+
+	v := r.b.At(r.pos)
+	r.pos++
+
+	return v, nil
+}
+
+// ReadUint32 reads numBits bits, in the range 0-32, as an unsigned value.
+func (r *Reader) ReadUint32(numBits int) (uint32, error) {
+	if numBits < 0 || numBits > 32 {
+		return 0, fmt.Errorf("bitset: numBits %d out of range 0-32", numBits)
+	}
+	if r.Remaining() < numBits {
+		return 0, ErrShortRead
+	}
+
+	var v uint32
+	for i := 0; i < numBits; i++ {
+		v <<= 1
+		if r.b.At(r.pos) {
+			v |= 1
+		}
+		r.pos++
+	}
+
+	return v, nil
+}
+
+// ReadBitset reads numBits bits into a new Bitset.
+func (r *Reader) ReadBitset(numBits int) (*Bitset, error) {
+	if numBits < 0 || r.Remaining() < numBits {
+		return nil, ErrShortRead
+	}
+
+	result := r.b.Substr(r.pos, r.pos+numBits)
+	r.pos += numBits
+
+	return result, nil
+}
+
+// ReadLengthPrefixed reads a countBits wide count n, followed by n values of
+// unitBits each, as used by QR Code data segments (e.g. a mode's character
+// count indicator followed by its characters).
+//
+// unitBits must be in the range 1-32. A count larger than the values
+// remaining returns ErrShortRead before anything is allocated.
+func (r *Reader) ReadLengthPrefixed(countBits int, unitBits int) ([]uint32, error) {
+	if unitBits < 1 || unitBits > 32 {
+		return nil, fmt.Errorf("bitset: unitBits %d out of range 1-32", unitBits)
+	}
+
+	n, err := r.ReadUint32(countBits)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(n) > uint64(r.Remaining()/unitBits) {
+		return nil, ErrShortRead
+	}
+
+	values := make([]uint32, n)
+	for i := range values {
+		if values[i], err = r.ReadUint32(unitBits); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,90 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package bitset
+
+import (
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	b := NewFromBase2String("1101 0010 1111 0000 1")
+	r := NewReader(b)
+
+	if v, err := r.ReadUint32(4); err != nil || v != 0xd {
+		t.Errorf("ReadUint32(4) = %#x, %v, expected 0xd", v, err)
+	}
+
+	if v, err := r.ReadBool(); err != nil || v {
+		t.Errorf("ReadBool() = %t, %v, expected false", v, err)
+	}
+
+	sub, err := r.ReadBitset(7)
+	if err != nil || !sub.Equals(NewFromBase2String("010 1111")) {
+		t.Errorf("ReadBitset(7) = %v, %v, expected 0101111", sub, err)
+	}
+
+	if r.Offset() != 12 || r.Remaining() != 5 {
+		t.Errorf("Offset()=%d Remaining()=%d, expected 12 and 5", r.Offset(), r.Remaining())
+	}
+
+	if _, err := r.ReadUint32(6); err != ErrShortRead {
+		t.Errorf("ReadUint32(6) error %v, expected ErrShortRead", err)
+	}
+	if _, err := r.ReadUint32(33); err == nil {
+		t.Error("ReadUint32(33) succeeded, expected error")
+	}
+
+	if v, err := r.ReadUint32(5); err != nil || v != 0x01 {
+		t.Errorf("ReadUint32(5) = %#x, %v, expected 0x1", v, err)
+	}
+
+	if _, err := r.ReadBool(); err != ErrShortRead {
+		t.Errorf("ReadBool() at end error %v, expected ErrShortRead", err)
+	}
+}
+
+func TestReadLengthPrefixed(t *testing.T) {
+	b := New()
+	b.AppendUint32(3, 4)
+	b.AppendUint32(5, 3)
+	b.AppendUint32(0, 3)
+	b.AppendUint32(7, 3)
+
+	values, err := NewReader(b).ReadLengthPrefixed(4, 3)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []uint32{5, 0, 7}
+	if len(values) != len(expected) {
+		t.Fatalf("got %v, expected %v", values, expected)
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Errorf("got %v, expected %v", values, expected)
+		}
+	}
+
+	// A count larger than the data available.
+	short := New()
+	short.AppendUint32(9, 4)
+	short.AppendUint32(1, 8)
+
+	if _, err := NewReader(short).ReadLengthPrefixed(4, 8); err != ErrShortRead {
+		t.Errorf("got error %v, expected ErrShortRead", err)
+	}
+
+	// A zero unit width would allow 2^32 values from no data.
+	huge := New()
+	huge.AppendUint32(0xffffffff, 32)
+
+	for _, unitBits := range []int{0, -1, 33} {
+		if _, err := NewReader(huge).ReadLengthPrefixed(32, unitBits); err == nil {
+			t.Errorf("unitBits %d: got nil error", unitBits)
+		}
+	}
+	if _, err := NewReader(huge).ReadLengthPrefixed(16, 1); err != ErrShortRead {
+		t.Errorf("got error %v, expected ErrShortRead", err)
+	}
+}
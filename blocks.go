@@ -0,0 +1,140 @@
+package qrcode
+
+import (
+	"image"
+
+	"github.com/yougg/go-qrcode/reedsolomon"
+)
+
+// Block is an error correction block. The data codewords of a QR Code are
+// split into blocks, and Reed-Solomon error correction codewords are computed
+// for each block. The codewords of all blocks are then interleaved, see
+// Codewords, so that damage to one area of the symbol is spread across the
+// blocks.
+type Block struct {
+	// Data codewords, and the error correction codewords computed from them.
+	Data []byte
+	ECC  []byte
+
+	// Positions of the codewords in Codewords: Data[i] is codeword
+	// DataIndex[i], and ECC[i] is codeword ECCIndex[i].
+	DataIndex []int
+	ECCIndex  []int
+}
+
+// Blocks returns the error correction blocks of the QR Code, for teaching
+// tools and validators. Use CodewordModules to find the modules of each
+// codeword.
+func (q *QRCode) Blocks() []Block {
+	var blocks []Block
+
+	data := q.data.Bytes()
+	start := 0
+	for _, b := range q.version.block {
+		for j := 0; j < b.numBlocks; j++ {
+			end := start + b.numDataCodewords
+			numErrorCodewords := b.numCodewords - b.numDataCodewords
+
+			encoded := reedsolomon.Encode(q.data.Substr(start*8, end*8), numErrorCodewords).Bytes()
+			blocks = append(blocks, Block{
+				Data: data[start:end:end],
+				ECC:  encoded[b.numDataCodewords:],
+			})
+
+			start = end
+		}
+	}
+
+	// Data codewords are interleaved first, then error correction codewords.
+	// Later blocks may have one more data codeword than earlier ones.
+	n := 0
+	interleave := func(codewords func(b *Block) []byte, index func(b *Block) *[]int) {
+		for i := 0; ; i++ {
+			placed := false
+			for j := range blocks {
+				if i < len(codewords(&blocks[j])) {
+					*index(&blocks[j]) = append(*index(&blocks[j]), n)
+					n++
+					placed = true
+				}
+			}
+			if !placed {
+				return
+			}
+		}
+	}
+	interleave(func(b *Block) []byte { return b.Data }, func(b *Block) *[]int { return &b.DataIndex })
+	interleave(func(b *Block) []byte { return b.ECC }, func(b *Block) *[]int { return &b.ECCIndex })
+
+	return blocks
+}
+
+// interleaveBlocks returns the codewords of blocks in their interleaved
+// order.
+func interleaveBlocks(blocks []Block) []byte {
+	var total int
+	for _, b := range blocks {
+		total += len(b.Data) + len(b.ECC)
+	}
+
+	codewords := make([]byte, total)
+	for _, b := range blocks {
+		for i, c := range b.Data {
+			codewords[b.DataIndex[i]] = c
+		}
+		for i, c := range b.ECC {
+			codewords[b.ECCIndex[i]] = c
+		}
+	}
+
+	return codewords
+}
+
+// CodewordModules returns the modules of each codeword of Codewords, most
+// significant bit first, as Bitmap coordinates. Codewords are placed in
+// pairs of columns from the right, alternately upwards and downwards,
+// around the function patterns.
+func (q *QRCode) CodewordModules() [][8]image.Point {
+	points := q.dataModules()
+
+	modules := make([][8]image.Point, len(points)/8)
+	for i := range modules {
+		copy(modules[i][:], points[i*8:])
+	}
+
+	return modules
+}
+
+// dataModules returns the data modules of the symbol in placement order, as
+// Bitmap coordinates, including those of the remainder bits.
+func (q *QRCode) dataModules() []image.Point {
+	n := q.version.symbolSize()
+	qz := q.symbol.quietZoneSize
+	top, _, _, left := q.quietZoneExtra()
+
+	var points []image.Point
+	up := true
+	for right := n - 1; right > 0; right -= 2 {
+		// Skip the vertical timing pattern in column 6.
+		if right == 6 {
+			right--
+		}
+
+		for i := 0; i < n; i++ {
+			y := i
+			if up {
+				y = n - 1 - i
+			}
+
+			for _, x := range []int{right, right - 1} {
+				if q.pointType(x+qz, y+qz) == DataPoint {
+					points = append(points, image.Pt(x+qz+left, y+qz+top))
+				}
+			}
+		}
+
+		up = !up
+	}
+
+	return points
+}
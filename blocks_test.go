@@ -0,0 +1,97 @@
+package qrcode
+
+import (
+	"image"
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	// Version 5-Q has 2 blocks of 15 data codewords and 2 of 16, each with
+	// 18 error correction codewords.
+	q, err := New("HELLO WORLD", Level(High), MinVersion(5))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	blocks := q.Blocks()
+	if len(blocks) != 4 {
+		t.Fatalf("got %d blocks, expected 4", len(blocks))
+	}
+
+	codewords := q.Codewords()
+	for i, b := range blocks {
+		if expected := 15 + i/2; len(b.Data) != expected || len(b.DataIndex) != expected {
+			t.Errorf("block %d: got %d data codewords, expected %d", i, len(b.Data), expected)
+		}
+		if len(b.ECC) != 18 || len(b.ECCIndex) != 18 {
+			t.Errorf("block %d: got %d error correction codewords, expected 18", i, len(b.ECC))
+		}
+
+		// Codewords are interleaved one from each block in turn; the last
+		// data codeword of the longer blocks follows the others.
+		if b.DataIndex[0] != i || b.DataIndex[1] != 4+i || b.ECCIndex[0] != 62+i {
+			t.Errorf("block %d: got indexes %v, %v", i, b.DataIndex, b.ECCIndex)
+		}
+		if i >= 2 && b.DataIndex[15] != 60+i-2 {
+			t.Errorf("block %d: last data codeword at %d, expected %d", i, b.DataIndex[15], 60+i-2)
+		}
+
+		for j, c := range b.Data {
+			if codewords[b.DataIndex[j]] != c {
+				t.Errorf("block %d: data codeword %d is not at %d", i, j, b.DataIndex[j])
+			}
+		}
+		for j, c := range b.ECC {
+			if codewords[b.ECCIndex[j]] != c {
+				t.Errorf("block %d: error correction codeword %d is not at %d", i, j, b.ECCIndex[j])
+			}
+		}
+	}
+}
+
+func TestCodewordModules(t *testing.T) {
+	for _, opts := range [][]Option{
+		{Level(Medium)},
+		{Level(Highest), MinVersion(7), Margin(4)},
+		{Level(Low), MinVersion(2), QuietZone(6, 1, 2, 3)},
+	} {
+		q, err := New("https://example.org", opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		codewords := q.Codewords()
+		modules := q.CodewordModules()
+		if len(modules) != len(codewords) {
+			t.Fatalf("got modules of %d codewords, expected %d", len(modules), len(codewords))
+		}
+
+		if got, expected := len(q.dataModules()), len(codewords)*8+q.version.numRemainderBits; got != expected {
+			t.Errorf("got %d data modules, expected %d", got, expected)
+		}
+
+		top, _, _, left := q.quietZoneExtra()
+		origin := image.Pt(q.symbol.quietZoneSize+left, q.symbol.quietZoneSize+top)
+		bitmap := q.Bitmap()
+		seen := map[image.Point]bool{}
+
+		for i, points := range modules {
+			for k, p := range points {
+				if seen[p] {
+					t.Fatalf("codeword %d bit %d: module %v used twice", i, k, p)
+				}
+				seen[p] = true
+
+				if got := q.PointType(p.X, p.Y); got != DataPoint {
+					t.Fatalf("codeword %d bit %d: module %v is %s", i, k, p, got)
+				}
+
+				s := p.Sub(origin)
+				bit := bitmap[p.Y][p.X] != dataMask(q.mask, s.X, s.Y)
+				if expected := codewords[i]&(0x80>>k) != 0; bit != expected {
+					t.Fatalf("codeword %d bit %d: module %v is %v, expected %v", i, k, p, bit, expected)
+				}
+			}
+		}
+	}
+}
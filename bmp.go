@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"time"
+)
+
+// BMP returns the QR Code as a monochrome, 1 bit per pixel Windows bitmap,
+// as required by some POS terminals and printer drivers. The image is sized
+// as Image() is, with each pixel the nearer of the BackgroundColor (index 0)
+// and ForegroundColor (index 1). A Palette of two colors sets the order
+// instead, e.g. for drivers printing index 0 as black.
+func (q *QRCode) BMP() ([]byte, error) {
+	start := time.Now()
+
+	colors := color.Palette{q.BackgroundColor, q.ForegroundColor}
+	if len(q.customPalette) == 2 {
+		colors = q.palette()
+	}
+
+	img := q.Image()
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	// Rows are padded to a multiple of 4 bytes.
+	rowBytes := (width + 31) / 32 * 4
+	const headerSize = 14 + 40 + 2*4
+	imageSize := rowBytes * height
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	// BITMAPFILEHEADER: type, file size, reserved, offset of the pixels.
+	buf.WriteString("BM")
+	binary.Write(&buf, le, [3]uint32{uint32(headerSize + imageSize), 0, headerSize})
+
+	// BITMAPINFOHEADER: a positive height stores the rows bottom up. 2835
+	// pixels per metre is 72 dpi.
+	binary.Write(&buf, le, struct {
+		Size                   uint32
+		Width, Height          int32
+		Planes, BitCount       uint16
+		Compression, ImageSize uint32
+		XPelsPerMeter          int32
+		YPelsPerMeter          int32
+		ColorsUsed, Important  uint32
+	}{40, int32(width), int32(height), 1, 1, 0, uint32(imageSize), 2835, 2835, 2, 2})
+
+	// Color table, blue, green, red, reserved.
+	for _, c := range colors {
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		buf.Write([]byte{n.B, n.G, n.R, 0})
+	}
+
+	row := make([]byte, rowBytes)
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < width; x++ {
+			if colors.Index(img.At(b.Min.X+x, y)) == 1 {
+				row[x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+		buf.Write(row)
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("bmp", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
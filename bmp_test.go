@@ -0,0 +1,82 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+)
+
+func TestBMP(t *testing.T) {
+	q, err := New("https://example.org", Margin(4), Scale(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := q.BMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	le := binary.LittleEndian
+	size := len(q.Bitmap()) * 3
+	rowBytes := (size + 31) / 32 * 4
+
+	if string(data[:2]) != "BM" || int(le.Uint32(data[2:6])) != len(data) {
+		t.Fatalf("invalid file header % x", data[:14])
+	}
+	if w, h := int32(le.Uint32(data[18:22])), int32(le.Uint32(data[22:26])); int(w) != size || int(h) != size {
+		t.Fatalf("got %dx%d, expected %dx%d", w, h, size, size)
+	}
+	if bpp := le.Uint16(data[28:30]); bpp != 1 {
+		t.Fatalf("got %d bits per pixel, expected 1", bpp)
+	}
+
+	// Color table: white, then black.
+	if table := data[54:62]; !bytes.Equal(table, []byte{0xff, 0xff, 0xff, 0, 0, 0, 0, 0}) {
+		t.Errorf("got color table % x", table)
+	}
+
+	offset := int(le.Uint32(data[10:14]))
+	if len(data) != offset+rowBytes*size {
+		t.Fatalf("got %d bytes, expected %d", len(data), offset+rowBytes*size)
+	}
+
+	// Rows are stored bottom up.
+	bitmap := q.Bitmap()
+	for y, row := range bitmap {
+		stored := data[offset+(size-1-3*y)*rowBytes:]
+		for x, v := range row {
+			bit := stored[3*x/8]&(0x80>>uint(3*x%8)) != 0
+			if bit != v {
+				t.Fatalf("module (%d, %d): got %t, expected %t", x, y, bit, v)
+			}
+		}
+	}
+}
+
+func TestBMPPalette(t *testing.T) {
+	q, err := New("https://example.org", Palette(color.Palette{color.Black, color.White}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := q.BMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table := data[54:62]; !bytes.Equal(table, []byte{0, 0, 0, 0, 0xff, 0xff, 0xff, 0}) {
+		t.Errorf("got color table % x, expected black first", table)
+	}
+
+	rendered, err := BMPBytes("https://example.org", 0, Palette(color.Palette{color.Black, color.White}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rendered, data) {
+		t.Error("BMPBytes output differs from BMP")
+	}
+}
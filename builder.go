@@ -0,0 +1,120 @@
+//go:build !tinygo
+// +build !tinygo
+
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"io"
+)
+
+// Builder is a fluent alternative to passing Options to New:
+//
+//	png, err := qrcode.Build().Content("hello").Level(qrcode.High).Size(512).FG(c).PNG()
+//
+// Only a ContentBuilder, returned by Content, can produce output, so
+// forgetting the content is a compile error.
+type Builder struct{}
+
+// Build starts a new Builder.
+func Build() *Builder {
+	return &Builder{}
+}
+
+// Content sets the content to encode.
+func (b *Builder) Content(content string) *ContentBuilder {
+	return &ContentBuilder{content: content}
+}
+
+// ContentBuilder builds a QR Code with its content set. Each setter returns
+// the same ContentBuilder for chaining.
+type ContentBuilder struct {
+	content string
+	opts    []Option
+}
+
+// With appends arbitrary Options, for settings without a dedicated method.
+func (b *ContentBuilder) With(opts ...Option) *ContentBuilder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Level sets the error recovery level.
+func (b *ContentBuilder) Level(l RecoveryLevel) *ContentBuilder {
+	return b.With(Level(l))
+}
+
+// Size sets the image width and height in pixels.
+func (b *ContentBuilder) Size(size int) *ContentBuilder {
+	return b.With(Width(size), Height(size))
+}
+
+// Margin sets the quiet zone width in modules.
+func (b *ContentBuilder) Margin(m int) *ContentBuilder {
+	return b.With(Margin(m))
+}
+
+// MinVersion sets the smallest version to choose.
+func (b *ContentBuilder) MinVersion(v int) *ContentBuilder {
+	return b.With(MinVersion(v))
+}
+
+// FG sets the foreground color.
+func (b *ContentBuilder) FG(c color.Color) *ContentBuilder {
+	return b.With(ForegroundColor(c))
+}
+
+// BG sets the background color.
+func (b *ContentBuilder) BG(c color.Color) *ContentBuilder {
+	return b.With(BackgroundColor(c))
+}
+
+// QRCode encodes the content, see New.
+func (b *ContentBuilder) QRCode() (*QRCode, error) {
+	return New(b.content, b.opts...)
+}
+
+// Image encodes the content and returns the QR Code as an image.Image.
+func (b *ContentBuilder) Image() (image.Image, error) {
+	q, err := b.QRCode()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Image(), nil
+}
+
+// PNG encodes the content and returns the QR Code as a PNG image.
+func (b *ContentBuilder) PNG() ([]byte, error) {
+	q, err := b.QRCode()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PNG()
+}
+
+// Write encodes the content and writes the QR Code as a PNG image to w.
+func (b *ContentBuilder) Write(w io.Writer) error {
+	q, err := b.QRCode()
+	if err != nil {
+		return err
+	}
+
+	return q.Write(w)
+}
+
+// WriteFile encodes the content and writes the QR Code as a PNG image to the
+// named file.
+func (b *ContentBuilder) WriteFile(filename string) error {
+	q, err := b.QRCode()
+	if err != nil {
+		return err
+	}
+
+	return q.WriteFile(filename)
+}
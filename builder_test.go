@@ -0,0 +1,59 @@
+//go:build !tinygo
+// +build !tinygo
+
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	b := Build().Content("builder").Level(High).Size(100).FG(red).BG(color.White)
+
+	q, err := b.QRCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Content != "builder" || q.level != High || q.width != 100 || q.height != 100 || q.ForegroundColor != red {
+		t.Errorf("builder options not applied: %+v", q)
+	}
+
+	data, err := b.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 100 || size.Y != 100 {
+		t.Errorf("got image size %v, expected 100x100", size)
+	}
+
+	expected, err := New("builder", Level(High), Width(100), Height(100), ForegroundColor(red))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPNG, err := expected.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expectedPNG) {
+		t.Error("builder PNG differs from New with the equivalent options")
+	}
+}
+
+func TestBuilderError(t *testing.T) {
+	if _, err := Build().Content("x").MinVersion(41).PNG(); err == nil {
+		t.Error("PNG succeeded with invalid MinVersion, expected error")
+	}
+}
@@ -0,0 +1,122 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// captionFace is the bitmap font used for captions. Every glyph is
+// captionGlyphWidth pixels wide.
+var captionFace = basicfont.Face7x13
+
+const (
+	captionGlyphWidth = 7
+
+	// Text is enlarged by one pixel per captionPixelsPerScale pixels of image
+	// width, so roughly the same number of characters fit at any size.
+	captionPixelsPerScale = 256
+
+	// Placeholder for the characters removed from a long caption.
+	captionEllipsis = "..."
+)
+
+// captionText returns the caption to draw under the symbol, or "" for none.
+func (q *QRCode) captionText() string {
+	if q.captionContent {
+		return q.Content
+	}
+	return q.caption
+}
+
+// captionScale returns the text scale of the caption of an image width
+// pixels wide, and the padding around the text.
+func captionScale(width int) (textScale, padding int) {
+	textScale = max(1, width/captionPixelsPerScale)
+	return textScale, 2 * textScale
+}
+
+// captionHeight returns the height of the band addCaption adds to an image
+// width pixels wide, or 0 if there is no caption.
+func (q *QRCode) captionHeight(width int) int {
+	if q.captionText() == "" {
+		return 0
+	}
+
+	textScale, padding := captionScale(width)
+	return captionFace.Height*textScale + padding
+}
+
+// addCaption returns img extended downwards by a band with the caption text
+// centered in it.
+func (q *QRCode) addCaption(img *image.Paletted, text string) *image.Paletted {
+	b := img.Bounds()
+
+	textScale, padding := captionScale(b.Dx())
+	maxChars := (b.Dx() - 2*padding) / (captionGlyphWidth * textScale)
+	text = elide(text, maxChars)
+
+	out := image.NewPaletted(image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Max.Y+q.captionHeight(b.Dx())), img.Palette)
+
+	background := q.BackgroundColor
+	if q.quietZoneColor != nil {
+		background = q.quietZoneColor
+	}
+	draw.Draw(out, out.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(out, b, img, b.Min, draw.Src)
+
+	width := len([]rune(text)) * captionGlyphWidth * textScale
+	at := image.Pt(b.Min.X+(b.Dx()-width)/2, b.Max.Y)
+	drawText(out, text, at, textScale, image.NewUniform(q.ForegroundColor))
+
+	return out
+}
+
+// drawText draws s with its top left corner at point at, enlarging every
+// pixel of the font to a textScale x textScale block.
+func drawText(dst draw.Image, s string, at image.Point, textScale int, src image.Image) {
+	mask := image.NewAlpha(image.Rect(0, 0, len([]rune(s))*captionGlyphWidth, captionFace.Height))
+	d := font.Drawer{
+		Dst:  mask,
+		Src:  image.Opaque,
+		Face: captionFace,
+		Dot:  fixed.P(0, captionFace.Ascent),
+	}
+	d.DrawString(s)
+
+	mb := mask.Bounds()
+	for y := mb.Min.Y; y < mb.Max.Y; y++ {
+		for x := mb.Min.X; x < mb.Max.X; x++ {
+			if mask.AlphaAt(x, y).A == 0 {
+				continue
+			}
+
+			r := image.Rect(x*textScale, y*textScale, (x+1)*textScale, (y+1)*textScale).Add(at)
+			draw.Draw(dst, r, src, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// elide shortens s to at most n characters by replacing its middle with
+// captionEllipsis, keeping both the start (e.g. a URL's host) and the end.
+func elide(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	keep := n - len(captionEllipsis)
+	if keep < 2 {
+		return string(r[:max(0, n)])
+	}
+
+	head := (keep + 1) / 2
+	tail := keep - head
+	return string(r[:head]) + captionEllipsis + string(r[len(r)-tail:])
+}
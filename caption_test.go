@@ -0,0 +1,113 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestElide(t *testing.T) {
+	tests := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"https://example.org", 30, "https://example.org"},
+		{"https://example.org", 19, "https://example.org"},
+		{"https://example.org/a/long/path", 15, "https:...g/path"},
+		{"abcdef", 4, "abcd"},
+		{"abcdef", 0, ""},
+	}
+
+	for _, test := range tests {
+		if got := elide(test.s, test.n); got != test.expected {
+			t.Errorf("elide(%q, %d) = %q, expected %q", test.s, test.n, got, test.expected)
+		}
+	}
+}
+
+func TestCaption(t *testing.T) {
+	tests := []struct {
+		opts    []Option
+		caption bool
+	}{
+		{[]Option{Width(256), Height(256)}, false},
+		{[]Option{Width(256), Height(256), CaptionContent()}, true},
+		{[]Option{Width(256), Height(256), Caption("Asset 42")}, true},
+		{[]Option{Width(512), Height(512), Caption(strings.Repeat("x", 500))}, true},
+	}
+
+	for i, test := range tests {
+		q, err := New("https://example.org", test.opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		img := q.Image()
+		b := img.Bounds()
+
+		if !test.caption {
+			if b.Dy() != b.Dx() {
+				t.Errorf("test %d: image is %v, expected no caption band", i, b)
+			}
+			continue
+		}
+
+		if b.Dy() <= b.Dx() {
+			t.Fatalf("test %d: image is %v, expected a caption band", i, b)
+		}
+
+		// The symbol is unchanged, and the band has some text in it.
+		bitmap := q.Bitmap()
+		pixelsPerModule := b.Dx() / len(bitmap)
+		offset := (b.Dx() - pixelsPerModule*len(bitmap)) / 2
+		for y, row := range bitmap {
+			for x, v := range row {
+				expected := color.RGBAModel.Convert(color.White)
+				if v {
+					expected = color.RGBAModel.Convert(color.Black)
+				}
+				got := color.RGBAModel.Convert(img.At(offset+x*pixelsPerModule, offset+y*pixelsPerModule))
+				if got != expected {
+					t.Fatalf("test %d: module (%d, %d) is %v, expected %v", i, x, y, got, expected)
+				}
+			}
+		}
+
+		text := 0
+		for y := b.Dx(); y < b.Dy(); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				if color.RGBAModel.Convert(img.At(x, y)) == color.RGBAModel.Convert(color.Black) {
+					text++
+				}
+			}
+		}
+		if text == 0 {
+			t.Errorf("test %d: caption band is empty", i)
+		}
+
+		// Text never runs off the edges.
+		for y := b.Dx(); y < b.Dy(); y++ {
+			for _, x := range []int{0, b.Dx() - 1} {
+				if color.RGBAModel.Convert(img.At(x, y)) != color.RGBAModel.Convert(color.White) {
+					t.Errorf("test %d: caption touches the image edge at (%d, %d)", i, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestCaptionImageType(t *testing.T) {
+	q, err := New("https://example.org", Caption("label"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, ok := q.Image().(*image.Paletted); !ok {
+		t.Errorf("captioned image is %T, expected *image.Paletted", q.Image())
+	}
+}
@@ -0,0 +1,181 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CBOR (RFC 8949) major types. Only the definite length encodings used by
+// COSE messages are supported.
+const (
+	cborUint       byte = 0
+	cborNegInt     byte = 1
+	cborByteString byte = 2
+	cborTextString byte = 3
+	cborArray      byte = 4
+	cborMap        byte = 5
+	cborTag        byte = 6
+)
+
+// cborHead returns the initial bytes of a CBOR data item of major type and
+// argument n.
+func cborHead(major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return []byte{major | byte(n)}
+	case n <= 0xff:
+		return []byte{major | 24, byte(n)}
+	case n <= 0xffff:
+		return []byte{major | 25, byte(n >> 8), byte(n)}
+	case n <= 0xffffffff:
+		return []byte{major | 26, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+
+	return []byte{major | 27, byte(n >> 56), byte(n >> 48), byte(n >> 40), byte(n >> 32),
+		byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+}
+
+// cborInt returns v as a CBOR integer.
+func cborInt(v int64) []byte {
+	if v < 0 {
+		return cborHead(cborNegInt, uint64(-1-v))
+	}
+	return cborHead(cborUint, uint64(v))
+}
+
+// cborBytes returns b as a CBOR byte string.
+func cborBytes(b []byte) []byte {
+	return append(cborHead(cborByteString, uint64(len(b))), b...)
+}
+
+// cborReader reads CBOR data items from data.
+type cborReader struct {
+	data []byte
+	pos  int
+}
+
+var errCBORTruncated = errors.New("CBOR data is truncated")
+
+// peekMajor returns the major type of the next data item, or 0xff at the end
+// of the data.
+func (r *cborReader) peekMajor() byte {
+	if r.pos >= len(r.data) {
+		return 0xff
+	}
+	return r.data[r.pos] >> 5
+}
+
+// head reads the initial bytes of a data item of major type major, and
+// returns its argument.
+func (r *cborReader) head(major byte) (uint64, error) {
+	if r.pos >= len(r.data) {
+		return 0, errCBORTruncated
+	}
+
+	b := r.data[r.pos]
+	if b>>5 != major {
+		return 0, fmt.Errorf("CBOR major type %d at %d, expected %d", b>>5, r.pos, major)
+	}
+	r.pos++
+
+	info := b & 0x1f
+	if info < 24 {
+		return uint64(info), nil
+	}
+	if info > 27 {
+		return 0, fmt.Errorf("unsupported CBOR additional information %d", info)
+	}
+
+	size := 1 << (info - 24)
+	if r.pos+size > len(r.data) {
+		return 0, errCBORTruncated
+	}
+
+	var n uint64
+	for _, c := range r.data[r.pos : r.pos+size] {
+		n = n<<8 | uint64(c)
+	}
+	r.pos += size
+
+	return n, nil
+}
+
+// bytes reads a byte string.
+func (r *cborReader) bytes() ([]byte, error) {
+	n, err := r.head(cborByteString)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, errCBORTruncated
+	}
+
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+
+	return b, nil
+}
+
+// int reads an integer.
+func (r *cborReader) int() (int64, error) {
+	major := r.peekMajor()
+	if major != cborUint && major != cborNegInt {
+		return 0, fmt.Errorf("CBOR major type %d at %d, expected an integer", major, r.pos)
+	}
+
+	n, err := r.head(major)
+	if err != nil {
+		return 0, err
+	}
+	if n > 1<<63-1 {
+		return 0, errors.New("CBOR integer overflows int64")
+	}
+
+	if major == cborNegInt {
+		return -1 - int64(n), nil
+	}
+	return int64(n), nil
+}
+
+// skip reads and discards a data item.
+func (r *cborReader) skip() error {
+	return r.skipDepth(0)
+}
+
+func (r *cborReader) skipDepth(depth int) error {
+	if depth > 16 {
+		return errors.New("CBOR data is nested too deeply")
+	}
+
+	major := r.peekMajor()
+	n, err := r.head(major)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case cborUint, cborNegInt:
+	case cborByteString, cborTextString:
+		if n > uint64(len(r.data)-r.pos) {
+			return errCBORTruncated
+		}
+		r.pos += int(n)
+	case cborArray, cborMap:
+		items := n
+		if major == cborMap {
+			items *= 2
+		}
+		for i := uint64(0); i < items; i++ {
+			if err := r.skipDepth(depth + 1); err != nil {
+				return err
+			}
+		}
+	case cborTag:
+		return r.skipDepth(depth + 1)
+	default:
+		return fmt.Errorf("unsupported CBOR major type %d", major)
+	}
+
+	return nil
+}
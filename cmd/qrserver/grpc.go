@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gRPC methods served, see qrserver.proto.
+const (
+	grpcRenderMethod = "/qrcode.v1.QRCodeRenderer/Render"
+	grpcHealthMethod = "/grpc.health.v1.Health/Check"
+)
+
+// gRPC status codes.
+const (
+	grpcOK                = 0
+	grpcInvalidArgument   = 3
+	grpcResourceExhausted = 8
+	grpcUnimplemented     = 12
+	grpcInternal          = 13
+)
+
+// grpcHTTPStatus maps gRPC status codes to the HTTP status the REST API
+// returns for the same failure, for metrics.
+var grpcHTTPStatus = map[int]int{
+	grpcOK:                http.StatusOK,
+	grpcInvalidArgument:   http.StatusBadRequest,
+	grpcResourceExhausted: http.StatusTooManyRequests,
+	grpcUnimplemented:     http.StatusNotFound,
+	grpcInternal:          http.StatusInternalServerError,
+}
+
+// healthServing is the SERVING status of a grpc.health.v1.HealthCheckResponse.
+const healthServing = 1
+
+// isGRPC reports whether r is a gRPC call.
+func isGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// handleGRPC serves unary gRPC calls of the QRCodeRenderer and Health
+// services, without compression.
+func (s *server) handleGRPC(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	format := ""
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	finish := func(code int, msg string) {
+		w.Header().Set("Grpc-Status", strconv.Itoa(code))
+		if msg != "" {
+			w.Header().Set("Grpc-Message", grpcMessageEscape(msg))
+		}
+		if r.URL.Path == grpcRenderMethod && code != grpcOK {
+			s.metrics.observe(format, grpcHTTPStatus[code], time.Since(start), 0)
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		finish(grpcUnimplemented, "method must be POST")
+		return
+	}
+
+	msg, code, err := readGRPCMessage(r.Body)
+	if err != nil {
+		finish(code, err.Error())
+		return
+	}
+
+	var reply []byte
+	switch r.URL.Path {
+	case grpcHealthMethod:
+		reply = appendProtoVarint(nil, 1, healthServing)
+	case grpcRenderMethod:
+		req, err := decodeRenderRequest(msg)
+		format = req.Format
+		if err != nil {
+			finish(grpcInvalidArgument, err.Error())
+			return
+		}
+		if s.limiter != nil && !s.limiter.Allow(r) {
+			finish(grpcResourceExhausted, "rate limit exceeded")
+			return
+		}
+		if len(req.Payload) > s.maxPayload {
+			finish(grpcResourceExhausted, fmt.Sprintf("payload is %d bytes (maximum %d)", len(req.Payload), s.maxPayload))
+			return
+		}
+
+		q, err := newCode(req, s.metrics)
+		if err != nil {
+			finish(grpcInvalidArgument, err.Error())
+			return
+		}
+		b, err := encode(q, req.Format)
+		if err != nil {
+			finish(grpcInternal, err.Error())
+			return
+		}
+
+		contentType, _ := contentType(req.Format)
+		reply = encodeRenderResponse(b, contentType)
+		s.metrics.observe(format, http.StatusOK, time.Since(start), len(b))
+	default:
+		finish(grpcUnimplemented, fmt.Sprintf("unknown method %s", r.URL.Path))
+		return
+	}
+
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(reply)))
+	w.Write(prefix[:])
+	w.Write(reply)
+
+	finish(grpcOK, "")
+}
+
+// readGRPCMessage reads the single length-prefixed message of a unary call.
+// On error it also returns the gRPC status code to reply with.
+func readGRPCMessage(body io.Reader) ([]byte, int, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, grpcInvalidArgument, fmt.Errorf("reading message: %s", err.Error())
+	}
+
+	if prefix[0] != 0 {
+		return nil, grpcUnimplemented, fmt.Errorf("compressed messages are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > maxRequestBytes {
+		return nil, grpcResourceExhausted, fmt.Errorf("message is %d bytes (maximum %d)", length, maxRequestBytes)
+	}
+
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, grpcInvalidArgument, fmt.Errorf("reading message: %s", err.Error())
+	}
+
+	return msg, grpcOK, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// grpcCall makes a unary gRPC call of method with the message req to the
+// server at url, over cleartext HTTP/2. It returns the reply message and the
+// grpc-status.
+func grpcCall(t *testing.T, url, method string, req []byte) ([]byte, string) {
+	t.Helper()
+
+	p := new(http.Protocols)
+	p.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: p}}
+
+	var body bytes.Buffer
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(req)))
+	body.Write(prefix[:])
+	body.Write(req)
+
+	r, err := http.NewRequest("POST", url+method, &body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	r.Header.Set("Content-Type", "application/grpc")
+	r.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+
+	reply, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	status := resp.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+	}
+	if len(reply) == 0 {
+		return nil, status
+	}
+	if len(reply) < 5 || int(binary.BigEndian.Uint32(reply[1:5])) != len(reply)-5 {
+		t.Fatalf("%s: malformed reply %x", method, reply)
+	}
+
+	return reply[5:], status
+}
+
+func newGRPCServer(s *server) *httptest.Server {
+	ts := httptest.NewUnstartedServer(s.routes())
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetHTTP1(true)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+
+	return ts
+}
+
+func TestGRPCRender(t *testing.T) {
+	ts := newGRPCServer(newServer())
+	defer ts.Close()
+
+	req := appendProtoBytes(nil, 1, []byte("hello"))
+	req = appendProtoVarint(req, 2, uint64(1<<64-4)) // -4 pixels per module.
+	req = appendProtoBytes(req, 3, []byte("H"))
+	req = appendProtoVarint(req, 7, 0)
+
+	reply, status := grpcCall(t, ts.URL, grpcRenderMethod, req)
+	if status != "0" {
+		t.Fatalf("got grpc-status %s, expected 0", status)
+	}
+
+	fields, err := readProto(reply)
+	if err != nil || len(fields) != 2 {
+		t.Fatalf("got reply fields %v, %v", fields, err)
+	}
+	if ct := string(fields[1].b); ct != "image/png" {
+		t.Errorf("got content type %q, expected image/png", ct)
+	}
+
+	img, err := png.Decode(bytes.NewReader(fields[0].b))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if img.Bounds().Dx() != 21*4 {
+		t.Errorf("got %dpx wide image, expected 84px", img.Bounds().Dx())
+	}
+	// A margin of 0 puts a finder pattern in the corner.
+	if r, _, _, _ := img.At(0, 0).RGBA(); r != 0 {
+		t.Error("expected a dark module in the corner with margin 0")
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	s := newServer()
+	s.maxPayload = 10
+	ts := newGRPCServer(s)
+	defer ts.Close()
+
+	negative := uint64(1<<64 - 1) // -1 as an int32 varint.
+
+	tests := []struct {
+		name, method string
+		req          []byte
+		expected     string
+	}{
+		{"no payload", grpcRenderMethod, nil, "3"},
+		{"margin", grpcRenderMethod, appendProtoVarint(appendProtoBytes(nil, 1, []byte("a")), 7, 10000000), "3"},
+		{"negative margin", grpcRenderMethod, appendProtoVarint(appendProtoBytes(nil, 1, []byte("a")), 7, negative), "3"},
+		{"size", grpcRenderMethod, appendProtoVarint(appendProtoBytes(nil, 1, []byte("a")), 2, 100000), "3"},
+		{"level", grpcRenderMethod, appendProtoBytes(appendProtoBytes(nil, 1, []byte("a")), 3, []byte("X")), "3"},
+		{"format", grpcRenderMethod, appendProtoBytes(appendProtoBytes(nil, 1, []byte("a")), 4, []byte("bmp")), "3"},
+		{"payload too large", grpcRenderMethod, appendProtoBytes(nil, 1, []byte("0123456789a")), "8"},
+		{"malformed", grpcRenderMethod, []byte{0x0a, 0x05, 'a'}, "3"},
+		{"unknown method", "/qrcode.v1.QRCodeRenderer/Unknown", nil, "12"},
+	}
+
+	for _, test := range tests {
+		if _, status := grpcCall(t, ts.URL, test.method, test.req); status != test.expected {
+			t.Errorf("%s: got grpc-status %s, expected %s", test.name, status, test.expected)
+		}
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	ts := newGRPCServer(newServer())
+	defer ts.Close()
+
+	reply, status := grpcCall(t, ts.URL, grpcHealthMethod, nil)
+	if status != "0" {
+		t.Fatalf("got grpc-status %s, expected 0", status)
+	}
+	if fields, err := readProto(reply); err != nil || len(fields) != 1 || fields[0].v != healthServing {
+		t.Errorf("got health reply %v, %v, expected SERVING", fields, err)
+	}
+
+	// HTTP/1 requests still reach the REST API.
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got /healthz status %d, expected 200", resp.StatusCode)
+	}
+}
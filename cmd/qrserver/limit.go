@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides whether a request may be served. Implementations must
+// be safe for concurrent use. A server without a RateLimiter serves every
+// request.
+type RateLimiter interface {
+	Allow(r *http.Request) bool
+}
+
+// RateLimiterFunc adapts a function to the RateLimiter interface.
+type RateLimiterFunc func(r *http.Request) bool
+
+// Allow returns f(r).
+func (f RateLimiterFunc) Allow(r *http.Request) bool {
+	return f(r)
+}
+
+// clientLimiter is a token bucket RateLimiter per client IP address.
+type clientLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket holds the tokens of one client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newClientLimiter returns a RateLimiter allowing each client rate requests
+// per second on average, and bursts of up to burst requests.
+func newClientLimiter(rate float64, burst int) *clientLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &clientLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+func (l *clientLimiter) Allow(r *http.Request) bool {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	// Every minute, forget clients whose bucket has filled up again, to bound
+	// memory use.
+	if now.Sub(l.lastSweep) >= time.Minute {
+		for c, other := range l.buckets {
+			if other != b && other.tokens+now.Sub(other.last).Seconds()*l.rate >= l.burst {
+				delete(l.buckets, c)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
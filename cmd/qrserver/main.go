@@ -0,0 +1,216 @@
+// Command qrserver is a small QR Code rendering service.
+//
+// It serves a REST API which mirrors the gRPC service defined in
+// qrserver.proto:
+//
+//	GET  /render?payload=...&size=256&level=M&format=png&foreground=%23000000
+//	POST /render  {"payload": "...", "size": 256, "level": "M", "format": "png"}
+//	GET  /healthz  liveness check
+//	GET  /readyz   readiness check
+//	GET  /metrics  Prometheus metrics
+//
+// The gRPC service, and grpc.health.v1.Health/Check, are served on the same
+// address over cleartext HTTP/2 (h2c), without TLS or compression.
+//
+// Rendered codes carry an ETag, the QR Code's Fingerprint and format, and
+// requests with a matching If-None-Match get 304 Not Modified without
+// rendering. Payloads over -max-payload bytes get 413, and clients over the
+// -rate limit get 429.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRequestBytes bounds the size of a POST /render body.
+const maxRequestBytes = 64 << 10
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	maxPayload := flag.Int("max-payload", defaultMaxPayload, "maximum payload size in bytes")
+	rate := flag.Float64("rate", 0, "requests per second allowed per client IP, 0 for no limit")
+	burst := flag.Int("burst", 10, "requests a client may burst above -rate")
+	flag.Parse()
+
+	s := newServer()
+	s.maxPayload = *maxPayload
+	if *rate > 0 {
+		s.limiter = newClientLimiter(*rate, *burst)
+	}
+
+	log.Printf("qrserver listening on %s", *addr)
+
+	srv := &http.Server{Addr: *addr, Handler: s.routes(), Protocols: new(http.Protocols)}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+// defaultMaxPayload is the default limit on the payload size. It is below
+// the capacity of the largest QR Code, which is impractical to scan.
+const defaultMaxPayload = 2048
+
+// server holds the service state.
+type server struct {
+	metrics *metrics
+
+	// Maximum payload size in bytes.
+	maxPayload int
+
+	// Rate limiter for /render, or nil for no limit.
+	limiter RateLimiter
+}
+
+func newServer() *server {
+	return &server{metrics: newMetrics(), maxPayload: defaultMaxPayload}
+}
+
+// routes returns the HTTP handler serving every endpoint, and gRPC calls.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/render", s.handleRender)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPC(r) {
+			s.handleGRPC(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) handleRender(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	fail := func(format string, status int, msg string) {
+		s.metrics.observe(format, status, time.Since(start), 0)
+		http.Error(w, msg, status)
+	}
+
+	if s.limiter != nil && !s.limiter.Allow(r) {
+		w.Header().Set("Retry-After", "1")
+		fail("", http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	req, err := parseRequest(r)
+	if err != nil {
+		fail(req.Format, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Payload) > s.maxPayload {
+		fail(req.Format, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("payload is %d bytes (maximum %d)", len(req.Payload), s.maxPayload))
+		return
+	}
+
+	q, err := newCode(req, s.metrics)
+	if err != nil {
+		fail(req.Format, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	contentType, _ := contentType(req.Format)
+	etag := fmt.Sprintf(`"%s-%s"`, q.Fingerprint(), strings.ToLower(req.Format))
+
+	w.Header().Set("ETag", etag)
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		s.metrics.observe(req.Format, http.StatusNotModified, time.Since(start), 0)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b, err := encode(q, req.Format)
+	if err != nil {
+		fail(req.Format, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	s.metrics.observe(req.Format, http.StatusOK, time.Since(start), len(b))
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.Write(b)
+}
+
+// etagMatch reports whether an If-None-Match header value matches etag. Weak
+// validators match their strong equivalents, as RFC 7232 requires.
+func etagMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRequest reads a renderRequest from the query string (GET) or a JSON
+// body (POST).
+func parseRequest(r *http.Request) (renderRequest, error) {
+	req := defaultRequest()
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		v := r.URL.Query()
+
+		req.Payload = v.Get("payload")
+		req.Foreground = v.Get("foreground")
+		req.Background = v.Get("background")
+		if f := v.Get("format"); f != "" {
+			req.Format = f
+		}
+		if l := v.Get("level"); l != "" {
+			if err := req.Level.Set(l); err != nil {
+				return req, err
+			}
+		}
+		for name, dst := range map[string]*int{"size": &req.Size, "margin": &req.Margin} {
+			if n := v.Get(name); n != "" {
+				i, err := strconv.Atoi(n)
+				if err != nil {
+					return req, fmt.Errorf("invalid %s %q", name, n)
+				}
+				*dst = i
+			}
+		}
+	case http.MethodPost:
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return req, err
+		}
+	default:
+		return req, fmt.Errorf("method %s not allowed", r.Method)
+	}
+
+	return req, req.checkRange()
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.metrics.writeTo(w)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yougg/go-qrcode"
+)
+
+func TestRender(t *testing.T) {
+	h := newServer().routes()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/render?payload=hello&size=128&level=H", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d (%s), expected 200", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("got Content-Type %s, expected image/png", ct)
+	}
+
+	img, err := png.Decode(w.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if img.Bounds().Dx() != 128 {
+		t.Errorf("got %dpx wide image, expected 128px", img.Bounds().Dx())
+	}
+
+	w = httptest.NewRecorder()
+	body := `{"payload": "hello", "format": "txt", "level": "L"}`
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/render", strings.NewReader(body)))
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "██") {
+		t.Errorf("POST txt render got status %d body %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	h := newServer().routes()
+
+	tests := []struct {
+		method, target, body string
+		expected             int
+	}{
+		{"GET", "/render", "", http.StatusUnprocessableEntity},
+		{"GET", "/render?payload=a&level=X", "", http.StatusBadRequest},
+		{"GET", "/render?payload=a&size=big", "", http.StatusBadRequest},
+		{"GET", "/render?payload=a&format=bmp", "", http.StatusUnprocessableEntity},
+		{"GET", "/render?payload=a&foreground=notacolor", "", http.StatusUnprocessableEntity},
+		{"GET", "/render?payload=a&size=100000", "", http.StatusBadRequest},
+		{"GET", "/render?payload=a&margin=10000000", "", http.StatusBadRequest},
+		{"GET", "/render?payload=a&margin=-1", "", http.StatusBadRequest},
+		{"POST", "/render", `{"payload": "a", "margin": 65}`, http.StatusBadRequest},
+		{"POST", "/render", `{"payload": "a", "unknown": 1}`, http.StatusBadRequest},
+		{"DELETE", "/render", "", http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+
+		if w.Code != test.expected {
+			t.Errorf("%s %s got status %d, expected %d", test.method, test.target, w.Code, test.expected)
+		}
+	}
+}
+
+func TestRenderConditional(t *testing.T) {
+	h := newServer().routes()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/render?payload=etag", nil))
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `-png"`) {
+		t.Fatalf("got status %d, ETag %q", w.Code, etag)
+	}
+
+	tests := []struct {
+		target, ifNoneMatch string
+		expected            int
+	}{
+		{"/render?payload=etag", etag, http.StatusNotModified},
+		{"/render?payload=etag", `"other", W/` + etag, http.StatusNotModified},
+		{"/render?payload=etag", "*", http.StatusNotModified},
+		{"/render?payload=etag", `"other"`, http.StatusOK},
+		{"/render?payload=etag&format=txt", etag, http.StatusOK},
+		{"/render?payload=etag&size=128", etag, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.target, nil)
+		r.Header.Set("If-None-Match", test.ifNoneMatch)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != test.expected {
+			t.Errorf("%s If-None-Match %s got status %d, expected %d", test.target, test.ifNoneMatch, w.Code, test.expected)
+		}
+		if w.Code == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("%s: 304 response has a body", test.target)
+		}
+	}
+}
+
+func TestRenderLimits(t *testing.T) {
+	s := newServer()
+	s.maxPayload = 10
+
+	w := httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest("GET", "/render?payload=01234567890", nil))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized payload got status %d, expected 413", w.Code)
+	}
+
+	allow := false
+	s.limiter = RateLimiterFunc(func(r *http.Request) bool { return allow })
+
+	w = httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest("GET", "/render?payload=a", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("rate limited request got status %d, expected 429 with Retry-After", w.Code)
+	}
+
+	allow = true
+	w = httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest("GET", "/render?payload=a", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("allowed request got status %d, expected 200", w.Code)
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newClientLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	request := func(addr string) *http.Request {
+		r := httptest.NewRequest("GET", "/render", nil)
+		r.RemoteAddr = addr
+		return r
+	}
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow(request("10.0.0.1:1000")) {
+			t.Fatalf("burst request %d denied", i)
+		}
+	}
+	if l.Allow(request("10.0.0.1:1001")) {
+		t.Error("request over the burst allowed")
+	}
+	if !l.Allow(request("10.0.0.2:1000")) {
+		t.Error("another client's request denied")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow(request("10.0.0.1:1000")) {
+		t.Error("request denied after a token was refilled")
+	}
+	if l.Allow(request("10.0.0.1:1000")) {
+		t.Error("request allowed before a token was refilled")
+	}
+
+	now = now.Add(time.Hour)
+	l.Allow(request("10.0.0.3:1000"))
+	if len(l.buckets) != 1 {
+		t.Errorf("got %d buckets after idle clients refilled, expected 1", len(l.buckets))
+	}
+}
+
+func TestHealthAndMetrics(t *testing.T) {
+	h := newServer().routes()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/render?payload=a", nil))
+
+	for _, target := range []string{"/healthz", "/readyz"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s got status %d, expected 200", target, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, expected := range []string{
+		`qrserver_requests_total{format="png",code="200"} 1`,
+		`qrserver_versions_total{version="1"} 1`,
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("metrics %q missing %q", w.Body.String(), expected)
+		}
+	}
+}
+
+func TestMetricsFormatLabel(t *testing.T) {
+	h := newServer().routes()
+
+	for _, format := range []string{"PNG", "bmp", "x\"y", "a\nb"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/render?payload=a&format="+url.QueryEscape(format), nil))
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, expected := range []string{
+		`qrserver_requests_total{format="png",code="200"} 1`,
+		`qrserver_requests_total{format="invalid",code="422"} 3`,
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("metrics %q missing %q", w.Body.String(), expected)
+		}
+	}
+
+	if got, expected := labelEscaper.Replace("a\\b\"c\nd"), `a\\b\"c\nd`; got != expected {
+		t.Errorf("got label %s, expected %s", got, expected)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	for _, s := range []string{"#000", "000000", "#000000ff", "black", "rgb(0, 0, 0)"} {
+		c, err := qrcode.ParseColor(s)
+		if err != nil {
+			t.Errorf("ParseColor(%q) failed: %s", s, err.Error())
+			continue
+		}
+		if r, g, b, a := c.RGBA(); r != 0 || g != 0 || b != 0 || a != 0xffff {
+			t.Errorf("ParseColor(%q) = %v, expected opaque black", s, c)
+		}
+	}
+}
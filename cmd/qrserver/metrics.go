@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yougg/go-qrcode"
+)
+
+// metrics collects request statistics and writes them in the Prometheus text
+// exposition format.
+type metrics struct {
+	mu sync.Mutex
+
+	// Requests by format label, see formatLabel, and status code.
+	requests map[[2]string]uint64
+
+	// Render latency and output size totals.
+	renderSeconds float64
+	renderCount   uint64
+	outputBytes   uint64
+
+	// Encoding statistics, reported by the qrcode package.
+	encodeSeconds float64
+	maskSeconds   float64
+	versions      map[int]uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{requests: make(map[[2]string]uint64), versions: make(map[int]uint64)}
+}
+
+// Encoded implements qrcode.Metrics.
+func (m *metrics) Encoded(d time.Duration, version int, level qrcode.RecoveryLevel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.encodeSeconds += d.Seconds()
+	m.versions[version]++
+}
+
+// MasksEvaluated implements qrcode.Metrics.
+func (m *metrics) MasksEvaluated(d time.Duration, mask int, penalty int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maskSeconds += d.Seconds()
+}
+
+// Rendered implements qrcode.Metrics. Output sizes are recorded by observe,
+// which also covers formats rendered outside the qrcode package.
+func (m *metrics) Rendered(format string, d time.Duration, numBytes int) {
+}
+
+// observe records a single render request.
+func (m *metrics) observe(format string, status int, d time.Duration, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[[2]string{formatLabel(format), fmt.Sprint(status)}]++
+	m.renderSeconds += d.Seconds()
+	m.renderCount++
+	m.outputBytes += uint64(size)
+}
+
+// formatLabel returns the metrics label of a requested format: the format in
+// lower case, "" if the request was rejected before it was read, or "invalid"
+// for every unknown format, so clients can't add labels without limit.
+func formatLabel(format string) string {
+	if format == "" {
+		return ""
+	}
+	if _, err := contentType(format); err != nil {
+		return "invalid"
+	}
+
+	return strings.ToLower(format)
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeTo writes the metrics to w.
+func (m *metrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([][2]string, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	fmt.Fprintln(w, "# HELP qrserver_requests_total Render requests by format and HTTP status code.")
+	fmt.Fprintln(w, "# TYPE qrserver_requests_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(w, "qrserver_requests_total{format=\"%s\",code=\"%s\"} %d\n",
+			labelEscaper.Replace(k[0]), labelEscaper.Replace(k[1]), m.requests[k])
+	}
+
+	fmt.Fprintln(w, "# HELP qrserver_render_seconds Time spent rendering.")
+	fmt.Fprintln(w, "# TYPE qrserver_render_seconds summary")
+	fmt.Fprintf(w, "qrserver_render_seconds_sum %g\n", m.renderSeconds)
+	fmt.Fprintf(w, "qrserver_render_seconds_count %d\n", m.renderCount)
+
+	fmt.Fprintln(w, "# HELP qrserver_encode_seconds_total Time spent encoding content, including mask evaluation.")
+	fmt.Fprintln(w, "# TYPE qrserver_encode_seconds_total counter")
+	fmt.Fprintf(w, "qrserver_encode_seconds_total %g\n", m.encodeSeconds)
+
+	fmt.Fprintln(w, "# HELP qrserver_mask_seconds_total Time spent evaluating data masks.")
+	fmt.Fprintln(w, "# TYPE qrserver_mask_seconds_total counter")
+	fmt.Fprintf(w, "qrserver_mask_seconds_total %g\n", m.maskSeconds)
+
+	versions := make([]int, 0, len(m.versions))
+	for v := range m.versions {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+
+	fmt.Fprintln(w, "# HELP qrserver_versions_total QR Codes encoded by version.")
+	fmt.Fprintln(w, "# TYPE qrserver_versions_total counter")
+	for _, v := range versions {
+		fmt.Fprintf(w, "qrserver_versions_total{version=\"%d\"} %d\n", v, m.versions[v])
+	}
+
+	fmt.Fprintln(w, "# HELP qrserver_output_bytes_total Bytes of rendered output.")
+	fmt.Fprintln(w, "# TYPE qrserver_output_bytes_total counter")
+	fmt.Fprintf(w, "qrserver_output_bytes_total %d\n", m.outputBytes)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoField is a field read from a protocol buffer message. Varint fields
+// have v set, length delimited fields b.
+type protoField struct {
+	num  int
+	wire int
+	v    uint64
+	b    []byte
+}
+
+// readProto returns the fields of the protocol buffer message b, in order.
+func readProto(b []byte) ([]protoField, error) {
+	var fields []protoField
+
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid message: bad field key")
+		}
+		b = b[n:]
+
+		f := protoField{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.v, n = binary.Uvarint(b); n <= 0 {
+				return nil, fmt.Errorf("invalid message: bad varint in field %d", f.num)
+			}
+			b = b[n:]
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || length > uint64(len(b)-n) {
+				return nil, fmt.Errorf("invalid message: bad length of field %d", f.num)
+			}
+			f.b = b[n : n+int(length)]
+			b = b[n+int(length):]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if f.wire == wireFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, fmt.Errorf("invalid message: truncated field %d", f.num)
+			}
+			b = b[size:]
+		default:
+			return nil, fmt.Errorf("invalid message: unsupported wire type %d", f.wire)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// decodeRenderRequest returns the RenderRequest message b. Fields missing
+// from b keep their defaults, see defaultRequest.
+func decodeRenderRequest(b []byte) (renderRequest, error) {
+	req := defaultRequest()
+
+	fields, err := readProto(b)
+	if err != nil {
+		return req, err
+	}
+
+	for _, f := range fields {
+		var err error
+		switch {
+		case f.num == 1 && f.wire == wireBytes:
+			req.Payload = string(f.b)
+		case f.num == 2 && f.wire == wireVarint:
+			req.Size = int(int32(f.v))
+		case f.num == 3 && f.wire == wireBytes:
+			if len(f.b) > 0 {
+				err = req.Level.Set(string(f.b))
+			}
+		case f.num == 4 && f.wire == wireBytes:
+			if len(f.b) > 0 {
+				req.Format = string(f.b)
+			}
+		case f.num == 5 && f.wire == wireBytes:
+			req.Foreground = string(f.b)
+		case f.num == 6 && f.wire == wireBytes:
+			req.Background = string(f.b)
+		case f.num == 7 && f.wire == wireVarint:
+			req.Margin = int(int32(f.v))
+		case f.num >= 1 && f.num <= 7:
+			err = fmt.Errorf("invalid message: field %d has wire type %d", f.num, f.wire)
+		}
+		if err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+// appendProtoBytes appends field num of b to the message m.
+func appendProtoBytes(m []byte, num int, b []byte) []byte {
+	m = binary.AppendUvarint(m, uint64(num)<<3|wireBytes)
+	m = binary.AppendUvarint(m, uint64(len(b)))
+	return append(m, b...)
+}
+
+// appendProtoVarint appends field num of v to the message m.
+func appendProtoVarint(m []byte, num int, v uint64) []byte {
+	m = binary.AppendUvarint(m, uint64(num)<<3|wireVarint)
+	return binary.AppendUvarint(m, v)
+}
+
+// encodeRenderResponse returns a RenderResponse message.
+func encodeRenderResponse(data []byte, contentType string) []byte {
+	m := appendProtoBytes(nil, 1, data)
+	return appendProtoBytes(m, 2, []byte(contentType))
+}
+
+// grpcMessageEscape percent-encodes s for the grpc-message trailer, as the
+// gRPC HTTP/2 protocol requires.
+func grpcMessageEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
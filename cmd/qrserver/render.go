@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/gif"
+	"strings"
+
+	"github.com/yougg/go-qrcode"
+)
+
+// renderRequest describes a QR Code to render. It mirrors RenderRequest in
+// qrserver.proto.
+type renderRequest struct {
+	// Content to encode.
+	Payload string `json:"payload"`
+
+	// Image width and height in pixels. Negative values give the number of
+	// pixels per module, as for qrcode.Width.
+	Size int `json:"size"`
+
+	// Error recovery level: L, M, Q or H.
+	Level qrcode.RecoveryLevel `json:"level"`
+
+	// Output format: png, gif or txt.
+	Format string `json:"format"`
+
+	// Colors as accepted by qrcode.ParseColor, e.g. #1a73e8 or rgb(26, 115, 232).
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+
+	// Quiet zone width in modules, at most maxMargin.
+	Margin int `json:"margin"`
+}
+
+// Limits on the image size, to bound memory use.
+const (
+	maxSize            = 4096
+	maxPixelsPerModule = 16
+	maxMargin          = 64
+)
+
+// defaultRequest returns a renderRequest with every field at its default.
+func defaultRequest() renderRequest {
+	return renderRequest{
+		Size:   256,
+		Level:  qrcode.Medium,
+		Format: "png",
+		Margin: 4,
+	}
+}
+
+// contentTypes are the content types of the output formats.
+var contentTypes = map[string]string{
+	"png": "image/png",
+	"gif": "image/gif",
+	"txt": "text/plain; charset=utf-8",
+}
+
+// contentType returns the content type of format, or an error if format is
+// unknown.
+func contentType(format string) (string, error) {
+	if format == "" {
+		format = "png"
+	}
+
+	ct, ok := contentTypes[strings.ToLower(format)]
+	if !ok {
+		return "", fmt.Errorf("unknown format %q (expected png, gif or txt)", format)
+	}
+
+	return ct, nil
+}
+
+// checkRange returns an error if the size or margin of r is out of range.
+func (r renderRequest) checkRange() error {
+	if r.Size > maxSize || r.Size < -maxPixelsPerModule {
+		return fmt.Errorf("size %d out of range", r.Size)
+	}
+	if r.Margin < 0 || r.Margin > maxMargin {
+		return fmt.Errorf("margin %d out of range (maximum %d)", r.Margin, maxMargin)
+	}
+
+	return nil
+}
+
+// newCode validates r and encodes its payload. Encoding events are reported
+// to m.
+func newCode(r renderRequest, m qrcode.Metrics) (*qrcode.QRCode, error) {
+	if r.Payload == "" {
+		return nil, errors.New("payload is required")
+	}
+	if err := r.checkRange(); err != nil {
+		return nil, err
+	}
+	if _, err := contentType(r.Format); err != nil {
+		return nil, err
+	}
+
+	opts := []qrcode.Option{
+		qrcode.Level(r.Level),
+		qrcode.Width(r.Size),
+		qrcode.Height(r.Size),
+		qrcode.Margin(r.Margin),
+		qrcode.Instrument(m),
+	}
+
+	if r.Foreground != "" {
+		c, err := qrcode.ParseColor(r.Foreground)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, qrcode.ForegroundColor(c))
+	}
+	if r.Background != "" {
+		c, err := qrcode.ParseColor(r.Background)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, qrcode.BackgroundColor(c))
+	}
+
+	return qrcode.New(r.Payload, opts...)
+}
+
+// encode returns q in format, which contentType has accepted.
+func encode(q *qrcode.QRCode, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "gif":
+		var buf bytes.Buffer
+		err := gif.Encode(&buf, q.Image(), nil)
+		return buf.Bytes(), err
+	case "txt":
+		return []byte(q.ToString(false)), nil
+	}
+
+	return q.PNG()
+}
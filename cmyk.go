@@ -0,0 +1,228 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+)
+
+// Ink is the printing ink used for the dark modules of TIFF and PDF output.
+// Light modules are left as unprinted paper.
+type Ink struct {
+	// Process color of the ink. For a spot color this is the alternate used
+	// for proofing and by devices without the named ink.
+	CMYK color.CMYK
+
+	// Spot color name, e.g. "PANTONE 286 C", or empty for a process color.
+	Spot string
+}
+
+// BlackInk prints the modules in 100% K only, avoiding rich black and the
+// registration errors it causes in fine modules.
+var BlackInk = Ink{CMYK: color.CMYK{K: 0xff}}
+
+// Millimetres per inch, and PDF points (1/72 inch) per millimetre.
+const (
+	mmPerInch   = 25.4
+	pointsPerMM = 72 / mmPerInch
+)
+
+// TIFF returns the QR Code as an uncompressed CMYK TIFF image, laid out as by
+// Image(), with foreground pixels printed in ink and every other pixel left
+// unprinted. A spot Ink is written as its CMYK process color.
+func (q *QRCode) TIFF(ink Ink) ([]byte, error) {
+	start := time.Now()
+
+	img := q.Image()
+	b := img.Bounds()
+
+	fg := color.RGBAModel.Convert(q.ForegroundColor)
+
+	const numEntries = 14
+	ifdEnd := 8 + 2 + numEntries*12 + 4
+	bitsPerSampleOffset := ifdEnd
+	xResolutionOffset := bitsPerSampleOffset + 8
+	yResolutionOffset := xResolutionOffset + 8
+	pixelOffset := yResolutionOffset + 8
+	pixelBytes := 4 * b.Dx() * b.Dy()
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	w := func(v interface{}) {
+		binary.Write(&buf, le, v)
+	}
+
+	buf.WriteString("II")
+	w(uint16(42))
+	w(uint32(8))
+
+	const (
+		typeShort    = 3
+		typeLong     = 4
+		typeRational = 5
+	)
+	entry := func(tag, typ uint16, count, value uint32) {
+		w(tag)
+		w(typ)
+		w(count)
+		if typ == typeShort && count == 1 {
+			w(uint16(value))
+			w(uint16(0))
+		} else {
+			w(value)
+		}
+	}
+
+	w(uint16(numEntries))
+	entry(256, typeLong, 1, uint32(b.Dx()))                // ImageWidth.
+	entry(257, typeLong, 1, uint32(b.Dy()))                // ImageLength.
+	entry(258, typeShort, 4, uint32(bitsPerSampleOffset))  // BitsPerSample.
+	entry(259, typeShort, 1, 1)                            // Compression: none.
+	entry(262, typeShort, 1, 5)                            // PhotometricInterpretation: separated.
+	entry(273, typeLong, 1, uint32(pixelOffset))           // StripOffsets.
+	entry(277, typeShort, 1, 4)                            // SamplesPerPixel.
+	entry(278, typeLong, 1, uint32(b.Dy()))                // RowsPerStrip.
+	entry(279, typeLong, 1, uint32(pixelBytes))            // StripByteCounts.
+	entry(282, typeRational, 1, uint32(xResolutionOffset)) // XResolution.
+	entry(283, typeRational, 1, uint32(yResolutionOffset)) // YResolution.
+	entry(284, typeShort, 1, 1)                            // PlanarConfiguration: chunky.
+	entry(296, typeShort, 1, 2)                            // ResolutionUnit: inch.
+	entry(332, typeShort, 1, 1)                            // InkSet: CMYK.
+	w(uint32(0))                                           // No next IFD.
+
+	w([4]uint16{8, 8, 8, 8})
+	w([2]uint32{72, 1})
+	w([2]uint32{72, 1})
+
+	inked := []byte{ink.CMYK.C, ink.CMYK.M, ink.CMYK.Y, ink.CMYK.K}
+	paper := []byte{0, 0, 0, 0}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == fg {
+				buf.Write(inked)
+			} else {
+				buf.Write(paper)
+			}
+		}
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("tiff", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PDF returns the QR Code as a single page vector PDF, sizeMM millimetres
+// square including the quiet zone. The dark modules are filled with ink, in
+// DeviceCMYK or, for a spot Ink, a Separation color space with the ink's
+// CMYK as the alternate. Captions, stamps and other image options are not
+// drawn.
+func (q *QRCode) PDF(ink Ink, sizeMM float64) ([]byte, error) {
+	if sizeMM <= 0 {
+		return nil, errors.New("pdf: size must be positive")
+	}
+
+	start := time.Now()
+
+	bitmap := q.symbol.bitmap()
+	n := len(bitmap)
+	pageSize := sizeMM * pointsPerMM
+	module := pageSize / float64(n)
+
+	c := float64(ink.CMYK.C) / 0xff
+	m := float64(ink.CMYK.M) / 0xff
+	y := float64(ink.CMYK.Y) / 0xff
+	k := float64(ink.CMYK.K) / 0xff
+
+	var content bytes.Buffer
+	resources := "<< >>"
+	if ink.Spot != "" {
+		content.WriteString("/CS0 cs 1 scn\n")
+		resources = fmt.Sprintf("<< /ColorSpace << /CS0 [/Separation %s /DeviceCMYK "+
+			"<< /FunctionType 2 /Domain [0 1] /C0 [0 0 0 0] /C1 [%s %s %s %s] /N 1 >>] >> >>",
+			pdfName(ink.Spot), pdfNumber(c), pdfNumber(m), pdfNumber(y), pdfNumber(k))
+	} else {
+		fmt.Fprintf(&content, "%s %s %s %s k\n", pdfNumber(c), pdfNumber(m), pdfNumber(y), pdfNumber(k))
+	}
+
+	// One rectangle per horizontal run of dark modules. PDF's origin is the
+	// bottom left corner.
+	for row, modules := range bitmap {
+		for x := 0; x < n; x++ {
+			if !modules[x] {
+				continue
+			}
+
+			run := x
+			for run < n && modules[run] {
+				run++
+			}
+
+			fmt.Fprintf(&content, "%s %s %s %s re\n", pdfNumber(float64(x)*module),
+				pdfNumber(float64(n-1-row)*module), pdfNumber(float64(run-x)*module), pdfNumber(module))
+			x = run
+		}
+	}
+	content.WriteString("f\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents 4 0 R >>",
+			pdfNumber(pageSize), pdfNumber(pageSize), resources),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	if q.metrics != nil {
+		q.metrics.Rendered("pdf", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pdfNumber formats v as a PDF real number with up to 4 decimal places.
+func pdfNumber(v float64) string {
+	s := fmt.Sprintf("%.4f", v)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// pdfName returns s as a PDF name object, escaping delimiters, whitespace
+// and non-ASCII bytes as #xx.
+func pdfName(s string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' || strings.IndexByte("#()<>[]{}/%", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,171 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTIFF(t *testing.T) {
+	q, err := New("https://example.org", Width(66), Height(66), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ink := Ink{CMYK: color.CMYK{C: 0x10, M: 0x20, Y: 0x30, K: 0xff}}
+	data, err := q.TIFF(ink)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(data[0:4]) != "II*\x00" {
+		t.Fatalf("got header %q, expected little endian TIFF", data[0:4])
+	}
+
+	le := binary.LittleEndian
+	ifd := le.Uint32(data[4:8])
+	tags := map[uint16]uint32{}
+	for i := 0; i < int(le.Uint16(data[ifd:])); i++ {
+		e := data[int(ifd)+2+12*i:]
+		value := le.Uint32(e[8:12])
+		if le.Uint16(e[2:4]) == 3 && le.Uint32(e[4:8]) == 1 {
+			value = uint32(le.Uint16(e[8:10]))
+		}
+		tags[le.Uint16(e[0:2])] = value
+	}
+
+	img := q.Image()
+	b := img.Bounds()
+
+	for tag, expected := range map[uint16]uint32{
+		256: uint32(b.Dx()),
+		257: uint32(b.Dy()),
+		262: 5, // Separated.
+		277: 4,
+		279: uint32(4 * b.Dx() * b.Dy()),
+		332: 1, // CMYK.
+	} {
+		if tags[tag] != expected {
+			t.Errorf("tag %d is %d, expected %d", tag, tags[tag], expected)
+		}
+	}
+
+	pix := data[tags[273]:]
+	if len(pix) != 4*b.Dx()*b.Dy() {
+		t.Fatalf("got %d bytes of pixels, expected %d", len(pix), 4*b.Dx()*b.Dy())
+	}
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			expected := []byte{0, 0, 0, 0}
+			if color.RGBAModel.Convert(img.At(x, y)) == color.RGBAModel.Convert(color.Black) {
+				expected = []byte{0x10, 0x20, 0x30, 0xff}
+			}
+
+			i := 4 * (y*b.Dx() + x)
+			if !bytes.Equal(pix[i:i+4], expected) {
+				t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, pix[i:i+4], expected)
+			}
+		}
+	}
+}
+
+func TestPDF(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := []struct {
+		ink      Ink
+		contains []string
+	}{
+		{
+			BlackInk,
+			[]string{"0 0 0 1 k\n", "/MediaBox [0 0 85.0394 85.0394]"},
+		},
+		{
+			Ink{CMYK: color.CMYK{C: 0xff, M: 0x80}, Spot: "PANTONE 286 C"},
+			[]string{"/CS0 cs 1 scn\n", "/Separation /PANTONE#20286#20C /DeviceCMYK", "/C1 [1 0.502 0 0]"},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := q.PDF(test.ink, 30)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		s := string(data)
+		if !strings.HasPrefix(s, "%PDF-1.4\n") || !strings.HasSuffix(s, "%%EOF\n") {
+			t.Errorf("missing PDF header or trailer")
+		}
+
+		for _, c := range test.contains {
+			if !strings.Contains(s, c) {
+				t.Errorf("PDF does not contain %q", c)
+			}
+		}
+
+		checkPDFXref(t, s)
+
+		// Every dark module is covered by exactly one rectangle.
+		module := 30 * pointsPerMM / float64(len(q.Bitmap()))
+		area := 0.0
+		for _, m := range regexp.MustCompile(`([\d.]+) ([\d.]+) ([\d.]+) ([\d.]+) re`).FindAllStringSubmatch(s, -1) {
+			w, _ := strconv.ParseFloat(m[3], 64)
+			h, _ := strconv.ParseFloat(m[4], 64)
+			area += w * h
+		}
+
+		dark := 0
+		for _, row := range q.Bitmap() {
+			for _, v := range row {
+				if v {
+					dark++
+				}
+			}
+		}
+
+		if expected := float64(dark) * module * module; area < expected*0.999 || area > expected*1.001 {
+			t.Errorf("rectangles cover %f square points, expected %f", area, expected)
+		}
+	}
+
+	if _, err := q.PDF(BlackInk, 0); err == nil {
+		t.Error("PDF with zero size succeeded, expected error")
+	}
+}
+
+// checkPDFXref checks every cross-reference table entry points to its
+// object.
+func checkPDFXref(t *testing.T, s string) {
+	startxref := strings.LastIndex(s, "startxref\n")
+	xref, err := strconv.Atoi(strings.Fields(s[startxref+len("startxref\n"):])[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(s[xref:], "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		if !strings.HasPrefix(s[offset:], fmt.Sprintf("%d 0 obj", i)) {
+			t.Errorf("xref entry %d does not point to its object", i)
+		}
+	}
+}
+
+func TestPDFName(t *testing.T) {
+	if got := pdfName("PANTONE 286/C#"); got != "/PANTONE#20286#2FC#23" {
+		t.Errorf("got %q", got)
+	}
+}
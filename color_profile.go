@@ -0,0 +1,96 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+)
+
+// pngIHDREnd is the offset just after the IHDR chunk, which is always first
+// and 13 bytes long.
+const pngIHDREnd = len(pngSignature) + 8 + 13 + 4
+
+// colorProfileChunk returns the color space chunk chosen by ICCProfile: sRGB
+// by default, an embedded ICC profile, or nothing.
+func (q *QRCode) colorProfileChunk() ([]byte, error) {
+	var chunk bytes.Buffer
+	e := &pngChunkWriter{w: &chunk}
+
+	switch {
+	case !q.iccProfileSet:
+		// Perceptual rendering intent.
+		e.writeChunk("sRGB", []byte{0})
+	case q.iccProfile != nil:
+		name := q.iccProfileName
+		if len(name) < 1 || len(name) > 79 {
+			return nil, errors.New("png: ICC profile name must be 1-79 bytes")
+		}
+
+		var data bytes.Buffer
+		data.WriteString(name)
+		data.Write([]byte{0, 0}) // Name terminator, and zlib compression.
+
+		if q.reproducible {
+			data.Write(fixedZlib(q.iccProfile))
+		} else {
+			zw := zlib.NewWriter(&data)
+			if _, err := zw.Write(q.iccProfile); err != nil {
+				return nil, err
+			}
+			if err := zw.Close(); err != nil {
+				return nil, err
+			}
+		}
+
+		e.writeChunk("iCCP", data.Bytes())
+	}
+
+	return chunk.Bytes(), e.err
+}
+
+// colorProfileWriter passes the output of image/png through to w, inserting
+// chunk after IHDR. Only the signature and IHDR are buffered.
+type colorProfileWriter struct {
+	w      io.Writer
+	chunk  []byte
+	header []byte
+	n      int
+}
+
+func (c *colorProfileWriter) Write(p []byte) (int, error) {
+	written := 0
+
+	if len(c.header) < pngIHDREnd {
+		k := min(len(p), pngIHDREnd-len(c.header))
+		c.header = append(c.header, p[:k]...)
+		p = p[k:]
+		written = k
+
+		if len(c.header) < pngIHDREnd {
+			return written, nil
+		}
+
+		if string(c.header[:len(pngSignature)]) != pngSignature {
+			return written, errors.New("png: invalid encoder output")
+		}
+
+		for _, b := range [][]byte{c.header, c.chunk} {
+			n, err := c.w.Write(b)
+			c.n += n
+			if err == nil && n < len(b) {
+				err = io.ErrShortWrite
+			}
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	n, err := c.w.Write(p)
+	c.n += n
+	return written + n, err
+}
@@ -0,0 +1,103 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image/png"
+	"io/ioutil"
+	"testing"
+)
+
+// pngChunks returns the chunks of a PNG file, in order, keyed by type.
+func pngChunks(t *testing.T, data []byte) (types []string, chunks map[string][]byte) {
+	chunks = map[string][]byte{}
+	for i := len(pngSignature); i < len(data); {
+		n := int(binary.BigEndian.Uint32(data[i : i+4]))
+		name := string(data[i+4 : i+8])
+		types = append(types, name)
+		chunks[name] = data[i+8 : i+8+n]
+		i += 12 + n
+	}
+	return types, chunks
+}
+
+func TestPNGColorProfile(t *testing.T) {
+	profile := bytes.Repeat([]byte("fake icc profile "), 10)
+
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil, "sRGB"},
+		{[]Option{ICCProfile("Custom", profile)}, "iCCP"},
+		{[]Option{ICCProfile("", nil)}, ""},
+	}
+
+	for _, test := range tests {
+		q, err := New("https://example.org", test.opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		data, err := q.PNG()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+			t.Fatalf("%s: %s", test.expected, err.Error())
+		}
+
+		types, chunks := pngChunks(t, data)
+		if types[0] != "IHDR" {
+			t.Errorf("first chunk is %s, expected IHDR", types[0])
+		}
+
+		_, hasSRGB := chunks["sRGB"]
+		_, hasICCP := chunks["iCCP"]
+
+		switch test.expected {
+		case "sRGB":
+			if types[1] != "sRGB" || hasICCP {
+				t.Errorf("got chunks %v, expected sRGB after IHDR", types)
+			}
+		case "iCCP":
+			if types[1] != "iCCP" || hasSRGB {
+				t.Errorf("got chunks %v, expected iCCP after IHDR", types)
+			}
+
+			iccp := chunks["iCCP"]
+			if !bytes.HasPrefix(iccp, []byte("Custom\x00\x00")) {
+				t.Fatalf("iCCP chunk starts %q, expected name and compression method", iccp[:8])
+			}
+
+			r, err := zlib.NewReader(bytes.NewReader(iccp[8:]))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			got, err := ioutil.ReadAll(r)
+			if err != nil || !bytes.Equal(got, profile) {
+				t.Errorf("embedded profile is %q, %v, expected %q", got, err, profile)
+			}
+		default:
+			if hasSRGB || hasICCP {
+				t.Errorf("got chunks %v, expected no color space", types)
+			}
+		}
+	}
+}
+
+func TestPNGColorProfileInvalidName(t *testing.T) {
+	q, err := New("https://example.org", ICCProfile("", []byte("profile")))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := q.PNG(); err == nil {
+		t.Error("PNG() with an empty profile name succeeded, expected error")
+	}
+}
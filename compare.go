@@ -0,0 +1,155 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Colors of the image returned by DiffImages.
+var (
+	diffUnchangedDark  = color.RGBA{0xc0, 0xc0, 0xc0, 0xff}
+	diffUnchangedLight = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	diffChanged        = color.RGBA{0xff, 0x00, 0x00, 0xff}
+)
+
+// DiffImages compares two images of QR Codes module by module, for golden
+// image tests that should only fail when the symbol itself changes. Colors,
+// image size, quiet zone width and file metadata are ignored.
+//
+// Each image is read by locating its top left finder pattern and sampling the
+// center of every module, so the images must be upright and unscaled by any
+// smoothing filter. changedModules is the number of modules which differ, with
+// every module counted as changed if the symbols differ in size. diff shows
+// the symbol of a with changed modules in red.
+//
+// If no symbol is found in a or b, changedModules is -1 and diff is nil.
+func DiffImages(a, b image.Image) (changedModules int, diff image.Image) {
+	ga, ok := readModuleGrid(a)
+	if !ok {
+		return -1, nil
+	}
+	gb, ok := readModuleGrid(b)
+	if !ok {
+		return -1, nil
+	}
+
+	n := len(ga.modules)
+	sameSize := n == len(gb.modules)
+	if !sameSize {
+		changedModules = max(n, len(gb.modules)) * max(n, len(gb.modules))
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, n, n),
+		color.Palette{diffUnchangedLight, diffUnchangedDark, diffChanged})
+
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			switch {
+			case !sameSize || ga.modules[y][x] != gb.modules[y][x]:
+				img.SetColorIndex(x, y, 2)
+				if sameSize {
+					changedModules++
+				}
+			case ga.modules[y][x]:
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return changedModules, scaleNearest(img, n*ga.pixelsPerModule(), n*ga.pixelsPerModule())
+}
+
+// moduleGrid is a symbol read from an image by readModuleGrid.
+type moduleGrid struct {
+	// modules[y][x] is true if the module is dark. The quiet zone is
+	// excluded.
+	modules [][]bool
+
+	// Width of a module in pixels.
+	moduleSize float64
+}
+
+// pixelsPerModule returns the module size rounded to a whole number of
+// pixels, at least 1.
+func (g *moduleGrid) pixelsPerModule() int {
+	return max(int(math.Round(g.moduleSize)), 1)
+}
+
+// readModuleGrid samples the modules of the QR Code in img.
+func readModuleGrid(img image.Image) (*moduleGrid, bool) {
+	b := img.Bounds()
+
+	dark := func(x, y int) bool {
+		c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+		luma := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+		return c.A >= 0x80 && luma < 0x80
+	}
+
+	// The top left corner of the finder pattern is on the first row and the
+	// first column containing a dark pixel. They are searched separately, as
+	// the quiet zone and modules need not be square.
+	y0 := -1
+	for y := b.Min.Y; y < b.Max.Y && y0 < 0; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if dark(x, y) {
+				y0 = y
+				break
+			}
+		}
+	}
+	x0 := -1
+	for x := b.Min.X; x < b.Max.X && x0 < 0; x++ {
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			if dark(x, y) {
+				x0 = x
+				break
+			}
+		}
+	}
+	if x0 < 0 || y0 < 0 || !dark(x0, y0) {
+		return nil, false
+	}
+
+	// The top and left edges of the finder pattern are 7 dark modules.
+	run := 0
+	for x := x0; x < b.Max.X && dark(x, y0); x++ {
+		run++
+	}
+	moduleSize := float64(run) / 7
+
+	run = 0
+	for y := y0; y < b.Max.Y && dark(x0, y); y++ {
+		run++
+	}
+	moduleHeight := float64(run) / 7
+
+	// The top edge of the top right finder pattern ends the symbol.
+	x1 := x0
+	for x := b.Max.X - 1; x > x0; x-- {
+		if dark(x, y0) {
+			x1 = x + 1
+			break
+		}
+	}
+
+	n := int(math.Round(float64(x1-x0) / moduleSize))
+	if n < 21 || (n-17)%4 != 0 {
+		return nil, false
+	}
+
+	g := &moduleGrid{modules: make([][]bool, n), moduleSize: moduleSize}
+	for y := 0; y < n; y++ {
+		g.modules[y] = make([]bool, n)
+		for x := 0; x < n; x++ {
+			px := x0 + int((float64(x)+0.5)*moduleSize)
+			py := y0 + int((float64(y)+0.5)*moduleHeight)
+			g.modules[y][x] = image.Pt(px, py).In(b) && dark(px, py)
+		}
+	}
+
+	return g, true
+}
@@ -0,0 +1,101 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDiffImages(t *testing.T) {
+	golden, err := New("https://example.org", Width(-4), Height(-4), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	same, err := New("https://example.org", Width(-7), Height(-7), Margin(2),
+		ForegroundColor(color.RGBA{0x10, 0x20, 0x60, 0xff}), BackgroundColor(color.RGBA{0xff, 0xf0, 0xe0, 0xff}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	changed, diff := DiffImages(golden.Image(), same.Image())
+	if changed != 0 {
+		t.Errorf("recolored and resized image has %d changed modules, expected 0", changed)
+	}
+	if size := len(golden.Bitmap()) - 8; diff.Bounds() != image.Rect(0, 0, size*4, size*4) {
+		t.Errorf("diff image is %v, expected %dx%d", diff.Bounds(), size*4, size*4)
+	}
+
+	// Flip a single data module.
+	bitmap := golden.Bitmap()
+	img := golden.Image().(*image.Paletted)
+	flipped := image.NewPaletted(img.Bounds(), img.Palette)
+	copy(flipped.Pix, img.Pix)
+	mx, my := 4+10, 4+10
+	for y := my * 4; y < my*4+4; y++ {
+		for x := mx * 4; x < mx*4+4; x++ {
+			if bitmap[my][mx] {
+				flipped.SetColorIndex(x, y, 0)
+			} else {
+				flipped.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	changed, diff = DiffImages(golden.Image(), flipped)
+	if changed != 1 {
+		t.Errorf("got %d changed modules, expected 1", changed)
+	}
+	if got := color.RGBAModel.Convert(diff.At(10*4+1, 10*4+1)); got != diffChanged {
+		t.Errorf("changed module is %v in diff, expected %v", got, diffChanged)
+	}
+
+	other, err := New("https://example.org/other", Width(-4), Height(-4), MinVersion(golden.VersionNumber))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if changed, _ := DiffImages(golden.Image(), other.Image()); changed == 0 {
+		t.Error("different content has no changed modules")
+	}
+
+	// Non-square modules, and a quiet zone wider on the left than at the top.
+	wide, err := New("https://example.org", Width(-6), Height(-3), Margin(1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if changed, _ := DiffImages(golden.Image(), wide.Image()); changed != 0 {
+		t.Errorf("6x3px modules: got %d changed modules, expected 0", changed)
+	}
+
+	shifted := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx()+40, img.Bounds().Dy()))
+	for y := 0; y < shifted.Bounds().Dy(); y++ {
+		for x := 0; x < shifted.Bounds().Dx(); x++ {
+			shifted.Set(x, y, color.White)
+			if x >= 40 {
+				shifted.Set(x, y, img.At(x-40, y))
+			}
+		}
+	}
+	if changed, _ := DiffImages(golden.Image(), shifted); changed != 0 {
+		t.Errorf("shifted image: got %d changed modules, expected 0", changed)
+	}
+
+	deep, err := New("https://example.org", Width(-4), Height(-4), QuietZone(8, 4, 4, 4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if changed, _ := DiffImages(deep.Image(), deep.Image()); changed != 0 {
+		t.Errorf("QuietZone(8, 4, 4, 4) image compared with itself: got %d changed modules, expected 0", changed)
+	}
+	if changed, _ := DiffImages(golden.Image(), deep.Image()); changed != 0 {
+		t.Errorf("QuietZone(8, 4, 4, 4): got %d changed modules, expected 0", changed)
+	}
+
+	blank := image.NewRGBA(image.Rect(0, 0, 50, 50))
+	if changed, diff := DiffImages(golden.Image(), blank); changed != -1 || diff != nil {
+		t.Errorf("blank image got %d, %v, expected -1, nil", changed, diff)
+	}
+}
@@ -0,0 +1,78 @@
+package qrcode
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// compressedMagic starts a CompressPayload payload. 0xFF never starts UTF-8
+// text, so text content can't be mistaken for it.
+const compressedMagic = "\xffQZ"
+
+// maxDecompressedSize limits the payloads DecompressPayload returns, against
+// decompression bombs.
+const maxDecompressedSize = 1 << 20
+
+// CompressPayload deflates the content (RFC 1951) and prefixes it with a 3
+// byte magic header, 0xFF 'Q' 'Z', before encoding it in byte mode, so larger
+// payloads such as JSON documents fit. The content is kept as it is if that
+// is no shorter. Content then holds the encoded bytes.
+//
+// Scanners return the compressed bytes: readers must use DecompressPayload,
+// so only use it with codes read by your own applications.
+func CompressPayload() Option {
+	return func(q *QRCode) {
+		q.compressPayload = true
+	}
+}
+
+// compressContent returns content deflated with the magic header, or content
+// unchanged if that is shorter.
+func compressContent(content string) (string, error) {
+	var b bytes.Buffer
+	b.WriteString(compressedMagic)
+
+	w, err := flate.NewWriter(&b, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, content); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	// Content which starts with the magic header must be compressed, so
+	// DecompressPayload doesn't misread it.
+	if b.Len() >= len(content) && !strings.HasPrefix(content, compressedMagic) {
+		return content, nil
+	}
+
+	return b.String(), nil
+}
+
+// DecompressPayload returns the content of a code made with CompressPayload,
+// e.g. the bytes read by a scanner. Data without the magic header is returned
+// unchanged.
+func DecompressPayload(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(compressedMagic)) {
+		return data, nil
+	}
+
+	r := flate.NewReader(bytes.NewReader(data[len(compressedMagic):]))
+	defer r.Close()
+
+	content, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed payload: %s", err.Error())
+	} else if len(content) > maxDecompressedSize {
+		return nil, errors.New("compressed payload too large")
+	}
+
+	return content, nil
+}
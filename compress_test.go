@@ -0,0 +1,77 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompressPayload(t *testing.T) {
+	content := `{"items":[` + strings.Repeat(`{"sku":"A-1001","qty":1,"price":"9.99"},`, 40) + `{}]}`
+
+	plain, err := New(content, Level(Low))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	q, err := New(content, Level(Low), CompressPayload())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if q.VersionNumber >= plain.VersionNumber {
+		t.Errorf("got version %d, expected smaller than %d", q.VersionNumber, plain.VersionNumber)
+	}
+
+	info, err := VerifyBitmap(q.Bitmap())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !bytes.HasPrefix(info.Content, []byte("\xffQZ")) {
+		t.Errorf("decoded content %q has no magic header", info.Content[:8])
+	}
+
+	got, err := DecompressPayload(info.Content)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(got) != content {
+		t.Errorf("got %q, expected %q", got, content)
+	}
+}
+
+func TestCompressPayloadShort(t *testing.T) {
+	// Content that doesn't compress is kept as it is.
+	q, err := New("hello", CompressPayload())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if q.Content != "hello" {
+		t.Errorf("got content %q, expected hello", q.Content)
+	}
+	if got, err := DecompressPayload([]byte(q.Content)); err != nil || string(got) != "hello" {
+		t.Errorf("DecompressPayload = %q, %v, expected hello", got, err)
+	}
+
+	// Unless it starts with the magic header.
+	q, err = New("\xffQZ", CompressPayload())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got, err := DecompressPayload([]byte(q.Content)); err != nil || string(got) != "\xffQZ" {
+		t.Errorf("DecompressPayload = %q, %v, expected the magic header", got, err)
+	}
+}
+
+func TestDecompressPayloadInvalid(t *testing.T) {
+	if _, err := DecompressPayload([]byte("\xffQZ\x07not deflate")); err == nil {
+		t.Error("DecompressPayload succeeded, expected error")
+	}
+
+	bomb, err := compressContent(strings.Repeat("a", maxDecompressedSize+1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := DecompressPayload([]byte(bomb)); err == nil {
+		t.Error("DecompressPayload succeeded, expected error for an oversized payload")
+	}
+}
@@ -0,0 +1,73 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+)
+
+// checkConflicts returns the options of q which contradict each other, joined
+// as one error under Strict. Otherwise they are reported to the Logger, and
+// drawn as well as they can be.
+func (q *QRCode) checkConflicts() error {
+	conflicts := q.conflicts()
+	if !q.strict {
+		for _, err := range conflicts {
+			q.debug("option conflict", "error", err)
+		}
+		return nil
+	}
+
+	return errors.Join(conflicts...)
+}
+
+// conflicts returns an error for each pair of options of q which contradict
+// each other.
+func (q *QRCode) conflicts() []error {
+	var errs []error
+	conflict := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Errorf("conflicting options: "+format, a...))
+	}
+
+	fg := color.NRGBAModel.Convert(q.ForegroundColor).(color.NRGBA)
+	bg := color.NRGBAModel.Convert(q.BackgroundColor).(color.NRGBA)
+	if fg.A == 0 {
+		conflict("a transparent ForegroundColor hides the dark modules")
+	} else if fg == bg {
+		conflict("ForegroundColor and BackgroundColor are the same color")
+	}
+
+	if q.noQuietZone {
+		for _, o := range []struct {
+			name string
+			set  bool
+		}{
+			{"QuietZoneColor", q.quietZoneColor != nil},
+			{"QuietZoneRadius", q.quietZoneRadius > 0},
+			{"QuietZoneOutline", q.outlineWidth > 0 && q.outlineColor != nil},
+			{"Stamp", q.stamp != ""},
+			{"OrientationMarker", q.orientationMarker != NoEdge},
+		} {
+			if o.set {
+				conflict("NoQuietZone leaves no quiet zone for %s", o.name)
+			}
+		}
+	}
+
+	if p := q.customPalette; p != nil {
+		if q.quietZoneRadius > 0 && !hasColor(p, color.Transparent) {
+			conflict("QuietZoneRadius needs color.Transparent in the Palette")
+		}
+		if q.quietZoneColor != nil && !hasColor(p, q.quietZoneColor) {
+			conflict("QuietZoneColor is not in the Palette")
+		}
+		if q.outlineWidth > 0 && q.outlineColor != nil && !hasColor(p, q.outlineColor) {
+			conflict("QuietZoneOutline color is not in the Palette")
+		}
+		if q.transform != nil && len(p) == 256 {
+			conflict("Transform needs a Palette entry free for transparency")
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,94 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestOptionOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		check func(q *QRCode) bool
+	}{
+		{"Scale after Width", []Option{Width(256), Scale(4)}, func(q *QRCode) bool { return q.scale == 4 }},
+		{"Width after Scale", []Option{Scale(4), Width(256)}, func(q *QRCode) bool { return q.scale == 0 && q.width == 256 }},
+		{"Downscale after Scale", []Option{Scale(4), Downscale(0.5)}, func(q *QRCode) bool { return q.scale == 0 && q.downscale }},
+		{"Scale after Downscale", []Option{Downscale(0.5), Scale(4)}, func(q *QRCode) bool { return q.scale == 4 && !q.downscale }},
+		{"Caption after CaptionContent", []Option{CaptionContent(), Caption("hi")}, func(q *QRCode) bool { return q.captionText() == "hi" }},
+		{"CaptionContent after Caption", []Option{Caption("hi"), CaptionContent()}, func(q *QRCode) bool { return q.captionText() == q.Content }},
+		{"Margin after NoQuietZone", []Option{NoQuietZone(), Margin(4)}, func(q *QRCode) bool { return len(q.Bitmap()) == 29 }},
+		{"NoQuietZone after Margin", []Option{Margin(4), NoQuietZone()}, func(q *QRCode) bool { return len(q.Bitmap()) == 21 }},
+		{"ExactVersion", []Option{ExactVersion(5)}, func(q *QRCode) bool { return q.VersionNumber == 5 }},
+		{"MinVersion after ExactVersion", []Option{ExactVersion(5), MinVersion(3)}, func(q *QRCode) bool { return q.VersionNumber == 3 }},
+		{"Version", []Option{Version(5)}, func(q *QRCode) bool { return q.VersionNumber == 1 }},
+		{"QuitZoneSize", []Option{Margin(1), QuitZoneSize(3)}, func(q *QRCode) bool { return q.margin == 3 }},
+	}
+
+	for _, test := range tests {
+		q, err := New("hello", test.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if !test.check(q) {
+			t.Errorf("%s: the later option did not win", test.name)
+		}
+	}
+
+	// Defaults come first.
+	SetDefaults(Scale(4))
+	defer SetDefaults()
+	q, err := New("hello", Width(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.scale != 0 {
+		t.Error("Width did not replace the default Scale")
+	}
+}
+
+func TestVersionTooSmall(t *testing.T) {
+	if _, err := New(strings.Repeat("x", 100), ExactVersion(1)); err == nil {
+		t.Error("100 bytes in version 1 succeeded, expected an error")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"transparent foreground", []Option{ForegroundColor(color.Transparent)}},
+		{"same colors", []Option{ForegroundColor(color.Black), BackgroundColor(color.Gray{0})}},
+		{"no quiet zone color", []Option{NoQuietZone(), QuietZoneColor(color.White)}},
+		{"no quiet zone stamp", []Option{Margin(4), NoQuietZone(), Stamp("1/2")}},
+		{"palette radius", []Option{Palette(color.Palette{color.White, color.Black}), QuietZoneRadius(4)}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewStrict("hello", test.opts...); err == nil || !strings.Contains(err.Error(), "conflicting options") {
+			t.Errorf("%s: NewStrict returned %v, expected a conflict", test.name, err)
+		}
+
+		// Without Strict the conflict is only logged.
+		l := &recordingLogger{}
+		if _, err := New("hello", append(test.opts, Logging(l))...); err != nil {
+			t.Errorf("%s: New returned %v, expected no error", test.name, err)
+		}
+		logged := false
+		for _, msg := range l.messages {
+			logged = logged || msg == "option conflict"
+		}
+		if !logged {
+			t.Errorf("%s: conflict not logged", test.name)
+		}
+	}
+
+	if _, err := NewStrict("hello", Margin(4), QuietZoneColor(color.White)); err != nil {
+		t.Errorf("NewStrict without conflicts returned %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Package conformance checks QR Codes against reference data published in
+// ISO/IEC 18004 and widely used encoder tutorials, so encoder regressions are
+// caught by tests rather than by manual phone scans.
+//
+// The reference data is:
+//
+//   - the final codeword sequences of worked examples (Vectors), including
+//     ISO/IEC 18004 Annex I.
+//   - the module matrix of the ISO/IEC 18004 Annex I symbol.
+//   - the 32 Format Information bit sequences (ISO/IEC 18004 Annex C).
+//   - the 34 Version Information bit sequences (ISO/IEC 18004 Annex D).
+//
+// Module placement and masking are checked against the Annex I matrix, and
+// for every other symbol by qrcode.VerifyBitmap, which also decodes it.
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/yougg/go-qrcode"
+)
+
+// Vector is a reference QR Code: content encoded at a version and level, and
+// the resulting interleaved data and error correction codewords.
+type Vector struct {
+	// Source of the reference data.
+	Source string
+
+	Content string
+	Version int
+	Level   qrcode.RecoveryLevel
+
+	// Mask pattern of the reference symbol, or -1 if unspecified.
+	Mask int
+
+	Codewords []byte
+
+	// Matrix is the reference symbol without its quiet zone, top row first,
+	// with '#' for dark modules, or nil if unspecified.
+	Matrix []string
+}
+
+// Vectors are the reference QR Codes known to Check.
+var Vectors = []Vector{
+	{
+		Source:  "ISO/IEC 18004:2015 Annex I",
+		Content: "01234567",
+		Version: 1,
+		Level:   qrcode.Medium,
+		Mask:    2,
+		Codewords: []byte{
+			0x10, 0x20, 0x0c, 0x56, 0x61, 0x80, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11,
+			0xa5, 0x24, 0xd4, 0xc1, 0xed, 0x36, 0xc7, 0x87, 0x2c, 0x55,
+		},
+		Matrix: []string{
+			"#######..#.##.#######",
+			"#.....#..####.#.....#",
+			"#.###.#.#.....#.###.#",
+			"#.###.#.##....#.###.#",
+			"#.###.#.#.###.#.###.#",
+			"#.....#.#...#.#.....#",
+			"#######.#.#.#.#######",
+			"........#..##........",
+			"#.#####..#..#.#####..",
+			"...#.#.##.#.#..#.##..",
+			"..#...##.#.#.#..#####",
+			"....#....#.....####..",
+			"...######..#.#..#....",
+			"........#.#####..##..",
+			"#######..##.#.##.....",
+			"#.....#.#.#####...#.#",
+			"#.###.#.#...#..#.##..",
+			"#.###.#.##..#..#.....",
+			"#.###.#.#.##.#..#.#..",
+			"#.....#........##.##.",
+			"#######.####.#..#.#..",
+		},
+	},
+	{
+		Source:  "Thonky QR Code Tutorial",
+		Content: "HELLO WORLD",
+		Version: 1,
+		Level:   qrcode.Medium,
+		Mask:    -1,
+		Codewords: []byte{
+			0x20, 0x5b, 0x0b, 0x78, 0xd1, 0x72, 0xdc, 0x4d, 0x43, 0x40, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11,
+			0xc4, 0x23, 0x27, 0x77, 0xeb, 0xd7, 0xe7, 0xe2, 0x5d, 0x17,
+		},
+	},
+}
+
+// formatInfo is the Format Information of each level (L, M, Q, H) and mask
+// pattern, after masking with 0x5412.
+var formatInfo = [4][8]uint32{
+	{0x77c4, 0x72f3, 0x7daa, 0x789d, 0x662f, 0x6318, 0x6c41, 0x6976},
+	{0x5412, 0x5125, 0x5e7c, 0x5b4b, 0x45f9, 0x40ce, 0x4f97, 0x4aa0},
+	{0x355f, 0x3068, 0x3f31, 0x3a06, 0x24b4, 0x2183, 0x2eda, 0x2bed},
+	{0x1689, 0x13be, 0x1ce7, 0x19d0, 0x0762, 0x0255, 0x0d0c, 0x083b},
+}
+
+// versionInfo is the Version Information of versions 7-40.
+var versionInfo = [...]uint32{
+	0x07c94, 0x085bc, 0x09a99, 0x0a4d3, 0x0bbf6, 0x0c762, 0x0d847, 0x0e60d,
+	0x0f928, 0x10b78, 0x1145d, 0x12a17, 0x13532, 0x149a6, 0x15683, 0x168c9,
+	0x177ec, 0x18ec4, 0x191e1, 0x1afab, 0x1b08e, 0x1cc1a, 0x1d33f, 0x1ed75,
+	0x1f250, 0x209d5, 0x216f0, 0x228ba, 0x2379f, 0x24b0b, 0x2542e, 0x26a64,
+	0x27541, 0x28c69,
+}
+
+// Check returns an error describing every way q deviates from the reference
+// data, or nil if it conforms:
+//
+//   - the symbol must pass qrcode.VerifyBitmap, and decode to q's content,
+//     version, level and mask pattern.
+//   - the Format and Version Information must match the ISO/IEC 18004 tables.
+//   - if a Vector has the same content, version and level, the codewords (and
+//     mask pattern, if specified) must match it.
+func Check(q *qrcode.QRCode) error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	level, mask := q.RecoveryLevel(), q.MaskPattern()
+
+	info, err := qrcode.VerifyBitmap(q.Bitmap())
+	if err != nil {
+		fail("symbol does not verify: %v", err)
+	} else {
+		if string(info.Content) != q.EncodedContent() {
+			fail("symbol decodes to %q, expected %q", info.Content, q.EncodedContent())
+		}
+		if info.Version != q.Version() || info.Level != level || info.MaskPattern != mask {
+			fail("symbol is %d-%s mask %d, expected %d-%s mask %d",
+				info.Version, info.Level, info.MaskPattern, q.Version(), level, mask)
+		}
+	}
+
+	if level >= qrcode.Low && level <= qrcode.Highest && mask >= 0 && mask < 8 {
+		if got, want := q.FormatInfo(), formatInfo[level][mask]; got != want {
+			fail("format information is %015b, expected %015b", got, want)
+		}
+	}
+
+	got, ok := q.VersionInfo()
+	switch {
+	case q.Version() < 7:
+		if ok {
+			fail("version %d has version information", q.Version())
+		}
+	case q.Version() <= 40:
+		if want := versionInfo[q.Version()-7]; !ok || got != want {
+			fail("version information is %018b, expected %018b", got, want)
+		}
+	}
+
+	for _, v := range Vectors {
+		if v.Content != q.EncodedContent() || v.Version != q.Version() || v.Level != level {
+			continue
+		}
+
+		if codewords := q.Codewords(); !bytes.Equal(codewords, v.Codewords) {
+			fail("codewords % x do not match %s: % x", codewords, v.Source, v.Codewords)
+		}
+		if v.Mask >= 0 && v.Mask != mask {
+			fail("mask pattern %d does not match %s: %d", mask, v.Source, v.Mask)
+		}
+		if v.Matrix != nil && v.Mask == mask && info.Content != nil {
+			if x, y, ok := matrixMismatch(q.Bitmap(), v.Matrix); !ok {
+				fail("module (%d, %d) does not match %s", x, y, v.Source)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matrixMismatch compares the symbol in bitmap with matrix. The symbol's top
+// left corner is the first row and column with a dark module, as the edges
+// of the quiet zone may differ. It returns the first module differing, or ok
+// if there is none.
+func matrixMismatch(bitmap [][]bool, matrix []string) (x, y int, ok bool) {
+	top, left := -1, -1
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark && top < 0 {
+				top = y
+			}
+			if dark && (left < 0 || x < left) {
+				left = x
+			}
+		}
+	}
+	if top < 0 || len(bitmap) < top+len(matrix) {
+		return 0, 0, false
+	}
+
+	for y, row := range matrix {
+		if len(bitmap[top+y]) < left+len(row) {
+			return 0, y, false
+		}
+		for x := 0; x < len(row); x++ {
+			if bitmap[top+y][left+x] != (row[x] == '#') {
+				return x, y, false
+			}
+		}
+	}
+
+	return 0, 0, true
+}
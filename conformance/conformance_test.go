@@ -0,0 +1,99 @@
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yougg/go-qrcode"
+)
+
+func TestVectors(t *testing.T) {
+	for _, v := range Vectors {
+		q, err := qrcode.New(v.Content, qrcode.Level(v.Level), qrcode.MinVersion(v.Version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Version() != v.Version {
+			t.Fatalf("%s: encoded as version %d, expected %d", v.Source, q.Version(), v.Version)
+		}
+
+		if err := Check(q); err != nil {
+			t.Errorf("%s: %v", v.Source, err)
+		}
+	}
+}
+
+func TestCheckVersionsAndLevels(t *testing.T) {
+	for version := 1; version <= 40; version++ {
+		for _, level := range []qrcode.RecoveryLevel{qrcode.Low, qrcode.Medium, qrcode.High, qrcode.Highest} {
+			q, err := qrcode.New("https://example.org", qrcode.Level(level), qrcode.MinVersion(version))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Check(q); err != nil {
+				t.Errorf("%d-%s: %v", version, level, err)
+			}
+		}
+	}
+}
+
+func TestCheckQuietZone(t *testing.T) {
+	q, err := qrcode.New("01234567", qrcode.Level(qrcode.Medium), qrcode.QuietZone(8, 4, 4, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Check(q); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckDeviation(t *testing.T) {
+	q, err := qrcode.New("01234567", qrcode.Level(qrcode.Medium), qrcode.OverrideFormatInfo(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Check(q)
+	if err == nil || !strings.Contains(err.Error(), "does not verify") {
+		t.Errorf("got %v, expected a verification error", err)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	for _, v := range Vectors {
+		if v.Matrix == nil {
+			continue
+		}
+
+		// The reference matrix itself must decode to the vector.
+		const quietZoneSize = 4
+		size := len(v.Matrix) + 2*quietZoneSize
+		bitmap := make([][]bool, size)
+		for y := range bitmap {
+			bitmap[y] = make([]bool, size)
+		}
+		for y, row := range v.Matrix {
+			for x := 0; x < len(row); x++ {
+				bitmap[y+quietZoneSize][x+quietZoneSize] = row[x] == '#'
+			}
+		}
+
+		info, err := qrcode.VerifyBitmap(bitmap)
+		if err != nil {
+			t.Fatalf("%s: reference matrix does not verify: %v", v.Source, err)
+		}
+		if string(info.Content) != v.Content || info.Version != v.Version || info.Level != v.Level || info.MaskPattern != v.Mask {
+			t.Errorf("%s: reference matrix is %q %d-%s mask %d", v.Source, info.Content, info.Version, info.Level, info.MaskPattern)
+		}
+
+		if _, _, ok := matrixMismatch(bitmap, v.Matrix); !ok {
+			t.Errorf("%s: reference matrix does not match itself", v.Source)
+		}
+		bitmap[quietZoneSize+10][quietZoneSize+12] = !bitmap[quietZoneSize+10][quietZoneSize+12]
+		if x, y, ok := matrixMismatch(bitmap, v.Matrix); ok || x != 12 || y != 10 {
+			t.Errorf("%s: flipped module (12, 10) got (%d, %d) %t", v.Source, x, y, ok)
+		}
+	}
+}
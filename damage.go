@@ -0,0 +1,207 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+)
+
+// DamageOptions configures the damage applied by Damage. The zero value
+// applies no damage.
+type DamageOptions struct {
+	// Fraction, 0-1, of the symbol's area covered by blotches (stains or
+	// stickers). Each blotch is solid foreground or background color.
+	Occlusion float64
+
+	// Number of blotches the Occlusion is split between. Defaults to 1.
+	Blotches int
+
+	// Number of light scratches drawn across the symbol.
+	Scratches int
+
+	// Rotation in degrees, clockwise around the image center.
+	Rotation float64
+
+	// Perspective (keystone) warp, 0-1: the top edge is narrowed by this
+	// fraction, as if the code were photographed from below.
+	Perspective float64
+
+	// Box blur radius in pixels.
+	Blur int
+
+	// Seed for the random placement of blotches and scratches, so fixtures
+	// are reproducible.
+	Seed int64
+}
+
+// Damage returns the QR Code as an image, as drawn by Image(), with
+// simulated damage applied. It produces test fixtures for checking a scanner
+// copes with the damage each RecoveryLevel is designed to recover from.
+func (q *QRCode) Damage(opts DamageOptions) image.Image {
+	src := q.Image()
+	b := src.Bounds()
+
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, src, b.Min, draw.Src)
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	symbolRect := q.symbolRect(b)
+	pixelsPerModule := max(1, symbolRect.Dx()/q.symbol.symbolSize)
+
+	if opts.Occlusion > 0 {
+		blotches := max(1, opts.Blotches)
+		area := opts.Occlusion * float64(symbolRect.Dx()*symbolRect.Dy()) / float64(blotches)
+		radius := math.Sqrt(area / math.Pi)
+
+		for i := 0; i < blotches; i++ {
+			c := q.ForegroundColor
+			if rng.Intn(2) == 0 {
+				c = q.BackgroundColor
+			}
+
+			center := randomPoint(rng, symbolRect)
+			fillCircle(img, center, radius, c)
+		}
+	}
+
+	for i := 0; i < opts.Scratches; i++ {
+		from := randomPoint(rng, symbolRect)
+		to := randomPoint(rng, symbolRect)
+		drawLine(img, from, to, max(1, pixelsPerModule/3), q.BackgroundColor)
+	}
+
+	if opts.Rotation != 0 || opts.Perspective != 0 {
+		img = warp(img, opts.Rotation, opts.Perspective, q.BackgroundColor)
+	}
+
+	if opts.Blur > 0 {
+		img = boxBlur(img, opts.Blur)
+	}
+
+	return img
+}
+
+// randomPoint returns a random point within r.
+func randomPoint(rng *rand.Rand, r image.Rectangle) image.Point {
+	return image.Pt(r.Min.X+rng.Intn(max(1, r.Dx())), r.Min.Y+rng.Intn(max(1, r.Dy())))
+}
+
+// fillCircle fills the pixels whose centers are within radius of center.
+func fillCircle(img *image.RGBA, center image.Point, radius float64, c color.Color) {
+	r := int(math.Ceil(radius))
+	for y := center.Y - r; y <= center.Y+r; y++ {
+		for x := center.X - r; x <= center.X+r; x++ {
+			dx, dy := float64(x-center.X), float64(y-center.Y)
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// drawLine draws a line width pixels wide from a to b.
+func drawLine(img *image.RGBA, a, b image.Point, width int, c color.Color) {
+	steps := max(abs(b.X-a.X), abs(b.Y-a.Y))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+
+		x := a.X + int(math.Round(t*float64(b.X-a.X)))
+		y := a.Y + int(math.Round(t*float64(b.Y-a.Y)))
+		draw.Draw(img, image.Rect(x-width/2, y-width/2, x-width/2+width, y-width/2+width),
+			image.NewUniform(c), image.Point{}, draw.Src)
+	}
+}
+
+// warp returns img rotated clockwise by degrees and keystoned by
+// perspective, see DamageOptions. Areas with no source pixel are filled with
+// background.
+func warp(img *image.RGBA, degrees, perspective float64, background color.Color) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+
+	cx := float64(b.Min.X) + float64(b.Dx())/2
+	cy := float64(b.Min.Y) + float64(b.Dy())/2
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// Map each destination pixel center back to the source: undo the
+			// rotation, then the keystone.
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := dx*cos + dy*sin
+			sy := -dx*sin + dy*cos
+
+			width := 1 - perspective*(0.5-sy/float64(b.Dy()))
+			if width <= 0 {
+				out.Set(x, y, background)
+				continue
+			}
+			sx /= width
+
+			p := image.Pt(int(math.Floor(sx+cx)), int(math.Floor(sy+cy)))
+			if p.In(b) {
+				out.SetRGBA(x, y, img.RGBAAt(p.X, p.Y))
+			} else {
+				out.Set(x, y, background)
+			}
+		}
+	}
+
+	return out
+}
+
+// boxBlur returns img blurred by averaging each pixel with its neighbours up
+// to radius pixels away, horizontally then vertically.
+func boxBlur(img *image.RGBA, radius int) *image.RGBA {
+	b := img.Bounds()
+
+	pass := func(src *image.RGBA, horizontal bool) *image.RGBA {
+		dst := image.NewRGBA(b)
+
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				var sum [4]int
+				n := 0
+
+				for i := -radius; i <= radius; i++ {
+					p := image.Pt(x, y+i)
+					if horizontal {
+						p = image.Pt(x+i, y)
+					}
+					if !p.In(b) {
+						continue
+					}
+
+					c := src.RGBAAt(p.X, p.Y)
+					sum[0] += int(c.R)
+					sum[1] += int(c.G)
+					sum[2] += int(c.B)
+					sum[3] += int(c.A)
+					n++
+				}
+
+				dst.SetRGBA(x, y, color.RGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), uint8(sum[3] / n)})
+			}
+		}
+
+		return dst
+	}
+
+	return pass(pass(img, true), false)
+}
+
+// abs returns the absolute value of a.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,131 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDamageNone(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if n := countDifferentPixels(q.Image(), q.Damage(DamageOptions{})); n != 0 {
+		t.Errorf("zero DamageOptions changed %d pixels", n)
+	}
+}
+
+func TestDamageOcclusion(t *testing.T) {
+	q, err := New("https://example.org", Width(200), Height(200), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	opts := DamageOptions{Occlusion: 0.1, Blotches: 3, Scratches: 2, Seed: 7}
+
+	a := q.Damage(opts)
+	if n := countDifferentPixels(a, q.Damage(opts)); n != 0 {
+		t.Errorf("same Seed gave %d different pixels, expected identical images", n)
+	}
+
+	n := countDifferentPixels(q.Image(), a)
+	if n == 0 {
+		t.Fatal("no pixels damaged")
+	}
+
+	// Blotches are solid, so at most the occluded area changes, plus the
+	// scratches.
+	symbolRect := q.symbolRect(a.Bounds())
+	if limit := symbolRect.Dx() * symbolRect.Dy() / 4; n > limit {
+		t.Errorf("%d pixels damaged, expected at most %d", n, limit)
+	}
+
+	opts.Seed = 8
+	if countDifferentPixels(a, q.Damage(opts)) == 0 {
+		t.Error("different Seed gave identical damage")
+	}
+}
+
+func TestDamageRotation(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100), Margin(2))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	src := q.Image()
+	rotated := q.Damage(DamageOptions{Rotation: 180})
+
+	b := src.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			got := color.RGBAModel.Convert(rotated.At(x, y))
+			expected := color.RGBAModel.Convert(src.At(b.Max.X-1-x, b.Max.Y-1-y))
+			if got != expected {
+				t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, got, expected)
+			}
+		}
+	}
+}
+
+func TestDamagePerspective(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img := q.Damage(DamageOptions{Perspective: 0.5})
+
+	// Without a quiet zone the top left module is dark; the narrowed top edge
+	// moves it inwards, leaving background in the corner.
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != color.RGBAModel.Convert(color.White) {
+		t.Errorf("top left pixel is %v, expected background", got)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 99)); got != color.RGBAModel.Convert(color.Black) {
+		t.Errorf("bottom left pixel is %v, expected the unchanged finder pattern", got)
+	}
+}
+
+func TestDamageBlur(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img := q.Damage(DamageOptions{Blur: 2})
+
+	grey := false
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y && !grey; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if c.R != 0 && c.R != 0xff {
+				grey = true
+				break
+			}
+		}
+	}
+
+	if !grey {
+		t.Error("blurred image has no intermediate colors")
+	}
+}
+
+// countDifferentPixels returns the number of pixels which differ between a
+// and b, which must have the same bounds.
+func countDifferentPixels(a, b image.Image) int {
+	n := 0
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if color.RGBAModel.Convert(a.At(x, y)) != color.RGBAModel.Convert(b.At(x, y)) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+)
+
+// darkModeDim is how far the light color of the dark theme image is blended
+// towards the dark color, to avoid glare on a dark UI.
+const darkModeDim = 0.2
+
+// RenderPair returns matched images of the QR Code for light and dark themes.
+//
+// The light image is the same as Image(). The dark image inverts the theme
+// rather than the symbol: many scanners cannot read light modules on a dark
+// background, so the modules stay dark and the quiet zone stays lighter than
+// the modules. Instead the light color is dimmed to reduce glare, and the
+// corners are rounded (by one module, unless QuietZoneRadius is set) so the
+// code sits on a dark surface as a card, with transparent corners.
+func (q *QRCode) RenderPair() (light, dark image.Image) {
+	light = q.Image()
+
+	d := *q
+	fg, bg := q.ForegroundColor, q.BackgroundColor
+	if luminance(fg) > luminance(bg) {
+		fg, bg = bg, fg
+	}
+
+	d.ForegroundColor = fg
+	d.BackgroundColor = blend(bg, fg, darkModeDim)
+	if q.quietZoneColor != nil {
+		d.quietZoneColor = d.BackgroundColor
+	}
+	if d.quietZoneRadius <= 0 {
+		d.quietZoneRadius = light.Bounds().Dx() / q.symbol.size
+	}
+
+	dark = d.Image()
+
+	return light, dark
+}
+
+// luminance returns the relative luminance of c, in the range 0-1.
+func luminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+
+	return (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / 0xffff
+}
+
+// blend returns a mixed with the fraction t of b.
+func blend(a, b color.Color, t float64) color.Color {
+	ca := color.NRGBA64Model.Convert(a).(color.NRGBA64)
+	cb := color.NRGBA64Model.Convert(b).(color.NRGBA64)
+
+	mix := func(x, y uint16) uint16 {
+		return uint16(float64(x)*(1-t) + float64(y)*t)
+	}
+
+	return color.NRGBA64{
+		R: mix(ca.R, cb.R),
+		G: mix(ca.G, cb.G),
+		B: mix(ca.B, cb.B),
+		A: mix(ca.A, cb.A),
+	}
+}
@@ -0,0 +1,53 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRenderPair(t *testing.T) {
+	q, err := New("https://example.org", Width(-4), Height(-4), Margin(4),
+		ForegroundColor(color.White), BackgroundColor(color.Black))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	light, dark := q.RenderPair()
+
+	if light.Bounds() != dark.Bounds() {
+		t.Fatalf("light image bounds %v, dark image bounds %v, expected equal", light.Bounds(), dark.Bounds())
+	}
+
+	// The dark image has transparent rounded corners.
+	if _, _, _, a := dark.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("dark image corner has alpha %d, expected 0", a)
+	}
+
+	// The quiet zone is lighter than the modules, even though the QR Code was
+	// configured with light modules, and is dimmed from white.
+	quietZone := dark.At(dark.Bounds().Dx()/2, 4)
+	module := dark.At(16, 16)
+
+	if luminance(quietZone) <= luminance(module) {
+		t.Errorf("quiet zone %v is darker than module %v", quietZone, module)
+	}
+	if luminance(quietZone) >= 1 {
+		t.Errorf("quiet zone %v is not dimmed", quietZone)
+	}
+
+	// The QR Code's own colors are untouched.
+	if q.ForegroundColor != color.White || q.BackgroundColor != color.Black {
+		t.Error("RenderPair changed the QR Code's colors")
+	}
+}
+
+func TestBlend(t *testing.T) {
+	got := color.RGBAModel.Convert(blend(color.White, color.Black, 0.5)).(color.RGBA)
+
+	if got.R < 0x7e || got.R > 0x80 || got.R != got.G || got.G != got.B || got.A != 0xff {
+		t.Errorf("blend(white, black, 0.5) = %v, expected mid gray", got)
+	}
+}
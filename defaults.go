@@ -0,0 +1,37 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import "sync"
+
+// defaults holds the options applied by SetDefaults.
+var defaults struct {
+	sync.RWMutex
+	opts []Option
+}
+
+// SetDefaults replaces the options applied to every QR Code created by New and
+// NewSegments, e.g. an organization-wide recovery level and colors. Options
+// passed to New are applied after the defaults, and so take precedence.
+//
+// Calling SetDefaults with no options clears the defaults. It is safe to call
+// concurrently with New.
+func SetDefaults(opts ...Option) {
+	defaults.Lock()
+	defer defaults.Unlock()
+
+	defaults.opts = append([]Option(nil), opts...)
+}
+
+// withDefaults returns the default options followed by opts.
+func withDefaults(opts []Option) []Option {
+	defaults.RLock()
+	defer defaults.RUnlock()
+
+	if len(defaults.opts) == 0 {
+		return opts
+	}
+
+	return append(append([]Option(nil), defaults.opts...), opts...)
+}
@@ -0,0 +1,69 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"image/color"
+	"sync"
+	"testing"
+)
+
+func TestSetDefaults(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	SetDefaults(Level(Highest), ForegroundColor(red))
+	defer SetDefaults()
+
+	q, err := New("defaults")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.level != Highest || q.ForegroundColor != red {
+		t.Errorf("got level %s, foreground %v, expected defaults applied", q.level, q.ForegroundColor)
+	}
+
+	q, err = New("defaults", Level(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.level != Low || q.ForegroundColor != red {
+		t.Errorf("got level %s, foreground %v, expected Level option to override default", q.level, q.ForegroundColor)
+	}
+
+	q, err = NewSegments([]Segment{{Mode: Numeric, Data: "123"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.level != Highest {
+		t.Errorf("NewSegments got level %s, expected %s", q.level, Highest)
+	}
+
+	SetDefaults()
+
+	q, err = New("defaults")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.level != Low || q.ForegroundColor != color.Black {
+		t.Errorf("got level %s, foreground %v after clearing defaults", q.level, q.ForegroundColor)
+	}
+}
+
+func TestSetDefaultsConcurrent(t *testing.T) {
+	defer SetDefaults()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			SetDefaults(Level(RecoveryLevel(i % 4)))
+			if _, err := New("concurrent"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,155 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Downscale renders images smaller than the symbol, which Image() would
+// otherwise silently enlarge to one pixel per module, as thumbnails.
+//
+// Each pixel covers a fraction of the modules. With threshold > 0 the pixel
+// is the foreground color if at least threshold of its area is dark modules,
+// and the background color otherwise, so the thumbnail has no anti-aliasing.
+// With threshold 0 the foreground and background colors are blended by that
+// fraction in linear light, so the thumbnail keeps the symbol's average
+// brightness.
+//
+// Thumbnails show the modules only: captions, stamps, module shapes and
+// other decorations are not drawn. They are generally too small to scan.
+// Downscale replaces Scale.
+func Downscale(threshold float64) Option {
+	return func(q *QRCode) {
+		q.downscale = true
+		q.downscaleThreshold = threshold
+		q.scale = 0
+	}
+}
+
+// thumbnailSize returns the size of the Downscale thumbnail Image() returns,
+// and whether one is needed.
+func (q *QRCode) thumbnailSize() (width, height int, ok bool) {
+	realSize := q.symbol.size
+	if !q.downscale || q.scale > 0 {
+		return 0, 0, false
+	}
+
+	small := func(size int) bool { return size > 0 && size < realSize }
+	if !small(q.width) && !small(q.height) {
+		return 0, 0, false
+	}
+
+	width, height = q.width, q.height
+	if !small(width) {
+		width = realSize
+	}
+	if !small(height) {
+		height = realSize
+	}
+
+	return width, height, true
+}
+
+// thumbnail returns the symbol area-averaged down to width x height pixels,
+// see Downscale.
+func (q *QRCode) thumbnail(width, height int) image.Image {
+	bitmap := q.symbol.bitmap()
+	n := len(bitmap)
+
+	fg := color.NRGBAModel.Convert(q.ForegroundColor).(color.NRGBA)
+	bg := color.NRGBAModel.Convert(q.BackgroundColor).(color.NRGBA)
+
+	var out interface {
+		image.Image
+		Set(x, y int, c color.Color)
+	}
+	if q.downscaleThreshold > 0 {
+		p := color.Palette{q.BackgroundColor, q.ForegroundColor}
+		if q.customPalette != nil {
+			p = q.palette()
+		}
+		out = image.NewPaletted(image.Rect(0, 0, width, height), p)
+	} else {
+		out = image.NewNRGBA(image.Rect(0, 0, width, height))
+	}
+
+	// Module area covered by output pixel i of size along an axis of n
+	// modules: [i*n/size, (i+1)*n/size).
+	span := func(i, size int) (float64, float64) {
+		return float64(i*n) / float64(size), float64((i+1)*n) / float64(size)
+	}
+
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width)
+
+			dark := 0.0
+			for my := int(y0); my < n && float64(my) < y1; my++ {
+				h := math.Min(y1, float64(my+1)) - math.Max(y0, float64(my))
+				for mx := int(x0); mx < n && float64(mx) < x1; mx++ {
+					if bitmap[my][mx] {
+						w := math.Min(x1, float64(mx+1)) - math.Max(x0, float64(mx))
+						dark += w * h
+					}
+				}
+			}
+			coverage := dark / ((x1 - x0) * (y1 - y0))
+
+			if q.downscaleThreshold > 0 {
+				if coverage >= q.downscaleThreshold {
+					out.Set(x, y, q.ForegroundColor)
+				} else {
+					out.Set(x, y, q.BackgroundColor)
+				}
+				continue
+			}
+
+			out.Set(x, y, blendLinear(bg, fg, coverage))
+		}
+	}
+
+	return out
+}
+
+// blendLinear returns the mix of a and b with b weighted t (0-1), blended in
+// linear light rather than in the gamma encoded sRGB values.
+func blendLinear(a, b color.NRGBA, t float64) color.NRGBA {
+	mix := func(u, v uint8) uint8 {
+		l := (1-t)*srgbToLinear(u) + t*srgbToLinear(v)
+		return linearToSRGB(l)
+	}
+
+	return color.NRGBA{
+		R: mix(a.R, b.R),
+		G: mix(a.G, b.G),
+		B: mix(a.B, b.B),
+		A: uint8(math.Round((1-t)*float64(a.A) + t*float64(b.A))),
+	}
+}
+
+// srgbToLinear returns the linear light intensity (0-1) of an sRGB value.
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 0xff
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB returns the sRGB value of a linear light intensity (0-1).
+func linearToSRGB(l float64) uint8 {
+	var c float64
+	if l <= 0.0031308 {
+		c = l * 12.92
+	} else {
+		c = 1.055*math.Pow(l, 1/2.4) - 0.055
+	}
+
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 0xff))
+}
@@ -0,0 +1,82 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDownscale(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without Downscale, a small size is enlarged to the symbol size.
+	q.width, q.height = 10, 10
+	if got := q.Image().Bounds().Dx(); got != q.symbol.size {
+		t.Errorf("got width %d, expected the symbol size %d", got, q.symbol.size)
+	}
+
+	q, err = New("https://example.org", Level(Medium), Margin(4), Width(10), Height(10), Downscale(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := q.Image()
+	if img.Bounds() != image.Rect(0, 0, 10, 10) {
+		t.Fatalf("got bounds %v, expected 10x10", img.Bounds())
+	}
+
+	// The quiet zone corner is background; the blend elsewhere is grey.
+	if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c != (color.NRGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("corner is %v, expected white", c)
+	}
+	grey := false
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.R != 0 && c.R != 0xff {
+				grey = true
+			}
+		}
+	}
+	if !grey {
+		t.Error("blended thumbnail has no intermediate greys")
+	}
+
+	q, err = New("https://example.org", Level(Medium), Margin(4), Width(10), Height(10), Downscale(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img = q.Image()
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			if c := img.At(x, y); c != q.ForegroundColor && c != q.BackgroundColor {
+				t.Fatalf("pixel (%d, %d) is %v, expected foreground or background", x, y, c)
+			}
+		}
+	}
+
+	if _, err := New("x", Level(Medium), Downscale(2)); err == nil {
+		t.Error("New succeeded with threshold 2, expected error")
+	}
+}
+
+func TestBlendLinear(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 0xff}
+	white := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+
+	// Half black, half white is 50% linear light: sRGB 188, not 128.
+	if got := blendLinear(white, black, 0.5); got.R != 188 || got.A != 0xff {
+		t.Errorf("got %v, expected sRGB 188", got)
+	}
+	if got := blendLinear(white, black, 0); got != white {
+		t.Errorf("got %v, expected white", got)
+	}
+	if got := blendLinear(white, black, 1); got != black {
+		t.Errorf("got %v, expected black", got)
+	}
+}
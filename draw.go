@@ -0,0 +1,38 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Draw paints the QR Code directly into dst, with its top left corner at
+// point at and each module drawn as a scale x scale pixel block. The quiet
+// zone is included.
+//
+// No intermediate image is allocated, so this suits composing codes into
+// caller owned images such as posters, certificates or ID cards. The colors
+// are drawn over dst, so a transparent background color leaves dst visible
+// behind the light modules. Any part of the symbol outside dst is clipped.
+func (q *QRCode) Draw(dst draw.Image, at image.Point, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+
+	fg := image.NewUniform(q.ForegroundColor)
+	bg := image.NewUniform(q.BackgroundColor)
+
+	for y, row := range q.symbol.bitmap() {
+		for x, v := range row {
+			src := bg
+			if v {
+				src = fg
+			}
+
+			r := image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale).Add(at)
+			draw.Draw(dst, r, src, image.Point{}, draw.Over)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDraw(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	dst := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	for x := 0; x < 300; x++ {
+		for y := 0; y < 300; y++ {
+			dst.Set(x, y, red)
+		}
+	}
+
+	at := image.Pt(10, 20)
+	q.Draw(dst, at, 3)
+
+	bitmap := q.Bitmap()
+	size := len(bitmap) * 3
+
+	for x := 0; x < 300; x++ {
+		for y := 0; y < 300; y++ {
+			expected := red
+			if p := image.Pt(x, y).Sub(at); p.In(image.Rect(0, 0, size, size)) {
+				expected = color.RGBA{0xff, 0xff, 0xff, 0xff}
+				if bitmap[p.Y/3][p.X/3] {
+					expected = color.RGBA{0, 0, 0, 0xff}
+				}
+			}
+
+			if got := dst.RGBAAt(x, y); got != expected {
+				t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, got, expected)
+			}
+		}
+	}
+}
+
+func TestDrawTransparentBackground(t *testing.T) {
+	q, err := New("https://example.org", BackgroundColor(color.Transparent))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			dst.Set(x, y, red)
+		}
+	}
+
+	// Partially outside dst, which is clipped.
+	q.Draw(dst, image.Pt(-5, -5), 1)
+
+	bitmap := q.Bitmap()
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			expected := red
+			if bitmap[y+5][x+5] {
+				expected = color.RGBA{0, 0, 0, 0xff}
+			}
+
+			if got := dst.RGBAAt(x, y); got != expected {
+				t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, got, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Outlines returns the outlines of the dark areas of the Bitmap, for cutting
+// and engraving: touching dark modules are merged into a single closed path,
+// with a separate path around each light hole. Points are module corners in
+// Bitmap coordinates, (0, 0) being the top left corner of the Bitmap, with a
+// point only where the outline turns.
+//
+// Outer outlines run clockwise and holes anticlockwise (y down), so the dark
+// area is always on the right. Modules touching only at a corner are
+// separate outlines.
+func (q *QRCode) Outlines() [][]image.Point {
+	bitmap := q.Bitmap()
+	dark := func(x, y int) bool {
+		return y >= 0 && y < len(bitmap) && x >= 0 && x < len(bitmap[y]) && bitmap[y][x]
+	}
+
+	// The edges between dark and light modules, directed with the dark
+	// module on the right, by start point.
+	type edge struct{ from, to image.Point }
+	edges := map[image.Point][]edge{}
+	var starts []image.Point
+	add := func(from, to image.Point) {
+		if len(edges[from]) == 0 {
+			starts = append(starts, from)
+		}
+		edges[from] = append(edges[from], edge{from, to})
+	}
+
+	for y, row := range bitmap {
+		for x, v := range row {
+			if !v {
+				continue
+			}
+			if !dark(x, y-1) {
+				add(image.Pt(x, y), image.Pt(x+1, y))
+			}
+			if !dark(x+1, y) {
+				add(image.Pt(x+1, y), image.Pt(x+1, y+1))
+			}
+			if !dark(x, y+1) {
+				add(image.Pt(x+1, y+1), image.Pt(x, y+1))
+			}
+			if !dark(x-1, y) {
+				add(image.Pt(x, y+1), image.Pt(x, y))
+			}
+		}
+	}
+
+	// take removes and returns the edge from p, preferring a right turn from
+	// direction d, then straight on, then a left turn. Turning right at a
+	// corner shared by two diagonal dark modules keeps them apart.
+	take := func(p, d image.Point) edge {
+		candidates := edges[p]
+		best := 0
+		for i, e := range candidates {
+			if e.to.Sub(e.from) == image.Pt(-d.Y, d.X) {
+				best = i
+				break
+			}
+			if e.to.Sub(e.from) == d {
+				best = i
+			}
+		}
+
+		e := candidates[best]
+		edges[p] = append(candidates[:best:best], candidates[best+1:]...)
+		return e
+	}
+
+	var outlines [][]image.Point
+	for _, start := range starts {
+		for len(edges[start]) > 0 {
+			e := take(start, image.Pt(1, 0))
+			outline := []image.Point{e.from}
+			for {
+				d := e.to.Sub(e.from)
+				next := take(e.to, d)
+				if next.to.Sub(next.from) != d {
+					outline = append(outline, e.to)
+				}
+				e = next
+				if e.to == start {
+					break
+				}
+			}
+
+			// The start point is a corner unless the outline passes straight
+			// through it.
+			if e.to.Sub(e.from) == outline[1].Sub(outline[0]) {
+				outline = outline[1:]
+			}
+			outlines = append(outlines, outline)
+		}
+	}
+
+	return outlines
+}
+
+// DXF returns the Outlines of the QR Code as a DXF drawing, sizeMM
+// millimetres square including the quiet zone, for laser cutters and
+// engravers. Each outline is a closed polyline on layer "QR"; holes are
+// separate polylines inside them. Captions, stamps and other image options
+// are not drawn.
+func (q *QRCode) DXF(sizeMM float64) ([]byte, error) {
+	if sizeMM <= 0 {
+		return nil, errors.New("dxf: size must be positive")
+	}
+
+	start := time.Now()
+
+	bitmap := q.Bitmap()
+	height := len(bitmap)
+	module := sizeMM / float64(max(len(bitmap[0]), height))
+
+	var buf bytes.Buffer
+	group := func(code int, value string) {
+		fmt.Fprintf(&buf, "%d\n%s\n", code, value)
+	}
+
+	// Drawing units: millimetres.
+	group(0, "SECTION")
+	group(2, "HEADER")
+	group(9, "$INSUNITS")
+	group(70, "4")
+	group(0, "ENDSEC")
+
+	group(0, "SECTION")
+	group(2, "ENTITIES")
+	for _, outline := range q.Outlines() {
+		group(0, "POLYLINE")
+		group(8, "QR")
+		group(66, "1")
+		group(70, "1") // Closed.
+		group(10, "0")
+		group(20, "0")
+		group(30, "0")
+
+		// DXF's y axis points up.
+		for _, p := range outline {
+			group(0, "VERTEX")
+			group(8, "QR")
+			group(10, dxfNumber(float64(p.X)*module))
+			group(20, dxfNumber(float64(height-p.Y)*module))
+			group(30, "0")
+		}
+		group(0, "SEQEND")
+		group(8, "QR")
+	}
+	group(0, "ENDSEC")
+	group(0, "EOF")
+
+	if q.metrics != nil {
+		q.metrics.Rendered("dxf", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// dxfNumber formats v for DXF output, to 4 decimal places without trailing
+// zeros.
+func dxfNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
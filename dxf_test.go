@@ -0,0 +1,144 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestOutlines(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitmap := q.Bitmap()
+	outlines := q.Outlines()
+
+	dark := 0
+	for _, row := range bitmap {
+		for _, v := range row {
+			if v {
+				dark++
+			}
+		}
+	}
+	if len(outlines) == 0 || len(outlines) >= dark {
+		t.Fatalf("got %d outlines for %d dark modules, expected modules to be merged", len(outlines), dark)
+	}
+
+	for i, outline := range outlines {
+		for j, p := range outline {
+			prev := outline[(j+len(outline)-1)%len(outline)]
+			next := outline[(j+1)%len(outline)]
+			in, out := p.Sub(prev), next.Sub(p)
+			if (in.X != 0) == (in.Y != 0) || (out.X != 0) == (out.Y != 0) {
+				t.Fatalf("outline %d: segment at %v is not horizontal or vertical", i, p)
+			}
+			if (in.X == 0) == (out.X == 0) {
+				t.Fatalf("outline %d: %v is not a corner", i, p)
+			}
+		}
+	}
+
+	// Module centers inside an odd number of outlines are dark.
+	for y, row := range bitmap {
+		for x, v := range row {
+			inside := false
+			for _, outline := range outlines {
+				if containsPoint(outline, float64(x)+0.5, float64(y)+0.5) {
+					inside = !inside
+				}
+			}
+			if inside != v {
+				t.Fatalf("module (%d, %d): got inside %t, expected %t", x, y, inside, v)
+			}
+		}
+	}
+}
+
+func TestOutlinesDiagonal(t *testing.T) {
+	q, err := New("0", Margin(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each outline of the Bitmap may touch another only at corners, so no
+	// edge is shared.
+	edges := map[[2]image.Point]bool{}
+	for _, outline := range q.Outlines() {
+		for j, p := range outline {
+			next := outline[(j+1)%len(outline)]
+			d := next.Sub(p)
+			d = d.Div(max(abs(d.X), abs(d.Y)))
+			for a := p; a != next; a = a.Add(d) {
+				edge := [2]image.Point{a, a.Add(d)}
+				if edge[0].X > edge[1].X || edge[0].Y > edge[1].Y {
+					edge[0], edge[1] = edge[1], edge[0]
+				}
+				if edges[edge] {
+					t.Fatalf("edge %v is on more than one outline", edge)
+				}
+				edges[edge] = true
+			}
+		}
+	}
+}
+
+func TestDXF(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dxf, err := q.DXF(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(dxf)
+	if !strings.Contains(s, "SECTION\n2\nENTITIES\n") || !strings.HasSuffix(s, "0\nEOF\n") {
+		t.Fatalf("malformed dxf:\n%s", s)
+	}
+	if got, expected := strings.Count(s, "\nPOLYLINE\n"), len(q.Outlines()); got != expected {
+		t.Errorf("got %d polylines, expected %d", got, expected)
+	}
+
+	// The first outline is the top left corner of the top left finder
+	// pattern, 4 modules in from the top left corner of the drawing.
+	module := 50.0 / float64(len(q.Bitmap()))
+	if !strings.Contains(s, "VERTEX\n8\nQR\n10\n"+dxfNumber(4*module)+"\n20\n"+dxfNumber(50-4*module)+"\n") {
+		t.Errorf("first vertex not found:\n%s", s[:400])
+	}
+
+	if _, err := q.DXF(0); err == nil {
+		t.Error("DXF(0) succeeded, expected an error")
+	}
+
+	rendered, err := q.Render("dxf", RenderOptions{SizeMM: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rendered) != s {
+		t.Error("dxf renderer output differs from DXF")
+	}
+}
+
+// containsPoint returns true if (x, y) is inside the polygon, by the even-odd
+// rule.
+func containsPoint(polygon []image.Point, x, y float64) bool {
+	inside := false
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		if (float64(p.Y) > y) != (float64(q.Y) > y) {
+			cross := float64(p.X) + (y-float64(p.Y))*float64(q.X-p.X)/float64(q.Y-p.Y)
+			if x < cross {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
@@ -5,7 +5,7 @@ package qrcode
 
 import (
 	"errors"
-	"log"
+	"fmt"
 
 	"github.com/yougg/go-qrcode/bitset"
 )
@@ -36,7 +36,8 @@ import (
 // size, an optimisation routine coalesces segment types where possible, to
 // reduce the encoded data length.
 //
-// There are several other data modes available (e.g. Kanji mode) which are not
+// Kanji mode (13 bits per Shift JIS double byte character) is only used for
+// segments given explicitly to NewSegments. Other data modes (e.g. ECI) are not
 // implemented here.
 
 // A segment encoding mode.
@@ -53,6 +54,10 @@ const (
 	dataModeNumeric
 	dataModeAlphanumeric
 	dataModeByte
+
+	// Kanji is not a superset of the other modes, and is never chosen
+	// automatically.
+	dataModeKanji
 )
 
 // dataModeString returns d as a short printable string.
@@ -66,6 +71,8 @@ func dataModeString(d dataMode) string {
 		return "alphanumeric"
 	case dataModeByte:
 		return "byte"
+	case dataModeKanji:
+		return "kanji"
 	}
 
 	return "unknown"
@@ -98,11 +105,13 @@ type dataEncoder struct {
 	numericModeIndicator      *bitset.Bitset
 	alphanumericModeIndicator *bitset.Bitset
 	byteModeIndicator         *bitset.Bitset
+	kanjiModeIndicator        *bitset.Bitset
 
 	// Character count lengths.
 	numNumericCharCountBits      int
 	numAlphanumericCharCountBits int
 	numByteCharCountBits         int
+	numKanjiCharCountBits        int
 
 	// The raw input data.
 	data []byte
@@ -126,9 +135,11 @@ func newDataEncoder(t dataEncoderType) *dataEncoder {
 			numericModeIndicator:         bitset.New(b0, b0, b0, b1),
 			alphanumericModeIndicator:    bitset.New(b0, b0, b1, b0),
 			byteModeIndicator:            bitset.New(b0, b1, b0, b0),
+			kanjiModeIndicator:           bitset.New(b1, b0, b0, b0),
 			numNumericCharCountBits:      10,
 			numAlphanumericCharCountBits: 9,
 			numByteCharCountBits:         8,
+			numKanjiCharCountBits:        8,
 		}
 	case dataEncoderType10To26:
 		d = &dataEncoder{
@@ -137,9 +148,11 @@ func newDataEncoder(t dataEncoderType) *dataEncoder {
 			numericModeIndicator:         bitset.New(b0, b0, b0, b1),
 			alphanumericModeIndicator:    bitset.New(b0, b0, b1, b0),
 			byteModeIndicator:            bitset.New(b0, b1, b0, b0),
+			kanjiModeIndicator:           bitset.New(b1, b0, b0, b0),
 			numNumericCharCountBits:      12,
 			numAlphanumericCharCountBits: 11,
 			numByteCharCountBits:         16,
+			numKanjiCharCountBits:        10,
 		}
 	case dataEncoderType27To40:
 		d = &dataEncoder{
@@ -148,12 +161,14 @@ func newDataEncoder(t dataEncoderType) *dataEncoder {
 			numericModeIndicator:         bitset.New(b0, b0, b0, b1),
 			alphanumericModeIndicator:    bitset.New(b0, b0, b1, b0),
 			byteModeIndicator:            bitset.New(b0, b1, b0, b0),
+			kanjiModeIndicator:           bitset.New(b1, b0, b0, b0),
 			numNumericCharCountBits:      14,
 			numAlphanumericCharCountBits: 13,
 			numByteCharCountBits:         16,
+			numKanjiCharCountBits:        12,
 		}
 	default:
-		log.Panic("Unknown dataEncoderType")
+		panic("Unknown dataEncoderType")
 	}
 
 	return d
@@ -295,8 +310,12 @@ func (d *dataEncoder) encodeDataRaw(data []byte, dataMode dataMode, encoded *bit
 	// Append mode indicator.
 	encoded.Append(modeIndicator)
 
-	// Append character count.
-	encoded.AppendUint32(uint32(len(data)), charCountBits)
+	// Append character count. Kanji characters are two bytes each.
+	numChars := len(data)
+	if dataMode == dataModeKanji {
+		numChars /= 2
+	}
+	encoded.AppendUint32(uint32(numChars), charCountBits)
 
 	// Append data.
 	switch dataMode {
@@ -335,6 +354,10 @@ func (d *dataEncoder) encodeDataRaw(data []byte, dataMode dataMode, encoded *bit
 		for _, b := range data {
 			encoded.AppendByte(b, 8)
 		}
+	case dataModeKanji:
+		for i := 0; i+1 < len(data); i += 2 {
+			encoded.AppendUint32(encodeKanjiCharacter(data[i], data[i+1]), 13)
+		}
 	}
 }
 
@@ -347,11 +370,11 @@ func (d *dataEncoder) modeIndicator(dataMode dataMode) *bitset.Bitset {
 		return d.alphanumericModeIndicator
 	case dataModeByte:
 		return d.byteModeIndicator
+	case dataModeKanji:
+		return d.kanjiModeIndicator
 	default:
-		log.Panic("Unknown data mode")
+		panic("Unknown data mode")
 	}
-
-	return nil
 }
 
 // charCountBits returns the number of bits used to encode the length of a data
@@ -364,11 +387,11 @@ func (d *dataEncoder) charCountBits(dataMode dataMode) int {
 		return d.numAlphanumericCharCountBits
 	case dataModeByte:
 		return d.numByteCharCountBits
+	case dataModeKanji:
+		return d.numKanjiCharCountBits
 	default:
-		log.Panic("Unknown data mode")
+		panic("Unknown data mode")
 	}
-
-	return 0
 }
 
 // encodedLength returns the number of bits required to encode n symbols in
@@ -410,6 +433,8 @@ func (d *dataEncoder) encodedLength(dataMode dataMode, n int) (int, error) {
 		length += 6 * (n % 2)
 	case dataModeByte:
 		length += 8 * n
+	case dataModeKanji:
+		length += 13 * n
 	}
 
 	return length, nil
@@ -448,8 +473,29 @@ func encodeAlphanumericCharacter(v byte) uint32 {
 	case c == ':':
 		return 44
 	default:
-		log.Panicf("encodeAlphanumericCharacter() with non alphanumeric char %v.", v)
+		panic(fmt.Sprintf("encodeAlphanumericCharacter() with non alphanumeric char %v.", v))
 	}
+}
+
+// encodeKanjiCharacter returns the QR Code encoded value of the Shift JIS
+// double byte character (hi, lo), which must satisfy isKanji.
+func encodeKanjiCharacter(hi, lo byte) uint32 {
+	c := uint32(hi)<<8 | uint32(lo)
+
+	if c <= 0x9ffc {
+		c -= 0x8140
+	} else {
+		c -= 0xc140
+	}
+
+	return (c>>8)*0xc0 + c&0xff
+}
+
+// isKanji reports whether (hi, lo) is a Shift JIS double byte character
+// encodable in Kanji mode.
+func isKanji(hi, lo byte) bool {
+	c := uint32(hi)<<8 | uint32(lo)
 
-	return 0
+	return (c >= 0x8140 && c <= 0x9ffc || c >= 0xe040 && c <= 0xebbf) &&
+		lo >= 0x40 && lo <= 0xfc && lo != 0x7f
 }
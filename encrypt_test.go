@@ -0,0 +1,65 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	content := "badge:visitor:4711"
+
+	q, err := NewEncrypted(content, key, Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := VerifyBitmap(q.Bitmap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := string(info.Content)
+	if !strings.HasPrefix(envelope, "QRE1:") || strings.Contains(envelope, content) {
+		t.Fatalf("got envelope %q", envelope)
+	}
+	for _, c := range envelope {
+		if !strings.ContainsRune(base45Alphabet, c) {
+			t.Fatalf("envelope %q contains non alphanumeric mode character %q", envelope, c)
+		}
+	}
+
+	got, err := Decrypt(envelope, key)
+	if err != nil || got != content {
+		t.Errorf("Decrypt = %q, %v, expected %q", got, err, content)
+	}
+
+	// Each envelope uses a fresh nonce.
+	if other, _ := Encrypt(content, key); other == envelope {
+		t.Error("Encrypt returned the same envelope twice")
+	}
+
+	wrongKey := bytes.Repeat([]byte{0x43}, 32)
+	if _, err := Decrypt(envelope, wrongKey); err == nil {
+		t.Error("Decrypt succeeded with the wrong key")
+	}
+
+	modified := []byte(envelope)
+	modified[len(modified)-1] = '0' + (modified[len(modified)-1]+1)%10
+	if _, err := Decrypt(string(modified), key); err == nil {
+		t.Error("Decrypt succeeded on a modified envelope")
+	}
+
+	for _, invalid := range []string{"", "hello", "QRE1:", "QRE1:AB"} {
+		if _, err := Decrypt(invalid, key); err == nil {
+			t.Errorf("Decrypt(%q) succeeded, expected error", invalid)
+		}
+	}
+
+	if _, err := Encrypt(content, []byte("short")); err == nil {
+		t.Error("Encrypt succeeded with a 5 byte key")
+	}
+}
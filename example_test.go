@@ -1,3 +1,6 @@
+//go:build !tinygo
+// +build !tinygo
+
 // go-qrcode
 // Copyright 2014 Tom Harwood
 /*
@@ -5,6 +8,7 @@
 	- test integration (go test -v)
 	- idiomatic go code
 */
+
 package qrcode
 
 import (
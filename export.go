@@ -0,0 +1,48 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"fmt"
+	"os"
+)
+
+// ExportSizes writes the QR Code as a PNG image of each size in sizes, named
+// basePath-<size>.png, plus a scalable basePath.svg master, e.g. for the
+// 1x/2x/3x assets of an app. The symbol is encoded once and only rasterized
+// per size; all other options of the QR Code apply to every image.
+//
+// The names of the files written are returned, SVG first.
+func (q *QRCode) ExportSizes(sizes []int, basePath string) ([]string, error) {
+	for _, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid export size %d (must be positive)", size)
+		}
+	}
+
+	svg, err := q.Render("svg", RenderOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	filename := basePath + ".svg"
+	if err := os.WriteFile(filename, svg, 0644); err != nil {
+		return nil, err
+	}
+	filenames := []string{filename}
+
+	for _, size := range sizes {
+		c := *q
+		c.scale = 0
+		c.width, c.height = size, size
+
+		filename := fmt.Sprintf("%s-%d.png", basePath, size)
+		if err := c.WriteFile(filename); err != nil {
+			return filenames, err
+		}
+		filenames = append(filenames, filename)
+	}
+
+	return filenames, nil
+}
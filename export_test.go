@@ -0,0 +1,59 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportSizes(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "code")
+
+	q, err := New("export", Scale(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filenames, err := q.ExportSizes([]int{64, 128, 192}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{base + ".svg", base + "-64.png", base + "-128.png", base + "-192.png"}
+	if !reflect.DeepEqual(filenames, expected) {
+		t.Errorf("got files %v, expected %v", filenames, expected)
+	}
+
+	for i, size := range []int{64, 128, 192} {
+		f, err := os.Open(filenames[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+			t.Errorf("%s: got size %v, expected %d", filenames[i+1], b.Size(), size)
+		}
+	}
+
+	if _, err := os.Stat(base + ".svg"); err != nil {
+		t.Error(err)
+	}
+
+	if q.scale != 2 {
+		t.Error("ExportSizes changed the QR Code's options")
+	}
+
+	if _, err := q.ExportSizes([]int{0}, base); err == nil {
+		t.Error("ExportSizes succeeded with size 0, expected error")
+	}
+}
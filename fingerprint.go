@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+)
+
+// Fingerprint returns a hash of everything that determines the QR Code's
+// rendered output: the modules (and so the content, version, level, mask and
+// padding) and the drawing options. QR Codes with equal fingerprints render
+// identical images, so it can be used as an HTTP ETag or cache key without
+// hashing the images themselves.
+//
+// The fingerprint is 64 hex digits, stable across runs and platforms, but
+// may change between versions of this package.
+func (q *QRCode) Fingerprint() string {
+	h := sha256.New()
+	w := func(format string, a ...interface{}) {
+		fmt.Fprintf(h, format, a...)
+	}
+
+	// Resolve the image size as Image() does, so equivalent sizes give the
+	// same fingerprint.
+	_, _, _, _, imageWidth, imageHeight := q.layout()
+
+	w("qrcode fingerprint 1\n")
+	w("content %q\n", q.Content)
+	w("symbol %d %s %d\n", q.VersionNumber, q.level, q.mask)
+
+	width, rows := q.PackedBitmap()
+	w("modules %d\n", width)
+	for _, row := range rows {
+		h.Write(row)
+	}
+
+	// Every option changing the rendered output, beyond the modules and the
+	// image size, is written below.
+	w("colors %s %s %s %s\n", colorKey(q.ForegroundColor), colorKey(q.BackgroundColor),
+		colorKey(q.quietZoneColor), colorKey(q.outlineColor))
+	top, right, bottom, left := q.quietZoneExtra()
+	w("quiet zone %d %d %d %d %d %d\n", q.quietZoneRadius, q.outlineWidth, top, right, bottom, left)
+	w("size %d %d %t\n", imageWidth, imageHeight, q.snapToModule)
+	w("caption %q %t\n", q.caption, q.captionContent)
+	w("stamp %q %t\n", q.stamp, q.stampSequence)
+	if q.transform != nil {
+		w("transform %v\n", *q.transform)
+	}
+	w("orientation marker %d\n", q.orientationMarker)
+	w("downscale %t %g\n", q.thumbnailed(), q.downscaleThreshold)
+	w("shapes %d %d\n", q.moduleShape, q.eyeShape)
+	w("icc %t %q %x\n", q.iccProfileSet, q.iccProfileName, sha256.Sum256(q.iccProfile))
+	w("smoothing %t\n", !q.noSmoothing)
+	w("png metadata %t\n", q.pngMetadata)
+	w("reproducible %t\n", q.reproducible)
+	w("palette %d", len(q.customPalette))
+	for _, c := range q.customPalette {
+		w(" %s", colorKey(c))
+	}
+	w("\n")
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// colorKey returns c as non-premultiplied RGBA hex digits, or "none" if c is
+// nil.
+func colorKey(c color.Color) string {
+	if c == nil {
+		return "none"
+	}
+
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
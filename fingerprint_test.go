@@ -0,0 +1,74 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/yougg/go-qrcode/styles"
+	"golang.org/x/image/math/f64"
+)
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(content string, opts ...Option) string {
+		q, err := New(content, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q.Fingerprint()
+	}
+
+	base := fingerprint("fingerprint", Width(-4))
+	if len(base) != 64 {
+		t.Errorf("got fingerprint %q, expected 64 hex digits", base)
+	}
+	if again := fingerprint("fingerprint", Width(-4)); again != base {
+		t.Error("fingerprint is not stable")
+	}
+
+	q, err := New("fingerprint", Width(-4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Image()
+	if q.Fingerprint() != base {
+		t.Error("fingerprint changed after rendering")
+	}
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	different := map[string]string{
+		"content":    fingerprint("fingerprints", Width(-4)),
+		"level":      fingerprint("fingerprint", Width(-4), Level(Highest)),
+		"width":      fingerprint("fingerprint", Width(-5)),
+		"foreground": fingerprint("fingerprint", Width(-4), ForegroundColor(red)),
+		"margin":     fingerprint("fingerprint", Width(-4), Margin(4)),
+		"caption":    fingerprint("fingerprint", Width(-4), Caption("x")),
+		"padding":    fingerprint("fingerprint", Width(-4), PadCodewords(0)),
+		"quiet zone": fingerprint("fingerprint", Width(-4), QuietZoneColor(red)),
+		"radius":     fingerprint("fingerprint", Width(-4), QuietZoneRadius(2)),
+		"outline":    fingerprint("fingerprint", Width(-4), QuietZoneOutline(1, red)),
+		"snap":       fingerprint("fingerprint", Width(-4), SnapToModule()),
+		"stamp":      fingerprint("fingerprint", Width(-4), Stamp("x")),
+		"transform":  fingerprint("fingerprint", Width(-4), Transform(f64.Aff3{-1, 0, 0, 0, 1, 0})),
+		"style":      fingerprint("fingerprint", Width(-4), Style(styles.Dots)),
+		"icc":        fingerprint("fingerprint", Width(-4), ICCProfile("", nil)),
+		"smoothing":  fingerprint("fingerprint", Width(-4), NoSmoothing()),
+		"edges":      fingerprint("fingerprint", Width(-4), QuietZone(8, 4, 4, 4)),
+		"marker":     fingerprint("fingerprint", Width(-4), OrientationMarker(TopEdge)),
+		"metadata":   fingerprint("fingerprint", Width(-4), PNGMetadata()),
+		"encoding":   fingerprint("fingerprint", Width(-4), Reproducible()),
+		"palette":    fingerprint("fingerprint", Width(-4), Palette(color.Palette{color.White, color.Black})),
+	}
+	for name, f := range different {
+		if f == base {
+			t.Errorf("changing the %s did not change the fingerprint", name)
+		}
+	}
+
+	// Too small for the symbol: a larger image, or a Downscale thumbnail.
+	if fingerprint("fingerprint", Width(10)) == fingerprint("fingerprint", Width(10), Downscale(0)) {
+		t.Error("Downscale did not change the fingerprint")
+	}
+}
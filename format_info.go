@@ -0,0 +1,78 @@
+package qrcode
+
+import (
+	"fmt"
+
+	"github.com/yougg/go-qrcode/bitset"
+)
+
+// FormatInfo returns the 15-bit Format Information computed for the QR Code:
+// the error correction level and mask pattern, BCH protected and masked with
+// 0x5412 as specified by ISO/IEC 18004. Any OverrideFormatInfo is ignored.
+func (q *QRCode) FormatInfo() uint32 {
+	return uint32Of(q.version.formatInfo(q.mask))
+}
+
+// VersionInfo returns the 18-bit Version Information computed for the QR
+// Code, and true. Versions 1-6 have no Version Information, in which case 0
+// and false are returned. Any OverrideVersionInfo is ignored.
+func (q *QRCode) VersionInfo() (uint32, bool) {
+	v := q.version.versionInfo()
+	if v == nil {
+		return 0, false
+	}
+
+	return uint32Of(v), true
+}
+
+// checkInfoOverrides returns an error if an OverrideFormatInfo or
+// OverrideVersionInfo value does not fit, or does not apply to the version.
+func (q *QRCode) checkInfoOverrides() error {
+	if q.formatInfoOverride != nil && *q.formatInfoOverride>>formatInfoLengthBits != 0 {
+		return fmt.Errorf("format information override 0x%x exceeds %d bits",
+			*q.formatInfoOverride, formatInfoLengthBits)
+	}
+
+	if q.versionInfoOverride != nil {
+		if *q.versionInfoOverride>>versionInfoLengthBits != 0 {
+			return fmt.Errorf("version information override 0x%x exceeds %d bits",
+				*q.versionInfoOverride, versionInfoLengthBits)
+		}
+		if q.VersionNumber < 7 {
+			return fmt.Errorf("version %d has no version information to override", q.VersionNumber)
+		}
+	}
+
+	return nil
+}
+
+// applyInfoOverrides writes any OverrideFormatInfo or OverrideVersionInfo
+// value into the chosen symbol.
+func (q *QRCode) applyInfoOverrides() {
+	size := q.version.symbolSize()
+
+	if q.formatInfoOverride != nil {
+		f := bitset.New()
+		f.AppendUint32(*q.formatInfoOverride, formatInfoLengthBits)
+		setFormatInfo(q.symbol, size, f)
+	}
+
+	if q.versionInfoOverride != nil {
+		v := bitset.New()
+		v.AppendUint32(*q.versionInfoOverride, versionInfoLengthBits)
+		setVersionInfo(q.symbol, size, v)
+	}
+}
+
+// uint32Of returns the bits of b, at most 32, as an unsigned value.
+func uint32Of(b *bitset.Bitset) uint32 {
+	var v uint32
+	for _, bit := range b.Bits() {
+		v <<= 1
+		if bit {
+			v |= 1
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,99 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQRCodeFormatInfo(t *testing.T) {
+	// ISO/IEC 18004 Annex I example: level M, mask 2.
+	q, err := New("01234567", Level(Medium))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if got := q.FormatInfo(); got != 0x5e7c {
+		t.Errorf("FormatInfo() = 0x%04x, expected 0x5e7c", got)
+	}
+
+	if v, ok := q.VersionInfo(); ok || v != 0 {
+		t.Errorf("VersionInfo() = 0x%05x, %t, expected none for version 1", v, ok)
+	}
+}
+
+func TestQRCodeVersionInfo(t *testing.T) {
+	q, err := New("A", MinVersion(7))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if v, ok := q.VersionInfo(); !ok || v != 0x07c94 {
+		t.Errorf("VersionInfo() = 0x%05x, %t, expected 0x07c94", v, ok)
+	}
+}
+
+func TestOverrideInfo(t *testing.T) {
+	q, err := New("https://example.org", MinVersion(7))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := VerifyBitmap(q.Bitmap()); err != nil {
+		t.Fatalf("unmodified symbol failed verification: %s", err.Error())
+	}
+
+	v, _ := q.VersionInfo()
+
+	tests := []struct {
+		opts   []Option
+		errStr string
+	}{
+		{[]Option{OverrideFormatInfo(q.FormatInfo() ^ 0x0001)}, "format information"},
+		{[]Option{OverrideVersionInfo(v ^ 0x00100)}, "version information"},
+	}
+
+	for _, test := range tests {
+		damaged, err := New("https://example.org", append(test.opts, MinVersion(7))...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if damaged.mask != q.mask {
+			t.Errorf("override changed mask %d to %d", q.mask, damaged.mask)
+		}
+
+		_, err = VerifyBitmap(damaged.Bitmap())
+		if err == nil || !strings.Contains(err.Error(), test.errStr) {
+			t.Errorf("VerifyBitmap() error %v, expected %q error", err, test.errStr)
+		}
+	}
+
+	// Overriding with the computed values changes nothing.
+	same, err := New("https://example.org", MinVersion(7), OverrideFormatInfo(q.FormatInfo()),
+		OverrideVersionInfo(v))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for y, row := range same.Bitmap() {
+		for x, module := range row {
+			if module != q.Bitmap()[y][x] {
+				t.Fatalf("module (%d, %d) differs", x, y)
+			}
+		}
+	}
+}
+
+func TestOverrideInfoInvalid(t *testing.T) {
+	tests := [][]Option{
+		{OverrideFormatInfo(1 << 15)},
+		{OverrideVersionInfo(1 << 18), MinVersion(7)},
+		{OverrideVersionInfo(0x07c94)},
+	}
+
+	for i, opts := range tests {
+		if _, err := New("A", opts...); err == nil {
+			t.Errorf("test %d: New succeeded, expected error", i)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package legacy encodes PCX and TGA images, for industrial HMIs and other
+// equipment that only reads these formats.
+//
+// The encoders are only built with the legacyformats build tag, so default
+// builds stay small:
+//
+//	go build -tags legacyformats
+//
+// Importing the package then registers the "pcx" and "tga" Renderers:
+//
+//	import _ "github.com/yougg/go-qrcode/formats/legacy"
+//
+//	q, err := qrcode.New("https://example.org", qrcode.Scale(4))
+//	pcx, err := q.Render("pcx", qrcode.RenderOptions{})
+package legacy
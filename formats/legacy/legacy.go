@@ -0,0 +1,28 @@
+//go:build legacyformats && !tinygo
+// +build legacyformats,!tinygo
+
+package legacy
+
+import (
+	"bytes"
+	"image"
+	"io"
+
+	qrcode "github.com/yougg/go-qrcode"
+)
+
+func init() {
+	qrcode.RegisterRenderer("pcx", rendererFor(EncodePCX))
+	qrcode.RegisterRenderer("tga", rendererFor(EncodeTGA))
+}
+
+// rendererFor returns a Renderer writing the QR Code's Image with encode.
+func rendererFor(encode func(w io.Writer, img image.Image) error) qrcode.Renderer {
+	return qrcode.RendererFunc(func(sym qrcode.SymbolView, opts qrcode.RenderOptions) ([]byte, error) {
+		var buf bytes.Buffer
+		if err := encode(&buf, sym.Image()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+}
@@ -0,0 +1,98 @@
+//go:build legacyformats && !tinygo
+// +build legacyformats,!tinygo
+
+package legacy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+// EncodePCX writes img to w in PCX (ZSoft version 5) format, run length
+// encoded. Paletted images of up to 256 colors are written with 8 bits per
+// pixel and a 256 color palette; others as 24 bit color. PCX has no
+// transparency, so alpha is ignored.
+func EncodePCX(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 || b.Dx() > 0xffff || b.Dy() > 0xffff {
+		return errors.New("pcx: invalid image size")
+	}
+
+	p, paletted := img.(*image.Paletted)
+	paletted = paletted && len(p.Palette) <= 256
+
+	planes := 3
+	if paletted {
+		planes = 1
+	}
+
+	// Each plane of a line is padded to an even number of bytes.
+	bytesPerLine := (b.Dx() + 1) &^ 1
+
+	var header [128]byte
+	le := binary.LittleEndian
+	header[0] = 0x0a // ZSoft.
+	header[1] = 5    // Version 3.0 and later.
+	header[2] = 1    // Run length encoding.
+	header[3] = 8    // Bits per pixel per plane.
+	le.PutUint16(header[8:], uint16(b.Dx()-1))
+	le.PutUint16(header[10:], uint16(b.Dy()-1))
+	le.PutUint16(header[12:], 72)
+	le.PutUint16(header[14:], 72)
+	header[65] = byte(planes)
+	le.PutUint16(header[66:], uint16(bytesPerLine))
+	header[68] = 1 // Color palette.
+
+	bw := bufio.NewWriter(w)
+	bw.Write(header[:])
+
+	line := make([]byte, bytesPerLine)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for plane := 0; plane < planes; plane++ {
+			for x := 0; x < b.Dx(); x++ {
+				if paletted {
+					line[x] = p.ColorIndexAt(b.Min.X+x, y)
+					continue
+				}
+
+				c := color.NRGBAModel.Convert(img.At(b.Min.X+x, y)).(color.NRGBA)
+				line[x] = [3]uint8{c.R, c.G, c.B}[plane]
+			}
+			writePCXLine(bw, line)
+		}
+	}
+
+	if paletted {
+		var palette [1 + 768]byte
+		palette[0] = 0x0c
+		for i, c := range p.Palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			copy(palette[1+3*i:], []byte{n.R, n.G, n.B})
+		}
+		bw.Write(palette[:])
+	}
+
+	return bw.Flush()
+}
+
+// writePCXLine writes the run length encoding of line: runs of up to 63
+// equal bytes as a count byte with the top 2 bits set, then the byte. Single
+// bytes below 0xc0 are written as they are.
+func writePCXLine(w *bufio.Writer, line []byte) {
+	for i := 0; i < len(line); {
+		run := 1
+		for run < 63 && i+run < len(line) && line[i+run] == line[i] {
+			run++
+		}
+
+		if run > 1 || line[i] >= 0xc0 {
+			w.WriteByte(0xc0 | byte(run))
+		}
+		w.WriteByte(line[i])
+		i += run
+	}
+}
@@ -0,0 +1,115 @@
+//go:build legacyformats && !tinygo
+// +build legacyformats,!tinygo
+
+package legacy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	qrcode "github.com/yougg/go-qrcode"
+)
+
+// decodePCXLines returns the run length decoded scan lines of a PCX file.
+func decodePCXLines(t *testing.T, data []byte, n int) []byte {
+	var out []byte
+	for i := 128; len(out) < n; i++ {
+		if i >= len(data) {
+			t.Fatal("pcx: image data truncated")
+		}
+		if data[i] >= 0xc0 {
+			out = append(out, bytes.Repeat([]byte{data[i+1]}, int(data[i]&0x3f))...)
+			i++
+		} else {
+			out = append(out, data[i])
+		}
+	}
+	return out
+}
+
+func TestEncodePCXPaletted(t *testing.T) {
+	q, err := qrcode.New("https://example.org", qrcode.Margin(4), qrcode.Scale(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := q.Image().(*image.Paletted)
+	b := img.Bounds()
+
+	var buf bytes.Buffer
+	if err := EncodePCX(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	le := binary.LittleEndian
+	if data[0] != 0x0a || data[3] != 8 || data[65] != 1 {
+		t.Fatalf("invalid header % x", data[:70])
+	}
+	if w, h := int(le.Uint16(data[8:]))+1, int(le.Uint16(data[10:]))+1; w != b.Dx() || h != b.Dy() {
+		t.Fatalf("got %dx%d, expected %v", w, h, b.Size())
+	}
+
+	bytesPerLine := int(le.Uint16(data[66:]))
+	pixels := decodePCXLines(t, data, bytesPerLine*b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if got, expected := pixels[y*bytesPerLine+x], img.ColorIndexAt(x, y); got != expected {
+				t.Fatalf("pixel (%d, %d): got index %d, expected %d", x, y, got, expected)
+			}
+		}
+	}
+
+	palette := data[len(data)-769:]
+	if palette[0] != 0x0c || !bytes.Equal(palette[1:7], []byte{0xff, 0xff, 0xff, 0, 0, 0}) {
+		t.Errorf("got palette % x", palette[:7])
+	}
+}
+
+func TestEncodePCXTrueColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.NRGBA{0xd0, 0x10, 0x20, 0xff})
+	img.Set(2, 1, color.NRGBA{1, 2, 3, 0xff})
+
+	var buf bytes.Buffer
+	if err := EncodePCX(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if data[65] != 3 {
+		t.Fatalf("got %d planes, expected 3", data[65])
+	}
+
+	// Lines of 4 bytes: red, green then blue planes of each row.
+	pixels := decodePCXLines(t, data, 4*3*2)
+	expected := []byte{
+		0xd0, 0, 0, 0, 0x10, 0, 0, 0, 0x20, 0, 0, 0,
+		0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0,
+	}
+	if !bytes.Equal(pixels, expected) {
+		t.Errorf("got pixels % x, expected % x", pixels, expected)
+	}
+
+	if err := EncodePCX(&buf, image.NewNRGBA(image.Rectangle{})); err == nil {
+		t.Error("empty image encoded, expected an error")
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	q, err := qrcode.New("https://example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{"pcx", "tga"} {
+		data, err := q.Render(format, qrcode.RenderOptions{})
+		if err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		if len(data) == 0 {
+			t.Errorf("%s: empty output", format)
+		}
+	}
+}
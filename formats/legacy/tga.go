@@ -0,0 +1,47 @@
+//go:build legacyformats && !tinygo
+// +build legacyformats,!tinygo
+
+package legacy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+// EncodeTGA writes img to w in uncompressed 32 bit TGA (Truevision) format,
+// with an alpha channel, stored bottom up as most readers expect, and the
+// TGA 2.0 footer.
+func EncodeTGA(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 || b.Dx() > 0xffff || b.Dy() > 0xffff {
+		return errors.New("tga: invalid image size")
+	}
+
+	var header [18]byte
+	le := binary.LittleEndian
+	header[2] = 2 // Uncompressed true color.
+	le.PutUint16(header[12:], uint16(b.Dx()))
+	le.PutUint16(header[14:], uint16(b.Dy()))
+	header[16] = 32   // Bits per pixel.
+	header[17] = 0x08 // 8 alpha bits, bottom left origin.
+
+	bw := bufio.NewWriter(w)
+	bw.Write(header[:])
+
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			bw.Write([]byte{c.B, c.G, c.R, c.A})
+		}
+	}
+
+	// No extension or developer areas, then the signature.
+	bw.Write(make([]byte, 8))
+	bw.WriteString("TRUEVISION-XFILE.\x00")
+
+	return bw.Flush()
+}
@@ -0,0 +1,45 @@
+//go:build legacyformats && !tinygo
+// +build legacyformats,!tinygo
+
+package legacy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTGA(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 3))
+	img.Set(0, 0, color.NRGBA{1, 2, 3, 4})
+	img.Set(1, 2, color.NRGBA{5, 6, 7, 0xff})
+
+	var buf bytes.Buffer
+	if err := EncodeTGA(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	le := binary.LittleEndian
+	if data[2] != 2 || le.Uint16(data[12:]) != 2 || le.Uint16(data[14:]) != 3 || data[16] != 32 {
+		t.Fatalf("invalid header % x", data[:18])
+	}
+	if got, expected := len(data), 18+2*3*4+26; got != expected {
+		t.Fatalf("got %d bytes, expected %d", got, expected)
+	}
+	if !strings.HasSuffix(string(data), "TRUEVISION-XFILE.\x00") {
+		t.Error("missing TGA 2.0 footer")
+	}
+
+	// Bottom row first, BGRA.
+	pixels := data[18:]
+	if got := pixels[4:8]; !bytes.Equal(got, []byte{7, 6, 5, 0xff}) {
+		t.Errorf("got bottom right pixel % x", got)
+	}
+	if got := pixels[16:20]; !bytes.Equal(got, []byte{3, 2, 1, 4}) {
+		t.Errorf("got top left pixel % x", got)
+	}
+}
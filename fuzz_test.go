@@ -0,0 +1,103 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzNew checks that New never panics, whatever the content and options, and
+// that every QR Code it returns verifies and decodes to the original content.
+func FuzzNew(f *testing.F) {
+	f.Add("hello world", int(Medium), 4, 0, 0)
+	f.Add("", int(Highest), 0, 40, 2)
+	f.Add("0123456789", int(Low), 1, 7, 0)
+	f.Add("HELLO WORLD $%*+-./:", int(High), 4, 10, 0)
+	f.Add("\xff\x00\xfe", -1, 4, 0, 0)
+	f.Add("invalid options", 9, -1, 41, -4)
+
+	f.Fuzz(func(t *testing.T, content string, level, margin, minVersion, quietZone int) {
+		if margin > 64 || quietZone > 64 {
+			t.Skip("margin too large to allocate")
+		}
+
+		q, err := New(content,
+			Level(RecoveryLevel(level)),
+			Margin(margin),
+			MinVersion(minVersion),
+			func(q *QRCode) { q.QuitZoneSize = quietZone })
+		if err != nil {
+			return
+		}
+
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatalf("VerifyBitmap(%q) failed: %s", content, err)
+		}
+		if !bytes.Equal(info.Content, []byte(content)) {
+			t.Errorf("decoded %q, expected %q", info.Content, content)
+		}
+	})
+}
+
+// FuzzNewSegments checks that NewSegments never panics on arbitrary segments.
+func FuzzNewSegments(f *testing.F) {
+	f.Add(int(Numeric), "0123", int(Alphanumeric), "AB:")
+	f.Add(int(Kanji), "\x93\x5f", int(Byte), "\xff")
+	f.Add(0, "", 9, "x")
+
+	f.Fuzz(func(t *testing.T, mode1 int, data1 string, mode2 int, data2 string) {
+		q, err := NewSegments([]Segment{
+			{Mode: Mode(mode1), Data: data1},
+			{Mode: Mode(mode2), Data: data2},
+		})
+		if err != nil {
+			return
+		}
+
+		if _, err := VerifyBitmap(q.Bitmap()); err != nil {
+			t.Fatalf("VerifyBitmap failed: %s", err)
+		}
+	})
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"level", []Option{Level(RecoveryLevel(9))}},
+		{"negative level", []Option{Level(RecoveryLevel(-1))}},
+		{"margin", []Option{Margin(-1)}},
+		{"quiet zone", []Option{func(q *QRCode) { q.QuitZoneSize = -2 }}},
+		{"min version", []Option{MinVersion(41)}},
+	}
+
+	for _, test := range tests {
+		if _, err := New("hello", test.opts...); err == nil {
+			t.Errorf("%s: New succeeded, expected error", test.name)
+		}
+	}
+}
+
+func TestBuildRegularSymbolInvalid(t *testing.T) {
+	v := *getQRCodeVersion(Low, 1)
+	data := newDataEncoder(dataEncoderType1To9)
+
+	encoded, err := data.encode([]byte("A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := buildRegularSymbol(v, 8, encoded, 4); err == nil {
+		t.Error("mask 8 accepted, expected error")
+	}
+
+	// Data longer than the symbol capacity.
+	encoded.AppendNumBools(v.numDataBits()*2, false)
+	if _, err := buildRegularSymbol(v, 0, encoded, 4); err == nil {
+		t.Error("oversized data accepted, expected error")
+	}
+}
@@ -0,0 +1,157 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Item is one code generated by GenerateAll.
+type Item struct {
+	// Content to encode.
+	Content string
+
+	// Filename the PNG image is written to, or the object name with
+	// GenerateAllTo.
+	Filename string
+
+	// Options passed to New.
+	Options []Option
+}
+
+// ItemError is the error for one Item of a GenerateAll batch.
+type ItemError struct {
+	// Index of the item in the batch.
+	Index int
+
+	Filename string
+	Err      error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d (%s): %s", e.Index, e.Filename, e.Err.Error())
+}
+
+// BatchError collects the errors of a GenerateAll batch, ordered by item
+// index.
+type BatchError struct {
+	Errors []*ItemError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d items failed, first: %s", len(e.Errors), e.Errors[0].Error())
+}
+
+// GenerateAll renders and writes every item in parallel, using at most
+// workers goroutines (runtime.NumCPU() if workers <= 0). Only one image per
+// worker is held in memory at a time, so very large catalogs can be generated.
+//
+// onProgress, if not nil, is called after each item with the number of items
+// done so far. Calls are never concurrent.
+//
+// Every item is attempted. If any fail, a *BatchError describing each failure
+// is returned.
+func GenerateAll(items []Item, workers int, onProgress func(done, total int)) error {
+	return generateAll(nil, items, workers, onProgress, false)
+}
+
+// GenerateAllTo is like GenerateAll, but writes each item to sink as the
+// object named by its Filename, in the format of its extension, see
+// WriteToSink.
+func GenerateAllTo(sink Sink, items []Item, workers int, onProgress func(done, total int)) error {
+	return generateAll(sink, items, workers, onProgress, false)
+}
+
+// GenerateAllFailFast is like GenerateAll, but stops starting new items after
+// the first failure. Items already in progress are finished.
+func GenerateAllFailFast(items []Item, workers int, onProgress func(done, total int)) error {
+	return generateAll(nil, items, workers, onProgress, true)
+}
+
+func generateAll(sink Sink, items []Item, workers int, onProgress func(done, total int), failFast bool) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(items))
+
+	var (
+		mu     sync.Mutex
+		done   int
+		failed bool
+		errs   []*ItemError
+		wg     sync.WaitGroup
+	)
+
+	next := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				err := generateItem(sink, &items[i])
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, &ItemError{Index: i, Filename: items[i].Filename, Err: err})
+					failed = true
+				}
+				done++
+				if onProgress != nil {
+					onProgress(done, len(items))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range items {
+		if failFast {
+			mu.Lock()
+			stop := failed
+			mu.Unlock()
+			if stop {
+				break
+			}
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	// Workers finish out of order.
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+
+	return &BatchError{Errors: errs}
+}
+
+// generateItem renders item and writes it to sink, or to its file if sink is
+// nil.
+func generateItem(sink Sink, item *Item) error {
+	if item.Filename == "" {
+		return errors.New("no filename")
+	}
+
+	q, err := New(item.Content, item.Options...)
+	if err != nil {
+		return err
+	}
+
+	if sink != nil {
+		return q.WriteToSink(sink, item.Filename)
+	}
+
+	return q.WriteFile(item.Filename)
+}
@@ -0,0 +1,117 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateAll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	var items []Item
+	for i := 0; i < 20; i++ {
+		items = append(items, Item{
+			Content:  fmt.Sprintf("https://example.org/sku/%d", i),
+			Filename: filepath.Join(dir, fmt.Sprintf("%d.png", i)),
+			Options:  []Option{Width(64), Height(64)},
+		})
+	}
+
+	var calls []int
+	err = GenerateAll(items, 4, func(done, total int) {
+		if total != len(items) {
+			t.Errorf("total is %d, expected %d", total, len(items))
+		}
+		calls = append(calls, done)
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for i, done := range calls {
+		if done != i+1 {
+			t.Fatalf("progress calls %v, expected 1 to %d", calls, len(items))
+		}
+	}
+	if len(calls) != len(items) {
+		t.Errorf("got %d progress calls, expected %d", len(calls), len(items))
+	}
+
+	for _, item := range items {
+		data, err := ioutil.ReadFile(item.Filename)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !strings.HasPrefix(string(data), pngSignature) {
+			t.Errorf("%s is not a PNG", item.Filename)
+		}
+	}
+}
+
+func TestGenerateAllErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	items := []Item{
+		{Content: "ok", Filename: filepath.Join(dir, "0.png")},
+		{Content: strings.Repeat("#", 3000), Filename: filepath.Join(dir, "1.png")},
+		{Content: "ok", Filename: filepath.Join(dir, "2.png")},
+		{Content: "no filename"},
+		{Content: "ok", Filename: filepath.Join(dir, "4.png")},
+	}
+
+	err = GenerateAll(items, 2, nil)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("got error %v, expected a *BatchError", err)
+	}
+
+	if len(batchErr.Errors) != 2 || batchErr.Errors[0].Index != 1 || batchErr.Errors[1].Index != 3 {
+		t.Errorf("got %s, expected items 1 and 3 to fail", err.Error())
+	}
+
+	for _, i := range []int{0, 2, 4} {
+		if _, err := os.Stat(items[i].Filename); err != nil {
+			t.Errorf("item %d not written: %s", i, err.Error())
+		}
+	}
+}
+
+func TestGenerateAllFailFast(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	items := []Item{{Content: "no filename"}}
+	for i := 0; i < 50; i++ {
+		items = append(items, Item{Content: "ok", Filename: filepath.Join(dir, fmt.Sprintf("%d.png", i))})
+	}
+
+	done := 0
+	err = GenerateAllFailFast(items, 1, func(d, total int) { done = d })
+	if err == nil {
+		t.Fatal("GenerateAllFailFast succeeded, expected error")
+	}
+
+	// With one worker, at most one more item is handed out before the
+	// failure is seen.
+	if done > 2 {
+		t.Errorf("%d items done, expected generation to stop", done)
+	}
+}
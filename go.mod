@@ -1,6 +1,5 @@
 module github.com/yougg/go-qrcode
 
-require (
-	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646
-	golang.org/x/image v0.0.0-20180926015637-991ec62608f3
-)
+go 1.24
+
+require golang.org/x/image v0.0.0-20180926015637-991ec62608f3
@@ -0,0 +1,115 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// Heatmap colors, translucent so the modules show through.
+var (
+	// Function patterns, format and version information: covering any of
+	// them can stop the code being found or decoded at all.
+	heatmapFunction = color.NRGBA{0xd0, 0x10, 0x10, 0xb0}
+	// The 4 module quiet zone ISO/IEC 18004 requires to be kept clear.
+	heatmapQuietZone = color.NRGBA{0x80, 0x80, 0x80, 0x80}
+	// Data codewords: damage is corrected up to the recovery level, but
+	// costs the content itself.
+	heatmapData = color.NRGBA{0xff, 0x98, 0x00, 0x90}
+	// Error correction codewords: damage is corrected up to the recovery
+	// level.
+	heatmapECC = color.NRGBA{0x20, 0xb0, 0x40, 0x90}
+	// Remainder bits, which are not read: safe to cover.
+	heatmapRemainder = color.NRGBA{0x20, 0x60, 0xff, 0x90}
+)
+
+// HeatmapOverlay returns a translucent overlay of the image drawn by Image()
+// (without a caption or transform), tinting each module by the damage it can
+// sustain, to help designers place logos:
+//
+//   - red: function patterns and format and version information, which must
+//     not be covered
+//   - grey: the 4 module quiet zone, which must be kept clear
+//   - orange: data codewords and green: error correction codewords, which
+//     tolerate damage to as many codewords per block as the recovery level
+//     allows, see Stats.CorrectableCodewords
+//   - blue: remainder bits, which are not read and are safe to cover
+//
+// The "heatmap" Renderer writes the image with the overlay drawn over it as a
+// PNG image.
+func (q *QRCode) HeatmapOverlay() *image.NRGBA {
+	c := *q
+	c.caption, c.captionContent = "", false
+	c.transform = nil
+	bounds := c.Image().Bounds()
+
+	overlay := image.NewNRGBA(bounds)
+	symbolRect := q.symbolRect(bounds)
+	n := q.version.symbolSize()
+	moduleX, moduleY := symbolRect.Dx()/n, symbolRect.Dy()/n
+
+	module := func(x, y int) image.Rectangle {
+		return image.Rect(x*moduleX, y*moduleY, (x+1)*moduleX, (y+1)*moduleY).Add(symbolRect.Min)
+	}
+	fill := func(r image.Rectangle, c color.Color) {
+		draw.Draw(overlay, r.Intersect(bounds), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	quietZone := image.Rectangle{Min: module(-4, -4).Min, Max: module(n+3, n+3).Max}
+	fill(quietZone, heatmapQuietZone)
+	fill(symbolRect, color.Transparent)
+
+	qz := q.symbol.quietZoneSize
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if q.pointType(x+qz, y+qz) != DataPoint {
+				fill(module(x, y), heatmapFunction)
+			}
+		}
+	}
+
+	numData := 0
+	for _, b := range q.version.block {
+		numData += b.numBlocks * b.numDataCodewords
+	}
+	numCodewords := len(q.Codewords())
+
+	top, _, _, left := q.quietZoneExtra()
+	for i, p := range q.dataModules() {
+		c := heatmapECC
+		switch {
+		case i >= numCodewords*8:
+			c = heatmapRemainder
+		case i < numData*8:
+			c = heatmapData
+		}
+		fill(module(p.X-qz-left, p.Y-qz-top), c)
+	}
+
+	return overlay
+}
+
+// renderHeatmap is the heatmap Renderer: the image drawn by Image(), without
+// a caption or transform, with the HeatmapOverlay drawn over it, as a PNG
+// image.
+func renderHeatmap(sym SymbolView, opts RenderOptions) ([]byte, error) {
+	c := *sym.q
+	c.caption, c.captionContent = "", false
+	c.transform = nil
+
+	src := c.Image()
+	img := image.NewNRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	draw.Draw(img, img.Bounds(), sym.q.HeatmapOverlay(), img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestHeatmapOverlay(t *testing.T) {
+	// Version 2 has 7 remainder bits.
+	q, err := New("https://example.org", Level(Medium), MinVersion(2), Margin(4), Scale(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overlay := q.HeatmapOverlay()
+	if got, expected := overlay.Bounds(), q.Image().Bounds(); got != expected {
+		t.Fatalf("got overlay bounds %v, expected %v", got, expected)
+	}
+
+	// at returns the overlay color of the center of module p of the Bitmap.
+	at := func(p image.Point) color.NRGBA {
+		return overlay.NRGBAAt(p.X*4+2, p.Y*4+2)
+	}
+
+	modules := q.dataModules()
+	numCodewords := len(q.Codewords())
+	tests := []struct {
+		name     string
+		module   image.Point
+		expected color.NRGBA
+	}{
+		{"quiet zone", image.Pt(0, 0), heatmapQuietZone},
+		{"finder pattern", image.Pt(4, 4), heatmapFunction},
+		{"timing pattern", image.Pt(12, 10), heatmapFunction},
+		{"first data codeword", modules[0], heatmapData},
+		{"last error correction codeword", modules[numCodewords*8-1], heatmapECC},
+		{"remainder bit", modules[len(modules)-1], heatmapRemainder},
+	}
+	for _, test := range tests {
+		if got := at(test.module); got != test.expected {
+			t.Errorf("%s at %v: got %v, expected %v", test.name, test.module, got, test.expected)
+		}
+	}
+
+	b, err := q.Render("heatmap", RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != overlay.Bounds() {
+		t.Errorf("got heatmap bounds %v, expected %v", img.Bounds(), overlay.Bounds())
+	}
+}
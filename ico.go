@@ -0,0 +1,87 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// icoSizes are the image sizes ICO bundles by default.
+var icoSizes = []int{16, 32, 64}
+
+// ICO returns the QR Code as a Windows icon (favicon) containing a PNG
+// image of each size in pixels, 16, 32 and 64 by default. Sizes must be 1-256
+// inclusive.
+//
+// Small sizes are downscaled from the smallest image with whole modules, and
+// are typically too small to scan; they identify the code at a glance.
+func (q *QRCode) ICO(sizes ...int) ([]byte, error) {
+	start := time.Now()
+
+	if len(sizes) == 0 {
+		sizes = icoSizes
+	}
+
+	images := make([][]byte, len(sizes))
+	for i, size := range sizes {
+		if size < 1 || size > 256 {
+			return nil, fmt.Errorf("ico: invalid size %d (expected 1-256 inclusive)", size)
+		}
+
+		c := *q
+		c.scale = 0
+		c.width, c.height = size, size
+
+		var img image.Image = c.Image()
+		if img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+			if q.noSmoothing {
+				img = scaleNearest(img, size, size)
+			} else {
+				scaled := image.NewRGBA(image.Rect(0, 0, size, size))
+				draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+				img = scaled
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		images[i] = buf.Bytes()
+	}
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	// ICONDIR header: reserved, type 1 (icon), number of images.
+	binary.Write(&buf, le, [3]uint16{0, 1, uint16(len(sizes))})
+
+	// ICONDIRENTRY for each image, then the images.
+	offset := 6 + 16*len(sizes)
+	for i, size := range sizes {
+		// A width and height of 0 means 256.
+		dim := uint8(size % 256)
+		buf.Write([]byte{dim, dim, 0, 0})
+		binary.Write(&buf, le, [2]uint16{1, 32})
+		binary.Write(&buf, le, [2]uint32{uint32(len(images[i])), uint32(offset)})
+
+		offset += len(images[i])
+	}
+	for _, img := range images {
+		buf.Write(img)
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("ico", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+	"testing"
+)
+
+func TestICO(t *testing.T) {
+	q, err := New("icon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sizes := range [][]int{nil, {48, 256}} {
+		data, err := q.ICO(sizes...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := sizes
+		if expected == nil {
+			expected = []int{16, 32, 64}
+		}
+
+		le := binary.LittleEndian
+		if le.Uint16(data[0:]) != 0 || le.Uint16(data[2:]) != 1 || int(le.Uint16(data[4:])) != len(expected) {
+			t.Fatalf("bad ICONDIR header % x", data[:6])
+		}
+
+		for i, size := range expected {
+			entry := data[6+16*i:]
+
+			dim := int(entry[0])
+			if dim == 0 {
+				dim = 256
+			}
+			if dim != size || entry[1] != entry[0] {
+				t.Errorf("entry %d: got %dx%d, expected %d", i, entry[0], entry[1], size)
+			}
+
+			length := le.Uint32(entry[8:])
+			offset := le.Uint32(entry[12:])
+			img, err := png.Decode(bytes.NewReader(data[offset : offset+length]))
+			if err != nil {
+				t.Fatalf("entry %d: %s", i, err)
+			}
+			if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+				t.Errorf("entry %d: image is %v, expected %d", i, b.Size(), size)
+			}
+		}
+	}
+
+	if _, err := q.ICO(257); err == nil {
+		t.Error("ICO succeeded with size 257, expected error")
+	}
+}
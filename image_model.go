@@ -0,0 +1,41 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/draw"
+)
+
+// ImageRGBA returns Image() as an *image.RGBA, ready for compositing with
+// draw.Draw without a per-pixel color model conversion.
+func (q *QRCode) ImageRGBA() *image.RGBA {
+	src := q.Image()
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	return img
+}
+
+// ImageNRGBA returns Image() as an *image.NRGBA, with non-premultiplied alpha.
+func (q *QRCode) ImageNRGBA() *image.NRGBA {
+	src := q.Image()
+	img := image.NewNRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	return img
+}
+
+// ImageGray returns Image() as an *image.Gray. Colors are converted to their
+// luminance, and alpha is discarded.
+func (q *QRCode) ImageGray() *image.Gray {
+	src := q.Image()
+	img := image.NewGray(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	return img
+}
@@ -0,0 +1,54 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageColorModels(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	q, err := New("color models", Width(80), Height(80), ForegroundColor(red))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := q.Image()
+
+	images := []struct {
+		name  string
+		img   image.Image
+		model color.Model
+	}{
+		{"RGBA", q.ImageRGBA(), color.RGBAModel},
+		{"NRGBA", q.ImageNRGBA(), color.NRGBAModel},
+		{"Gray", q.ImageGray(), color.GrayModel},
+	}
+
+	for _, test := range images {
+		if test.img.Bounds() != src.Bounds() {
+			t.Errorf("%s: got bounds %v, expected %v", test.name, test.img.Bounds(), src.Bounds())
+			continue
+		}
+		if test.img.ColorModel() != test.model {
+			t.Errorf("%s: unexpected color model", test.name)
+		}
+
+		b := src.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				expected := test.model.Convert(src.At(x, y))
+				if got := test.img.At(x, y); got != expected {
+					t.Fatalf("%s: pixel (%d, %d) is %v, expected %v", test.name, x, y, got, expected)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lintMaxURLLength is the URL length above which Lint suggests a shorter URL.
+// Longer URLs need large, dense symbols which are slow and unreliable to scan
+// from print.
+const lintMaxURLLength = 200
+
+// Warning is a possible problem with content, found by Lint.
+type Warning struct {
+	// Short identifier of the check, e.g. "trailing-space".
+	Code string
+
+	// Human readable description.
+	Message string
+}
+
+// String returns the warning as "code: message".
+func (w Warning) String() string {
+	return w.Code + ": " + w.Message
+}
+
+// Lint checks content for common mistakes which make QR Codes decode to
+// something other than intended, or fail to scan. The content can still be
+// encoded; the warnings are advisory.
+func Lint(content string) []Warning {
+	var warnings []Warning
+	warn := func(code, format string, a ...interface{}) {
+		warnings = append(warnings, Warning{Code: code, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if content == "" {
+		warn("empty", "content is empty")
+		return warnings
+	}
+
+	if trimmed := strings.TrimLeftFunc(content, unicode.IsSpace); trimmed != content {
+		warn("leading-space", "content starts with white space")
+	}
+	if trimmed := strings.TrimRightFunc(content, unicode.IsSpace); trimmed != content {
+		warn("trailing-space", "content ends with white space")
+	}
+
+	if !utf8.ValidString(content) {
+		warn("invalid-utf8", "content is not valid UTF-8")
+	}
+
+	nonASCII := false
+	for i, r := range content {
+		if r >= utf8.RuneSelf {
+			nonASCII = true
+		} else if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			warn("control-character", "control character %U at byte %d", r, i)
+		}
+	}
+	if nonASCII {
+		warn("non-ascii", "non-ASCII characters are encoded as UTF-8 without an ECI designator; some readers assume ISO-8859-1")
+	}
+
+	lower := strings.ToLower(content)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		if len(content) > lintMaxURLLength {
+			warn("long-url", "URL is %d bytes long; consider a shorter URL (at most %d)", len(content), lintMaxURLLength)
+		}
+	}
+
+	if strings.HasPrefix(lower, "wifi:") {
+		lintWiFi(content, warn)
+	}
+
+	return warnings
+}
+
+// lintWiFi checks a "WIFI:T:WPA;S:name;P:password;;" network configuration.
+// The prefix and field keys are case sensitive for many readers.
+func lintWiFi(content string, warn func(code, format string, a ...interface{})) {
+	if !strings.HasPrefix(content, "WIFI:") {
+		warn("wifi-case", "prefix %q must be upper case \"WIFI:\"", content[:5])
+	}
+
+	if !strings.HasSuffix(content, ";;") {
+		warn("wifi-terminator", "WIFI: configuration should end with \";;\"")
+	}
+
+	for _, field := range splitUnescaped(content[5:], ';') {
+		i := strings.IndexByte(field, ':')
+		if i < 0 {
+			continue
+		}
+
+		key := field[:i]
+		switch strings.ToUpper(key) {
+		case "T", "S", "P", "H":
+			if key != strings.ToUpper(key) {
+				warn("wifi-key-case", "WIFI: key %q must be upper case %q", key, strings.ToUpper(key))
+			}
+		}
+	}
+}
+
+// splitUnescaped splits s at each sep not preceded by a backslash.
+func splitUnescaped(s string, sep byte) []string {
+	var fields []string
+
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(fields, s[start:])
+}
@@ -0,0 +1,45 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package qrcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"https://example.org", nil},
+		{"WIFI:T:WPA;S:home;P:secret;;", nil},
+		{"", []string{"empty"}},
+		{" hello ", []string{"leading-space", "trailing-space"}},
+		{"hello\n", []string{"trailing-space"}},
+		{"bell\x07", []string{"control-character"}},
+		{"\xff", []string{"invalid-utf8", "non-ascii"}},
+		{"café", []string{"non-ascii"}},
+		{"https://example.org/" + strings.Repeat("a", 200), []string{"long-url"}},
+		{"wifi:T:WPA;S:home;;", []string{"wifi-case"}},
+		{"WIFI:t:WPA;s:home;P:a\\;b;;", []string{"wifi-key-case", "wifi-key-case"}},
+		{"WIFI:T:WPA;S:home", []string{"wifi-terminator"}},
+	}
+
+	for _, test := range tests {
+		var codes []string
+		for _, w := range Lint(test.content) {
+			codes = append(codes, w.Code)
+
+			if w.Message == "" {
+				t.Errorf("Lint(%q): warning %s has no message", test.content, w.Code)
+			}
+		}
+
+		if !reflect.DeepEqual(codes, test.expected) {
+			t.Errorf("Lint(%q) = %v, expected %v", test.content, codes, test.expected)
+		}
+	}
+}
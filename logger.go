@@ -0,0 +1,25 @@
+package qrcode
+
+// Logger receives debug events from a QRCode: the version chosen, the penalty
+// score of each data mask and timings. Events are a message followed by
+// alternating key and value pairs, so a *log/slog.Logger can be used
+// directly. See Logging.
+//
+// The package never writes to the standard log package itself.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// Logging reports debug events to l.
+func Logging(l Logger) Option {
+	return func(q *QRCode) {
+		q.logger = l
+	}
+}
+
+// debug reports a debug event to the Logger, if any.
+func (q *QRCode) debug(msg string, args ...interface{}) {
+	if q.logger != nil {
+		q.logger.Debug(msg, args...)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.messages = append(l.messages, msg)
+}
+
+func TestLogging(t *testing.T) {
+	l := &recordingLogger{}
+	if _, err := New("https://example.org", Logging(l)); err != nil {
+		t.Fatal(err)
+	}
+
+	count := map[string]int{}
+	for _, msg := range l.messages {
+		count[msg]++
+	}
+	if count["version chosen"] != 1 || count["mask evaluated"] != 8 || count["mask chosen"] != 1 || count["encoded"] != 1 {
+		t.Errorf("got events %v", count)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	q, err := New("https://example.org", Logging(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "msg=\"version chosen\" version=") {
+		t.Errorf("slog output missing version event:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "msg=\"mask chosen\" mask=") || q.mask < 0 {
+		t.Errorf("slog output missing mask event:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,387 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// logoMaxRatio is the largest fraction of the image width or height a logo
+// may cover. Larger logos are scaled down to fit.
+const logoMaxRatio = 0.2
+
+// cornerLogoInset is the distance in modules of a corner logo from the edges
+// of the symbol: a 7 module finder pattern, its separator and the format
+// information.
+const cornerLogoInset = 9
+
+// LogoPosition is where a logo is placed on the QR Code.
+type LogoPosition int
+
+const (
+	// Over the center of the symbol.
+	LogoCenter LogoPosition = iota
+
+	// In a corner of the symbol, inset by cornerLogoInset modules to keep
+	// the finder patterns, their separators and the format information
+	// clear.
+	LogoTopLeft
+	LogoTopRight
+	LogoBottomLeft
+	LogoBottomRight
+
+	// At the point given to LogoPoint.
+	LogoCustom
+)
+
+// LogoShape is the clipping mask applied to a logo, its padding and border.
+type LogoShape int
+
+const (
+	// No clipping.
+	LogoSquare LogoShape = iota
+
+	// A circle (or ellipse, for logos which are not square).
+	LogoCircle
+
+	// A rectangle with rounded corners, see LogoCornerRadius.
+	LogoRoundedRect
+)
+
+// logoStyle holds the settings for drawing a logo.
+type logoStyle struct {
+	position LogoPosition
+	point    image.Point
+
+	shape        LogoShape
+	cornerRadius int
+
+	borderWidth int
+	borderColor color.Color
+
+	padding      int
+	paddingColor color.Color
+
+	// see LogoAvoidPatterns and LogoStrict.
+	avoid  bool
+	strict bool
+}
+
+// LogoOption configures the placement and style of a logo added with AddLogo.
+type LogoOption func(l *logoStyle)
+
+// LogoAt places the logo at position p. The default is LogoCenter.
+func LogoAt(p LogoPosition) LogoOption {
+	return func(l *logoStyle) {
+		l.position = p
+	}
+}
+
+// LogoPoint places the top left corner of the logo (including padding and
+// border) at pixel p of the image.
+func LogoPoint(p image.Point) LogoOption {
+	return func(l *logoStyle) {
+		l.position = LogoCustom
+		l.point = p
+	}
+}
+
+// LogoMask clips the logo, padding and border to shape s.
+func LogoMask(s LogoShape) LogoOption {
+	return func(l *logoStyle) {
+		l.shape = s
+	}
+}
+
+// LogoCornerRadius sets the corner radius in pixels used by LogoRoundedRect,
+// and selects that shape.
+func LogoCornerRadius(r int) LogoOption {
+	return func(l *logoStyle) {
+		l.shape = LogoRoundedRect
+		l.cornerRadius = r
+	}
+}
+
+// LogoBorder draws a border of width pixels in color c around the logo.
+func LogoBorder(width int, c color.Color) LogoOption {
+	return func(l *logoStyle) {
+		l.borderWidth = width
+		l.borderColor = c
+	}
+}
+
+// LogoPadding surrounds the logo with width pixels of space in color c. A nil
+// color uses the QR Code's background color.
+func LogoPadding(width int, c color.Color) LogoOption {
+	return func(l *logoStyle) {
+		l.padding = width
+		l.paddingColor = c
+	}
+}
+
+// LogoAvoidPatterns moves the logo to the nearest position where it covers no
+// function patterns (see PointType), shrinking it if there is no such
+// position. Only data modules are then lost under the logo.
+func LogoAvoidPatterns() LogoOption {
+	return func(l *logoStyle) {
+		l.avoid = true
+	}
+}
+
+// LogoStrict makes PlaceLogo return an error instead of covering a function
+// pattern. With LogoAvoidPatterns, the error is returned only if no position
+// and size is found.
+func LogoStrict() LogoOption {
+	return func(l *logoStyle) {
+		l.strict = true
+	}
+}
+
+// AddLogo returns the QR Code image with logo drawn over it.
+//
+// The logo keeps its own size and aspect ratio, and is only scaled down if it
+// is larger than logoMaxRatio of the QR Code. Transparent areas of the logo
+// let the QR Code show through. By default the logo is placed over the center
+// of the symbol, unclipped and without any border or padding.
+//
+// Covering modules relies on the error recovery capacity of the QR Code, so a
+// High or Highest recovery level is recommended.
+//
+// AddLogo is PlaceLogo without error reporting: if the logo cannot be placed
+// under LogoStrict, the image is returned without it.
+func (q *QRCode) AddLogo(logo image.Image, opts ...LogoOption) *image.RGBA {
+	img, err := q.PlaceLogo(logo, opts...)
+	if err != nil {
+		return q.ImageRGBA()
+	}
+
+	return img
+}
+
+// PlaceLogo is like AddLogo, but returns an error if the logo would cover a
+// function pattern and LogoStrict is given.
+func (q *QRCode) PlaceLogo(logo image.Image, opts ...LogoOption) (*image.RGBA, error) {
+	img := q.ImageRGBA()
+
+	if err := q.DrawLogo(img, logo, opts...); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// DrawLogo draws logo over dst, which is an image of the QR Code of any color
+// model, e.g. from Image, ImageRGBA or an artistic render. The symbol is
+// assumed to be centered in dst.Bounds(), as Image draws it.
+//
+// The logo is composited with draw.Over, so a transparent dst stays
+// transparent around the logo. dst is left unchanged if an error is returned.
+func (q *QRCode) DrawLogo(dst draw.Image, logo image.Image, opts ...LogoOption) error {
+	var l logoStyle
+	for _, opt := range opts {
+		opt(&l)
+	}
+	if l.paddingColor == nil {
+		l.paddingColor = q.BackgroundColor
+	}
+	if l.borderColor == nil {
+		l.borderColor = q.ForegroundColor
+	}
+
+	bounds := dst.Bounds()
+
+	maxWidth := int(float64(bounds.Dx()) * logoMaxRatio)
+	maxHeight := int(float64(bounds.Dy()) * logoMaxRatio)
+	logo = fitLogo(logo, maxWidth, maxHeight, !q.noSmoothing)
+
+	inset := l.borderWidth + l.padding
+	badge := image.Rect(0, 0, logo.Bounds().Dx()+2*inset, logo.Bounds().Dy()+2*inset)
+	symbolRect := q.symbolRect(bounds)
+	modules := q.symbol.symbolSize
+	corner := image.Pt(cornerLogoInset*symbolRect.Dx()/modules, cornerLogoInset*symbolRect.Dy()/modules)
+	badge = badge.Add(l.origin(symbolRect, badge.Size(), corner))
+
+	if l.avoid || l.strict {
+		p := q.newPatternMap(bounds)
+
+		if l.avoid && p.covers(badge) {
+			logo, badge = p.avoid(logo, badge, inset, !q.noSmoothing)
+		}
+		if l.strict && p.covers(badge) {
+			return errors.New("logo covers a function pattern")
+		}
+	}
+
+	if l.borderWidth > 0 {
+		mask := &shapeMask{badge, l.shape, l.cornerRadius}
+		draw.DrawMask(dst, badge, image.NewUniform(l.borderColor), image.ZP, mask, badge.Min, draw.Over)
+	}
+
+	padded := badge.Inset(l.borderWidth)
+	if l.padding > 0 {
+		mask := &shapeMask{padded, l.shape, l.cornerRadius - l.borderWidth}
+		draw.DrawMask(dst, padded, image.NewUniform(l.paddingColor), image.ZP, mask, padded.Min, draw.Over)
+	}
+
+	inner := badge.Inset(inset)
+	mask := &shapeMask{inner, l.shape, l.cornerRadius - inset}
+	draw.DrawMask(dst, inner, logo, logo.Bounds().Min, mask, inner.Min, draw.Over)
+
+	return nil
+}
+
+// origin returns the top left corner of a logo of the given size placed on
+// the symbol occupying symbolRect. Corner logos are inset from the corners by
+// corner pixels.
+func (l *logoStyle) origin(symbolRect image.Rectangle, size, corner image.Point) image.Point {
+	inner := image.Rectangle{symbolRect.Min.Add(corner), symbolRect.Max.Sub(corner)}
+
+	switch l.position {
+	case LogoTopLeft:
+		return inner.Min
+	case LogoTopRight:
+		return image.Pt(inner.Max.X-size.X, inner.Min.Y)
+	case LogoBottomLeft:
+		return image.Pt(inner.Min.X, inner.Max.Y-size.Y)
+	case LogoBottomRight:
+		return inner.Max.Sub(size)
+	case LogoCustom:
+		return l.point
+	}
+
+	return image.Pt(symbolRect.Min.X+(symbolRect.Dx()-size.X)/2,
+		symbolRect.Min.Y+(symbolRect.Dy()-size.Y)/2)
+}
+
+// symbolRect returns the area of an image with bounds r, as produced by
+// Image(), which is covered by the symbol. The quiet zone is excluded.
+//
+// Images of the size Image() draws, with or without the Caption band, use
+// its layout. Others, such as artistic renders, are taken to be the symbol
+// and quiet zone scaled to fill r.
+func (q *QRCode) symbolRect(r image.Rectangle) image.Rectangle {
+	realSize := q.symbol.size
+	quietZoneSize := q.symbol.quietZoneSize
+
+	// Deeper edges of QuietZone are whole modules outside the centered
+	// symbol, so the module size is unchanged by them.
+	top, right, bottom, left := q.quietZoneExtra()
+
+	pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY, width, height := q.layout()
+	width += (left + right) * pixelsPerModuleX
+	height += (top + bottom) * pixelsPerModuleY
+	if r.Dx() == width && (r.Dy() == height || r.Dy() == height+q.captionHeight(width)) {
+		offsetX += r.Min.X + left*pixelsPerModuleX
+		offsetY += r.Min.Y + top*pixelsPerModuleY
+	} else {
+		modulesX := realSize + left + right
+		modulesY := realSize + top + bottom
+
+		pixelsPerModuleX = r.Dx() / modulesX
+		pixelsPerModuleY = r.Dy() / modulesY
+
+		offsetX = r.Min.X + (r.Dx()-modulesX*pixelsPerModuleX)/2 + left*pixelsPerModuleX
+		offsetY = r.Min.Y + (r.Dy()-modulesY*pixelsPerModuleY)/2 + top*pixelsPerModuleY
+	}
+
+	return image.Rect(
+		offsetX+quietZoneSize*pixelsPerModuleX,
+		offsetY+quietZoneSize*pixelsPerModuleY,
+		offsetX+(realSize-quietZoneSize)*pixelsPerModuleX,
+		offsetY+(realSize-quietZoneSize)*pixelsPerModuleY,
+	)
+}
+
+// fitLogo returns logo scaled down to fit within maxWidth x maxHeight pixels,
+// preserving its aspect ratio. Logos which already fit are returned unchanged.
+//
+// Paletted logos, typically line art or pixel art, are scaled with
+// scaleNearest to keep their edges sharp. Others are smoothed if smooth is
+// true.
+func fitLogo(logo image.Image, maxWidth, maxHeight int, smooth bool) image.Image {
+	w, h := logo.Bounds().Dx(), logo.Bounds().Dy()
+	if w <= maxWidth && h <= maxHeight {
+		return logo
+	}
+
+	ratio := math.Min(float64(maxWidth)/float64(w), float64(maxHeight)/float64(h))
+	fitWidth := max(int(float64(w)*ratio), 1)
+	fitHeight := max(int(float64(h)*ratio), 1)
+
+	if _, ok := logo.(*image.Paletted); ok || !smooth {
+		return scaleNearest(logo, fitWidth, fitHeight)
+	}
+
+	fit := image.NewRGBA(image.Rect(0, 0, fitWidth, fitHeight))
+	draw.CatmullRom.Scale(fit, fit.Bounds(), logo, logo.Bounds(), draw.Src, nil)
+
+	return fit
+}
+
+// shapeMask is an alpha mask which is opaque inside a shape fitted to rect.
+type shapeMask struct {
+	rect   image.Rectangle
+	shape  LogoShape
+	radius int
+}
+
+func (m *shapeMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m *shapeMask) Bounds() image.Rectangle {
+	return m.rect
+}
+
+func (m *shapeMask) At(x, y int) color.Color {
+	if m.contains(x, y) {
+		return color.Opaque
+	}
+	return color.Transparent
+}
+
+// contains returns true if the pixel at (x, y) is inside the shape.
+func (m *shapeMask) contains(x, y int) bool {
+	if !(image.Point{x, y}.In(m.rect)) {
+		return false
+	}
+
+	// Measure from pixel centers.
+	px := float64(x) + 0.5
+	py := float64(y) + 0.5
+
+	switch m.shape {
+	case LogoCircle:
+		rx := float64(m.rect.Dx()) / 2
+		ry := float64(m.rect.Dy()) / 2
+		dx := (px - float64(m.rect.Min.X) - rx) / rx
+		dy := (py - float64(m.rect.Min.Y) - ry) / ry
+
+		return dx*dx+dy*dy <= 1
+	case LogoRoundedRect:
+		r := float64(m.radius)
+		r = math.Min(r, float64(m.rect.Dx())/2)
+		r = math.Min(r, float64(m.rect.Dy())/2)
+		if r <= 0 {
+			return true
+		}
+
+		// Distance from the nearest corner circle's center, if the pixel is
+		// in a corner.
+		cx := math.Max(float64(m.rect.Min.X)+r-px, px-(float64(m.rect.Max.X)-r))
+		cy := math.Max(float64(m.rect.Min.Y)+r-py, py-(float64(m.rect.Max.Y)-r))
+		if cx <= 0 || cy <= 0 {
+			return true
+		}
+
+		return cx*cx+cy*cy <= r*r
+	}
+
+	return true
+}
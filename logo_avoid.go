@@ -0,0 +1,131 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+)
+
+// patternMap locates the function pattern modules of an image produced by
+// Image(), for placing logos with LogoAvoidPatterns and LogoStrict.
+type patternMap struct {
+	// Pixel position of Bitmap module (0, 0), and the module size.
+	origin           image.Point
+	pixelsPerModuleX int
+	pixelsPerModuleY int
+
+	// Area covered by the symbol, excluding the quiet zone.
+	symbolRect image.Rectangle
+
+	// sum[y][x] is the number of function pattern modules above and left of
+	// module (x, y).
+	sum [][]int
+}
+
+// newPatternMap returns the patternMap of an image with the given bounds.
+func (q *QRCode) newPatternMap(bounds image.Rectangle) *patternMap {
+	symbolRect := q.symbolRect(bounds)
+	size := q.symbol.size
+	n := q.version.symbolSize()
+	quietZone := q.symbol.quietZoneSize
+
+	p := &patternMap{
+		pixelsPerModuleX: symbolRect.Dx() / n,
+		pixelsPerModuleY: symbolRect.Dy() / n,
+		symbolRect:       symbolRect,
+		sum:              make([][]int, size+1),
+	}
+	p.origin = symbolRect.Min.Sub(image.Pt(quietZone*p.pixelsPerModuleX, quietZone*p.pixelsPerModuleY))
+
+	p.sum[0] = make([]int, size+1)
+	for y := 0; y < size; y++ {
+		p.sum[y+1] = make([]int, size+1)
+		for x := 0; x < size; x++ {
+			v := 0
+			if t := q.pointType(x, y); t != DataPoint && t != QuietZonePoint {
+				v = 1
+			}
+			p.sum[y+1][x+1] = v + p.sum[y][x+1] + p.sum[y+1][x] - p.sum[y][x]
+		}
+	}
+
+	return p
+}
+
+// covers reports whether the pixel rectangle r overlaps any function pattern
+// module.
+func (p *patternMap) covers(r image.Rectangle) bool {
+	size := len(p.sum) - 1
+
+	clamp := func(v int) int {
+		return min(max(v, 0), size)
+	}
+
+	x0 := clamp(floorDiv(r.Min.X-p.origin.X, p.pixelsPerModuleX))
+	y0 := clamp(floorDiv(r.Min.Y-p.origin.Y, p.pixelsPerModuleY))
+	x1 := clamp(-floorDiv(p.origin.X-r.Max.X, p.pixelsPerModuleX))
+	y1 := clamp(-floorDiv(p.origin.Y-r.Max.Y, p.pixelsPerModuleY))
+
+	if x0 >= x1 || y0 >= y1 {
+		return false
+	}
+
+	return p.sum[y1][x1]-p.sum[y0][x1]-p.sum[y1][x0]+p.sum[y0][x0] > 0
+}
+
+// avoid returns logo and its badge (the logo plus inset pixels of padding and
+// border) moved to the nearest position within the symbol where it covers no
+// function patterns. The logo is shrunk in steps of 10% until it fits. If it
+// never fits, logo and badge are returned unchanged.
+func (p *patternMap) avoid(logo image.Image, badge image.Rectangle, inset int, smooth bool) (image.Image, image.Rectangle) {
+	center := badge.Min.Add(badge.Size().Div(2))
+
+	shrunk := logo
+	for {
+		size := shrunk.Bounds().Size().Add(image.Pt(2*inset, 2*inset))
+		if at, ok := p.nearestFree(size, center); ok {
+			return shrunk, image.Rectangle{at, at.Add(size)}
+		}
+
+		w := shrunk.Bounds().Dx() * 9 / 10
+		h := shrunk.Bounds().Dy() * 9 / 10
+		if w < 1 || h < 1 {
+			return logo, badge
+		}
+		shrunk = fitLogo(logo, w, h, smooth)
+	}
+}
+
+// nearestFree returns the top left corner of the free area of the given size
+// whose center is closest to center. Candidate areas are aligned to modules
+// and lie within the symbol.
+func (p *patternMap) nearestFree(size image.Point, center image.Point) (image.Point, bool) {
+	var best image.Point
+	bestDistance := -1
+
+	for y := p.symbolRect.Min.Y; y+size.Y <= p.symbolRect.Max.Y; y += p.pixelsPerModuleY {
+		for x := p.symbolRect.Min.X; x+size.X <= p.symbolRect.Max.X; x += p.pixelsPerModuleX {
+			at := image.Pt(x, y)
+			if p.covers(image.Rectangle{at, at.Add(size)}) {
+				continue
+			}
+
+			d := at.Add(size.Div(2)).Sub(center)
+			if distance := d.X*d.X + d.Y*d.Y; bestDistance < 0 || distance < bestDistance {
+				best = at
+				bestDistance = distance
+			}
+		}
+	}
+
+	return best, bestDistance >= 0
+}
+
+// floorDiv returns a / b rounded towards negative infinity, for b > 0.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
@@ -0,0 +1,102 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// redBounds returns the bounding box of the pure red pixels of img.
+func redBounds(img *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) == (color.RGBA{0xff, 0, 0, 0xff}) {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+func TestPlaceLogoAvoidPatterns(t *testing.T) {
+	q, err := New("https://example.org/avoid", Level(Highest), Width(-4), Height(-4), Margin(4), MinVersion(7))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 24, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 24; x++ {
+			logo.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+
+	// Over the center alignment pattern, at module (22, 22) of the symbol.
+	center := LogoPoint(image.Pt((4+22)*4-12, (4+22)*4-12))
+
+	p := q.newPatternMap(q.Image().Bounds())
+	if !p.covers(image.Rect(0, 0, 24, 24).Add(image.Pt((4+22)*4-12, (4+22)*4-12))) {
+		t.Fatal("test logo does not cover the alignment pattern")
+	}
+
+	if _, err := q.PlaceLogo(logo, center, LogoStrict()); err == nil {
+		t.Error("PlaceLogo with LogoStrict succeeded, expected error")
+	}
+
+	if img := q.AddLogo(logo, center, LogoStrict()); !redBounds(img).Empty() {
+		t.Error("AddLogo drew a logo rejected by LogoStrict")
+	}
+
+	for _, opts := range [][]LogoOption{
+		{center, LogoAvoidPatterns()},
+		{center, LogoAvoidPatterns(), LogoStrict()},
+		{LogoAvoidPatterns(), LogoPadding(4, nil), LogoBorder(2, color.Black)},
+	} {
+		img, err := q.PlaceLogo(logo, opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		placed := redBounds(img)
+		if placed.Empty() {
+			t.Fatal("logo not drawn")
+		}
+		if p.covers(placed) {
+			t.Errorf("logo at %v covers a function pattern", placed)
+		}
+	}
+}
+
+func TestPlaceLogoAvoidPatternsShrinks(t *testing.T) {
+	// Version 1 has a 12x12 module area free of function patterns, too small
+	// for the padded logo.
+	q, err := New("A", Level(Highest), Width(-4), Height(-4), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			logo.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+
+	img, err := q.PlaceLogo(logo, LogoPadding(20, nil), LogoAvoidPatterns(), LogoStrict())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	placed := redBounds(img)
+	if placed.Empty() || placed.Dx() >= 20 {
+		t.Errorf("logo is %v, expected a smaller logo", placed)
+	}
+	if q.newPatternMap(img.Bounds()).covers(placed) {
+		t.Errorf("logo at %v covers a function pattern", placed)
+	}
+}
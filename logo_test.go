@@ -0,0 +1,242 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestFitLogo(t *testing.T) {
+	tests := []struct {
+		width, height       int
+		maxWidth, maxHeight int
+		expected            image.Rectangle
+	}{
+		{40, 40, 50, 50, image.Rect(0, 0, 40, 40)},
+		{400, 100, 20, 20, image.Rect(0, 0, 20, 5)},
+		{100, 400, 20, 20, image.Rect(0, 0, 5, 20)},
+		{1000, 1, 10, 10, image.Rect(0, 0, 10, 1)},
+	}
+
+	for _, test := range tests {
+		logo := image.NewRGBA(image.Rect(0, 0, test.width, test.height))
+
+		got := fitLogo(logo, test.maxWidth, test.maxHeight, true).Bounds()
+		if got != test.expected {
+			t.Errorf("fitLogo(%dx%d, %d, %d) has bounds %v, expected %v", test.width,
+				test.height, test.maxWidth, test.maxHeight, got, test.expected)
+		}
+	}
+}
+
+func TestAddLogoPosition(t *testing.T) {
+	q, err := New("https://example.org", Level(Highest), Width(-4), Height(-4), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	logo := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			logo.Set(x, y, red)
+		}
+	}
+
+	// 4px per module, with a 4 module quiet zone, and corner logos inset by
+	// 9 modules.
+	size := q.symbol.size * 4
+	inset := 4*4 + 9*4
+
+	tests := []struct {
+		opts     []LogoOption
+		expected image.Point
+	}{
+		{nil, image.Pt((size-8)/2, (size-8)/2)},
+		{[]LogoOption{LogoAt(LogoTopLeft)}, image.Pt(inset, inset)},
+		{[]LogoOption{LogoAt(LogoTopRight)}, image.Pt(size-inset-8, inset)},
+		{[]LogoOption{LogoAt(LogoBottomLeft)}, image.Pt(inset, size-inset-8)},
+		{[]LogoOption{LogoAt(LogoBottomRight)}, image.Pt(size-inset-8, size-inset-8)},
+		{[]LogoOption{LogoPoint(image.Pt(3, 5))}, image.Pt(3, 5)},
+	}
+
+	for i, test := range tests {
+		img := q.AddLogo(logo, test.opts...)
+		if img.Bounds().Dx() != size {
+			t.Fatalf("image is %dpx wide, expected %dpx", img.Bounds().Dx(), size)
+		}
+
+		for x := 0; x < 8; x++ {
+			for y := 0; y < 8; y++ {
+				p := test.expected.Add(image.Pt(x, y))
+				if img.RGBAAt(p.X, p.Y) != red {
+					t.Fatalf("test %d: pixel %v is %v, expected red", i, p, img.RGBAAt(p.X, p.Y))
+				}
+			}
+		}
+	}
+
+	// Corner logos keep the finder patterns and format information clear.
+	for _, p := range []LogoPosition{LogoTopLeft, LogoTopRight, LogoBottomLeft, LogoBottomRight} {
+		if _, err := q.PlaceLogo(logo, LogoAt(p), LogoStrict()); err != nil {
+			t.Errorf("corner logo %d: %s", p, err.Error())
+		}
+	}
+}
+
+func TestAddLogoCaption(t *testing.T) {
+	plain, err := New("https://example.org", Level(Highest), Width(210), Height(210))
+	if err != nil {
+		t.Fatal(err)
+	}
+	captioned, err := plain.With(Caption("example.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The caption makes the image taller, but the symbol stays in place.
+	b := captioned.Image().Bounds()
+	if b.Dy() <= 210 {
+		t.Fatalf("captioned image is %v, expected it taller than 210px", b)
+	}
+	if got, expected := captioned.symbolRect(b), plain.symbolRect(plain.Image().Bounds()); got != expected {
+		t.Errorf("got symbol %v, expected %v", got, expected)
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(color.RGBA{0xff, 0, 0, 0xff}), image.Point{}, draw.Src)
+
+	for _, opts := range [][]LogoOption{
+		nil,
+		{LogoAt(LogoTopLeft), LogoStrict()},
+		{LogoAt(LogoBottomRight), LogoStrict()},
+		{LogoAt(LogoTopRight), LogoAvoidPatterns()},
+	} {
+		expected, err := plain.PlaceLogo(logo, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := captioned.PlaceLogo(logo, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !sameImage(got.SubImage(expected.Bounds()), expected) {
+			t.Errorf("%d options: the logo moved under a caption", len(opts))
+		}
+	}
+}
+
+func TestAddLogoBorderAndMask(t *testing.T) {
+	q, err := New("https://example.org", Level(Highest), Width(-4), Height(-4), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			logo.Set(x, y, color.RGBA{0, 0, 0xff, 0xff})
+		}
+	}
+
+	green := color.RGBA{0, 0xff, 0, 0xff}
+	yellow := color.RGBA{0xff, 0xff, 0, 0xff}
+	img := q.AddLogo(logo, LogoPoint(image.Pt(20, 20)), LogoBorder(2, green),
+		LogoPadding(3, yellow), LogoMask(LogoCircle))
+
+	// The badge is 10+2*3+2*2 = 20px wide.
+	if c := img.RGBAAt(30, 20); c != green {
+		t.Errorf("top of border is %v, expected %v", c, green)
+	}
+	if c := img.RGBAAt(30, 23); c != yellow {
+		t.Errorf("top of padding is %v, expected %v", c, yellow)
+	}
+	if c := img.RGBAAt(30, 30); c != (color.RGBA{0, 0, 0xff, 0xff}) {
+		t.Errorf("center of logo is %v, expected blue", c)
+	}
+
+	// The corners are clipped by the circle, leaving the QR Code.
+	for _, p := range []image.Point{{20, 20}, {39, 20}, {20, 39}, {39, 39}} {
+		c := img.RGBAAt(p.X, p.Y)
+		if c == green || c == yellow {
+			t.Errorf("corner %v is %v, expected to be clipped", p, c)
+		}
+	}
+}
+
+func TestLogoMask(t *testing.T) {
+	r := image.Rect(0, 0, 10, 10)
+
+	tests := []struct {
+		mask     shapeMask
+		p        image.Point
+		expected bool
+	}{
+		{shapeMask{r, LogoSquare, 0}, image.Pt(0, 0), true},
+		{shapeMask{r, LogoSquare, 0}, image.Pt(10, 0), false},
+		{shapeMask{r, LogoCircle, 0}, image.Pt(0, 0), false},
+		{shapeMask{r, LogoCircle, 0}, image.Pt(5, 0), true},
+		{shapeMask{r, LogoCircle, 0}, image.Pt(5, 5), true},
+		{shapeMask{r, LogoRoundedRect, 3}, image.Pt(0, 0), false},
+		{shapeMask{r, LogoRoundedRect, 3}, image.Pt(1, 1), true},
+		{shapeMask{r, LogoRoundedRect, 3}, image.Pt(3, 0), true},
+		{shapeMask{r, LogoRoundedRect, 0}, image.Pt(0, 0), true},
+	}
+
+	for _, test := range tests {
+		if got := test.mask.contains(test.p.X, test.p.Y); got != test.expected {
+			t.Errorf("shape %d radius %d contains %v = %t, expected %t", test.mask.shape,
+				test.mask.radius, test.p, got, test.expected)
+		}
+	}
+}
+
+func TestDrawLogo(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+
+	q, err := New("draw logo", Level(Highest), Margin(4), Width(100), Height(100), BackgroundColor(color.Transparent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	small := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			small.Set(x, y, red)
+		}
+	}
+
+	dsts := []struct {
+		name string
+		dst  draw.Image
+	}{
+		{"NRGBA", q.ImageNRGBA()},
+		{"RGBA", q.ImageRGBA()},
+		{"Gray", q.ImageGray()},
+		{"Paletted", q.Image().(*image.Paletted)},
+	}
+
+	for _, test := range dsts {
+		if err := q.DrawLogo(test.dst, small); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		center := test.dst.ColorModel().Convert(test.dst.At(50, 50))
+		if center != test.dst.ColorModel().Convert(red) {
+			t.Errorf("%s: center pixel is %v, expected the logo color", test.name, center)
+		}
+	}
+
+	img := q.ImageNRGBA()
+	if err := q.DrawLogo(img, small); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("corner alpha is %d, expected the transparent background to be kept", a)
+	}
+}
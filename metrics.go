@@ -0,0 +1,30 @@
+package qrcode
+
+import "time"
+
+// Metrics receives instrumentation events from a QRCode, so services
+// embedding the package can export them (e.g. as Prometheus metrics) without
+// wrapping every call. See Instrument.
+//
+// Implementations must be safe for concurrent use if shared between
+// goroutines.
+type Metrics interface {
+	// Encoded is called once New has finished encoding content, with the
+	// total time taken and the version and level chosen.
+	Encoded(d time.Duration, version int, level RecoveryLevel)
+
+	// MasksEvaluated is called once all data masks have been evaluated, with
+	// the time taken and the mask chosen.
+	MasksEvaluated(d time.Duration, mask int, penalty int)
+
+	// Rendered is called when the QR Code is encoded as an image file, with
+	// the format (e.g. "png"), the time taken and the output size.
+	Rendered(format string, d time.Duration, numBytes int)
+}
+
+// Instrument reports encoding and rendering events to m.
+func Instrument(m Metrics) Option {
+	return func(q *QRCode) {
+		q.metrics = m
+	}
+}
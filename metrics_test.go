@@ -0,0 +1,65 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	encoded        int
+	version        int
+	level          RecoveryLevel
+	masksEvaluated int
+	mask           int
+	rendered       int
+	format         string
+	numBytes       int
+}
+
+func (m *recordingMetrics) Encoded(d time.Duration, version int, level RecoveryLevel) {
+	m.encoded++
+	m.version = version
+	m.level = level
+}
+
+func (m *recordingMetrics) MasksEvaluated(d time.Duration, mask int, penalty int) {
+	m.masksEvaluated++
+	m.mask = mask
+}
+
+func (m *recordingMetrics) Rendered(format string, d time.Duration, numBytes int) {
+	m.rendered++
+	m.format = format
+	m.numBytes = numBytes
+}
+
+func TestInstrument(t *testing.T) {
+	m := &recordingMetrics{}
+
+	q, err := New("01234567", Level(Medium), Instrument(m))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if m.encoded != 1 || m.version != 1 || m.level != Medium {
+		t.Errorf("Encoded called %d times with version %d level %s, expected once with 1-M",
+			m.encoded, m.version, m.level)
+	}
+	if m.masksEvaluated != 1 || m.mask != q.mask {
+		t.Errorf("MasksEvaluated called %d times with mask %d, expected once with %d",
+			m.masksEvaluated, m.mask, q.mask)
+	}
+
+	png, err := q.PNG()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if m.rendered != 1 || m.format != "png" || m.numBytes != len(png) {
+		t.Errorf("Rendered called %d times with %s %d bytes, expected once with png %d bytes",
+			m.rendered, m.format, m.numBytes, len(png))
+	}
+}
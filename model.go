@@ -0,0 +1,221 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"time"
+)
+
+// STL returns the QR Code as a binary STL model for 3D printing: a base plate
+// sizeMM millimetres square, including the quiet zone, and baseMM thick, with
+// the dark modules raised reliefMM above it. Adjacent dark modules are merged
+// into rectangular blocks, each a separate closed box standing on the plate.
+// Captions, stamps and other image options are not modelled.
+func (q *QRCode) STL(sizeMM, baseMM, reliefMM float64) ([]byte, error) {
+	if err := checkModel(sizeMM, baseMM, reliefMM); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	boxes := q.modelBoxes(sizeMM, baseMM, reliefMM)
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 80)) // Header, unused.
+	binary.Write(&buf, binary.LittleEndian, uint32(12*len(boxes)))
+
+	for _, b := range boxes {
+		for _, f := range b.faces() {
+			binary.Write(&buf, binary.LittleEndian, f)
+		}
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("stl", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// OpenSCAD returns the model of STL as an OpenSCAD script, for customising
+// before printing, e.g. engraving the code into another design.
+func (q *QRCode) OpenSCAD(sizeMM, baseMM, reliefMM float64) ([]byte, error) {
+	if err := checkModel(sizeMM, baseMM, reliefMM); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	boxes := q.modelBoxes(sizeMM, baseMM, reliefMM)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// QR Code, %smm square.\nunion() {\n", dxfNumber(sizeMM))
+	for _, b := range boxes {
+		fmt.Fprintf(&buf, "  translate([%s, %s, %s]) cube([%s, %s, %s]);\n",
+			scadNumber(b.min[0]), scadNumber(b.min[1]), scadNumber(b.min[2]),
+			scadNumber(b.max[0]-b.min[0]), scadNumber(b.max[1]-b.min[1]), scadNumber(b.max[2]-b.min[2]))
+	}
+	buf.WriteString("}\n")
+
+	if q.metrics != nil {
+		q.metrics.Rendered("scad", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scadNumber formats v for OpenSCAD output, to 4 decimal places without
+// trailing zeros.
+func scadNumber(v float32) string {
+	return dxfNumber(float64(v))
+}
+
+// checkModel returns an error if the STL and OpenSCAD sizes are invalid.
+func checkModel(sizeMM, baseMM, reliefMM float64) error {
+	if sizeMM <= 0 || reliefMM <= 0 || baseMM < 0 {
+		return errors.New("model: size and relief must be positive, and base not negative")
+	}
+	return nil
+}
+
+// box is an axis aligned cuboid, in millimetres.
+type box struct {
+	min, max [3]float32
+}
+
+// stlFacet is a triangle of a binary STL file.
+type stlFacet struct {
+	Normal   [3]float32
+	Vertices [3][3]float32
+	Unused   uint16
+}
+
+// faces returns the 12 triangles of b, wound anticlockwise seen from outside.
+func (b box) faces() []stlFacet {
+	// Corner i has the max coordinate on axis a if bit a of i is set.
+	corner := func(i int) [3]float32 {
+		var c [3]float32
+		for a := 0; a < 3; a++ {
+			c[a] = b.min[a]
+			if i>>a&1 == 1 {
+				c[a] = b.max[a]
+			}
+		}
+		return c
+	}
+
+	// The corners of each side, anticlockwise seen from outside, and its
+	// normal.
+	sides := []struct {
+		corners [4]int
+		normal  [3]float32
+	}{
+		{[4]int{0, 2, 3, 1}, [3]float32{0, 0, -1}},
+		{[4]int{4, 5, 7, 6}, [3]float32{0, 0, 1}},
+		{[4]int{0, 1, 5, 4}, [3]float32{0, -1, 0}},
+		{[4]int{2, 6, 7, 3}, [3]float32{0, 1, 0}},
+		{[4]int{0, 4, 6, 2}, [3]float32{-1, 0, 0}},
+		{[4]int{1, 3, 7, 5}, [3]float32{1, 0, 0}},
+	}
+
+	var facets []stlFacet
+	for _, s := range sides {
+		c := s.corners
+		facets = append(facets,
+			stlFacet{Normal: s.normal, Vertices: [3][3]float32{corner(c[0]), corner(c[1]), corner(c[2])}},
+			stlFacet{Normal: s.normal, Vertices: [3][3]float32{corner(c[0]), corner(c[2]), corner(c[3])}})
+	}
+
+	return facets
+}
+
+// modelBoxes returns the boxes of the STL model: the
+// base plate, if baseMM is not zero, then the raised dark blocks. The y axis
+// points up, so the code reads correctly seen from above.
+func (q *QRCode) modelBoxes(sizeMM, baseMM, reliefMM float64) []box {
+	bitmap := q.Bitmap()
+	width, height := len(bitmap[0]), len(bitmap)
+	module := sizeMM / float64(max(width, height))
+
+	mm := func(v float64) float32 {
+		return float32(math.Round(v*1e4) / 1e4)
+	}
+
+	var boxes []box
+	if baseMM > 0 {
+		boxes = append(boxes, box{
+			max: [3]float32{mm(float64(width) * module), mm(float64(height) * module), mm(baseMM)},
+		})
+	}
+
+	for _, r := range darkBlocks(bitmap) {
+		boxes = append(boxes, box{
+			min: [3]float32{mm(float64(r.Min.X) * module), mm(float64(height-r.Max.Y) * module), mm(baseMM)},
+			max: [3]float32{mm(float64(r.Max.X) * module), mm(float64(height-r.Min.Y) * module), mm(baseMM + reliefMM)},
+		})
+	}
+
+	return boxes
+}
+
+// darkBlocks returns rectangles covering the dark modules of bitmap without
+// overlapping: each horizontal run of dark modules, extended down over the
+// identical runs of the following rows.
+func darkBlocks(bitmap [][]bool) []image.Rectangle {
+	// done marks the modules of the rectangles found so far.
+	done := make([][]bool, len(bitmap))
+	for y := range bitmap {
+		done[y] = make([]bool, len(bitmap[y]))
+	}
+
+	// isRun returns true if x0 to x1 is a whole run of dark modules of row y
+	// not yet covered.
+	isRun := func(y, x0, x1 int) bool {
+		row := bitmap[y]
+		if (x0 > 0 && row[x0-1]) || (x1 < len(row) && row[x1]) {
+			return false
+		}
+		for x := x0; x < x1; x++ {
+			if !row[x] || done[y][x] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var blocks []image.Rectangle
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] || done[y][x] {
+				continue
+			}
+
+			run := x
+			for run < len(row) && row[run] {
+				run++
+			}
+
+			bottom := y + 1
+			for bottom < len(bitmap) && isRun(bottom, x, run) {
+				bottom++
+			}
+
+			for j := y; j < bottom; j++ {
+				for i := x; i < run; i++ {
+					done[j][i] = true
+				}
+			}
+			blocks = append(blocks, image.Rect(x, y, run, bottom))
+			x = run
+		}
+	}
+
+	return blocks
+}
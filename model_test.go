@@ -0,0 +1,153 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestDarkBlocks(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitmap := q.Bitmap()
+	blocks := darkBlocks(bitmap)
+
+	covered := make(map[[2]int]bool)
+	dark := 0
+	for _, r := range blocks {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if covered[[2]int{x, y}] {
+					t.Fatalf("module (%d, %d) is covered twice", x, y)
+				}
+				covered[[2]int{x, y}] = true
+			}
+		}
+	}
+	for y, row := range bitmap {
+		for x, v := range row {
+			if v {
+				dark++
+			}
+			if covered[[2]int{x, y}] != v {
+				t.Fatalf("module (%d, %d): got covered %t, expected %t", x, y, !v, v)
+			}
+		}
+	}
+
+	if len(blocks) >= dark {
+		t.Errorf("got %d blocks for %d dark modules, expected modules to be merged", len(blocks), dark)
+	}
+}
+
+func TestSTL(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stl, err := q.STL(40, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numBoxes := len(darkBlocks(q.Bitmap())) + 1
+	if got, expected := len(stl), 84+50*12*numBoxes; got != expected {
+		t.Fatalf("got %d bytes, expected %d", got, expected)
+	}
+
+	var count uint32
+	r := bytes.NewReader(stl[80:])
+	binary.Read(r, binary.LittleEndian, &count)
+	if count != uint32(12*numBoxes) {
+		t.Fatalf("got %d triangles, expected %d", count, 12*numBoxes)
+	}
+
+	for i := 0; i < int(count); i++ {
+		var f stlFacet
+		if err := binary.Read(r, binary.LittleEndian, &f); err != nil {
+			t.Fatal(err)
+		}
+
+		// The winding agrees with the normal.
+		v := f.Vertices
+		var a, b [3]float32
+		for k := 0; k < 3; k++ {
+			a[k], b[k] = v[1][k]-v[0][k], v[2][k]-v[0][k]
+		}
+		cross := [3]float32{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+		dot := cross[0]*f.Normal[0] + cross[1]*f.Normal[1] + cross[2]*f.Normal[2]
+		if dot <= 0 {
+			t.Fatalf("triangle %d is wound against its normal %v", i, f.Normal)
+		}
+
+		for _, p := range v {
+			if p[0] < 0 || p[0] > 40 || p[1] < 0 || p[1] > 40 || p[2] < 0 || p[2] > 3 {
+				t.Fatalf("triangle %d: vertex %v outside the model", i, p)
+			}
+		}
+	}
+
+	rendered, err := q.Render("stl", RenderOptions{SizeMM: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rendered, stl) {
+		t.Error("stl renderer output differs from STL")
+	}
+
+	if _, err := q.STL(40, 2, 0); err == nil {
+		t.Error("STL with no relief succeeded, expected an error")
+	}
+
+	// Without a base plate, only the dark blocks are modelled.
+	stl, err = q.STL(40, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := len(stl), 84+50*12*(numBoxes-1); got != expected {
+		t.Errorf("got %d bytes without a base, expected %d", got, expected)
+	}
+
+	rendered, err = q.Render("stl", RenderOptions{SizeMM: 40, BaseMM: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rendered, stl) {
+		t.Error("stl renderer with a negative BaseMM differs from STL without a base")
+	}
+}
+
+func TestOpenSCAD(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scad, err := q.OpenSCAD(40, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(scad)
+	if got, expected := strings.Count(s, "cube("), len(darkBlocks(q.Bitmap()))+1; got != expected {
+		t.Errorf("got %d cubes, expected %d", got, expected)
+	}
+	if !strings.Contains(s, "  translate([0, 0, 0]) cube([40, 40, 2]);\n") {
+		t.Errorf("base plate not found:\n%s", s[:200])
+	}
+	if !strings.HasPrefix(s, "// QR Code") || !strings.HasSuffix(s, "}\n") {
+		t.Errorf("malformed script:\n%s", s)
+	}
+
+	if _, err := q.OpenSCAD(0, 2, 1); err == nil {
+		t.Error("OpenSCAD with no size succeeded, expected an error")
+	}
+}
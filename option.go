@@ -2,34 +2,299 @@ package qrcode
 
 import (
 	"image/color"
+
+	"golang.org/x/image/math/f64"
 )
 
+// Option configures a QRCode. Options are applied in order, after those of
+// SetDefaults, and a later option replaces the settings of an earlier one:
+// Width(256), Scale(4) draws 4 pixel modules, and Scale(4), Width(256) a 256
+// pixel wide image. Options that still contradict each other, such as
+// NoQuietZone with a QuietZoneColor, are an error under Strict.
 type Option func(q *QRCode)
 
+// Width sets the image width in pixels, replacing Scale. See Image() for
+// negative sizes.
 func Width(w int) Option {
 	return func(q *QRCode) {
 		q.width = w
+		q.scale = 0
 	}
 }
 
+// Height sets the image height in pixels, replacing Scale.
 func Height(h int) Option {
 	return func(q *QRCode) {
 		q.height = h
+		q.scale = 0
+	}
+}
+
+// Scale draws each module as an exact n x n pixel block, replacing Width,
+// Height and Downscale. No resampling is done, so edges stay crisp at 2x/3x
+// for hi-DPI displays.
+func Scale(n int) Option {
+	return func(q *QRCode) {
+		q.scale = n
+		q.downscale = false
+	}
+}
+
+// ModulePixelSize draws each module as a w x h pixel block, for displays
+// with non-square pixels, e.g. 2:1 character cells or anamorphic LED
+// matrices, replacing Width, Height, Scale and Downscale. It is the same as
+// Width(-w), Height(-h); sizes below 1 are taken as 1.
+func ModulePixelSize(w, h int) Option {
+	return func(q *QRCode) {
+		q.width = -max(w, 1)
+		q.height = -max(h, 1)
+		q.scale = 0
+		q.downscale = false
+	}
+}
+
+// MinModulePixels grows images so every module is at least n x n pixels, as
+// smaller modules are a common cause of unscannable codes. Under Strict, New
+// returns an error instead.
+func MinModulePixels(n int) Option {
+	return func(q *QRCode) {
+		q.minModulePixels = n
+	}
+}
+
+// Strict makes New return an error instead of silently adjusting options
+// which cannot be honoured, see MinModulePixels, or ignoring options which
+// contradict each other, see Option.
+func Strict() Option {
+	return func(q *QRCode) {
+		q.strict = true
+	}
+}
+
+// ExactSize returns images of exactly the requested Width and Height. When
+// the size is not a multiple of the symbol size, the remaining pixels are
+// distributed into the quiet zone. This is the default.
+func ExactSize() Option {
+	return func(q *QRCode) {
+		q.snapToModule = false
+	}
+}
+
+// SnapToModule shrinks images to the largest whole number of pixels per
+// module that fits the requested Width and Height, so there are no leftover
+// pixels.
+func SnapToModule() Option {
+	return func(q *QRCode) {
+		q.snapToModule = true
+	}
+}
+
+// NoSmoothing scales logos and ImageGenerator backgrounds by nearest neighbor
+// sampling instead of smoothing them. Antialiased edges near modules can stop
+// cheap scanners reading the code.
+func NoSmoothing() Option {
+	return func(q *QRCode) {
+		q.noSmoothing = true
+	}
+}
+
+// Caption prints text under the symbol in images returned by Image(), as a
+// human readable fallback. The image is made taller to fit it, and text too
+// long for the image width is elided in the middle.
+func Caption(text string) Option {
+	return func(q *QRCode) {
+		q.caption = text
+		q.captionContent = false
+	}
+}
+
+// CaptionContent prints the encoded content under the symbol, see Caption.
+func CaptionContent() Option {
+	return func(q *QRCode) {
+		q.captionContent = true
+		q.caption = ""
+	}
+}
+
+// Stamp prints a small serial or label, such as "042/500", in the bottom
+// right corner of the quiet zone, to help match printed sheets to their data.
+// It is omitted if the quiet zone is too small to hold it.
+func Stamp(text string) Option {
+	return func(q *QRCode) {
+		q.stamp = text
+	}
+}
+
+// StampSequence makes NewBatch Stamp each code with its position in the
+// batch, e.g. "042/500".
+func StampSequence() Option {
+	return func(q *QRCode) {
+		q.stampSequence = true
+	}
+}
+
+// OverrideFormatInfo draws the 15-bit value f in place of the computed
+// Format Information, e.g. FormatInfo() with some bits flipped. It produces
+// deliberately damaged symbols for testing scanners.
+func OverrideFormatInfo(f uint32) Option {
+	return func(q *QRCode) {
+		q.formatInfoOverride = &f
+	}
+}
+
+// OverrideVersionInfo draws the 18-bit value v in place of the computed
+// Version Information, see OverrideFormatInfo. It is only valid for versions
+// 7-40.
+func OverrideVersionInfo(v uint32) Option {
+	return func(q *QRCode) {
+		q.versionInfoOverride = &v
+	}
+}
+
+// PadCodewords fills unused data capacity with the codewords pad, repeated,
+// in place of the standard alternating 0xEC and 0x11. Scanners ignore pad
+// codewords, so this changes the texture of the data region without affecting
+// the content. VerifyBitmap reports non-standard padding as an error.
+func PadCodewords(pad ...byte) Option {
+	return func(q *QRCode) {
+		if len(pad) == 0 {
+			q.padCodeword = nil
+			return
+		}
+		q.padCodeword = func(i int) byte {
+			return pad[i%len(pad)]
+		}
+	}
+}
+
+// PadFunc fills unused data capacity with f(0), f(1), ..., see PadCodewords.
+func PadFunc(f func(i int) byte) Option {
+	return func(q *QRCode) {
+		q.padCodeword = f
+	}
+}
+
+// LegacyMaskPenalty selects the data mask with the penalty rule 3 scoring of
+// earlier releases, which missed some finder-like patterns. Use it only to
+// reproduce previously generated codes exactly: codes are valid either way,
+// but the corrected scoring is more likely to choose the best mask.
+func LegacyMaskPenalty() Option {
+	return func(q *QRCode) {
+		q.legacyMaskPenalty = true
+	}
+}
+
+// Transform applies the affine transform aff, mapping symbol image
+// coordinates to output coordinates, to images returned by Image(), e.g. to
+// pre-rotate or skew codes for mockups. The output is enlarged to fit, with a
+// transparent background. See RotationTransform and SkewTransform.
+//
+// A transform which cannot be inverted is ignored, and the straight image is
+// returned.
+func Transform(aff f64.Aff3) Option {
+	return func(q *QRCode) {
+		q.transform = &aff
+	}
+}
+
+// ICCProfile embeds the ICC color profile data, named name, in PNG images,
+// in place of the default sRGB tag. A nil profile writes no color space
+// information at all.
+func ICCProfile(name string, profile []byte) Option {
+	return func(q *QRCode) {
+		q.iccProfileName = name
+		q.iccProfile = profile
+		q.iccProfileSet = true
+	}
+}
+
+// PNGMetadata writes tEXt chunks to PNG images recording how they were made,
+// so asset management systems can audit them without decoding: the SHA-256
+// hash of the content (never the content itself), the version, recovery
+// level and mask, and the library version as the "Software" keyword.
+func PNGMetadata() Option {
+	return func(q *QRCode) {
+		q.pngMetadata = true
+	}
+}
+
+// Reproducible writes PNG images with a fixed encoding, so the same code and
+// options always give byte-identical files, across Go releases too, e.g. for
+// content-addressed storage. Images are somewhat larger than with the default
+// image/png encoding. No timestamps or Exif data are written either way.
+// EncodeBandedPNG is not affected.
+func Reproducible() Option {
+	return func(q *QRCode) {
+		q.reproducible = true
+	}
+}
+
+// Palette sets the palette of paletted images, in index order, e.g. for
+// printer drivers that always print index 0 as black. It must contain the
+// ForegroundColor and BackgroundColor, and any other colors drawn, such as a
+// QuietZoneColor, or color.Transparent for a QuietZoneRadius; colors not in
+// it are drawn as the nearest entry. Unused entries are kept, to give the
+// image a fixed color count. A nil palette restores the default.
+func Palette(p color.Palette) Option {
+	return func(q *QRCode) {
+		q.customPalette = nil
+		if p != nil {
+			q.customPalette = append(color.Palette{}, p...)
+		}
 	}
 }
 
 func Margin(m int) Option {
 	return func(q *QRCode) {
 		q.margin = m
+		q.quietZoneEdges = nil
+		q.noQuietZone = false
 	}
 }
 
-func QuitZoneSize(s int) Option {
+// QuietZone sets the quiet zone of each edge in modules, e.g. a deeper top
+// edge to hold a label, replacing Margin. Image, Bitmap, ToString and svg
+// output have the deeper edges; pdf output and PlaceInTemplate keep the
+// narrowest edge all round.
+//
+// The image is larger than the requested Width and Height by the difference
+// between each edge and the narrowest, so modules stay square.
+func QuietZone(top, right, bottom, left int) Option {
+	return func(q *QRCode) {
+		q.quietZoneEdges = &[4]int{top, right, bottom, left}
+		q.margin = min(min(top, right), min(bottom, left))
+		q.noQuietZone = false
+	}
+}
+
+// NoQuietZone leaves the quiet zone out of the symbol, replacing Margin, for
+// callers that draw it themselves, e.g. as CSS padding or in a label
+// template. Images are shrunk to a whole number of pixels per module, as by
+// SnapToModule, so they have no light border either.
+//
+// Scanners need a light quiet zone around the symbol to find it: ISO/IEC
+// 18004 requires at least 4 modules on every side, free of text, lines and
+// other graphics. Codes placed without one often fail to scan, especially
+// on patterned or dark backgrounds, so the caller must provide it.
+func NoQuietZone() Option {
 	return func(q *QRCode) {
-		q.version.setQuietZoneSize(s)
+		q.noQuietZone = true
 	}
 }
 
+// QuietZoneSize sets the quiet zone of every edge to s modules. It is the
+// same as Margin.
+func QuietZoneSize(s int) Option {
+	return Margin(s)
+}
+
+// QuitZoneSize sets the quiet zone to s modules.
+//
+// Deprecated: Use QuietZoneSize.
+func QuitZoneSize(s int) Option {
+	return QuietZoneSize(s)
+}
+
 func ForegroundColor(c color.Color) Option {
 	return func(q *QRCode) {
 		if nil == c {
@@ -50,12 +315,118 @@ func BackgroundColor(c color.Color) Option {
 	}
 }
 
+// QuietZoneColor fills the quiet zone with c instead of the background color.
+func QuietZoneColor(c color.Color) Option {
+	return func(q *QRCode) {
+		q.quietZoneColor = c
+	}
+}
+
+// QuietZoneRadius rounds the corners of the image with radius r pixels. The
+// area outside the corners is transparent.
+func QuietZoneRadius(r int) Option {
+	return func(q *QRCode) {
+		q.quietZoneRadius = r
+	}
+}
+
+// QuietZoneOutline draws a frame of width pixels in color c around the edge
+// of the image, following any rounded corners. The frame is drawn over the
+// quiet zone, which should be wider than the frame.
+func QuietZoneOutline(width int, c color.Color) Option {
+	return func(q *QRCode) {
+		q.outlineWidth = width
+		q.outlineColor = c
+	}
+}
+
+// URLMode selects how New interprets content as a URL, see Normalize. Modes
+// may be combined, e.g. URLValidate|URLRequireHTTPS.
+type URLMode int
+
+const (
+	// URLTrim removes leading and trailing white space.
+	URLTrim URLMode = 1 << iota
+
+	// URLValidate trims the content, and checks it is an absolute URL with a
+	// host for http and https. Internationalized host names are converted to
+	// punycode and lower cased.
+	URLValidate
+
+	// URLRequireHTTPS implies URLValidate, and rejects URLs whose scheme is
+	// not https.
+	URLRequireHTTPS
+
+	// URLAddScheme implies URLValidate, and prefixes content without a
+	// scheme (e.g. "example.org/page") with "http://", or "https://" with
+	// URLRequireHTTPS.
+	URLAddScheme
+)
+
+// Normalize makes New validate and normalize the content as a URL, returning
+// an error for malformed URLs instead of encoding an unscannable link. Content
+// is encoded unchanged by default.
+//
+// TinyGo builds have no URL support, and New returns an error for any mode
+// other than 0.
+func Normalize(mode URLMode) Option {
+	return func(q *QRCode) {
+		q.urlMode = mode
+	}
+}
+
+// Edge is a side of the image.
+type Edge int
+
+const (
+	// NoEdge is the zero Edge, meaning none.
+	NoEdge Edge = iota
+	TopEdge
+	RightEdge
+	BottomEdge
+	LeftEdge
+)
+
+// OrientationMarker draws a small arrow in the quiet zone at edge e, pointing
+// away from the symbol, e.g. TopEdge for "this side up" on labels which must
+// be applied in a specific orientation. The marker keeps a gap of one module
+// from the symbol, and is omitted if the quiet zone is too small for it.
+// NoEdge removes the marker.
+func OrientationMarker(e Edge) Option {
+	return func(q *QRCode) {
+		q.orientationMarker = e
+	}
+}
+
 func Level(l RecoveryLevel) Option {
 	return func(q *QRCode) {
 		q.level = l
 	}
 }
 
+// MinVersion chooses a QR Code version of at least v (1-40 inclusive), e.g.
+// to keep every code in a print run the same physical module count. A larger
+// version is still chosen automatically if the content needs it.
+func MinVersion(v int) Option {
+	return func(q *QRCode) {
+		q.minVersion = v
+		q.exactVersion = false
+	}
+}
+
+// ExactVersion chooses QR Code version v (1-40 inclusive) exactly, replacing
+// MinVersion. New returns an error if the content does not fit.
+// ExactVersion(0) chooses automatically.
+func ExactVersion(v int) Option {
+	return func(q *QRCode) {
+		q.minVersion = v
+		q.exactVersion = v > 0
+	}
+}
+
+// Version has no effect: New chooses the version from the content.
+//
+// Deprecated: Use MinVersion or ExactVersion.
 func Version(v int) Option {
 	return func(q *QRCode) {
 		q.VersionNumber = v
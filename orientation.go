@@ -0,0 +1,58 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+)
+
+// drawOrientationMarker draws the OrientationMarker arrow into the quiet zone
+// of img, centered on the symbol and pointing away from it.
+func (q *QRCode) drawOrientationMarker(img *image.Paletted, pixelsPerModuleX, pixelsPerModuleY int) {
+	if q.orientationMarker == NoEdge {
+		return
+	}
+
+	b := img.Bounds()
+	symbolRect := q.symbolRect(b)
+	center := image.Pt((symbolRect.Min.X+symbolRect.Max.X)/2, (symbolRect.Min.Y+symbolRect.Max.Y)/2)
+
+	// space is the depth of the quiet zone available at the edge, leaving a
+	// gap of one module from the symbol. at maps a depth d from the image
+	// edge and an offset a along it to a point.
+	var space int
+	var at func(d, a int) image.Point
+
+	switch q.orientationMarker {
+	case TopEdge:
+		space = symbolRect.Min.Y - b.Min.Y - pixelsPerModuleY
+		at = func(d, a int) image.Point { return image.Pt(center.X+a, b.Min.Y+d) }
+	case BottomEdge:
+		space = b.Max.Y - symbolRect.Max.Y - pixelsPerModuleY
+		at = func(d, a int) image.Point { return image.Pt(center.X+a, b.Max.Y-1-d) }
+	case LeftEdge:
+		space = symbolRect.Min.X - b.Min.X - pixelsPerModuleX
+		at = func(d, a int) image.Point { return image.Pt(b.Min.X+d, center.Y+a) }
+	case RightEdge:
+		space = b.Max.X - symbolRect.Max.X - pixelsPerModuleX
+		at = func(d, a int) image.Point { return image.Pt(b.Max.X-1-d, center.Y+a) }
+	default:
+		return
+	}
+
+	// The arrow is a triangle half the depth of the space, centered in it,
+	// with its apex towards the image edge.
+	height := space / 2
+	if height < 2 {
+		return
+	}
+	apex := (space - height) / 2
+
+	for i := 0; i < height; i++ {
+		for a := -i; a <= i; a++ {
+			p := at(apex+i, a)
+			img.Set(p.X, p.Y, q.ForegroundColor)
+		}
+	}
+}
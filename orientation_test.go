@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"testing"
+)
+
+func TestOrientationMarker(t *testing.T) {
+	plain, err := New("orientation", Margin(4), Scale(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := plain.Image()
+
+	// The quiet zone along each edge, which must hold every changed pixel.
+	tests := []struct {
+		edge Edge
+		area func(b, symbol image.Rectangle) image.Rectangle
+	}{
+		{TopEdge, func(b, s image.Rectangle) image.Rectangle { return image.Rect(b.Min.X, b.Min.Y, b.Max.X, s.Min.Y) }},
+		{BottomEdge, func(b, s image.Rectangle) image.Rectangle { return image.Rect(b.Min.X, s.Max.Y, b.Max.X, b.Max.Y) }},
+		{LeftEdge, func(b, s image.Rectangle) image.Rectangle { return image.Rect(b.Min.X, s.Min.Y, s.Min.X, s.Max.Y) }},
+		{RightEdge, func(b, s image.Rectangle) image.Rectangle { return image.Rect(s.Max.X, s.Min.Y, b.Max.X, s.Max.Y) }},
+	}
+
+	for _, test := range tests {
+		q, err := New("orientation", Margin(4), Scale(8), OrientationMarker(test.edge))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := q.Image()
+
+		b := img.Bounds()
+		symbol := q.symbolRect(b)
+		area := test.area(b, symbol)
+
+		// Keep one module clear of the symbol.
+		gap := symbol.Inset(-8)
+
+		changed := 0
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if img.At(x, y) == base.At(x, y) {
+					continue
+				}
+				changed++
+
+				p := image.Pt(x, y)
+				if !p.In(area) || p.In(gap) {
+					t.Fatalf("edge %d: pixel %v changed outside the marker area %v", test.edge, p, area)
+				}
+				if img.At(x, y) != q.ForegroundColor {
+					t.Fatalf("edge %d: pixel %v is not the foreground color", test.edge, p)
+				}
+			}
+		}
+
+		// A triangle of height 12: 1 + 3 + ... + 23 pixels.
+		if changed != 12*12 {
+			t.Errorf("edge %d: %d pixels changed, expected 144", test.edge, changed)
+		}
+
+		if _, err := VerifyBitmap(q.Bitmap()); err != nil {
+			t.Error(err)
+		}
+	}
+
+	q, err := New("orientation", Margin(1), Scale(8), OrientationMarker(TopEdge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img := q.Image(); img.At(img.Bounds().Dx()/2, 4) != q.BackgroundColor {
+		t.Error("marker drawn in a quiet zone too small for it")
+	}
+}
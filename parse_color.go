@@ -0,0 +1,175 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// ParseColor parses a color as given by web pages and APIs:
+//
+//	#1a73e8, #1a73e880, #fff, #fff8  hex, with or without the #
+//	rgb(26, 115, 232)                 also rgba(), percentages and "/ alpha"
+//	hsl(217, 82%, 51%)                also hsla()
+//	cornflowerblue, transparent       CSS named colors
+//
+// Matching is case insensitive. The result is a color.NRGBA.
+func ParseColor(s string) (color.Color, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+
+	if t == "transparent" {
+		return color.NRGBA{}, nil
+	}
+	if c, ok := colornames.Map[t]; ok {
+		return color.NRGBA{c.R, c.G, c.B, c.A}, nil
+	}
+
+	if open := strings.IndexByte(t, '('); open > 0 && strings.HasSuffix(t, ")") {
+		c, err := parseColorFunction(t[:open], t[open+1:len(t)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q: %s", s, err.Error())
+		}
+		return c, nil
+	}
+
+	c, ok := parseHexColor(strings.TrimPrefix(t, "#"))
+	if !ok {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return c, nil
+}
+
+// parseHexColor parses rgb, rgba, rrggbb or rrggbbaa hex digits.
+func parseHexColor(h string) (color.NRGBA, bool) {
+	if len(h) == 3 || len(h) == 4 {
+		long := make([]byte, 0, 8)
+		for i := 0; i < len(h); i++ {
+			long = append(long, h[i], h[i])
+		}
+		h = string(long)
+	}
+	if len(h) == 6 {
+		h += "ff"
+	}
+
+	v, err := strconv.ParseUint(h, 16, 32)
+	if len(h) != 8 || err != nil {
+		return color.NRGBA{}, false
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, true
+}
+
+// parseColorFunction parses the arguments of a CSS rgb(), rgba(), hsl() or
+// hsla() color.
+func parseColorFunction(name string, args string) (color.NRGBA, error) {
+	fields := strings.FieldsFunc(args, func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == '\t'
+	})
+	if len(fields) != 3 && len(fields) != 4 {
+		return color.NRGBA{}, fmt.Errorf("%s() takes 3 or 4 arguments", name)
+	}
+
+	alpha := 1.0
+	if len(fields) == 4 {
+		a, err := parseColorNumber(fields[3], 1)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		alpha = a
+	}
+
+	var r, g, b float64
+	switch name {
+	case "rgb", "rgba":
+		var channels [3]float64
+		for i := range channels {
+			v, err := parseColorNumber(fields[i], 255)
+			if err != nil {
+				return color.NRGBA{}, err
+			}
+			channels[i] = v
+		}
+		r, g, b = channels[0], channels[1], channels[2]
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(fields[0], "deg"), 64)
+		if err != nil {
+			return color.NRGBA{}, fmt.Errorf("invalid hue %q", fields[0])
+		}
+		if !strings.HasSuffix(fields[1], "%") || !strings.HasSuffix(fields[2], "%") {
+			return color.NRGBA{}, fmt.Errorf("saturation and lightness must be percentages")
+		}
+		s, err := parseColorNumber(fields[1], 1)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		l, err := parseColorNumber(fields[2], 1)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		r, g, b = hslToRGB(h, s, l)
+	default:
+		return color.NRGBA{}, fmt.Errorf("unknown color function %s()", name)
+	}
+
+	return color.NRGBA{R: colorByte(r), G: colorByte(g), B: colorByte(b), A: colorByte(alpha)}, nil
+}
+
+// parseColorNumber parses a number in the range 0-max, or a percentage, and
+// returns it as a fraction in the range 0-1.
+func parseColorNumber(s string, max float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSuffix(s, "%")
+		max = 100
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	return math.Max(0, math.Min(1, v/max)), nil
+}
+
+// hslToRGB converts a hue in degrees, and saturation and lightness in the
+// range 0-1, to red, green and blue in the range 0-1.
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return r + m, g + m, b + m
+}
+
+// colorByte converts v in the range 0-1 to 0-255.
+func colorByte(v float64) uint8 {
+	return uint8(math.Round(v * 255))
+}
@@ -0,0 +1,49 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected color.NRGBA
+	}{
+		{"#1a73e8", color.NRGBA{0x1a, 0x73, 0xe8, 0xff}},
+		{"1A73E8", color.NRGBA{0x1a, 0x73, 0xe8, 0xff}},
+		{"#1a73e880", color.NRGBA{0x1a, 0x73, 0xe8, 0x80}},
+		{"#fff", color.NRGBA{0xff, 0xff, 0xff, 0xff}},
+		{"#f008", color.NRGBA{0xff, 0, 0, 0x88}},
+		{"rgb(26, 115, 232)", color.NRGBA{26, 115, 232, 0xff}},
+		{"RGBA(26,115,232,0.5)", color.NRGBA{26, 115, 232, 0x80}},
+		{"rgb(100% 0% 50% / 25%)", color.NRGBA{0xff, 0, 0x80, 0x40}},
+		{"rgb(300, -5, 0)", color.NRGBA{0xff, 0, 0, 0xff}},
+		{"hsl(0, 100%, 50%)", color.NRGBA{0xff, 0, 0, 0xff}},
+		{"hsl(120deg 100% 25%)", color.NRGBA{0, 0x80, 0, 0xff}},
+		{"hsla(240, 100%, 50%, 0)", color.NRGBA{0, 0, 0xff, 0}},
+		{" CornflowerBlue ", color.NRGBA{100, 149, 237, 0xff}},
+		{"transparent", color.NRGBA{}},
+	}
+
+	for _, test := range tests {
+		c, err := ParseColor(test.s)
+		if err != nil {
+			t.Errorf("ParseColor(%q) failed: %s", test.s, err.Error())
+			continue
+		}
+		if c != test.expected {
+			t.Errorf("ParseColor(%q) = %v, expected %v", test.s, c, test.expected)
+		}
+	}
+
+	for _, s := range []string{"", "#12", "#12345", "#gggggg", "rgb(1, 2)", "rgb(a, b, c)",
+		"hsl(0, 1, 1)", "cmyk(0, 0, 0, 1)", "notacolor"} {
+		if _, err := ParseColor(s); err == nil {
+			t.Errorf("ParseColor(%q) succeeded, expected error", s)
+		}
+	}
+}
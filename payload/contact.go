@@ -0,0 +1,230 @@
+package payload
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// WiFi is a Wi-Fi network configuration, which phones offer to join.
+type WiFi struct {
+	// Network name, 1-32 bytes.
+	SSID string
+
+	// Security of the network and its Password: 8-63 characters or 64
+	// hex digits for WPA, 5 or 13 characters or 10 or 26 hex digits for
+	// WEP, and none for an open network.
+	Security WiFiSecurity
+	Password string
+
+	// Hidden is set for a network that does not broadcast its SSID.
+	Hidden bool
+}
+
+// WiFiSecurity is the security of a WiFi network.
+type WiFiSecurity string
+
+const (
+	// WiFiOpen is a network without a password.
+	WiFiOpen WiFiSecurity = ""
+	// WiFiWPA is a WPA, WPA2 or WPA3 personal network.
+	WiFiWPA WiFiSecurity = "WPA"
+	// WiFiWEP is a WEP network.
+	WiFiWEP WiFiSecurity = "WEP"
+)
+
+// Validate returns the FieldErrors of an invalid SSID, security or password.
+func (w WiFi) Validate() error {
+	var v validator
+	v.checkf(len(w.SSID) >= 1 && len(w.SSID) <= 32, "SSID", w.SSID,
+		"SSID is %d bytes long, expected 1-32", len(w.SSID))
+
+	isHex := func(s string, lengths ...int) bool {
+		for _, n := range lengths {
+			if len(s) == n && strings.Trim(strings.ToLower(s), "0123456789abcdef") == "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	p := w.Password
+	switch w.Security {
+	case WiFiOpen:
+		v.checkf(p == "", "Password", p, "open networks have no password, set Security")
+	case WiFiWPA:
+		v.checkf(len(p) >= 8 && len(p) <= 63 || isHex(p, 64), "Password", p,
+			"WPA passwords are 8-63 characters or 64 hex digits, got %d characters", len(p))
+	case WiFiWEP:
+		v.checkf(len(p) == 5 || len(p) == 13 || isHex(p, 10, 26), "Password", p,
+			"WEP keys are 5 or 13 characters or 10 or 26 hex digits, got %d characters", len(p))
+	default:
+		v.checkf(false, "Security", string(w.Security), "unknown Wi-Fi security %q", w.Security)
+	}
+
+	return v.err()
+}
+
+// Build returns the "WIFI:" configuration, or the errors of Validate.
+func (w WiFi) Build() (string, error) {
+	if err := w.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("WIFI:T:")
+	if w.Security == WiFiOpen {
+		b.WriteString("nopass")
+	} else {
+		b.WriteString(string(w.Security))
+	}
+	b.WriteString(";S:")
+	// Readers may take an SSID of hex digits for the hex bytes of the name.
+	ssid := wifiEscape(w.SSID)
+	if len(ssid)%2 == 0 && strings.Trim(strings.ToLower(ssid), "0123456789abcdef") == "" {
+		ssid = `"` + ssid + `"`
+	}
+	b.WriteString(ssid)
+	if w.Password != "" {
+		b.WriteString(";P:")
+		b.WriteString(wifiEscape(w.Password))
+	}
+	if w.Hidden {
+		b.WriteString(";H:true")
+	}
+	b.WriteString(";;")
+
+	return b.String(), nil
+}
+
+// wifiEscape escapes the special characters of a WIFI: value.
+func wifiEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `"`, `\"`, `:`, `\:`).Replace(s)
+}
+
+// Email is a mailto: link composing an email.
+type Email struct {
+	// Recipient address, e.g. "support@example.com", and optional copies.
+	To string
+	Cc []string
+
+	// Optional subject and body.
+	Subject string
+	Body    string
+}
+
+// Validate returns the FieldErrors of an invalid address.
+func (e Email) Validate() error {
+	var v validator
+	v.check("To", e.To, checkEmail(e.To))
+	for _, cc := range e.Cc {
+		v.check("Cc", cc, checkEmail(cc))
+	}
+	return v.err()
+}
+
+// Build returns the link, or the errors of Validate.
+func (e Email) Build() (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+
+	var params []string
+	if len(e.Cc) > 0 {
+		params = append(params, "cc="+strings.Join(e.Cc, ","))
+	}
+	if e.Subject != "" {
+		params = append(params, "subject="+queryEscape(e.Subject))
+	}
+	if e.Body != "" {
+		params = append(params, "body="+queryEscape(e.Body))
+	}
+
+	s := "mailto:" + e.To
+	if len(params) > 0 {
+		s += "?" + strings.Join(params, "&")
+	}
+
+	return s, nil
+}
+
+// checkEmail returns an error if address is not a plain email address, e.g.
+// "name@example.com".
+func checkEmail(address string) error {
+	a, err := mail.ParseAddress(address)
+	if err != nil || a.Name != "" || a.Address != address {
+		return fmt.Errorf("invalid email address %q, expected e.g. name@example.com", address)
+	}
+	if strings.ContainsAny(address, "?&#%") {
+		return fmt.Errorf("email address %q has characters not allowed in a mailto: link", address)
+	}
+	return nil
+}
+
+// Phone is a tel: link calling a number.
+type Phone struct {
+	// International number in E.164 form, "+" and the country code, e.g.
+	// "+44 7700 900123". Spaces, dashes, dots and parentheses are ignored.
+	Number string
+}
+
+// Validate returns the FieldError of an invalid number.
+func (p Phone) Validate() error {
+	var v validator
+	_, err := e164(p.Number)
+	v.check("Number", p.Number, err)
+	return v.err()
+}
+
+// Build returns the link, or the error of Validate.
+func (p Phone) Build() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	number, _ := e164(p.Number)
+
+	return "tel:" + number, nil
+}
+
+// SMS is a text message to a number, which phones open in the messaging app.
+type SMS struct {
+	// International number in E.164 form, as for Phone.
+	Number string
+
+	// Optional message.
+	Body string
+}
+
+// Validate returns the FieldError of an invalid number.
+func (s SMS) Validate() error {
+	var v validator
+	_, err := e164(s.Number)
+	v.check("Number", s.Number, err)
+	return v.err()
+}
+
+// Build returns the "SMSTO:" message, or the error of Validate.
+func (s SMS) Build() (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	number, _ := e164(s.Number)
+
+	return "SMSTO:" + number + ":" + s.Body, nil
+}
+
+// e164 returns phone in E.164 form, "+" and the digits, or an error if it is
+// not an international number.
+func e164(phone string) (string, error) {
+	s := strings.TrimSpace(phone)
+	if !strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "00") {
+		return "", fmt.Errorf("phone number %q has no country code, expected e.g. +44 7700 900123", phone)
+	}
+
+	digits, err := phoneDigits(s)
+	if err != nil {
+		return "", err
+	}
+
+	return "+" + digits, nil
+}
@@ -0,0 +1,68 @@
+package payload
+
+import "testing"
+
+func TestWiFi(t *testing.T) {
+	tests := []struct {
+		w        WiFi
+		expected string
+	}{
+		{WiFi{SSID: "Home", Security: WiFiWPA, Password: "correct horse"}, "WIFI:T:WPA;S:Home;P:correct horse;;"},
+		{WiFi{SSID: `Caf\é; "2.4":GHz`, Security: WiFiWPA, Password: "a;b,c:d\\e", Hidden: true},
+			`WIFI:T:WPA;S:Caf\\é\; \"2.4\"\:GHz;P:a\;b\,c\:d\\e;H:true;;`},
+		{WiFi{SSID: "Guest"}, "WIFI:T:nopass;S:Guest;;"},
+		{WiFi{SSID: "CAFE", Security: WiFiWEP, Password: "0123456789"}, `WIFI:T:WEP;S:"CAFE";P:0123456789;;`},
+	}
+
+	for _, test := range tests {
+		got, err := test.w.Build()
+		if err != nil || got != test.expected {
+			t.Errorf("got %q, %v, expected %q", got, err, test.expected)
+		}
+	}
+
+	invalid := []WiFi{
+		{},
+		{SSID: "Home", Password: "secret"},
+		{SSID: "Home", Security: WiFiWPA, Password: "short"},
+		{SSID: "Home", Security: WiFiWEP, Password: "123456"},
+		{SSID: "Home", Security: "WPA3", Password: "correct horse"},
+	}
+	for _, w := range invalid {
+		if got, err := w.Build(); err == nil {
+			t.Errorf("%+v: got %q, expected error", w, got)
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	got, err := Email{To: "support@example.com", Cc: []string{"a@example.com", "b@example.com"}, Subject: "Order 42", Body: "Hi & thanks"}.Build()
+	expected := "mailto:support@example.com?cc=a@example.com,b@example.com&subject=Order%2042&body=Hi%20%26%20thanks"
+	if err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+
+	for _, to := range []string{"", "support", "support@", "Support <support@example.com>", "a?b@example.com"} {
+		if got, err := (Email{To: to}).Build(); err == nil {
+			t.Errorf("%q: got %q, expected error", to, got)
+		}
+	}
+}
+
+func TestPhoneAndSMS(t *testing.T) {
+	got, err := Phone{Number: "0044 7700 900123"}.Build()
+	if expected := "tel:+447700900123"; err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+
+	got, err = SMS{Number: "+1 (202) 555-0100", Body: "STOP"}.Build()
+	if expected := "SMSTO:+12025550100:STOP"; err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+
+	for _, number := range []string{"", "202 555 0100", "+1 202", "+1 202 555 0100 1234 567", "+1 202 555 O100"} {
+		if got, err := (SMS{Number: number}).Build(); err == nil {
+			t.Errorf("%q: got %q, expected error", number, got)
+		}
+	}
+}
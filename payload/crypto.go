@@ -0,0 +1,295 @@
+package payload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bitcoin is a BIP-21 bitcoin: payment URI.
+type Bitcoin struct {
+	// Legacy (1..., 3...) or SegWit (bc1...) address; testnet addresses are
+	// accepted too.
+	Address string
+
+	// Optional amount in BTC, as a decimal with at most 8 decimal places,
+	// e.g. "0.0005".
+	Amount string
+
+	// Optional label for the address and message for the payment, shown by
+	// the wallet.
+	Label   string
+	Message string
+}
+
+// Validate returns the FieldErrors of an invalid address or amount.
+func (b Bitcoin) Validate() error {
+	var v validator
+	v.check("Address", b.Address, checkBitcoinAddress(b.Address))
+	if b.Amount != "" {
+		v.check("Amount", b.Amount, checkDecimal(b.Amount, 8))
+	}
+	return v.err()
+}
+
+// Build returns the URI, or the errors of Validate.
+func (b Bitcoin) Build() (string, error) {
+	if err := b.Validate(); err != nil {
+		return "", err
+	}
+
+	var params []string
+	if b.Amount != "" {
+		params = append(params, "amount="+b.Amount)
+	}
+	if b.Label != "" {
+		params = append(params, "label="+queryEscape(b.Label))
+	}
+	if b.Message != "" {
+		params = append(params, "message="+queryEscape(b.Message))
+	}
+
+	s := "bitcoin:" + b.Address
+	if len(params) > 0 {
+		s += "?" + strings.Join(params, "&")
+	}
+
+	return s, nil
+}
+
+// Ethereum is an EIP-681 ethereum: payment URI.
+type Ethereum struct {
+	// Address, "0x" and 40 hex digits. Mixed case addresses must have a
+	// valid EIP-55 checksum; all lower or upper case addresses are
+	// checksummed in the URI.
+	Address string
+
+	// Optional chain id, e.g. 1 for mainnet. Zero omits it.
+	ChainID uint64
+
+	// Optional amount in wei, as a decimal integer, e.g.
+	// "1000000000000000000" for 1 ether.
+	Value string
+}
+
+// Validate returns the FieldErrors of an invalid address or value.
+func (e Ethereum) Validate() error {
+	var v validator
+	_, err := checksumEthereumAddress(e.Address)
+	v.check("Address", e.Address, err)
+	if e.Value != "" {
+		v.checkf(allDigits(e.Value), "Value", e.Value,
+			"invalid ethereum value %q (expected an integer number of wei)", e.Value)
+	}
+	return v.err()
+}
+
+// Build returns the URI, or the errors of Validate.
+func (e Ethereum) Build() (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	address, _ := checksumEthereumAddress(e.Address)
+
+	s := "ethereum:" + address
+	if e.ChainID > 0 {
+		s += "@" + strconv.FormatUint(e.ChainID, 10)
+	}
+	if e.Value != "" {
+		s += "?value=" + e.Value
+	}
+
+	return s, nil
+}
+
+// checkDecimal returns an error if s is not a non-negative decimal number
+// with at most places decimal places.
+func checkDecimal(s string, places int) error {
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if !allDigits(whole) || (hasPoint && !allDigits(fraction)) {
+		return fmt.Errorf("%q is not a decimal number", s)
+	}
+	if len(fraction) > places {
+		return fmt.Errorf("%q has more than %d decimal places", s, places)
+	}
+	return nil
+}
+
+// checksumEthereumAddress returns address with its EIP-55 checksum, or an
+// error if it is malformed or a mixed case address has an invalid checksum.
+func checksumEthereumAddress(address string) (string, error) {
+	hexAddress, ok := strings.CutPrefix(address, "0x")
+	if !ok || len(hexAddress) != 40 {
+		return "", fmt.Errorf("invalid ethereum address %q", address)
+	}
+	if _, err := hex.DecodeString(hexAddress); err != nil {
+		return "", fmt.Errorf("invalid ethereum address %q", address)
+	}
+
+	lower := strings.ToLower(hexAddress)
+	digest := keccak256([]byte(lower))
+
+	// Letters are upper case where the digest nibble is 8 or more.
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		nibble := digest[i/2] >> 4
+		if i%2 == 1 {
+			nibble = digest[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	mixedCase := hexAddress != lower && hexAddress != strings.ToUpper(hexAddress)
+	if mixedCase && hexAddress != string(checksummed) {
+		return "", fmt.Errorf("ethereum address %q has an invalid EIP-55 checksum", address)
+	}
+
+	return "0x" + string(checksummed), nil
+}
+
+// checkBitcoinAddress returns an error if address is not a valid Base58Check
+// or Bech32/Bech32m bitcoin address.
+func checkBitcoinAddress(address string) error {
+	lower := strings.ToLower(address)
+	if strings.HasPrefix(lower, "bc1") || strings.HasPrefix(lower, "tb1") {
+		return checkSegwitAddress(address)
+	}
+
+	data, err := base58Decode(address)
+	if err != nil || len(data) != 25 {
+		return fmt.Errorf("invalid bitcoin address %q", address)
+	}
+
+	switch data[0] {
+	case 0x00, 0x05, 0x6f, 0xc4: // P2PKH and P2SH, mainnet and testnet.
+	default:
+		return fmt.Errorf("invalid bitcoin address %q: unknown version %d", address, data[0])
+	}
+
+	first := sha256.Sum256(data[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], data[21:]) {
+		return fmt.Errorf("bitcoin address %q has an invalid checksum", address)
+	}
+
+	return nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Decode returns the bytes encoded in the Bitcoin Base58 string s.
+func base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty base58 string")
+	}
+
+	// Big endian base 256 digits of the value, built up one base 58 digit at
+	// a time.
+	var value []byte
+	for _, c := range []byte(s) {
+		digit := strings.IndexByte(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+
+		carry := digit
+		for i := len(value) - 1; i >= 0; i-- {
+			carry += int(value[i]) * 58
+			value[i] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			value = append([]byte{byte(carry)}, value...)
+			carry >>= 8
+		}
+	}
+
+	// Leading "1"s are leading zero bytes.
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+
+	return append(make([]byte, zeros), value...), nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32 (BIP-173) and Bech32m (BIP-350) checksum constants.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// checkSegwitAddress returns an error if address is not a valid SegWit
+// address: Bech32 for witness version 0, Bech32m for later versions.
+func checkSegwitAddress(address string) error {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return fmt.Errorf("bitcoin address %q mixes upper and lower case", address)
+	}
+	s := strings.ToLower(address)
+
+	sep := strings.LastIndexByte(s, '1')
+	hrp, rest := s[:sep], s[sep+1:]
+	if len(s) > 90 || len(rest) < 7 {
+		return fmt.Errorf("invalid bitcoin address %q", address)
+	}
+
+	values := make([]byte, len(rest))
+	for i := range rest {
+		v := strings.IndexByte(bech32Charset, rest[i])
+		if v < 0 {
+			return fmt.Errorf("invalid bitcoin address %q: invalid character %q", address, rest[i])
+		}
+		values[i] = byte(v)
+	}
+
+	version := values[0]
+	want := uint32(bech32Const)
+	if version > 0 {
+		want = bech32mConst
+	}
+	if version > 16 || bech32Polymod(hrp, values) != want {
+		return fmt.Errorf("bitcoin address %q has an invalid checksum", address)
+	}
+
+	// The witness program, converted from 5 to 8 bit groups.
+	program := values[1 : len(values)-6]
+	n := len(program) * 5 / 8
+	if len(program)*5%8 >= 5 || n < 2 || n > 40 || (version == 0 && n != 20 && n != 32) {
+		return fmt.Errorf("invalid bitcoin address %q: invalid witness program length", address)
+	}
+
+	return nil
+}
+
+// bech32Polymod returns the Bech32 checksum polynomial of hrp and values.
+func bech32Polymod(hrp string, values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	step := func(v byte) {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 != 0 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] >> 5)
+	}
+	step(0)
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] & 31)
+	}
+	for _, v := range values {
+		step(v)
+	}
+
+	return chk
+}
@@ -0,0 +1,98 @@
+package payload
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBitcoin(t *testing.T) {
+	tests := []struct {
+		b        Bitcoin
+		expected string
+	}{
+		{Bitcoin{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}, "bitcoin:1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{Bitcoin{Address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", Amount: "0.0005", Label: "Café & Bar", Message: "Order #42"},
+			"bitcoin:3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy?amount=0.0005&label=Caf%C3%A9%20%26%20Bar&message=Order%20%2342"},
+		{Bitcoin{Address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", Amount: "1"},
+			"bitcoin:BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4?amount=1"},
+		{Bitcoin{Address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+			"bitcoin:bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"},
+		{Bitcoin{Address: "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+			"bitcoin:tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+	}
+
+	for _, test := range tests {
+		got, err := test.b.Build()
+		if err != nil {
+			t.Errorf("%s: %v", test.b.Address, err)
+		} else if got != test.expected {
+			t.Errorf("got %q, expected %q", got, test.expected)
+		}
+	}
+
+	invalid := []Bitcoin{
+		{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"},                        // Checksum.
+		{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a"},                        // Base58 has no 0.
+		{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"},                // Checksum.
+		{Address: "bc1qw508d6qejxtdg4y5r3zarvaRy0c5xw7kv8f3t4"},                // Mixed case.
+		{Address: "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du"},                     // Bech32 checksum with version 2.
+		{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Amount: "0.000000001"}, // 9 decimal places.
+		{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Amount: "1e-3"},
+		{Address: ""},
+	}
+	for _, b := range invalid {
+		if got, err := b.Build(); err == nil {
+			t.Errorf("%+v: got %q, expected error", b, got)
+		}
+	}
+}
+
+func TestEthereum(t *testing.T) {
+	// EIP-55 examples.
+	for _, address := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		got, err := Ethereum{Address: address}.Build()
+		if err != nil || got != "ethereum:"+address {
+			t.Errorf("%s: got %q, %v", address, got, err)
+		}
+	}
+
+	got, err := Ethereum{Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ChainID: 1, Value: "1000000000000000000"}.Build()
+	if expected := "ethereum:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed@1?value=1000000000000000000"; err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+
+	invalid := []Ethereum{
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"}, // Checksum.
+		{Address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA"},
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg"},
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Value: "1.5"},
+	}
+	for _, e := range invalid {
+		if got, err := e.Build(); err == nil {
+			t.Errorf("%+v: got %q, expected error", e, got)
+		}
+	}
+}
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+
+	for _, test := range tests {
+		got := keccak256([]byte(test.data))
+		if hex.EncodeToString(got[:]) != test.expected {
+			t.Errorf("keccak256(%q) = %x, expected %s", test.data, got, test.expected)
+		}
+	}
+}
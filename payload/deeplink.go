@@ -0,0 +1,198 @@
+package payload
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AndroidIntent is an Android intent:// link, opening an app at a path and
+// falling back to a web page if the app is not installed.
+type AndroidIntent struct {
+	// Package name of the app, e.g. "com.example.app".
+	Package string
+
+	// Scheme the app handles, e.g. "https" or "exampleapp".
+	Scheme string
+
+	// Host and path to open, e.g. "example.com/offers/42".
+	Path string
+
+	// Optional page opened in the browser if the app is not installed.
+	FallbackURL string
+}
+
+// Validate returns the FieldErrors of an invalid package, scheme or fallback
+// URL.
+func (a AndroidIntent) Validate() error {
+	var v validator
+	v.check("Package", a.Package, checkPackage(a.Package))
+	v.checkf(a.Scheme != "" && !strings.ContainsAny(a.Scheme, ";#:/ "), "Scheme", a.Scheme,
+		"invalid intent scheme %q", a.Scheme)
+	if a.FallbackURL != "" {
+		v.check("FallbackURL", a.FallbackURL, checkWebURL(a.FallbackURL))
+	}
+	return v.err()
+}
+
+// Build returns the intent link, or the errors of Validate.
+func (a AndroidIntent) Build() (string, error) {
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("intent://")
+	b.WriteString((&url.URL{Path: strings.TrimPrefix(a.Path, "//")}).EscapedPath())
+	b.WriteString("#Intent;scheme=")
+	b.WriteString(a.Scheme)
+	b.WriteString(";package=")
+	b.WriteString(a.Package)
+
+	if a.FallbackURL != "" {
+		b.WriteString(";S.browser_fallback_url=")
+		b.WriteString(url.QueryEscape(a.FallbackURL))
+	}
+	b.WriteString(";end")
+
+	return b.String(), nil
+}
+
+// AppStore returns the Apple App Store web URL of the app with the numeric
+// id, e.g. "id284882215" or "284882215". It opens the App Store app on iOS
+// and the store's web page elsewhere.
+func AppStore(appID string) (string, error) {
+	id := strings.TrimPrefix(appID, "id")
+	if !allDigits(id) {
+		return "", fmt.Errorf("invalid App Store app id %q", appID)
+	}
+
+	return "https://apps.apple.com/app/id" + id, nil
+}
+
+// PlayStore returns the Google Play web URL of the app with the package name,
+// e.g. "com.example.app". It opens the Play Store app on Android and the
+// store's web page elsewhere. referrer, if not empty, is passed to the app on
+// install for attribution.
+func PlayStore(packageName, referrer string) (string, error) {
+	if err := checkPackage(packageName); err != nil {
+		return "", err
+	}
+
+	s := "https://play.google.com/store/apps/details?id=" + packageName
+	if referrer != "" {
+		s += "&referrer=" + queryEscape(referrer)
+	}
+
+	return s, nil
+}
+
+// WhatsApp returns a wa.me link opening a chat with phone, an international
+// number with country code, with text pre-filled if not empty.
+func WhatsApp(phone, text string) (string, error) {
+	digits, err := phoneDigits(phone)
+	if err != nil {
+		return "", err
+	}
+
+	s := "https://wa.me/" + digits
+	if text != "" {
+		s += "?text=" + queryEscape(text)
+	}
+
+	return s, nil
+}
+
+// Telegram returns a t.me link to the user, group, channel or bot username
+// (with or without "@"). start, if not empty, is passed to a bot as its
+// start parameter (at most 64 characters of A-Z, a-z, 0-9, _ and -).
+func Telegram(username, start string) (string, error) {
+	name := strings.TrimPrefix(username, "@")
+	if len(name) < 5 || len(name) > 32 || !onlyChars(name, "_") {
+		return "", fmt.Errorf("invalid Telegram username %q", username)
+	}
+
+	s := "https://t.me/" + name
+	if start != "" {
+		if len(start) > 64 || !onlyChars(start, "_-") {
+			return "", fmt.Errorf("invalid Telegram start parameter %q", start)
+		}
+		s += "?start=" + start
+	}
+
+	return s, nil
+}
+
+// FaceTime returns a link starting a FaceTime call with target, a phone
+// number or email address, audio only if audioOnly is set.
+func FaceTime(target string, audioOnly bool) (string, error) {
+	t := strings.TrimSpace(target)
+
+	if !strings.Contains(t, "@") {
+		digits, err := phoneDigits(t)
+		if err != nil {
+			return "", err
+		}
+		t = "+" + digits
+	} else if strings.ContainsAny(t, " /?#") {
+		return "", fmt.Errorf("invalid FaceTime address %q", target)
+	}
+
+	if audioOnly {
+		return "facetime-audio:" + t, nil
+	}
+	return "facetime:" + t, nil
+}
+
+// Zoom returns a link joining the Zoom meeting with the numeric id (spaces
+// and dashes are ignored), with the passcode pre-filled if not empty.
+func Zoom(meetingID, passcode string) (string, error) {
+	id := strings.NewReplacer(" ", "", "-", "").Replace(meetingID)
+	if !allDigits(id) || len(id) < 9 || len(id) > 11 {
+		return "", fmt.Errorf("invalid Zoom meeting id %q", meetingID)
+	}
+
+	s := "https://zoom.us/j/" + id
+	if passcode != "" {
+		s += "?pwd=" + queryEscape(passcode)
+	}
+
+	return s, nil
+}
+
+// checkPackage returns an error if name is not a valid Android package name.
+func checkPackage(name string) error {
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid Android package name %q", name)
+	}
+
+	for _, p := range parts {
+		if p == "" || !onlyChars(p, "_") || (p[0] >= '0' && p[0] <= '9') || p[0] == '_' {
+			return fmt.Errorf("invalid Android package name %q", name)
+		}
+	}
+
+	return nil
+}
+
+// checkWebURL returns an error if s is not an absolute http or https URL.
+func checkWebURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid web URL %q", s)
+	}
+	return nil
+}
+
+// onlyChars returns true if s only contains ASCII letters, digits and the
+// characters of extra.
+func onlyChars(s, extra string) bool {
+	for _, c := range s {
+		isAlnum := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !isAlnum && !strings.ContainsRune(extra, c) {
+			return false
+		}
+	}
+	return true
+}
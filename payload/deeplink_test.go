@@ -0,0 +1,69 @@
+package payload
+
+import "testing"
+
+func TestDeepLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() (string, error)
+		expected string
+	}{
+		{"intent", AndroidIntent{
+			Package:     "com.example.app",
+			Scheme:      "https",
+			Path:        "example.com/offers/summer sale",
+			FallbackURL: "https://example.com/offers?id=42&ref=qr",
+		}.Build, "intent://example.com/offers/summer%20sale#Intent;scheme=https;package=com.example.app;" +
+			"S.browser_fallback_url=https%3A%2F%2Fexample.com%2Foffers%3Fid%3D42%26ref%3Dqr;end"},
+		{"intent without fallback", AndroidIntent{Package: "com.example.app", Scheme: "exampleapp", Path: "open"}.Build,
+			"intent://open#Intent;scheme=exampleapp;package=com.example.app;end"},
+		{"app store", func() (string, error) { return AppStore("id284882215") }, "https://apps.apple.com/app/id284882215"},
+		{"play store", func() (string, error) { return PlayStore("com.example.app", "utm_source=poster&utm_medium=qr") },
+			"https://play.google.com/store/apps/details?id=com.example.app&referrer=utm_source%3Dposter%26utm_medium%3Dqr"},
+		{"whatsapp", func() (string, error) { return WhatsApp("+44 7700 900123", "Hi! Is 5 & 6 free?") },
+			"https://wa.me/447700900123?text=Hi%21%20Is%205%20%26%206%20free%3F"},
+		{"whatsapp 00", func() (string, error) { return WhatsApp("0044-7700-900123", "") }, "https://wa.me/447700900123"},
+		{"telegram", func() (string, error) { return Telegram("@example_bot", "ref-poster_1") },
+			"https://t.me/example_bot?start=ref-poster_1"},
+		{"facetime", func() (string, error) { return FaceTime("+1 (555) 010-0199", false) }, "facetime:+15550100199"},
+		{"facetime audio", func() (string, error) { return FaceTime("someone@example.com", true) },
+			"facetime-audio:someone@example.com"},
+		{"zoom", func() (string, error) { return Zoom("123 4567 8901", "a b&c") },
+			"https://zoom.us/j/12345678901?pwd=a%20b%26c"},
+	}
+
+	for _, test := range tests {
+		got, err := test.build()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if got != test.expected {
+			t.Errorf("%s: got %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestDeepLinksInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() (string, error)
+	}{
+		{"intent package", AndroidIntent{Package: "example", Scheme: "https"}.Build},
+		{"intent package digit", AndroidIntent{Package: "com.1example", Scheme: "https"}.Build},
+		{"intent scheme", AndroidIntent{Package: "com.example.app", Scheme: "https;x"}.Build},
+		{"intent fallback", AndroidIntent{Package: "com.example.app", Scheme: "https", FallbackURL: "javascript:alert(1)"}.Build},
+		{"app store", func() (string, error) { return AppStore("example") }},
+		{"play store", func() (string, error) { return PlayStore("com..app", "") }},
+		{"whatsapp letters", func() (string, error) { return WhatsApp("+44 CALL ME", "") }},
+		{"whatsapp national", func() (string, error) { return WhatsApp("07700 900123", "") }},
+		{"telegram short", func() (string, error) { return Telegram("abc", "") }},
+		{"telegram start", func() (string, error) { return Telegram("example_bot", "a b") }},
+		{"facetime", func() (string, error) { return FaceTime("someone@example.com/x", false) }},
+		{"zoom", func() (string, error) { return Zoom("1234", "") }},
+	}
+
+	for _, test := range tests {
+		if got, err := test.build(); err == nil {
+			t.Errorf("%s: got %q, expected error", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MerchantPresented is an EMV QRCPS merchant-presented mode (MPM) payment
+// payload, the format of many national QR payment schemes. Build writes the
+// data objects in id order and appends the CRC.
+type MerchantPresented struct {
+	// Optional point of initiation method, data object 01.
+	Initiation Initiation
+
+	// Merchant account information, at least one, data objects 02-51.
+	Accounts []MerchantAccount
+
+	// Merchant category code (ISO 18245), 4 digits, e.g. "5411".
+	CategoryCode string
+
+	// Transaction currency, the ISO 4217 numeric code, e.g. "978" for EUR.
+	Currency string
+
+	// Optional transaction amount, as a decimal of at most 13 characters.
+	// Without it the payer enters the amount.
+	Amount string
+
+	// Optional tip or convenience fee. Fee is the fixed amount for
+	// TipFixed, or the percentage for TipPercentage.
+	Tip Tip
+	Fee string
+
+	// Country code (ISO 3166-1 alpha 2), merchant name (at most 25
+	// characters), city (at most 15) and optional postal code.
+	CountryCode  string
+	MerchantName string
+	MerchantCity string
+	PostalCode   string
+
+	// Optional additional data, data object 62.
+	AdditionalData *AdditionalData
+
+	// Optional merchant name and city in another language, data object 64.
+	Language *MerchantLanguage
+
+	// Optional unreserved templates, data objects 80-99.
+	Unreserved []Template
+}
+
+// Initiation is the point of initiation method of a MerchantPresented
+// payload.
+type Initiation int
+
+const (
+	// InitiationOmitted leaves out the point of initiation method.
+	InitiationOmitted Initiation = iota
+	// StaticCode is for codes used for more than one transaction.
+	StaticCode
+	// DynamicCode is for codes used for a single transaction.
+	DynamicCode
+)
+
+// Tip is the tip or convenience fee indicator of a MerchantPresented
+// payload.
+type Tip int
+
+const (
+	// NoTip leaves out the tip or convenience indicator.
+	NoTip Tip = iota
+	// TipPrompt has the app prompt the payer for a tip.
+	TipPrompt
+	// TipFixed adds the fixed convenience fee in Fee.
+	TipFixed
+	// TipPercentage adds a convenience fee of the percentage in Fee.
+	TipPercentage
+)
+
+// MerchantAccount is a merchant account information data object. Ids 02-25
+// are reserved for card networks and hold a Value, e.g. "04" for a
+// Mastercard merchant id. Ids 26-51 are templates identified by a GUI, e.g.
+// "br.gov.bcb.pix", with their own Fields.
+type MerchantAccount struct {
+	ID     string
+	Value  string
+	GUI    string
+	Fields []Field
+}
+
+// Template is a template data object identified by a GUI, with its own
+// Fields.
+type Template struct {
+	ID     string
+	GUI    string
+	Fields []Field
+}
+
+// Field is a data object within a template, with a two digit id.
+type Field struct {
+	ID    string
+	Value string
+}
+
+// AdditionalData is the additional data field template of a
+// MerchantPresented payload. All values are optional and at most 25
+// characters. A value of "***" asks the app to prompt the payer for it.
+type AdditionalData struct {
+	BillNumber     string
+	MobileNumber   string
+	StoreLabel     string
+	LoyaltyNumber  string
+	ReferenceLabel string
+	CustomerLabel  string
+	TerminalLabel  string
+	Purpose        string
+
+	// Consumer data the app should provide, any of "A" (address), "M"
+	// (mobile number) and "E" (email address), e.g. "ME".
+	ConsumerDataRequest string
+
+	// Payment system specific templates, ids 50-99.
+	Templates []Template
+}
+
+// MerchantLanguage is the merchant name and city in an alternate language.
+type MerchantLanguage struct {
+	// Language, ISO 639 alpha 2, e.g. "zh".
+	Preference string
+
+	// Merchant name (at most 25 characters) and optional city (at most 15).
+	Name string
+	City string
+}
+
+// Validate returns an error if a required field is missing or a field is too
+// long or invalid. Unlike the other payloads, it stops at the first invalid
+// field.
+func (m MerchantPresented) Validate() error {
+	_, err := m.Build()
+	return err
+}
+
+// Build returns the payload, or the error of Validate.
+func (m MerchantPresented) Build() (string, error) {
+	var w emvWriter
+	w.field("00", "01")
+
+	switch m.Initiation {
+	case InitiationOmitted:
+	case StaticCode:
+		w.field("01", "11")
+	case DynamicCode:
+		w.field("01", "12")
+	default:
+		return "", fieldError("Initiation", fmt.Sprint(m.Initiation), fmt.Errorf("invalid EMV point of initiation %d", m.Initiation))
+	}
+
+	if len(m.Accounts) == 0 {
+		return "", fieldError("Accounts", "", errors.New("EMV payload needs at least one merchant account"))
+	}
+	accounts := append([]MerchantAccount(nil), m.Accounts...)
+	sort.SliceStable(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	for i, a := range accounts {
+		if i > 0 && a.ID == accounts[i-1].ID {
+			return "", fieldError("Accounts", a.ID, fmt.Errorf("duplicate EMV merchant account id %s", a.ID))
+		}
+		switch {
+		case emvID(a.ID, 2, 25):
+			if a.Value == "" || a.GUI != "" || len(a.Fields) > 0 {
+				return "", fieldError("Accounts", a.ID, fmt.Errorf("EMV merchant account %s needs a Value and no GUI or Fields", a.ID))
+			}
+			w.field(a.ID, a.Value)
+		case emvID(a.ID, 26, 51):
+			if a.Value != "" {
+				return "", fieldError("Accounts", a.ID, fmt.Errorf("EMV merchant account %s is a template and takes a GUI and Fields, not a Value", a.ID))
+			}
+			value, err := emvTemplate(a.GUI, a.Fields)
+			if err != nil {
+				return "", fieldError("Accounts", a.ID, fmt.Errorf("EMV merchant account %s: %v", a.ID, err))
+			}
+			w.write(a.ID, value)
+		default:
+			return "", fieldError("Accounts", a.ID, fmt.Errorf("invalid EMV merchant account id %q, expected 02-51", a.ID))
+		}
+	}
+
+	if len(m.CategoryCode) != 4 || !allDigits(m.CategoryCode) {
+		return "", fieldError("CategoryCode", m.CategoryCode, fmt.Errorf("invalid EMV merchant category code %q, expected 4 digits", m.CategoryCode))
+	}
+	w.field("52", m.CategoryCode)
+
+	if len(m.Currency) != 3 || !allDigits(m.Currency) {
+		return "", fieldError("Currency", m.Currency, fmt.Errorf("invalid EMV currency %q, expected an ISO 4217 numeric code", m.Currency))
+	}
+	w.field("53", m.Currency)
+
+	if m.Amount != "" {
+		if err := checkDecimal(m.Amount, 12); err != nil || len(m.Amount) > 13 {
+			return "", fieldError("Amount", m.Amount, fmt.Errorf("invalid EMV amount %q, expected a decimal of at most 13 characters", m.Amount))
+		}
+		w.field("54", m.Amount)
+	}
+
+	switch m.Tip {
+	case NoTip, TipPrompt:
+		if m.Fee != "" {
+			return "", fieldError("Fee", m.Fee, errors.New("EMV convenience fee is only used with TipFixed or TipPercentage"))
+		}
+		if m.Tip == TipPrompt {
+			w.field("55", "01")
+		}
+	case TipFixed, TipPercentage:
+		if err := checkDecimal(m.Fee, 12); err != nil || len(m.Fee) > 13 {
+			return "", fieldError("Fee", m.Fee, fmt.Errorf("invalid EMV convenience fee %q, expected a decimal of at most 13 characters", m.Fee))
+		}
+		if m.Tip == TipFixed {
+			w.field("55", "02")
+			w.field("56", m.Fee)
+		} else {
+			w.field("55", "03")
+			w.field("57", m.Fee)
+		}
+	default:
+		return "", fieldError("Tip", fmt.Sprint(m.Tip), fmt.Errorf("invalid EMV tip indicator %d", m.Tip))
+	}
+
+	if len(m.CountryCode) != 2 || !onlyChars(m.CountryCode, "") || strings.ToUpper(m.CountryCode) != m.CountryCode {
+		return "", fieldError("CountryCode", m.CountryCode, fmt.Errorf("invalid EMV country code %q, expected e.g. \"BR\"", m.CountryCode))
+	}
+	w.field("58", m.CountryCode)
+
+	switch {
+	case m.MerchantName == "" || len(m.MerchantName) > 25:
+		return "", fieldError("MerchantName", m.MerchantName, fmt.Errorf("invalid EMV merchant name %q, expected 1-25 characters", m.MerchantName))
+	case m.MerchantCity == "" || len(m.MerchantCity) > 15:
+		return "", fieldError("MerchantCity", m.MerchantCity, fmt.Errorf("invalid EMV merchant city %q, expected 1-15 characters", m.MerchantCity))
+	case len(m.PostalCode) > 10:
+		return "", fieldError("PostalCode", m.PostalCode, fmt.Errorf("invalid EMV postal code %q, expected at most 10 characters", m.PostalCode))
+	}
+	w.field("59", m.MerchantName)
+	w.field("60", m.MerchantCity)
+	w.field("61", m.PostalCode)
+
+	if d := m.AdditionalData; d != nil {
+		value, err := d.build()
+		if err != nil {
+			return "", err
+		}
+		w.write("62", value)
+	}
+
+	if l := m.Language; l != nil {
+		switch {
+		case len(l.Preference) != 2 || !onlyChars(l.Preference, ""):
+			return "", fieldError("Language.Preference", l.Preference, fmt.Errorf("invalid EMV language %q, expected ISO 639 alpha 2", l.Preference))
+		case l.Name == "" || utf8.RuneCountInString(l.Name) > 25:
+			return "", fieldError("Language.Name", l.Name, fmt.Errorf("invalid EMV alternate merchant name %q, expected 1-25 characters", l.Name))
+		case utf8.RuneCountInString(l.City) > 15:
+			return "", fieldError("Language.City", l.City, fmt.Errorf("invalid EMV alternate merchant city %q, expected at most 15 characters", l.City))
+		}
+		var lw emvWriter
+		lw.field("00", l.Preference)
+		lw.text("01", l.Name)
+		lw.text("02", l.City)
+		if lw.err != nil {
+			return "", lw.err
+		}
+		w.write("64", lw.b.String())
+	}
+
+	if err := emvTemplates(&w, m.Unreserved, 80, 99); err != nil {
+		return "", err
+	}
+
+	return w.finish()
+}
+
+// build returns the value of the additional data field template.
+func (d *AdditionalData) build() (string, error) {
+	var w emvWriter
+	for _, f := range []Field{
+		{"01", d.BillNumber},
+		{"02", d.MobileNumber},
+		{"03", d.StoreLabel},
+		{"04", d.LoyaltyNumber},
+		{"05", d.ReferenceLabel},
+		{"06", d.CustomerLabel},
+		{"07", d.TerminalLabel},
+		{"08", d.Purpose},
+	} {
+		if len(f.Value) > 25 {
+			return "", fieldError("AdditionalData", f.Value, fmt.Errorf("EMV additional data field %s %q is longer than 25 characters", f.ID, f.Value))
+		}
+		w.field(f.ID, f.Value)
+	}
+
+	if r := d.ConsumerDataRequest; r != "" {
+		if len(r) > 3 || strings.Trim(r, "AME") != "" {
+			return "", fieldError("AdditionalData.ConsumerDataRequest", r, fmt.Errorf("invalid EMV consumer data request %q, expected any of \"A\", \"M\" and \"E\"", r))
+		}
+		w.field("09", r)
+	}
+
+	if err := emvTemplates(&w, d.Templates, 50, 99); err != nil {
+		return "", err
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+
+	return w.b.String(), nil
+}
+
+// emvTemplates writes templates, which must have distinct ids from min to
+// max, in id order.
+func emvTemplates(w *emvWriter, templates []Template, min, max int) error {
+	templates = append([]Template(nil), templates...)
+	sort.SliceStable(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	for i, t := range templates {
+		if !emvID(t.ID, min, max) {
+			return fmt.Errorf("invalid EMV template id %q, expected %02d-%02d", t.ID, min, max)
+		}
+		if i > 0 && t.ID == templates[i-1].ID {
+			return fmt.Errorf("duplicate EMV template id %s", t.ID)
+		}
+		value, err := emvTemplate(t.GUI, t.Fields)
+		if err != nil {
+			return fmt.Errorf("EMV template %s: %v", t.ID, err)
+		}
+		w.write(t.ID, value)
+	}
+
+	return nil
+}
+
+// emvTemplate returns the value of a template: the GUI as data object 00,
+// then fields, which must have distinct ids from 01 to 99, in id order.
+func emvTemplate(gui string, fields []Field) (string, error) {
+	if gui == "" {
+		return "", errors.New("template needs a GUI")
+	}
+
+	var w emvWriter
+	w.field("00", gui)
+
+	fields = append([]Field(nil), fields...)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+	for i, f := range fields {
+		if !emvID(f.ID, 1, 99) {
+			return "", fmt.Errorf("invalid field id %q, expected 01-99", f.ID)
+		}
+		if i > 0 && f.ID == fields[i-1].ID {
+			return "", fmt.Errorf("duplicate field id %s", f.ID)
+		}
+		w.field(f.ID, f.Value)
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+
+	return w.b.String(), nil
+}
+
+// emvID returns true if id is a two digit data object id from min to max.
+func emvID(id string, min, max int) bool {
+	if len(id) != 2 || !allDigits(id) {
+		return false
+	}
+	n := int(id[0]-'0')*10 + int(id[1]-'0')
+	return n >= min && n <= max
+}
+
+// emvWriter writes EMV QRCPS data objects: a two digit id, a two digit
+// length and the value. The first invalid object sets err, and later writes
+// are ignored.
+type emvWriter struct {
+	b   strings.Builder
+	err error
+}
+
+// field writes the data object id with value, unless value is empty. value
+// is printable ASCII.
+func (w *emvWriter) field(id, value string) {
+	for _, c := range value {
+		if c < 0x20 || c > 0x7e {
+			w.setErr(fmt.Errorf("EMV field %s has invalid character %q, only printable ASCII is allowed", id, c))
+			return
+		}
+	}
+	w.write(id, value)
+}
+
+// text writes the data object id with value, unless value is empty. value is
+// any printable text, as alternate language data objects allow.
+func (w *emvWriter) text(id, value string) {
+	for _, c := range value {
+		if !unicode.IsPrint(c) {
+			w.setErr(fmt.Errorf("EMV field %s has invalid character %q", id, c))
+			return
+		}
+	}
+	w.write(id, value)
+}
+
+// write writes the data object id with value, unless value is empty, without
+// checking its characters. Templates are written this way, as their fields
+// are already checked.
+func (w *emvWriter) write(id, value string) {
+	if w.err != nil || value == "" {
+		return
+	}
+
+	// Lengths are in characters, not bytes.
+	n := utf8.RuneCountInString(value)
+	if n > 99 {
+		w.err = fmt.Errorf("EMV field %s is %d characters long, the limit is 99", id, n)
+		return
+	}
+
+	fmt.Fprintf(&w.b, "%s%02d%s", id, n, value)
+}
+
+// setErr sets err, unless an earlier write failed.
+func (w *emvWriter) setErr(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// finish appends the CRC data object (id 63) and returns the payload.
+func (w *emvWriter) finish() (string, error) {
+	if w.err != nil {
+		return "", w.err
+	}
+
+	w.b.WriteString("6304")
+	s := w.b.String()
+
+	return fmt.Sprintf("%s%04X", s, crc16CCITT([]byte(s))), nil
+}
+
+// crc16CCITT returns the CRC-16/CCITT-FALSE of data: polynomial 0x1021,
+// initial value 0xffff, as EMV QRCPS specifies.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
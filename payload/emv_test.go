@@ -0,0 +1,132 @@
+package payload
+
+import (
+	"fmt"
+	"testing"
+)
+
+// emvExample is the EMV QRCPS MPM specification's example payload, with its
+// CRC of A13A.
+const emvExample = "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304"
+
+func TestCRC16CCITT(t *testing.T) {
+	if got := crc16CCITT([]byte(emvExample)); got != 0xa13a {
+		t.Errorf("got %04X, expected A13A", got)
+	}
+}
+
+func TestMerchantPresented(t *testing.T) {
+	// The specification's example; Build writes the data objects in id
+	// order.
+	m := MerchantPresented{
+		Initiation: DynamicCode,
+		Accounts: []MerchantAccount{
+			{ID: "31", GUI: "D15600000001", Fields: []Field{{"03", "12345678"}}},
+			{ID: "29", GUI: "D15600000000", Fields: []Field{{"05", "A93FO3230Q"}}},
+		},
+		CategoryCode: "4111",
+		Currency:     "156",
+		Amount:       "23.72",
+		Tip:          TipPrompt,
+		CountryCode:  "CN",
+		MerchantName: "BEST TRANSPORT",
+		MerchantCity: "BEIJING",
+		AdditionalData: &AdditionalData{
+			StoreLabel:          "1234",
+			CustomerLabel:       "***",
+			TerminalLabel:       "A6008667",
+			ConsumerDataRequest: "ME",
+		},
+		Language: &MerchantLanguage{Preference: "ZH", Name: "最佳运输", City: "北京"},
+		Unreserved: []Template{
+			{ID: "91", GUI: "A011223344998877", Fields: []Field{{"07", "12345678"}}},
+		},
+	}
+
+	expected := "000201" + "010212" +
+		"29300012D156000000000510A93FO3230Q" +
+		"31280012D15600000001030812345678" +
+		"52044111" + "5303156" + "540523.72" + "550201" +
+		"5802CN" + "5914BEST TRANSPORT" + "6007BEIJING" +
+		"6233030412340603***0708A60086670902ME" +
+		"64200002ZH0104最佳运输0202北京" +
+		"91320016A011223344998877070812345678" +
+		"6304"
+	expected += fmt.Sprintf("%04X", crc16CCITT([]byte(expected)))
+
+	got, err := m.Build()
+	if err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+}
+
+func TestMerchantPresentedFee(t *testing.T) {
+	m := MerchantPresented{
+		Accounts:     []MerchantAccount{{ID: "04", Value: "5413330089020011"}},
+		CategoryCode: "5812",
+		Currency:     "978",
+		Tip:          TipPercentage,
+		Fee:          "2.5",
+		CountryCode:  "DE",
+		MerchantName: "Imbiss",
+		MerchantCity: "Berlin",
+		PostalCode:   "10115",
+	}
+
+	expected := "000201" + "04165413330089020011" + "52045812" + "5303978" +
+		"550203" + "57032.5" + "5802DE" + "5906Imbiss" + "6006Berlin" + "610510115" + "6304"
+	expected += fmt.Sprintf("%04X", crc16CCITT([]byte(expected)))
+
+	got, err := m.Build()
+	if err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+}
+
+func TestMerchantPresentedInvalid(t *testing.T) {
+	valid := MerchantPresented{
+		Accounts:     []MerchantAccount{{ID: "26", GUI: "com.example", Fields: []Field{{"01", "42"}}}},
+		CategoryCode: "5812",
+		Currency:     "978",
+		CountryCode:  "DE",
+		MerchantName: "Imbiss",
+		MerchantCity: "Berlin",
+	}
+	if _, err := valid.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []func(m *MerchantPresented){
+		func(m *MerchantPresented) { m.Accounts = nil },
+		func(m *MerchantPresented) { m.Accounts = []MerchantAccount{{ID: "01", Value: "x"}} },
+		func(m *MerchantPresented) { m.Accounts = []MerchantAccount{{ID: "04"}} },
+		func(m *MerchantPresented) { m.Accounts = []MerchantAccount{{ID: "26", Value: "x"}} },
+		func(m *MerchantPresented) { m.Accounts = []MerchantAccount{{ID: "26", Fields: []Field{{"01", "42"}}}} },
+		func(m *MerchantPresented) { m.Accounts = append(m.Accounts, m.Accounts[0]) },
+		func(m *MerchantPresented) { m.Accounts[0].Fields = []Field{{"1", "42"}} },
+		func(m *MerchantPresented) { m.CategoryCode = "581" },
+		func(m *MerchantPresented) { m.Currency = "EUR" },
+		func(m *MerchantPresented) { m.Amount = "1,50" },
+		func(m *MerchantPresented) { m.Amount = "12345678901234" },
+		func(m *MerchantPresented) { m.Tip = TipFixed },
+		func(m *MerchantPresented) { m.Fee = "1" },
+		func(m *MerchantPresented) { m.CountryCode = "de" },
+		func(m *MerchantPresented) { m.MerchantName = "" },
+		func(m *MerchantPresented) { m.MerchantName = "Café" },
+		func(m *MerchantPresented) { m.MerchantCity = "A city name that is too long" },
+		func(m *MerchantPresented) {
+			m.AdditionalData = &AdditionalData{BillNumber: "A bill number that is too long"}
+		},
+		func(m *MerchantPresented) { m.AdditionalData = &AdditionalData{ConsumerDataRequest: "X"} },
+		func(m *MerchantPresented) { m.Language = &MerchantLanguage{Preference: "de"} },
+		func(m *MerchantPresented) { m.Unreserved = []Template{{ID: "79", GUI: "com.example"}} },
+	}
+	for i, change := range invalid {
+		m := valid
+		m.Accounts = append([]MerchantAccount(nil), valid.Accounts...)
+		change(&m)
+		if got, err := m.Build(); err == nil {
+			t.Errorf("%d: got %q, expected error", i, got)
+		}
+	}
+}
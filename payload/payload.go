@@ -0,0 +1,60 @@
+// Package payload builds QR Code content for common applications, with the
+// escaping and validation each expects:
+//
+//	content, err := payload.WhatsApp("+44 7700 900123", "Hello from the poster")
+//	q, err := qrcode.New(content)
+//
+// Builder types, e.g. SEPA and WiFi, have a Validate method, which Build
+// calls first, returning a FieldError for each invalid field so that
+// services can reject bad input field by field before encoding.
+package payload
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// queryEscape escapes s for a URL query value, with spaces as %20 rather
+// than "+", which some apps show literally.
+func queryEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// phoneDigits returns the digits of an international phone number, e.g.
+// "+44 7700 900123" or "0044-7700-900123", without the leading "+" or "00".
+func phoneDigits(phone string) (string, error) {
+	s := strings.TrimSpace(phone)
+	s = strings.TrimPrefix(s, "+")
+
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return "", fmt.Errorf("invalid character %q in phone number %q", c, phone)
+		}
+	}
+
+	digits := strings.TrimPrefix(b.String(), "00")
+	if len(digits) < 7 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("phone number %q is not an international number (country code and 7-15 digits)", phone)
+	}
+
+	return digits, nil
+}
+
+// allDigits returns true if s is a non-empty string of ASCII digits.
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
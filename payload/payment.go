@@ -0,0 +1,144 @@
+package payload
+
+import (
+	"strings"
+)
+
+// UPI is an Indian Unified Payments Interface upi://pay link.
+type UPI struct {
+	// Virtual payment address of the payee, e.g. "merchant@okbank".
+	Address string
+
+	// Payee name, shown by the app.
+	Name string
+
+	// Optional amount in INR, as a decimal with at most 2 decimal places.
+	Amount string
+
+	// Optional note shown with the payment, and optional transaction
+	// reference, e.g. an order or bill number.
+	Note      string
+	Reference string
+
+	// Optional 4 digit merchant category code.
+	MerchantCode string
+}
+
+// Validate returns the FieldErrors of an invalid address, name, amount or
+// merchant code.
+func (u UPI) Validate() error {
+	var v validator
+	handle, provider, ok := strings.Cut(u.Address, "@")
+	v.checkf(ok && handle != "" && provider != "" && onlyChars(handle, ".-_") && onlyChars(provider, ".-"),
+		"Address", u.Address, "invalid UPI address %q, expected e.g. name@bank", u.Address)
+	v.checkf(u.Name != "", "Name", u.Name, "UPI payee name is required")
+	if u.Amount != "" {
+		v.check("Amount", u.Amount, checkDecimal(u.Amount, 2))
+	}
+	if u.MerchantCode != "" {
+		v.checkf(len(u.MerchantCode) == 4 && allDigits(u.MerchantCode), "MerchantCode", u.MerchantCode,
+			"invalid UPI merchant code %q, expected 4 digits", u.MerchantCode)
+	}
+	return v.err()
+}
+
+// Build returns the link, or the errors of Validate.
+func (u UPI) Build() (string, error) {
+	if err := u.Validate(); err != nil {
+		return "", err
+	}
+
+	params := []string{"pa=" + queryEscape(u.Address), "pn=" + queryEscape(u.Name)}
+	if u.Amount != "" {
+		params = append(params, "am="+u.Amount)
+	}
+	params = append(params, "cu=INR")
+	if u.Note != "" {
+		params = append(params, "tn="+queryEscape(u.Note))
+	}
+	if u.Reference != "" {
+		params = append(params, "tr="+queryEscape(u.Reference))
+	}
+	if u.MerchantCode != "" {
+		params = append(params, "mc="+u.MerchantCode)
+	}
+
+	return "upi://pay?" + strings.Join(params, "&"), nil
+}
+
+// PIX is a Brazilian Pix static payment code (BR Code), a MerchantPresented
+// payload with a Pix merchant account.
+type PIX struct {
+	// Pix key of the receiver: a CPF or CNPJ number, phone number
+	// ("+55..."), email address or random key.
+	Key string
+
+	// Receiver name (at most 25 characters) and city (at most 15), in ASCII
+	// without accents.
+	Name string
+	City string
+
+	// Optional amount in BRL, as a decimal with at most 2 decimal places.
+	// Without it the payer enters the amount.
+	Amount string
+
+	// Optional transaction id, at most 25 letters and digits.
+	TxID string
+
+	// Optional description shown to the payer.
+	Description string
+}
+
+// Validate returns the FieldErrors of a missing, too long or invalid field.
+func (p PIX) Validate() error {
+	var v validator
+	v.checkf(p.Key != "" && len(p.Key) <= 77, "Key", p.Key,
+		"invalid Pix key %q, expected 1-77 characters", p.Key)
+	v.checkf(p.Name != "" && len(p.Name) <= 25, "Name", p.Name,
+		"invalid Pix receiver name %q, expected 1-25 characters", p.Name)
+	v.checkf(p.City != "" && len(p.City) <= 15, "City", p.City,
+		"invalid Pix receiver city %q, expected 1-15 characters", p.City)
+	if p.Amount != "" {
+		v.check("Amount", p.Amount, checkDecimal(p.Amount, 2))
+	}
+	v.checkf(len(p.TxID) <= 25 && onlyChars(p.TxID, ""), "TxID", p.TxID,
+		"invalid Pix transaction id %q, expected at most 25 letters and digits", p.TxID)
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	// Characters and the lengths of the merchant account template.
+	return p.merchantPresented().Validate()
+}
+
+// Build returns the payload, or the errors of Validate.
+func (p PIX) Build() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	return p.merchantPresented().Build()
+}
+
+// merchantPresented returns the payload as a MerchantPresented payload.
+func (p PIX) merchantPresented() MerchantPresented {
+	txID := p.TxID
+	if txID == "" {
+		txID = "***"
+	}
+
+	account := MerchantAccount{ID: "26", GUI: "br.gov.bcb.pix", Fields: []Field{{"01", p.Key}}}
+	if p.Description != "" {
+		account.Fields = append(account.Fields, Field{"02", p.Description})
+	}
+
+	return MerchantPresented{
+		Accounts:       []MerchantAccount{account},
+		CategoryCode:   "0000",
+		Currency:       "986",
+		Amount:         p.Amount,
+		CountryCode:    "BR",
+		MerchantName:   p.Name,
+		MerchantCity:   p.City,
+		AdditionalData: &AdditionalData{ReferenceLabel: txID},
+	}
+}
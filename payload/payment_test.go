@@ -0,0 +1,74 @@
+package payload
+
+import "testing"
+
+func TestUPI(t *testing.T) {
+	got, err := UPI{
+		Address:      "shop.42@okbank",
+		Name:         "Corner Shop",
+		Amount:       "149.50",
+		Note:         "Order 7 & tea",
+		MerchantCode: "5411",
+	}.Build()
+	expected := "upi://pay?pa=shop.42%40okbank&pn=Corner%20Shop&am=149.50&cu=INR&tn=Order%207%20%26%20tea&mc=5411"
+	if err != nil || got != expected {
+		t.Errorf("got %q, %v, expected %q", got, err, expected)
+	}
+
+	invalid := []UPI{
+		{Address: "shop", Name: "Shop"},
+		{Address: "shop@", Name: "Shop"},
+		{Address: "shop@okbank"},
+		{Address: "shop@okbank", Name: "Shop", Amount: "1.505"},
+		{Address: "shop@okbank", Name: "Shop", MerchantCode: "541"},
+	}
+	for _, u := range invalid {
+		if got, err := u.Build(); err == nil {
+			t.Errorf("%+v: got %q, expected error", u, got)
+		}
+	}
+}
+
+func TestPIX(t *testing.T) {
+	tests := []struct {
+		p        PIX
+		expected string
+	}{
+		// Example from the Banco Central do Brasil BR Code manual.
+		{
+			PIX{Key: "123e4567-e12b-12d1-a456-426655440000", Name: "Fulano de Tal", City: "BRASILIA"},
+			"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			PIX{Key: "fulano@example.com", Name: "Fulano de Tal", City: "BRASILIA", Amount: "10.00", TxID: "PEDIDO42", Description: "Cafe"},
+			"00020126480014br.gov.bcb.pix0118fulano@example.com0204Cafe520400005303986540510.005802BR5913Fulano de Tal6008BRASILIA62120508PEDIDO4263049466",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.p.Build()
+		if err != nil || got != test.expected {
+			t.Errorf("got %q, %v, expected %q", got, err, test.expected)
+		}
+	}
+}
+
+func TestPIXInvalid(t *testing.T) {
+	valid := PIX{Key: "fulano@example.com", Name: "Fulano de Tal", City: "BRASILIA"}
+
+	invalid := []func(p *PIX){
+		func(p *PIX) { p.Key = "" },
+		func(p *PIX) { p.Name = "A name longer than 25 characters" },
+		func(p *PIX) { p.City = "" },
+		func(p *PIX) { p.City = "São Paulo" },
+		func(p *PIX) { p.Amount = "10,00" },
+		func(p *PIX) { p.TxID = "pedido-42" },
+	}
+	for i, change := range invalid {
+		p := valid
+		change(&p)
+		if got, err := p.Build(); err == nil {
+			t.Errorf("%d: got %q, expected error", i, got)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SEPA is a European Payments Council SEPA credit transfer (EPC QR Code,
+// also known as GiroCode), read by most European banking apps.
+type SEPA struct {
+	// Beneficiary name, at most 70 characters.
+	Name string
+
+	// Beneficiary IBAN; spaces are ignored.
+	IBAN string
+
+	// Optional BIC of the beneficiary bank, 8 or 11 characters.
+	BIC string
+
+	// Optional amount in EUR, from 0.01 to 999999999.99, as a decimal with
+	// at most 2 decimal places. Without it the payer enters the amount.
+	Amount string
+
+	// Optional ISO 20022 purpose code, 4 letters, e.g. "GDDS".
+	Purpose string
+
+	// Optional remittance information, either a structured creditor
+	// Reference (at most 35 characters, e.g. an ISO 11649 "RF..."
+	// reference) or free Text (at most 140 characters).
+	Reference string
+	Text      string
+
+	// Optional note shown to the payer, at most 70 characters.
+	Information string
+}
+
+// Validate returns the FieldErrors of a missing, too long or invalid field.
+func (s SEPA) Validate() error {
+	var v validator
+	v.checkf(s.Name != "" && len([]rune(s.Name)) <= 70, "Name", s.Name,
+		"beneficiary name is required, at most 70 characters")
+	v.check("IBAN", s.IBAN, checkIBAN(s.IBAN))
+	if s.BIC != "" {
+		v.check("BIC", s.BIC, checkBIC(s.BIC))
+	}
+	if s.Amount != "" {
+		v.check("Amount", s.Amount, checkEuroAmount(s.Amount))
+	}
+	if s.Purpose != "" {
+		v.checkf(len(s.Purpose) == 4 && upperLetters(s.Purpose),
+			"Purpose", s.Purpose, "invalid purpose code %q, expected 4 upper case letters", s.Purpose)
+	}
+	if s.Reference != "" {
+		v.checkf(s.Text == "", "Text", s.Text, "only one of Reference and Text may be set")
+		v.check("Reference", s.Reference, checkCreditorReference(s.Reference))
+	}
+	v.checkf(len([]rune(s.Text)) <= 140, "Text", s.Text, "remittance text is longer than 140 characters")
+	v.checkf(len([]rune(s.Information)) <= 70, "Information", s.Information,
+		"information is longer than 70 characters")
+	return v.err()
+}
+
+// Build returns the payload, or the errors of Validate.
+func (s SEPA) Build() (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	amount := ""
+	if s.Amount != "" {
+		amount = "EUR" + s.Amount
+	}
+
+	lines := []string{
+		"BCD",
+		"002", // Version, with an optional BIC.
+		"1",   // UTF-8.
+		"SCT",
+		strings.ToUpper(s.BIC),
+		s.Name,
+		normalizeIBAN(s.IBAN),
+		amount,
+		s.Purpose,
+		s.Reference,
+		s.Text,
+		s.Information,
+	}
+	for lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	payload := strings.Join(lines, "\n")
+	if len(payload) > 331 {
+		return "", fmt.Errorf("SEPA payload is %d bytes long, the limit is 331", len(payload))
+	}
+
+	return payload, nil
+}
+
+// sepaIBANLengths are the IBAN lengths of the SEPA countries.
+var sepaIBANLengths = map[string]int{
+	"AD": 24, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27, "GB": 22,
+	"GI": 23, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IS": 26, "IT": 27,
+	"LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MT": 31, "NL": 18,
+	"NO": 15, "PL": 28, "PT": 25, "RO": 24, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "VA": 22,
+}
+
+// normalizeIBAN returns iban without spaces, in upper case.
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// checkIBAN returns an error if iban is not a valid IBAN of a SEPA country.
+func checkIBAN(iban string) error {
+	s := normalizeIBAN(iban)
+	if len(s) < 5 || !onlyChars(s, "") || !upperLetters(s[:2]) || !allDigits(s[2:4]) {
+		return fmt.Errorf("%q is not an IBAN, expected a country code, 2 check digits and the account number", iban)
+	}
+
+	n, ok := sepaIBANLengths[s[:2]]
+	if !ok {
+		return fmt.Errorf("%s is not a SEPA country", s[:2])
+	}
+	if len(s) != n {
+		return fmt.Errorf("%s IBANs have %d characters, %q has %d", s[:2], n, iban, len(s))
+	}
+
+	if mod97(s[4:]+s[:4]) != 1 {
+		return errors.New("check digits do not match, the IBAN has a typo")
+	}
+
+	return nil
+}
+
+// checkBIC returns an error if bic is not an 8 or 11 character BIC.
+func checkBIC(bic string) error {
+	s := strings.ToUpper(bic)
+	if (len(s) != 8 && len(s) != 11) || !upperLetters(s[:6]) || !onlyChars(s[6:], "") {
+		return fmt.Errorf("invalid BIC %q, expected 4 letters of the bank, 2 of the country, 2 of the location and an optional 3 of the branch", bic)
+	}
+	return nil
+}
+
+// checkEuroAmount returns an error if amount is not a valid SEPA credit
+// transfer amount.
+func checkEuroAmount(amount string) error {
+	if err := checkDecimal(amount, 2); err != nil {
+		return err
+	}
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > 9 {
+		return fmt.Errorf("%s is more than the maximum of 999999999.99", amount)
+	}
+	if whole == "" && strings.Trim(fraction, "0") == "" {
+		return errors.New("amount must be at least 0.01")
+	}
+
+	return nil
+}
+
+// checkCreditorReference returns an error if reference is too long, or is an
+// ISO 11649 "RF" reference with invalid check digits.
+func checkCreditorReference(reference string) error {
+	if len(reference) > 35 {
+		return errors.New("creditor reference is longer than 35 characters")
+	}
+
+	s := strings.ToUpper(strings.ReplaceAll(reference, " ", ""))
+	if !strings.HasPrefix(s, "RF") {
+		return nil
+	}
+	if len(s) < 5 || len(s) > 25 || !allDigits(s[2:4]) || !onlyChars(s, "") || mod97(s[4:]+s[:4]) != 1 {
+		return fmt.Errorf("invalid RF creditor reference %q, the check digits do not match", reference)
+	}
+
+	return nil
+}
+
+// mod97 returns the ISO 7064 MOD 97-10 remainder of s, letters and digits
+// with A-Z counted as 10-35.
+func mod97(s string) int {
+	r := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			r = (r*100 + int(c-'A') + 10) % 97
+		} else {
+			r = (r*10 + int(c-'0')) % 97
+		}
+	}
+	return r
+}
+
+// upperLetters returns true if s only contains ASCII upper case letters.
+func upperLetters(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package payload
+
+import "testing"
+
+func TestSEPA(t *testing.T) {
+	tests := []struct {
+		s        SEPA
+		expected string
+	}{
+		{
+			SEPA{Name: "Red Cross", IBAN: "BE71 0961 2345 6769", BIC: "gebabebb", Amount: "12.5", Text: "Donation"},
+			"BCD\n002\n1\nSCT\nGEBABEBB\nRed Cross\nBE71096123456769\nEUR12.5\n\n\nDonation",
+		},
+		{
+			SEPA{Name: "Müller GmbH", IBAN: "DE89370400440532013000", Reference: "RF18 5390 0754 7034", Information: "Invoice 42"},
+			"BCD\n002\n1\nSCT\n\nMüller GmbH\nDE89370400440532013000\n\n\nRF18 5390 0754 7034\n\nInvoice 42",
+		},
+		{
+			SEPA{Name: "A", IBAN: "GB82WEST12345698765432", Purpose: "GDDS"},
+			"BCD\n002\n1\nSCT\n\nA\nGB82WEST12345698765432\n\nGDDS",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.s.Build()
+		if err != nil || got != test.expected {
+			t.Errorf("got %q, %v, expected %q", got, err, test.expected)
+		}
+	}
+}
+
+func TestSEPAInvalid(t *testing.T) {
+	valid := SEPA{Name: "Red Cross", IBAN: "BE71096123456769"}
+
+	invalid := map[string]func(s *SEPA){
+		"Name":      func(s *SEPA) { s.Name = "" },
+		"IBAN":      func(s *SEPA) { s.IBAN = "BE71096123456768" },
+		"BIC":       func(s *SEPA) { s.BIC = "GEBA1EBB" },
+		"Amount":    func(s *SEPA) { s.Amount = "0.00" },
+		"Purpose":   func(s *SEPA) { s.Purpose = "gdds" },
+		"Reference": func(s *SEPA) { s.Reference = "RF19539007547034" },
+		"Text":      func(s *SEPA) { s.Reference, s.Text = "42", "Donation" },
+	}
+	for field, change := range invalid {
+		s := valid
+		change(&s)
+		if got, err := s.Build(); err == nil {
+			t.Errorf("%s: got %q, expected error", field, got)
+		} else if fe := fieldErrors(err); len(fe) != 1 || fe[0].Field != field {
+			t.Errorf("%s: got %v", field, err)
+		}
+	}
+}
+
+func TestCheckIBAN(t *testing.T) {
+	for _, iban := range []string{
+		"DE89 3704 0044 0532 0130 00",
+		"gb82west12345698765432",
+		"NL91ABNA0417164300",
+		"FR1420041010050500013M02606",
+		"NO9386011117947",
+	} {
+		if err := checkIBAN(iban); err != nil {
+			t.Errorf("%s: %v", iban, err)
+		}
+	}
+
+	for iban, expected := range map[string]string{
+		"DE89370400440532013001":   "check digits do not match, the IBAN has a typo",
+		"DE8937040044053201300":    `DE IBANs have 22 characters, "DE8937040044053201300" has 21`,
+		"US12345678901234":         "US is not a SEPA country",
+		"1234":                     `"1234" is not an IBAN, expected a country code, 2 check digits and the account number`,
+		"DE89-3704-0044-0532-0130": `"DE89-3704-0044-0532-0130" is not an IBAN, expected a country code, 2 check digits and the account number`,
+	} {
+		if err := checkIBAN(iban); err == nil || err.Error() != expected {
+			t.Errorf("%s: got %v, expected %q", iban, err, expected)
+		}
+	}
+}
+
+func TestCheckEuroAmount(t *testing.T) {
+	for _, amount := range []string{"0.01", "1", "999999999.99", "0012.30"} {
+		if err := checkEuroAmount(amount); err != nil {
+			t.Errorf("%s: %v", amount, err)
+		}
+	}
+	for _, amount := range []string{"0", "0.00", "1000000000", "1.001", "-1", "1,00"} {
+		if err := checkEuroAmount(amount); err == nil {
+			t.Errorf("%s: expected error", amount)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError is an invalid field of a payload. Validate and Build return
+// one FieldError per invalid field, joined by errors.Join; use errors.As to
+// report them field by field:
+//
+//	var fe *payload.FieldError
+//	if errors.As(err, &fe) {
+//		form.SetError(fe.Field, fe.Err)
+//	}
+type FieldError struct {
+	// Field is the name of the struct field, e.g. "IBAN".
+	Field string
+
+	// Value is the invalid value.
+	Value string
+
+	// Err describes why the value is invalid.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// validator collects the FieldErrors of a payload.
+type validator struct {
+	errs []error
+}
+
+// check records err, if not nil, as invalid field with value.
+func (v *validator) check(field, value string, err error) {
+	if err != nil {
+		v.errs = append(v.errs, fieldError(field, value, err))
+	}
+}
+
+// checkf records an error formatted from format and a, if ok is false, as
+// invalid field with value.
+func (v *validator) checkf(ok bool, field, value, format string, a ...interface{}) {
+	if !ok {
+		v.check(field, value, fmt.Errorf(format, a...))
+	}
+}
+
+// err returns the recorded errors joined, or nil if there are none.
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
+
+// fieldError returns err as invalid field with value.
+func fieldError(field, value string, err error) error {
+	return &FieldError{Field: field, Value: value, Err: err}
+}
@@ -0,0 +1,83 @@
+package payload
+
+import (
+	"errors"
+	"testing"
+)
+
+// fieldErrors returns the FieldErrors joined in err.
+func fieldErrors(err error) []*FieldError {
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else if err != nil {
+		errs = []error{err}
+	}
+
+	var fes []*FieldError
+	for _, e := range errs {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			fes = append(fes, fe)
+		}
+	}
+	return fes
+}
+
+func TestValidateFieldErrors(t *testing.T) {
+	err := UPI{Address: "shop", Amount: "1.505", MerchantCode: "54"}.Validate()
+
+	var fields []string
+	for _, fe := range fieldErrors(err) {
+		fields = append(fields, fe.Field)
+	}
+	expected := []string{"Address", "Name", "Amount", "MerchantCode"}
+	if len(fields) != len(expected) {
+		t.Fatalf("got fields %v, expected %v", fields, expected)
+	}
+	for i := range fields {
+		if fields[i] != expected[i] {
+			t.Errorf("got fields %v, expected %v", fields, expected)
+		}
+	}
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "Address" || fe.Value != "shop" {
+		t.Errorf("errors.As got %+v", fe)
+	}
+	if got, expected := fe.Error(), `Address: invalid UPI address "shop", expected e.g. name@bank`; got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func TestValidateBuilders(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid interface{ Validate() error }
+		field string
+		bad   interface{ Validate() error }
+	}{
+		{"AndroidIntent", AndroidIntent{Package: "com.example.app", Scheme: "https"}, "Scheme", AndroidIntent{Package: "com.example.app"}},
+		{"Bitcoin", Bitcoin{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}, "Amount", Bitcoin{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Amount: "x"}},
+		{"Ethereum", Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}, "Address", Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"}},
+		{"PIX", PIX{Key: "k", Name: "n", City: "c"}, "City", PIX{Key: "k", Name: "n"}},
+		{"MerchantPresented", MerchantPresented{Accounts: []MerchantAccount{{ID: "04", Value: "1"}}, CategoryCode: "5812", Currency: "978", CountryCode: "DE", MerchantName: "n", MerchantCity: "c"},
+			"Currency", MerchantPresented{Accounts: []MerchantAccount{{ID: "04", Value: "1"}}, CategoryCode: "5812", Currency: "EUR", CountryCode: "DE", MerchantName: "n", MerchantCity: "c"}},
+		{"SEPA", SEPA{Name: "n", IBAN: "BE71096123456769"}, "IBAN", SEPA{Name: "n", IBAN: "BE00096123456769"}},
+		{"WiFi", WiFi{SSID: "Home"}, "SSID", WiFi{SSID: "a name longer than thirty-two bytes"}},
+		{"Email", Email{To: "a@example.com"}, "To", Email{To: "Alice <a@example.com>"}},
+		{"Phone", Phone{Number: "+1 202 555 0100"}, "Number", Phone{Number: "202 555 0100"}},
+		{"SMS", SMS{Number: "+1 202 555 0100"}, "Number", SMS{Number: "+1 202"}},
+	}
+
+	for _, test := range tests {
+		if err := test.valid.Validate(); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+
+		var fe *FieldError
+		if err := test.bad.Validate(); !errors.As(err, &fe) || fe.Field != test.field {
+			t.Errorf("%s: got %v, expected a FieldError of %s", test.name, err, test.field)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+)
+
+// MinPrintableModuleMM is the smallest module size, in millimetres, that
+// typical label printers and phone cameras reproduce reliably.
+const MinPrintableModuleMM = 0.33
+
+// RenderPhysical returns the QR Code as an image for printing widthMM
+// millimetres wide, including the quiet zone, at dpi dots per inch.
+//
+// Modules are a whole number of dots, so the image is the largest whole
+// number of dots per module that fits, and may be slightly narrower than
+// widthMM. A warning with code "small-module" is returned if the modules are
+// smaller than MinPrintableModuleMM. Other image options of the QR Code apply.
+func (q *QRCode) RenderPhysical(widthMM float64, dpi int) (image.Image, []Warning, error) {
+	if widthMM <= 0 || dpi <= 0 {
+		return nil, nil, errors.New("width and dpi must be positive")
+	}
+
+	realSize := q.symbol.size
+	widthPixels := int(math.Floor(widthMM / mmPerInch * float64(dpi)))
+
+	pixelsPerModule := widthPixels / realSize
+	if pixelsPerModule < 1 {
+		return nil, nil, fmt.Errorf("%gmm at %d dpi is %d dots, fewer than the %d modules of the QR Code",
+			widthMM, dpi, widthPixels, realSize)
+	}
+
+	var warnings []Warning
+	if moduleMM := float64(pixelsPerModule) * mmPerInch / float64(dpi); moduleMM < MinPrintableModuleMM {
+		warnings = append(warnings, Warning{
+			Code: "small-module",
+			Message: fmt.Sprintf("modules are %.3gmm, below the printable minimum of %gmm; print larger or use a lower recovery level",
+				moduleMM, MinPrintableModuleMM),
+		})
+	}
+
+	c := *q
+	c.width, c.height = 0, 0
+	c.scale = pixelsPerModule
+
+	return c.Image(), warnings, nil
+}
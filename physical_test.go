@@ -0,0 +1,52 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import "testing"
+
+func TestRenderPhysical(t *testing.T) {
+	q, err := New("physical", Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := q.symbol.size
+
+	tests := []struct {
+		widthMM  float64
+		dpi      int
+		width    int
+		warnings int
+	}{
+		// 25.4mm at 300 dpi is 300 dots.
+		{25.4, 300, 300 / n * n, 0},
+		// 10mm at 203 dpi is 79 dots, 2 per module of 0.25mm.
+		{10, 203, 79 / n * n, 1},
+	}
+
+	for _, test := range tests {
+		img, warnings, err := q.RenderPhysical(test.widthMM, test.dpi)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b := img.Bounds(); b.Dx() != test.width || b.Dy() != test.width {
+			t.Errorf("%gmm at %d dpi: got size %v, expected %d", test.widthMM, test.dpi, b.Size(), test.width)
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("%gmm at %d dpi: got warnings %v, expected %d", test.widthMM, test.dpi, warnings, test.warnings)
+		}
+		for _, w := range warnings {
+			if w.Code != "small-module" {
+				t.Errorf("unexpected warning %s", w)
+			}
+		}
+	}
+
+	if _, _, err := q.RenderPhysical(2, 72); err == nil {
+		t.Error("RenderPhysical succeeded with fewer dots than modules, expected error")
+	}
+	if _, _, err := q.RenderPhysical(-1, 300); err == nil {
+		t.Error("RenderPhysical succeeded with a negative width, expected error")
+	}
+}
@@ -0,0 +1,63 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"runtime/debug"
+	"strconv"
+)
+
+// modulePath is the import path of this module, used to find its version.
+const modulePath = "github.com/yougg/go-qrcode"
+
+// metadataChunks returns the tEXt chunks written by PNGMetadata, or nothing if
+// it is not set.
+func (q *QRCode) metadataChunks() []byte {
+	if !q.pngMetadata {
+		return nil
+	}
+
+	var chunks bytes.Buffer
+	e := &pngChunkWriter{w: &chunks}
+
+	sum := sha256.Sum256([]byte(q.Content))
+	for _, kv := range [][2]string{
+		{"Software", modulePath + " " + libraryVersion()},
+		{"QR Content SHA-256", hex.EncodeToString(sum[:])},
+		{"QR Version", strconv.Itoa(q.VersionNumber)},
+		{"QR Level", q.level.String()},
+		{"QR Mask", strconv.Itoa(q.mask)},
+	} {
+		// Keyword, null separator, then Latin-1 text.
+		e.writeChunk("tEXt", []byte(kv[0]+"\x00"+kv[1]))
+	}
+
+	return chunks.Bytes()
+}
+
+// libraryVersion returns the version of this module in the running binary,
+// e.g. "v1.2.0", or "(devel)" if it is not known.
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+
+	return "(devel)"
+}
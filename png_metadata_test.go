@@ -0,0 +1,84 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pngText returns the keywords and text of the tEXt chunks of a PNG file.
+func pngText(data []byte) map[string]string {
+	text := map[string]string{}
+	for i := len(pngSignature); i < len(data); {
+		n := int(binary.BigEndian.Uint32(data[i : i+4]))
+		if string(data[i+4:i+8]) == "tEXt" {
+			keyword, value, _ := strings.Cut(string(data[i+8:i+8+n]), "\x00")
+			text[keyword] = value
+		}
+		i += 12 + n
+	}
+	return text
+}
+
+func TestPNGMetadata(t *testing.T) {
+	q, err := New("https://example.org", Level(High), PNGMetadata())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	text := pngText(data)
+	expected := map[string]string{
+		// sha256sum of "https://example.org".
+		"QR Content SHA-256": "50d7a905e3046b88638362cc34a31a1ae534766ca55e3aa397951efe653b062b",
+		"QR Version":         strconv.Itoa(q.VersionNumber),
+		"QR Level":           "Q",
+		"QR Mask":            strconv.Itoa(q.mask),
+	}
+	for k, v := range expected {
+		if text[k] != v {
+			t.Errorf("got %s %q, expected %q", k, text[k], v)
+		}
+	}
+	if !strings.HasPrefix(text["Software"], modulePath+" ") {
+		t.Errorf("got Software %q", text["Software"])
+	}
+	if strings.Contains(string(data), "example.org") {
+		t.Error("PNG contains the content")
+	}
+
+	// Banded encoding writes the same chunks.
+	var banded bytes.Buffer
+	if err := q.EncodeBandedPNG(&banded); err != nil {
+		t.Fatal(err)
+	}
+	if got := pngText(banded.Bytes()); len(got) != len(text) {
+		t.Errorf("got %d banded tEXt chunks, expected %d", len(got), len(text))
+	}
+
+	// Without the option there are none.
+	q, err = New("https://example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := pngText(data); len(text) != 0 {
+		t.Errorf("got tEXt chunks %v without PNGMetadata", text)
+	}
+}
@@ -0,0 +1,100 @@
+package qrcode
+
+// PointType classifies a module of the Bitmap by the part of the symbol it
+// belongs to. Renderers that stylize data modules should draw the function
+// patterns, every type except DataPoint and QuietZonePoint, unaltered.
+type PointType int
+
+const (
+	// DataPoint is a data or error correction module.
+	DataPoint PointType = iota
+
+	// FinderPatternPoint is part of a finder pattern, or the light separator
+	// around it.
+	FinderPatternPoint
+
+	// AlignmentPatternPoint is part of an alignment pattern.
+	AlignmentPatternPoint
+
+	// TimingPatternPoint is part of a timing pattern.
+	TimingPatternPoint
+
+	// FormatInfoPoint holds format information. The always dark module next
+	// to the bottom left finder pattern is included.
+	FormatInfoPoint
+
+	// VersionInfoPoint holds version information (versions 7 and up).
+	VersionInfoPoint
+
+	// QuietZonePoint is in the quiet zone around the symbol.
+	QuietZonePoint
+)
+
+// String returns the name of the point type, e.g. "finder".
+func (p PointType) String() string {
+	switch p {
+	case DataPoint:
+		return "data"
+	case FinderPatternPoint:
+		return "finder"
+	case AlignmentPatternPoint:
+		return "alignment"
+	case TimingPatternPoint:
+		return "timing"
+	case FormatInfoPoint:
+		return "format"
+	case VersionInfoPoint:
+		return "version"
+	case QuietZonePoint:
+		return "quiet zone"
+	}
+
+	return "unknown"
+}
+
+// PointType returns the type of module (x, y) of the Bitmap. Coordinates
+// include the quiet zone; modules outside the Bitmap are QuietZonePoint.
+func (q *QRCode) PointType(x, y int) PointType {
+	top, _, _, left := q.quietZoneExtra()
+	return q.pointType(x-left, y-top)
+}
+
+// pointType returns the type of module (x, y) of the symbol with its uniform
+// quiet zone, without the deeper edges of QuietZone.
+func (q *QRCode) pointType(x, y int) PointType {
+	n := q.version.symbolSize()
+	x -= q.symbol.quietZoneSize
+	y -= q.symbol.quietZoneSize
+
+	// The finder patterns are 7x7, plus a 1 module separator.
+	f := finderPatternSize + 1
+
+	switch {
+	case x < 0 || y < 0 || x >= n || y >= n:
+		return QuietZonePoint
+	case x < f && y < f, x >= n-f && y < f, x < f && y >= n-f:
+		return FinderPatternPoint
+	case y == f && x != finderPatternSize-1 && (x <= f || x >= n-f), x == f && y != finderPatternSize-1 && (y <= f || y >= n-f):
+		return FormatInfoPoint
+	case q.VersionNumber >= 7 && (x >= n-f-3 && x < n-f && y < 6 || y >= n-f-3 && y < n-f && x < 6):
+		return VersionInfoPoint
+	}
+
+	for _, cx := range alignmentPatternCenter[q.version.version] {
+		for _, cy := range alignmentPatternCenter[q.version.version] {
+			// Centers inside a finder pattern are skipped.
+			if cx < f && cy < f || cx >= n-f && cy < f || cx < f && cy >= n-f {
+				continue
+			}
+			if x >= cx-2 && x <= cx+2 && y >= cy-2 && y <= cy+2 {
+				return AlignmentPatternPoint
+			}
+		}
+	}
+
+	if x == finderPatternSize-1 || y == finderPatternSize-1 {
+		return TimingPatternPoint
+	}
+
+	return DataPoint
+}
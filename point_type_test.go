@@ -0,0 +1,90 @@
+package qrcode
+
+import (
+	"testing"
+)
+
+func TestQRCodePointType(t *testing.T) {
+	tests := []struct {
+		content  string
+		version  int
+		expected map[PointType]int
+	}{
+		{
+			"A",
+			1,
+			map[PointType]int{
+				FinderPatternPoint: 3 * 64,
+				FormatInfoPoint:    31,
+				TimingPatternPoint: 2 * 5,
+				DataPoint:          26 * 8,
+				QuietZonePoint:     29*29 - 21*21,
+			},
+		},
+		{
+			"A",
+			7,
+			map[PointType]int{
+				FinderPatternPoint:    3 * 64,
+				FormatInfoPoint:       31,
+				VersionInfoPoint:      36,
+				AlignmentPatternPoint: 6 * 25,
+				// Two alignment patterns sit on the timing patterns.
+				TimingPatternPoint: 2*29 - 2*5,
+				DataPoint:          196 * 8,
+				QuietZonePoint:     53*53 - 45*45,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		q, err := New(test.content, Level(Low), Margin(4), MinVersion(test.version))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if q.VersionNumber != test.version {
+			t.Fatalf("got version %d, expected %d", q.VersionNumber, test.version)
+		}
+
+		counts := map[PointType]int{}
+		for y := range q.Bitmap() {
+			for x := range q.Bitmap()[y] {
+				counts[q.PointType(x, y)]++
+			}
+		}
+
+		for pt := DataPoint; pt <= QuietZonePoint; pt++ {
+			if counts[pt] != test.expected[pt] {
+				t.Errorf("version %d has %d %s modules, expected %d", test.version, counts[pt], pt, test.expected[pt])
+			}
+		}
+	}
+}
+
+func TestQRCodePointTypeFunctionPatterns(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := []struct {
+		x, y     int
+		expected PointType
+	}{
+		{-1, 0, QuietZonePoint},
+		{0, 0, QuietZonePoint},
+		{4, 4, FinderPatternPoint},
+		{11, 11, FinderPatternPoint},
+		{12, 4, FormatInfoPoint},
+		{4 + 6, 4 + 10, TimingPatternPoint},
+		{4 + 10, 4 + 6, TimingPatternPoint},
+		{4 + 18, 4 + 18, AlignmentPatternPoint},
+		{4 + 10, 4 + 10, DataPoint},
+	}
+
+	for _, test := range tests {
+		if got := q.PointType(test.x, test.y); got != test.expected {
+			t.Errorf("PointType(%d, %d) is %s, expected %s", test.x, test.y, got, test.expected)
+		}
+	}
+}
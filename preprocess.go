@@ -0,0 +1,82 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preprocess transforms the content with f before it is encoded, e.g. to
+// shorten URLs with a link shortener, or UppercaseURL. Several Preprocess
+// options run in turn, in option order, after Normalize. New returns any
+// error from f.
+func Preprocess(f func(content string) (string, error)) Option {
+	return func(q *QRCode) {
+		q.preprocess = append(q.preprocess, f)
+	}
+}
+
+// preprocessContent returns content transformed by the Preprocess functions,
+// and compressed last, see CompressPayload.
+func (q *QRCode) preprocessContent(content string) (string, error) {
+	for _, f := range q.preprocess {
+		var err error
+		if content, err = f(content); err != nil {
+			return "", fmt.Errorf("preprocess: %s", err.Error())
+		}
+	}
+
+	if q.compressPayload {
+		return compressContent(content)
+	}
+
+	return content, nil
+}
+
+// UppercaseURL returns an http or https URL with its scheme and host in upper
+// case, which scanners treat the same, so they can be encoded in the denser
+// alphanumeric mode. A URL with no path, e.g. "https://example.org", becomes
+// entirely alphanumeric and often fits a smaller version. The path, query and
+// any user info are case sensitive, and are kept as they are.
+//
+// Other content is returned unchanged. Use it with Preprocess:
+//
+//	q, err := qrcode.New("https://example.org", qrcode.Preprocess(qrcode.UppercaseURL))
+func UppercaseURL(content string) (string, error) {
+	i := strings.Index(content, "://")
+	if i < 0 {
+		return content, nil
+	}
+
+	scheme := strings.ToLower(content[:i])
+	if scheme != "http" && scheme != "https" {
+		return content, nil
+	}
+
+	rest := content[i+3:]
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, path := rest[:end], rest[end:]
+
+	userInfo, host := "", authority
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		userInfo, host = authority[:at+1], authority[at+1:]
+	}
+	if host == "" {
+		return content, nil
+	}
+
+	return upperASCII(scheme) + "://" + userInfo + upperASCII(host) + path, nil
+}
+
+// upperASCII returns s with the ASCII letters a-z in upper case. Other
+// characters, such as those of internationalized domain names, are kept.
+func upperASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, s)
+}
@@ -17,213 +17,280 @@ Three functions cover most use cases:
 - Create a PNG image:
 
 	var png []byte
-	png, err := qrcode.Encode("https://example.org", qrcode.Medium, 256)
+	png, err := qrcode.Encode("https://example.org", qrcode.Medium, 256, 256, 4)
 
 - Create a PNG image and write to a file:
 
-	err := qrcode.WriteFile("https://example.org", qrcode.Medium, 256, "qr.png")
+	err := qrcode.WriteFile("https://example.org", qrcode.Medium, 256, "qr.png", 4)
 
 - Create a PNG image with custom colors and write to file:
 
-	err := qrcode.WriteColorFile("https://example.org", qrcode.Medium, 256, color.Black, color.White, "qr.png")
+	err := qrcode.WriteColorFile("https://example.org", qrcode.Medium, 256, color.Black, color.White, "qr.png", 4)
 
 All examples use the qrcode.Medium error Recovery Level and create a fixed
-256x256px size QR Code. The last function creates a white on black instead of black
-on white QR Code.
+256x256px size QR Code, with a quiet zone (margin) of 4 modules. The last
+function takes the background color before the foreground color, so it creates
+a white on black instead of black on white QR Code.
 
 To generate a variable sized image instead, specify a negative size (in place of
 the 256 above), such as -4 or -5. Larger negative numbers create larger images:
 A size of -5 sets each module (QR Code "pixel") to be 5px wide/high.
 
-- Create a PNG image (variable size, with minimum white padding) and write to a file:
+- Create a PNG image (variable size) and write to a file:
 
-	err := qrcode.WriteFile("https://example.org", qrcode.Medium, -5, "qr.png")
+	err := qrcode.WriteFile("https://example.org", qrcode.Medium, -5, "qr.png", 4)
 
 The maximum capacity of a QR Code varies according to the content encoded and
 the error recovery level. The maximum capacity is 2,953 bytes, 4,296
 alphanumeric characters, 7,089 numeric digits, or a combination of these.
 
+The encoder core (New, Bitmap, PackedBitmap and ToString) builds with TinyGo,
+for microcontrollers driving e-paper and LCD displays. Image output,
+encryption, signing and URL normalization are excluded from TinyGo builds by
+the tinygo build tag.
+
 This package implements a subset of QR Code 2005, as defined in ISO/IEC
-18004:2006.
+18004:2006. QR Code Model 1, the original layout with versions 1-14 and no
+alignment patterns, is not supported: its codeword and error correction
+tables are only defined in withdrawn standards. Rectangular Micro QR Code
+(rMQR, ISO/IEC 23941) symbols are not supported either, nor are iQR and Frame
+QR, which are proprietary and not publicly specified.
 */
 package qrcode
 
 import (
 	"bytes"
 	"errors"
-	"image"
+	"fmt"
 	"image/color"
-	"image/png"
-	"io"
-	"io/ioutil"
-	"log"
-	"os"
+	"reflect"
+	"time"
+
+	"golang.org/x/image/math/f64"
 
-	"github.com/nfnt/resize"
 	"github.com/yougg/go-qrcode/bitset"
-	"github.com/yougg/go-qrcode/reedsolomon"
+	"github.com/yougg/go-qrcode/styles"
 )
 
-const (
-	otherPoint             = iota
-	finderPatternPoint     = 1
-	alignmentPatternsPoint = 2
-	timingPatternsPoint    = 3
-)
+// A QRCode represents a valid encoded QRCode.
+type QRCode struct {
+	// Original content encoded.
+	//
+	// Deprecated: Use EncodedContent. Changing Content does not encode the
+	// QR Code again.
+	Content string
+	// content transforms applied before encoding, see Preprocess.
+	preprocess []func(string) (string, error)
+	// deflate the content, see CompressPayload.
+	compressPayload bool
 
-// Encode a QR Code and return a raw PNG image.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently returned. Negative values for size cause a
-// variable sized image to be returned: See the documentation for Image().
-//
-// To serve over HTTP, remember to send a Content-Type: image/png header.
-func Encode(content string, level RecoveryLevel, width, height, margin int) ([]byte, error) {
-	var opts = []Option{
-		Level(level),
-		Width(width),
-		Height(height),
-		Margin(margin),
-	}
+	// QR Code type.
+	level RecoveryLevel
+	// Deprecated: Use Version. Changing VersionNumber does not encode the QR
+	// Code again.
+	VersionNumber int
 
-	q, err := New(content, opts...)
+	// Colors of the dark and light modules.
+	//
+	// Deprecated: Use Foreground and Background to read them, and the
+	// ForegroundColor and BackgroundColor options with With or Set to change
+	// them.
+	ForegroundColor color.Color
+	BackgroundColor color.Color
 
-	if err != nil {
-		return nil, err
-	}
+	// Quiet zone styling, see QuietZoneColor, QuietZoneRadius and
+	// QuietZoneOutline.
+	quietZoneColor  color.Color
+	quietZoneRadius int
+	outlineWidth    int
+	outlineColor    color.Color
 
-	return q.PNG()
-}
+	encoder *dataEncoder
+	version qrCodeVersion
 
-// WriteFile encodes, then writes a QR Code to the given filename in PNG format.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently written. Negative values for size cause a variable
-// sized image to be written: See the documentation for Image().
-func WriteFile(content string, level RecoveryLevel, size int, filename string, margin int) error {
-	var opts = []Option{
-		Level(level),
-		Width(size),
-		Height(size),
-		Margin(margin),
-	}
+	data   *bitset.Bitset
+	symbol *symbol
+	mask   int
 
-	q, err := New(content, opts...)
+	// Length of the encoded content in bits, before the terminator and
+	// padding are added.
+	contentBits int
+	// Penalty score of the chosen mask.
+	penalty int
+	// Penalty scores of every mask, see MaskPenalties.
+	maskPenalties [8]PenaltyBreakdown
 
-	if err != nil {
-		return err
-	}
+	width, height, margin int
+	// instrumentation hooks, see Instrument.
+	metrics Metrics
+	// debug event hooks, see Logging.
+	logger Logger
+	// smallest version to choose, see MinVersion, and whether it must be
+	// chosen exactly, see ExactVersion.
+	minVersion   int
+	exactVersion bool
+	// pixels per module, see Scale.
+	scale int
+	// shrink images to a whole number of modules, see SnapToModule.
+	snapToModule bool
+	// text printed under the symbol, see Caption and CaptionContent.
+	caption        string
+	captionContent bool
+	// text printed in the quiet zone, see Stamp and StampSequence.
+	stamp         string
+	stampSequence bool
+	// values drawn in place of the computed format and version information,
+	// see OverrideFormatInfo and OverrideVersionInfo.
+	formatInfoOverride  *uint32
+	versionInfoOverride *uint32
+	// affine transform applied to images, see Transform.
+	transform *f64.Aff3
+	// shapes of the data modules and finder patterns, see Style.
+	moduleShape styles.Shape
+	eyeShape    styles.Shape
+	// color space written to PNG images, see ICCProfile.
+	iccProfileName string
+	iccProfile     []byte
+	iccProfileSet  bool
+	// palette of paletted images, see Palette.
+	customPalette color.Palette
+	// write tEXt chunks describing the code to PNG images, see PNGMetadata.
+	pngMetadata bool
+	// write PNG images with a fixed encoding, see Reproducible.
+	reproducible bool
+	// scale artwork with nearest neighbor sampling only, see NoSmoothing.
+	noSmoothing bool
+	// pad codeword i, see PadCodewords and PadFunc.
+	padCodeword func(i int) byte
+	// score masks as earlier releases did, see LegacyMaskPenalty.
+	legacyMaskPenalty bool
+	// smallest module size in pixels, see MinModulePixels.
+	minModulePixels int
+	// return errors instead of adjusting options, see Strict.
+	strict bool
+	// area-average images smaller than the symbol, see Downscale.
+	downscale          bool
+	downscaleThreshold float64
+	// edge of the quiet zone with an arrow, see OrientationMarker.
+	orientationMarker Edge
+	// how content is interpreted as a URL, see Normalize.
+	urlMode URLMode
+	// leave out the quiet zone, see NoQuietZone.
+	noQuietZone bool
+	// quiet zone of each edge, top, right, bottom and left, see QuietZone.
+	quietZoneEdges *[4]int
+	// QuitZoneSize sets the quiet zone in modules. A positive value written
+	// by an Option replaces Margin when that option is applied.
+	//
+	// Deprecated: Use the QuietZoneSize option.
+	QuitZoneSize int
+}
 
-	return q.WriteFile(filename)
+// checkPalette returns an error if the Palette is invalid.
+func (q *QRCode) checkPalette() error {
+	p := q.customPalette
+	if p == nil {
+		return nil
+	} else if len(p) < 2 || len(p) > 256 {
+		return fmt.Errorf("invalid palette of %d colors (expected 2-256)", len(p))
+	} else if !hasColor(p, q.ForegroundColor) || !hasColor(p, q.BackgroundColor) {
+		return errors.New("invalid palette (must contain the foreground and background colors)")
+	}
+	return nil
 }
 
-// WriteColorFile encodes, then writes a QR Code to the given filename in PNG format.
-// With WriteColorFile you can also specify the colors you want to use.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently written. Negative values for size cause a variable
-// sized image to be written: See the documentation for Image().
-func WriteColorFile(content string, level RecoveryLevel, size int, background, foreground color.Color, filename string, margin int) error {
-	var opts = []Option{
-		Level(level),
-		Width(size),
-		Height(size),
-		Margin(margin),
-		BackgroundColor(background),
-		ForegroundColor(foreground),
-	}
-
-	q, err := New(content, opts...)
-	if err != nil {
-		return err
+// hasColor returns true if p contains c exactly.
+func hasColor(p color.Palette, c color.Color) bool {
+	want := color.NRGBAModel.Convert(c)
+	for _, pc := range p {
+		if color.NRGBAModel.Convert(pc) == want {
+			return true
+		}
 	}
-
-	return q.WriteFile(filename)
+	return false
 }
 
-func EncodeWithLogo(level RecoveryLevel, str string, logo image.Image, margin int) (*bytes.Buffer, error) {
-	var buf bytes.Buffer
-	var colors color.Palette
-	var opts = []Option{
-		Level(level),
-		Margin(margin),
-	}
-	code, err := New(str, opts...)
-	if err != nil {
-		return nil, err
+// absorbQuitZoneSize moves a positive QuitZoneSize, written directly by an
+// Option, into the margin, so it takes effect in option order like Margin.
+// Negative sizes are kept for build to reject.
+func (q *QRCode) absorbQuitZoneSize() {
+	if q.QuitZoneSize > 0 {
+		QuietZoneSize(q.QuitZoneSize)(q)
+		q.QuitZoneSize = 0
 	}
+}
 
-	logo = resize.Resize(40, 40, logo, resize.NearestNeighbor)
-	for x := 0; x < logo.Bounds().Max.X; x++ {
-		for y := 0; y < logo.Bounds().Max.Y; y++ {
-			if contains(logo.At(x, y), colors) || len(colors) == 254 {
-				continue
-			}
-			colors = append(colors, logo.At(x, y)) // FIXME colors to code.Image()
-		}
+// quietZone returns the width of the quiet zone in modules.
+func (q *QRCode) quietZone() int {
+	if q.noQuietZone {
+		return 0
 	}
-	img := code.Image()
-	overlayLogo(img, logo)
+	return q.margin
+}
 
-	err = png.Encode(&buf, img)
-	if err != nil {
-		return nil, err
+// quietZoneExtra returns the quiet zone of each edge beyond the symbol's own
+// uniform quiet zone, see QuietZone.
+func (q *QRCode) quietZoneExtra() (top, right, bottom, left int) {
+	e := q.quietZoneEdges
+	if e == nil || q.noQuietZone {
+		return 0, 0, 0, 0
 	}
 
-	return &buf, nil
+	m := q.quietZone()
+	return e[0] - m, e[1] - m, e[2] - m, e[3] - m
 }
 
-func contains(item color.Color, input color.Palette) bool {
-	for _, v := range input {
-		r1, g1, b1, a1 := item.RGBA()
-		r2, g2, b2, a2 := v.RGBA()
-		if r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2 {
-			return true
-		}
+// Set applies opts to q in place, for options changing only how q is drawn,
+// such as ForegroundColor or Width. Options that would change the encoded
+// symbol, such as Level, Margin or PadCodewords, are an error, as are invalid
+// or conflicting options, and q is left unchanged: encode a new QRCode with
+// New instead. See also With.
+func (q *QRCode) Set(opts ...Option) error {
+	r := *q
+	before := r.symbolSettings()
+	r.apply(opts...)
+	// The version is chosen by encoding, so the Version option has no effect.
+	r.VersionNumber = q.VersionNumber
+
+	if !reflect.DeepEqual(r.symbolSettings(), before) {
+		return errors.New("options change the encoded symbol (use New)")
+	}
+	if err := r.checkPalette(); err != nil {
+		return err
+	}
+	if err := r.checkConflicts(); err != nil {
+		return err
 	}
-	return false
-}
 
-func overlayLogo(dst, src image.Image) {
-	offsetX := dst.Bounds().Max.X/2 - src.Bounds().Max.X/2
-	offsetY := dst.Bounds().Max.Y/2 - src.Bounds().Max.Y/2
+	*q = r
+	return nil
+}
 
-	for x := 0; x < src.Bounds().Max.X; x++ {
-		for y := 0; y < src.Bounds().Max.Y; y++ {
-			col := src.At(x, y)
-			dst.(*image.Paletted).Set(x+offsetX, y+offsetY, col)
+// symbolSettings returns the settings the encoded symbol was built from, for
+// comparison.
+func (q *QRCode) symbolSettings() []interface{} {
+	// Functions can't be compared, so compare the pad codewords they give,
+	// and the number of Preprocess functions.
+	var pad []byte
+	if q.padCodeword != nil {
+		for i := 0; i < q.version.numDataBits()/8; i++ {
+			pad = append(pad, q.padCodeword(i))
 		}
 	}
-}
-
-// A QRCode represents a valid encoded QRCode.
-type QRCode struct {
-	// Original content encoded.
-	Content string
 
-	// QR Code type.
-	level         RecoveryLevel
-	VersionNumber int
-
-	// User settable drawing options.
-	ForegroundColor color.Color
-	BackgroundColor color.Color
-
-	encoder *dataEncoder
-	version qrCodeVersion
-
-	data   *bitset.Bitset
-	symbol *symbol
-	mask   int
-
-	width, height, margin int
-	// set white space size.
-	QuitZoneSize int
+	return []interface{}{
+		q.Content, q.level, q.minVersion, q.quietZone(),
+		q.QuitZoneSize, q.urlMode, q.legacyMaskPenalty,
+		q.formatInfoOverride, q.versionInfoOverride, pad,
+		len(q.preprocess), q.compressPayload,
+	}
 }
 
-func (q *QRCode) Set(opts ...Option) {
+// apply applies opts to q, before it is encoded.
+func (q *QRCode) apply(opts ...Option) {
 	for _, opt := range opts {
 		opt(q)
+		q.absorbQuitZoneSize()
 	}
 	if nil == q.ForegroundColor {
 		q.ForegroundColor = color.Black
@@ -243,7 +310,56 @@ func New(content string, opts ...Option) (*QRCode, error) {
 	q := &QRCode{
 		Content: content,
 	}
-	q.Set(opts...)
+	q.apply(withDefaults(opts)...)
+
+	content, err := normalizeURL(content, q.urlMode)
+	if err != nil {
+		return nil, err
+	}
+	content, err = q.preprocessContent(content)
+	if err != nil {
+		return nil, err
+	}
+	q.Content = content
+
+	err = q.build(func(encoder *dataEncoder) (*bitset.Bitset, error) {
+		return encoder.encode([]byte(content))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// NewStrict constructs a QRCode as New does, with Strict: options which
+// cannot be honoured or contradict each other are an error.
+func NewStrict(content string, opts ...Option) (*QRCode, error) {
+	return New(content, append(append([]Option(nil), opts...), Strict())...)
+}
+
+// build encodes the data returned by encode using the smallest suitable
+// version, and completes the QR Code.
+func (q *QRCode) build(encode func(encoder *dataEncoder) (*bitset.Bitset, error)) error {
+	start := time.Now()
+
+	if q.minVersion > 40 {
+		return fmt.Errorf("invalid minimum version %d (expected 1-40 inclusive)", q.minVersion)
+	} else if q.level < Low || q.level > Highest {
+		return fmt.Errorf("invalid recovery level %d", q.level)
+	} else if e := q.quietZoneEdges; e != nil && min(min(e[0], e[1]), min(e[2], e[3])) < 0 {
+		return fmt.Errorf("invalid quiet zone %v (must not be negative)", *e)
+	} else if q.margin < 0 {
+		return fmt.Errorf("invalid margin %d (must not be negative)", q.margin)
+	} else if q.QuitZoneSize < 0 {
+		return fmt.Errorf("invalid quiet zone size %d (must not be negative)", q.QuitZoneSize)
+	} else if q.downscaleThreshold < 0 || q.downscaleThreshold > 1 {
+		return fmt.Errorf("invalid downscale threshold %g (must be 0-1)", q.downscaleThreshold)
+	} else if err := q.checkPalette(); err != nil {
+		return err
+	} else if err := q.checkConflicts(); err != nil {
+		return err
+	}
 
 	encoders := []dataEncoderType{dataEncoderType1To9, dataEncoderType10To26, dataEncoderType27To40}
 
@@ -254,13 +370,17 @@ func New(content string, opts ...Option) (*QRCode, error) {
 
 	for _, t := range encoders {
 		encoder = newDataEncoder(t)
-		encoded, err = encoder.encode([]byte(content))
+		if encoder.maxVersion < q.minVersion {
+			continue
+		}
+
+		encoded, err = encode(encoder)
 
 		if err != nil {
 			continue
 		}
 
-		chosenVersion = chooseQRCodeVersion(q.level, encoder, encoded.Len())
+		chosenVersion = chooseQRCodeVersion(q.level, encoder, encoded.Len(), q.minVersion)
 
 		if chosenVersion != nil {
 			break
@@ -268,20 +388,35 @@ func New(content string, opts ...Option) (*QRCode, error) {
 	}
 
 	if err != nil {
-		return nil, err
+		return err
 	} else if chosenVersion == nil {
-		return nil, errors.New("content too long to encode")
+		return errors.New("content too long to encode")
+	} else if q.exactVersion && chosenVersion.version != q.minVersion {
+		return fmt.Errorf("content too long to encode in version %d", q.minVersion)
 	}
 
 	q.VersionNumber = chosenVersion.version
 	q.encoder = encoder
 	q.data = encoded
+	q.contentBits = encoded.Len()
 	q.version = *chosenVersion
-	// set quitZoneSize
-	q.version.setQuietZoneSize(q.QuitZoneSize)
-	q.encode(chosenVersion.numTerminatorBitsRequired(encoded.Len()))
+	q.debug("version chosen", "version", q.VersionNumber, "level", q.level, "bits", q.contentBits)
+	if err = q.checkInfoOverrides(); err != nil {
+		return err
+	}
+	if err = q.encode(chosenVersion.numTerminatorBitsRequired(encoded.Len())); err != nil {
+		return err
+	}
+	if err = q.checkModulePixels(); err != nil {
+		return err
+	}
 
-	return q, nil
+	q.debug("encoded", "version", q.VersionNumber, "level", q.level, "duration", time.Since(start))
+	if q.metrics != nil {
+		q.metrics.Encoded(time.Since(start), q.VersionNumber, q.level)
+	}
+
+	return nil
 }
 
 func newWithForcedVersion(content string, version int, level RecoveryLevel) (*QRCode, error) {
@@ -295,7 +430,7 @@ func newWithForcedVersion(content string, version int, level RecoveryLevel) (*QR
 	case version >= 27 && version <= 40:
 		encoder = newDataEncoder(dataEncoderType27To40)
 	default:
-		log.Fatalf("Invalid version %d (expected 1-40 inclusive)", version)
+		return nil, fmt.Errorf("invalid version %d (expected 1-40 inclusive)", version)
 	}
 
 	var encoded *bitset.Bitset
@@ -323,186 +458,176 @@ func newWithForcedVersion(content string, version int, level RecoveryLevel) (*QR
 		encoder: encoder,
 		data:    encoded,
 		version: *chosenVersion,
+
+		contentBits: encoded.Len(),
 	}
 
-	q.encode(chosenVersion.numTerminatorBitsRequired(encoded.Len()))
+	if err := q.encode(chosenVersion.numTerminatorBitsRequired(encoded.Len())); err != nil {
+		return nil, err
+	}
 
 	return q, nil
 }
 
+// EncodedContent returns the content encoded, after any Preprocess functions
+// and Normalize.
+func (q *QRCode) EncodedContent() string {
+	return q.Content
+}
+
+// Version returns the QR Code version (1-40 inclusive).
+func (q *QRCode) Version() int {
+	return q.VersionNumber
+}
+
+// Foreground returns the color of the dark modules.
+func (q *QRCode) Foreground() color.Color {
+	return q.ForegroundColor
+}
+
+// Background returns the color of the light modules.
+func (q *QRCode) Background() color.Color {
+	return q.BackgroundColor
+}
+
 // Bitmap returns the QR Code as a 2D array of 1-bit pixels.
 //
 // bitmap[y][x] is true if the pixel at (x, y) is set.
 //
-// The bitmap includes the required "quiet zone" around the QR Code to aid
-// decoding.
+// The bitmap includes a "quiet zone" of Margin light modules around the QR
+// Code to aid decoding, none by default or with NoQuietZone. With QuietZone
+// the edges may differ, and the bitmap is not square.
 func (q *QRCode) Bitmap() [][]bool {
-	return q.symbol.bitmap()
-}
-
-// Image returns the QR Code as an image.Image.
-//
-// A positive size sets a fixed image width and height (e.g. 256 yields an
-// 256x256px image).
-//
-// Depending on the amount of data encoded, fixed size images can have different
-// amounts of padding (white space around the QR Code). As an alternative, a
-// variable sized image can be generated instead:
-//
-// A negative size causes a variable sized image to be returned. The image
-// returned is the minimum size required for the QR Code. Choose a larger
-// negative number to increase the scale of the image. e.g. a size of -5 causes
-// each module (QR Code "pixel") to be 5px in size.
-func (q *QRCode) Image() image.Image {
-	// Minimum pixels (both width and height) required.
-	realSize := q.symbol.size
+	bitmap := q.symbol.bitmap()
 
-	// Variable size support.
-	if q.width < 0 {
-		q.width = q.width * -1 * realSize
-	}
-	if q.height < 0 {
-		q.height = q.height * -1 * realSize
+	top, right, bottom, left := q.quietZoneExtra()
+	if top == 0 && right == 0 && bottom == 0 && left == 0 {
+		return bitmap
 	}
 
-	// Actual pixels available to draw the symbol. Automatically increase the
-	// image size if it's not large enough.
-	if q.width < realSize {
-		q.width = realSize
+	width := left + len(bitmap) + right
+	padded := make([][]bool, 0, top+len(bitmap)+bottom)
+	for i := 0; i < top; i++ {
+		padded = append(padded, make([]bool, width))
 	}
-	if q.height < realSize {
-		q.height = realSize
+	for _, row := range bitmap {
+		r := make([]bool, width)
+		copy(r[left:], row)
+		padded = append(padded, r)
+	}
+	for i := 0; i < bottom; i++ {
+		padded = append(padded, make([]bool, width))
 	}
 
-	// Size of each module drawn.
-	pixelsPerModuleX := q.width / realSize
-	pixelsPerModuleY := q.height / realSize
-
-	// Center the symbol within the image.
-	offsetX := (q.width - realSize*pixelsPerModuleX) / 2
-	offsetY := (q.height - realSize*pixelsPerModuleY) / 2
-
-	rect := image.Rectangle{Min: image.Point{0, 0}, Max: image.Point{X: q.width, Y: q.height}}
-
-	// Saves a few bytes to have them in this order
-	p := color.Palette([]color.Color{q.BackgroundColor, q.ForegroundColor})
-	img := image.NewPaletted(rect, p)
+	return padded
+}
 
-	for i := 0; i < q.width; i++ {
-		for j := 0; j < q.height; j++ {
-			img.Set(i, j, q.BackgroundColor)
-		}
-	}
+// PackedBitmap returns the Bitmap with 1 bit per module, for transmission to
+// displays and microcontrollers.
+//
+// width is the number of modules in each row, including the quiet zone. Each
+// row has (width+7)/8 bytes, filled most significant bit first; a set bit is a
+// dark module. Unused bits at the end of a row are zero.
+func (q *QRCode) PackedBitmap() (width int, rows [][]byte) {
+	bitmap := q.Bitmap()
 
-	bitmap := q.symbol.bitmap()
+	rows = make([][]byte, len(bitmap))
 	for y, row := range bitmap {
+		rows[y] = make([]byte, (len(row)+7)/8)
 		for x, v := range row {
 			if v {
-				startX := x*pixelsPerModuleX + offsetX
-				startY := y*pixelsPerModuleY + offsetY
-				for i := startX; i < startX+pixelsPerModuleX; i++ {
-					for j := startY; j < startY+pixelsPerModuleY; j++ {
-						img.Set(i, j, q.ForegroundColor)
-					}
-				}
+				rows[y][x/8] |= 0x80 >> uint(x%8)
 			}
 		}
 	}
 
-	if float64(q.width)/float64(img.Bounds().Dx()) > 1 {
-		tmp := scale(img, q.width)
-		return &tmp
-	}
-
-	return img
+	return len(bitmap[0]), rows
 }
 
-// PNG returns the QR Code as a PNG image.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently returned. Negative values for size cause a
-// variable sized image to be returned: See the documentation for Image().
-func (q *QRCode) PNG() ([]byte, error) {
-	img := q.Image()
-
-	encoder := png.Encoder{CompressionLevel: png.BestCompression}
-
-	var b bytes.Buffer
-	err := encoder.Encode(&b, img)
-
-	if err != nil {
-		return nil, err
-	}
+// Codewords returns the final codeword sequence placed in the symbol: the data
+// and error correction codewords of every block, interleaved, see Blocks.
+// Remainder bits are not included.
+func (q *QRCode) Codewords() []byte {
+	encoded := q.encodeBlocks()
 
-	return b.Bytes(), nil
+	return encoded.Substr(0, encoded.Len()-q.version.numRemainderBits).Bytes()
 }
 
-// Write writes the QR Code as a PNG image to io.Writer.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently written. Negative values for size cause a
-// variable sized image to be written: See the documentation for Image().
-func (q *QRCode) Write(out io.Writer) error {
-	png, err := q.PNG()
+// checkModulePixels returns an error under Strict if the image size would
+// make modules smaller than MinModulePixels.
+func (q *QRCode) checkModulePixels() error {
+	if !q.strict || q.minModulePixels <= 0 {
+		return nil
+	}
 
-	if err != nil {
-		return err
+	realSize := q.symbol.size
+	pixelsPerModule := func(size int) int {
+		switch {
+		case q.scale > 0:
+			return q.scale
+		case size < 0:
+			return -size
+		case size < realSize:
+			return 1
+		}
+		return size / realSize
 	}
-	_, err = out.Write(png)
-	return err
-}
 
-// WriteFile writes the QR Code as a PNG image to the specified file.
-//
-// size is both the image width and height in pixels. If size is too small then
-// a larger image is silently written. Negative values for size cause a
-// variable sized image to be written: See the documentation for Image().
-func (q *QRCode) WriteFile(filename string) error {
-	png, err := q.PNG()
-	if err != nil {
-		return err
+	if x, y := pixelsPerModule(q.width), pixelsPerModule(q.height); min(x, y) < q.minModulePixels {
+		return fmt.Errorf("image size gives %dx%d pixel modules, smaller than the minimum %d (version %d is %d modules wide)",
+			x, y, q.minModulePixels, q.VersionNumber, realSize)
 	}
 
-	return ioutil.WriteFile(filename, png, os.FileMode(0644))
+	return nil
 }
 
 // encode completes the steps required to encode the QR Code. These include
 // adding the terminator bits and padding, splitting the data into blocks and
 // applying the error correction, and selecting the best data mask.
-func (q *QRCode) encode(numTerminatorBits int) {
+func (q *QRCode) encode(numTerminatorBits int) error {
 	q.addTerminatorBits(numTerminatorBits)
-	q.addPadding()
+	if err := q.addPadding(); err != nil {
+		return err
+	}
 
 	encoded := q.encodeBlocks()
 
 	const numMasks int = 8
 	penalty := 0
 
-	for mask := 0; mask < numMasks; mask++ {
-		var s *symbol
-		var err error
-
-		s, err = buildRegularSymbol(q.version, mask, encoded, q.margin)
+	start := time.Now()
 
+	for mask := 0; mask < numMasks; mask++ {
+		s, err := buildRegularSymbol(q.version, mask, encoded, q.quietZone())
 		if err != nil {
-			log.Panic(err.Error())
+			return err
 		}
 
-		numEmptyModules := s.numEmptyModules()
-		if numEmptyModules != 0 {
-			log.Panicf("bug: numEmptyModules is %d (expected 0) (version=%d)", numEmptyModules, q.VersionNumber)
-		}
+		b := s.penaltyBreakdown(q.legacyMaskPenalty)
+		b.Mask = mask
+		q.maskPenalties[mask] = b
+		p := b.Total
 
-		p := s.penaltyScore()
-
-		// log.Printf("mask=%d p=%3d p1=%3d p2=%3d p3=%3d p4=%d\n", mask, p, s.penalty1(), s.penalty2(), s.penalty3(), s.penalty4())
+		q.debug("mask evaluated", "mask", mask, "penalty", p,
+			"penalty1", b.Rule1, "penalty2", b.Rule2, "penalty3", b.Rule3, "penalty4", b.Rule4)
 
 		if q.symbol == nil || p < penalty {
 			q.symbol = s
 			q.mask = mask
+			q.penalty = p
 			penalty = p
 		}
 	}
+
+	q.maskPenalties[q.mask].Chosen = true
+	q.applyInfoOverrides()
+
+	q.debug("mask chosen", "mask", q.mask, "penalty", q.penalty, "duration", time.Since(start))
+	if q.metrics != nil {
+		q.metrics.MasksEvaluated(time.Since(start), q.mask, q.penalty)
+	}
+
+	return nil
 }
 
 // addTerminatorBits adds final terminator bits to the encoded data.
@@ -521,68 +646,8 @@ func (q *QRCode) addTerminatorBits(numTerminatorBits int) {
 //
 // The QR Code's final data sequence is returned.
 func (q *QRCode) encodeBlocks() *bitset.Bitset {
-	// Split into blocks.
-	type dataBlock struct {
-		data          *bitset.Bitset
-		ecStartOffset int
-	}
-
-	block := make([]dataBlock, q.version.numBlocks())
-
-	start := 0
-	end := 0
-	blockID := 0
-
-	for _, b := range q.version.block {
-		for j := 0; j < b.numBlocks; j++ {
-			start = end
-			end = start + b.numDataCodewords*8
-
-			// Apply error correction to each block.
-			numErrorCodewords := b.numCodewords - b.numDataCodewords
-			block[blockID].data = reedsolomon.Encode(q.data.Substr(start, end), numErrorCodewords)
-			block[blockID].ecStartOffset = end - start
-
-			blockID++
-		}
-	}
-
-	// Interleave the blocks.
-
 	result := bitset.New()
-
-	// Combine data blocks.
-	working := true
-	for i := 0; working; i += 8 {
-		working = false
-
-		for j, b := range block {
-			if i >= block[j].ecStartOffset {
-				continue
-			}
-
-			result.Append(b.data.Substr(i, i+8))
-
-			working = true
-		}
-	}
-
-	// Combine error correction blocks.
-	working = true
-	for i := 0; working; i += 8 {
-		working = false
-
-		for j, b := range block {
-			offset := i + block[j].ecStartOffset
-			if offset >= block[j].data.Len() {
-				continue
-			}
-
-			result.Append(b.data.Substr(offset, offset+8))
-
-			working = true
-		}
-	}
+	result.AppendBytes(interleaveBlocks(q.Blocks()))
 
 	// Append remainder bits.
 	result.AppendNumBools(q.version.numRemainderBits, false)
@@ -599,12 +664,21 @@ func max(a int, b int) int {
 	return b
 }
 
+// min returns the minimum of a and b.
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
 // addPadding pads the encoded data upto the full length required.
-func (q *QRCode) addPadding() {
+func (q *QRCode) addPadding() error {
 	numDataBits := q.version.numDataBits()
 
 	if q.data.Len() == numDataBits {
-		return
+		return nil
 	}
 
 	// Pad to the nearest codeword boundary.
@@ -616,6 +690,13 @@ func (q *QRCode) addPadding() {
 		bitset.New(false, false, false, true, false, false, false, true),
 	}
 
+	// Insert custom pad codewords.
+	if q.padCodeword != nil {
+		for i := 0; numDataBits-q.data.Len() >= 8; i++ {
+			q.data.AppendByte(q.padCodeword(i), 8)
+		}
+	}
+
 	// Insert pad codewords alternately.
 	i := 0
 	for numDataBits-q.data.Len() >= 8 {
@@ -625,8 +706,10 @@ func (q *QRCode) addPadding() {
 	}
 
 	if q.data.Len() != numDataBits {
-		log.Panicf("BUG: got len %d, expected %d", q.data.Len(), numDataBits)
+		return fmt.Errorf("padded data is %d bits (expected %d)", q.data.Len(), numDataBits)
 	}
+
+	return nil
 }
 
 // ToString produces a multi-line string that forms a QR-code image.
@@ -645,42 +728,3 @@ func (q *QRCode) ToString(inverseColor bool) string {
 	}
 	return buf.String()
 }
-
-// getPointType return point type.
-func (q *QRCode) getPointType(x, y int) int {
-	qrSize := q.version.symbolSize()
-	// finderPatternPoint
-	if 0 <= x-q.symbol.quietZoneSize && x-q.symbol.quietZoneSize <= finderPatternSize && 0 <= y-q.symbol.quietZoneSize && y-q.symbol.quietZoneSize <= finderPatternSize { // top left
-		return finderPatternPoint
-	}
-	if qrSize-finderPatternSize <= x-q.symbol.quietZoneSize && x-q.symbol.quietZoneSize <= qrSize && 0 <= y-q.symbol.quietZoneSize && y-q.symbol.quietZoneSize <= finderPatternSize { // top right
-		return finderPatternPoint
-	}
-	if 0 <= x-q.symbol.quietZoneSize && x-q.symbol.quietZoneSize <= finderPatternSize && qrSize-finderPatternSize <= y-q.symbol.quietZoneSize && y-q.symbol.quietZoneSize <= qrSize { // bottom left
-		return finderPatternPoint
-	}
-	// alignmentPatternsPoint
-	alignmentPatternSize := len(alignmentPattern)
-	for _, x0 := range alignmentPatternCenter[q.version.version] {
-	TMP:
-		for _, y0 := range alignmentPatternCenter[q.version.version] {
-			// m.symbol.set2dPattern(x-2, y-2, alignmentPattern)
-			if x0-2 <= x-q.symbol.quietZoneSize && x-q.symbol.quietZoneSize < x0-2+alignmentPatternSize && y0-2 <= y-q.symbol.quietZoneSize && y-q.symbol.quietZoneSize < y0-2+alignmentPatternSize {
-				// there is alignment patterns.
-				for j, row := range alignmentPattern {
-					for i, value := range row {
-						if value != q.symbol.get(x0-2+i+q.symbol.quietZoneSize, y0-2+j+q.symbol.quietZoneSize) {
-							continue TMP
-						}
-					}
-				}
-				return alignmentPatternsPoint
-			}
-		}
-	}
-	// timingPatternsPoint
-	if (finderPatternSize+1 <= x && x <= q.symbol.size-finderPatternSize && y == finderPatternSize-1) || (x == finderPatternSize-1 && finderPatternSize+1 <= y && y <= q.symbol.size-finderPatternSize) {
-		return timingPatternsPoint
-	}
-	return 0
-}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime/pprof"
+
+	"github.com/yougg/go-qrcode/bench"
+)
+
+// runBench runs the "qrcode bench" subcommand with args, printing one line
+// per benchmark case.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	run := fs.String("run", "", "only run cases with names matching this regular expression, e.g. 'v40-H'")
+	render := fs.Bool("render", false, "also render PNG images of each size")
+	cpuProfile := fs.String("cpuprofile", "", "write a CPU profile to this file")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: qrcode bench [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := regexp.Compile(*run)
+	if err != nil {
+		return fmt.Errorf("Error: invalid -run: %v", err)
+	}
+
+	cases := bench.EncodeCases()
+	if *render {
+		cases = bench.RenderCases()
+	}
+
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	for _, c := range cases {
+		if !filter.MatchString(c.Name()) {
+			continue
+		}
+		if err := c.Run(); err != nil {
+			return err
+		}
+
+		r := c.Benchmark()
+		fmt.Printf("%-16s %s\t%s\n", c.Name(), r, r.MemString())
+	}
+
+	return nil
+}
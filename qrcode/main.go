@@ -7,16 +7,31 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/yougg/go-qrcode"
+	"github.com/yougg/go-qrcode/styles"
 )
 
 func main() {
-	outFile := flag.String("o", "", "out PNG file prefix, empty for stdout")
-	size := flag.Int("s", 256, "image size (pixel)")
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		checkError(runBench(os.Args[2:]))
+		return
+	}
+
+	defaultSize, defaultLevel, err := envDefaults()
+	checkError(err)
+
+	outFile := flag.String("o", "", "out file prefix, empty for stdout")
+	size := flag.Int("s", defaultSize, "image size (pixel), default from $QRCODE_SIZE")
 	textArt := flag.Bool("t", false, "print as text-art on stdout")
 	negative := flag.Bool("i", false, "invert black and white")
+	level := defaultLevel
+	flag.Var(&level, "l", "error recovery level: L, M, Q or H, default from $QRCODE_LEVEL")
+	style := flag.String("style", "", "style preset: "+styleNames())
+	config := flag.String("config", "", "theme file (JSON or YAML), see qrcode.LoadTheme")
+	lint := flag.Bool("lint", false, "print warnings about the content on stderr")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `qrcode -- QR Code encoder in Go
 https://github.com/yougg/go-qrcode
@@ -36,6 +51,15 @@ Usage:
 
        qrcode "homepage: https://github.com/yougg/go-qrcode" > out.png
 
+  3. Render with a theme file, for reproducible output in build pipelines.
+     Flags given on the command line override the theme:
+
+       qrcode -config qr.yaml -o out https://example.org
+
+  4. Benchmark the encoder and renderer, see "qrcode bench -h":
+
+       qrcode bench -render -cpuprofile cpu.out -run 'v40-H'
+
 `)
 	}
 	flag.Parse()
@@ -47,10 +71,45 @@ Usage:
 
 	content := strings.Join(flag.Args(), " ")
 
+	if *lint {
+		for _, w := range qrcode.Lint(content) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+	}
+
+	// Precedence, lowest first: built-in and environment defaults, the theme
+	// file, then flags set on the command line.
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
 	var opts = []qrcode.Option{
 		qrcode.Width(*size),
 		qrcode.Height(*size),
-		qrcode.Level(qrcode.Highest),
+		qrcode.Level(level),
+	}
+
+	theme := &qrcode.Theme{}
+	if *config != "" {
+		theme, err = loadTheme(*config)
+		checkError(err)
+
+		themeOpts, err := theme.Options()
+		checkError(err)
+		opts = append(opts, themeOpts...)
+	}
+
+	if *style != "" {
+		s, ok := styles.ByName(*style)
+		if !ok {
+			checkError(fmt.Errorf("Error: unknown style %q", *style))
+		}
+		opts = append(opts, qrcode.Style(s))
+	}
+	if set["s"] {
+		opts = append(opts, qrcode.Width(*size), qrcode.Height(*size))
+	}
+	if set["l"] {
+		opts = append(opts, qrcode.Level(level))
 	}
 
 	q, err := qrcode.New(content, opts...)
@@ -63,22 +122,67 @@ Usage:
 	}
 
 	if *negative {
-		q.ForegroundColor, q.BackgroundColor = q.BackgroundColor, q.ForegroundColor
+		q, err = q.With(qrcode.ForegroundColor(q.Background()), qrcode.BackgroundColor(q.Foreground()))
+		checkError(err)
+	}
+
+	if *outFile == "" {
+		checkError(theme.Write(q, os.Stdout))
+		return
 	}
 
-	var png []byte
-	png, err = q.PNG()
+	ext := strings.ToLower(theme.Format)
+	if ext == "" {
+		ext = "png"
+	}
+
+	fh, err := os.Create(*outFile + "." + ext)
 	checkError(err)
+	defer fh.Close()
+	checkError(theme.Write(q, fh))
+}
 
-	if *outFile == "" {
-		os.Stdout.Write(png)
-	} else {
-		var fh *os.File
-		fh, err = os.Create(*outFile + ".png")
-		checkError(err)
-		defer fh.Close()
-		fh.Write(png)
+// envDefaults returns the default image size and recovery level, taken from
+// $QRCODE_SIZE and $QRCODE_LEVEL if they are set.
+func envDefaults() (int, qrcode.RecoveryLevel, error) {
+	size := 256
+	level := qrcode.Highest
+
+	if s := os.Getenv("QRCODE_SIZE"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Error: invalid QRCODE_SIZE %q", s)
+		}
+		size = n
+	}
+
+	if s := os.Getenv("QRCODE_LEVEL"); s != "" {
+		if err := level.Set(s); err != nil {
+			return 0, 0, fmt.Errorf("Error: invalid QRCODE_LEVEL %q", s)
+		}
+	}
+
+	return size, level, nil
+}
+
+// loadTheme reads the theme file at path.
+func loadTheme(path string) (*qrcode.Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return qrcode.LoadTheme(f)
+}
+
+// styleNames returns the names of the style presets, for the usage message.
+func styleNames() string {
+	var names []string
+	for _, s := range styles.All {
+		names = append(names, s.Name)
 	}
+	return strings.Join(names, ", ")
 }
 
 func checkError(err error) {
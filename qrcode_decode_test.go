@@ -1,6 +1,9 @@
 // go-qrcode
 // Copyright 2014 Tom Harwood
 
+//go:build !tinygo
+// +build !tinygo
+
 package qrcode
 
 import (
@@ -24,6 +27,17 @@ import (
 var testDecode = flag.Bool("test-decode", false, "Enable decode tests. Requires zbarimg installed.")
 var testDecodeFuzz = flag.Bool("test-decode-fuzz", false, "Enable decode fuzz tests. Requires zbarimg installed.")
 
+// The differential tests decode random payloads in every mode, level and
+// version with an independent decoder, catching encoder bugs (e.g. in block
+// interleaving) that a decoder sharing this package's tables would not. Any
+// command reading a PNG image on stdin and writing the content to stdout can
+// be used, e.g. zbarimg or a wrapper around zxing-cpp:
+//
+//	go test -run Differential -reference-decoder 'zbarimg --quiet --raw -Sdisable -Sqrcode.enable /dev/stdin'
+//	go test -run XXX -fuzz FuzzDecodeDifferential -reference-decoder '...'
+var referenceDecoder = flag.String("reference-decoder", "", "Enable differential tests, decoding with this command (PNG on stdin, content on stdout).")
+var differentialIterations = flag.Int("differential-iterations", 256, "Number of random payloads decoded by TestDecodeDifferential.")
+
 func TestDecodeBasic(t *testing.T) {
 	if !*testDecode {
 		t.Skip("Decode tests not enabled")
@@ -116,7 +130,7 @@ func TestDecodeAllCharacters(t *testing.T) {
 
 	// zbarimg has trouble with null bytes, hence start from ASCII 1.
 	for i := 1; i < 256; i++ {
-		content += string(i)
+		content += string(rune(i))
 	}
 
 	q, err := New(content, Level(Low))
@@ -148,7 +162,7 @@ func TestDecodeFuzz(t *testing.T) {
 		for j := 0; j < length; j++ {
 			// zbarimg seems to have trouble with special characters, test printable
 			// characters only for now.
-			content += string(32 + r.Intn(94))
+			content += string(rune(32 + r.Intn(94)))
 		}
 
 		for _, level := range []RecoveryLevel{Low, Medium, High, Highest} {
@@ -166,6 +180,104 @@ func TestDecodeFuzz(t *testing.T) {
 	}
 }
 
+func TestDecodeDifferential(t *testing.T) {
+	if *referenceDecoder == "" {
+		t.Skip("Differential tests not enabled")
+	}
+
+	r := rand.New(rand.NewSource(0))
+	modes := []Mode{Numeric, Alphanumeric, Byte}
+	levels := []RecoveryLevel{Low, Medium, High, Highest}
+
+	for i := 0; i < *differentialIterations; i++ {
+		mode := modes[r.Intn(len(modes))]
+		level := levels[r.Intn(len(levels))]
+		minVersion := r.Intn(40) + 1
+
+		payload := make([]byte, r.Intn(3000)+1)
+		r.Read(payload)
+
+		differentialCheck(t, differentialContent(mode, payload), level, minVersion)
+	}
+}
+
+func FuzzDecodeDifferential(f *testing.F) {
+	f.Add(int(Numeric), []byte("0123456789"), int(Medium), 1)
+	f.Add(int(Alphanumeric), []byte("HELLO WORLD"), int(High), 7)
+	f.Add(int(Byte), []byte("https://example.org/?q=1"), int(Highest), 40)
+
+	f.Fuzz(func(t *testing.T, mode int, payload []byte, level int, minVersion int) {
+		if *referenceDecoder == "" {
+			t.Skip("Differential tests not enabled")
+		}
+		if len(payload) == 0 || level < int(Low) || level > int(Highest) || minVersion < 1 || minVersion > 40 {
+			return
+		}
+
+		differentialCheck(t, differentialContent(Mode(mode), payload), RecoveryLevel(level), minVersion)
+	})
+}
+
+// differentialContent maps payload onto the characters of mode, so the
+// content is encoded in that mode. Byte content is printable ASCII, which
+// every decoder returns unchanged.
+func differentialContent(mode Mode, payload []byte) string {
+	const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+	content := make([]byte, len(payload))
+	for i, b := range payload {
+		switch mode {
+		case Numeric:
+			content[i] = '0' + b%10
+		case Alphanumeric:
+			content[i] = alphanumeric[int(b)%len(alphanumeric)]
+		default:
+			content[i] = 'a' + b%26
+			if b%7 == 0 {
+				content[i] = 32 + b%95
+			}
+		}
+	}
+
+	return string(content)
+}
+
+// differentialCheck encodes content, shortening it until it fits, and checks
+// the reference decoder returns it.
+func differentialCheck(t *testing.T, content string, level RecoveryLevel, minVersion int) {
+	t.Helper()
+
+	var q *QRCode
+	var err error
+	for ; content != ""; content = content[:len(content)/2] {
+		q, err = New(content, Level(level), MinVersion(minVersion), Margin(4), Scale(4))
+		if err == nil {
+			break
+		}
+	}
+	if content == "" {
+		return
+	}
+
+	png, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := strings.Fields(*referenceDecoder)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(png)
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%d-%s %q: reference decoder failed: %v", q.VersionNumber, level, content, err)
+	}
+
+	if got := strings.TrimSuffix(string(out), "\n"); got != content {
+		t.Errorf("%d-%s mask %d: decoded %q, expected %q", q.VersionNumber, level, q.mask, got, content)
+	}
+}
+
 func zbarimgCheck(q *QRCode) error {
 	s, err := zbarimgDecode(q)
 	if err != nil {
@@ -4,6 +4,7 @@
 package qrcode
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 )
@@ -142,6 +143,40 @@ func TestQRCodeVersionCapacity(t *testing.T) {
 	}
 }
 
+func TestQRCodeMinVersion(t *testing.T) {
+	tests := []struct {
+		content    string
+		minVersion int
+		expected   int
+	}{
+		{"A", 0, 1},
+		{"A", 1, 1},
+		{"A", 5, 5},
+		{"A", 10, 10},
+		{"A", 27, 27},
+		{"A", 40, 40},
+		// The content needs version 2, which is larger than the minimum.
+		{strings.Repeat("#", 30), 1, 2},
+		{strings.Repeat("#", 1000), 10, 22},
+	}
+
+	for _, test := range tests {
+		q, err := New(test.content, Level(Low), MinVersion(test.minVersion))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if q.VersionNumber != test.expected {
+			t.Errorf("%d bytes with MinVersion(%d) chose version %d, expected %d",
+				len(test.content), test.minVersion, q.VersionNumber, test.expected)
+		}
+	}
+
+	if _, err := New("A", MinVersion(41)); err == nil {
+		t.Error("MinVersion(41) succeeded, expected error")
+	}
+}
+
 func TestQRCodeISOAnnexIExample(t *testing.T) {
 	var q *QRCode
 	q, err := New("01234567", Level(Medium))
@@ -159,6 +194,101 @@ func TestQRCodeISOAnnexIExample(t *testing.T) {
 	}
 }
 
+func TestQRCodeCodewords(t *testing.T) {
+	// ISO/IEC 18004 Annex I: "01234567" as a 1-M symbol.
+	q, err := New("01234567", Level(Medium))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []byte{
+		0x10, 0x20, 0x0c, 0x56, 0x61, 0x80, 0xec, 0x11,
+		0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11,
+		0xa5, 0x24, 0xd4, 0xc1, 0xed, 0x36, 0xc7, 0x87,
+		0x2c, 0x55,
+	}
+
+	if got := q.Codewords(); !bytes.Equal(got, expected) {
+		t.Errorf("got codewords % x, expected % x", got, expected)
+	}
+
+	// Multiple blocks are interleaved: 5-Q has two blocks of 15 and two of 16
+	// data codewords.
+	q, err = New(strings.Repeat("A", 60), Level(High), MinVersion(5))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	data := q.data.Bytes()
+	codewords := q.Codewords()
+	if len(codewords) != 134 {
+		t.Fatalf("got %d codewords, expected 134", len(codewords))
+	}
+	for i, expected := range []byte{data[0], data[15], data[30], data[46], data[1]} {
+		if codewords[i] != expected {
+			t.Errorf("codeword %d is 0x%02x, expected 0x%02x", i, codewords[i], expected)
+		}
+	}
+}
+
+func TestQRCodePadCodewords(t *testing.T) {
+	tests := []struct {
+		opt      Option
+		expected []byte
+	}{
+		{PadCodewords(), []byte{0xec, 0x11, 0xec, 0x11}},
+		{PadCodewords(0xaa), []byte{0xaa, 0xaa, 0xaa, 0xaa}},
+		{PadCodewords(1, 2, 3), []byte{1, 2, 3, 1}},
+		{PadFunc(func(i int) byte { return byte(i * 16) }), []byte{0x00, 0x10, 0x20, 0x30}},
+	}
+
+	for _, test := range tests {
+		// 1-M holds 16 data codewords. "01234567" uses the first 6, the
+		// remainder are padding.
+		q, err := New("01234567", Level(Medium), test.opt)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if got := q.Codewords()[6:10]; !bytes.Equal(got, test.expected) {
+			t.Errorf("got padding % x, expected % x", got, test.expected)
+		}
+	}
+}
+
+func TestQRCodePackedBitmap(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bitmap := q.Bitmap()
+	width, rows := q.PackedBitmap()
+
+	if width != len(bitmap) || len(rows) != len(bitmap) {
+		t.Fatalf("got width %d and %d rows, expected %d", width, len(rows), len(bitmap))
+	}
+
+	for y, row := range rows {
+		if len(row) != (width+7)/8 {
+			t.Fatalf("row %d has %d bytes, expected %d", y, len(row), (width+7)/8)
+		}
+
+		for x := 0; x < len(row)*8; x++ {
+			set := row[x/8]&(0x80>>uint(x%8)) != 0
+			if x >= width {
+				if set {
+					t.Fatalf("padding bit %d of row %d is set", x, y)
+				}
+				continue
+			}
+			if set != bitmap[y][x] {
+				t.Fatalf("module (%d, %d) is %v, expected %v", x, y, set, bitmap[y][x])
+			}
+		}
+	}
+}
+
 func BenchmarkQRCodeURLSize(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		New("http://www.example.org", Level(Medium))
@@ -0,0 +1,58 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+)
+
+// palette returns the colors used by Image(), or a copy of the Palette.
+func (q *QRCode) palette() color.Palette {
+	if q.customPalette != nil {
+		return append(color.Palette{}, q.customPalette...)
+	}
+
+	// Saves a few bytes to have them in this order
+	p := color.Palette([]color.Color{q.BackgroundColor, q.ForegroundColor})
+
+	if q.quietZoneColor != nil {
+		p = append(p, q.quietZoneColor)
+	}
+	if q.outlineWidth > 0 && q.outlineColor != nil {
+		p = append(p, q.outlineColor)
+	}
+	if q.quietZoneRadius > 0 {
+		p = append(p, color.Transparent)
+	}
+
+	return p
+}
+
+// drawQuietZone applies the quiet zone color, rounded corners and outline
+// frame to img, as drawn by Image().
+func (q *QRCode) drawQuietZone(img *image.Paletted) {
+	if q.quietZoneColor == nil && q.quietZoneRadius <= 0 && (q.outlineWidth <= 0 || q.outlineColor == nil) {
+		return
+	}
+
+	b := img.Bounds()
+	symbolRect := q.symbolRect(b)
+
+	outer := &shapeMask{b, LogoRoundedRect, q.quietZoneRadius}
+	inner := &shapeMask{b.Inset(q.outlineWidth), LogoRoundedRect, q.quietZoneRadius - q.outlineWidth}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			switch {
+			case !outer.contains(x, y):
+				img.Set(x, y, color.Transparent)
+			case q.outlineWidth > 0 && q.outlineColor != nil && !inner.contains(x, y):
+				img.Set(x, y, q.outlineColor)
+			case q.quietZoneColor != nil && !(image.Point{x, y}.In(symbolRect)):
+				img.Set(x, y, q.quietZoneColor)
+			}
+		}
+	}
+}
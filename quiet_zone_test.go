@@ -0,0 +1,254 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestQuietZoneStyles(t *testing.T) {
+	navy := color.RGBA{0, 0, 0x80, 0xff}
+	red := color.RGBA{0xff, 0, 0, 0xff}
+
+	q, err := New("https://example.org", Width(-4), Height(-4), Margin(4),
+		QuietZoneColor(navy), QuietZoneRadius(10), QuietZoneOutline(2, red))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img := q.Image()
+	size := img.Bounds().Dx()
+
+	tests := []struct {
+		x, y     int
+		expected color.Color
+	}{
+		// Outside the rounded corner.
+		{0, 0, color.Transparent},
+		{size - 1, size - 1, color.Transparent},
+		// The outline frame.
+		{size / 2, 0, red},
+		{size / 2, 1, red},
+		{0, size / 2, red},
+		// The quiet zone.
+		{size / 2, 2, navy},
+		{size / 2, 15, navy},
+		// The top left finder pattern starts after the quiet zone.
+		{16, 16, color.Black},
+	}
+
+	for _, test := range tests {
+		got := color.RGBAModel.Convert(img.At(test.x, test.y))
+		expected := color.RGBAModel.Convert(test.expected)
+		if got != expected {
+			t.Errorf("pixel (%d, %d) is %v, expected %v", test.x, test.y, got, expected)
+		}
+	}
+}
+
+func TestQuietZoneDefault(t *testing.T) {
+	q, err := New("https://example.org", Width(-4), Height(-4), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img := q.Image()
+
+	for _, p := range [][2]int{{0, 0}, {img.Bounds().Dx() / 2, 0}, {15, 15}} {
+		got := color.RGBAModel.Convert(img.At(p[0], p[1]))
+		if got != color.RGBAModel.Convert(color.White) {
+			t.Errorf("pixel %v is %v, expected white", p, got)
+		}
+	}
+}
+
+func TestNoQuietZone(t *testing.T) {
+	for _, opts := range [][]Option{
+		{Margin(4), NoQuietZone()},
+		{QuietZone(1, 2, 3, 4), NoQuietZone()},
+	} {
+		q, err := New("hello", append(opts, Width(100), Height(100))...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if got := len(q.Bitmap()); got != 21 {
+			t.Errorf("got bitmap size %d, expected 21", got)
+		}
+
+		img := q.Image()
+		if size := img.Bounds().Size(); size.X != 84 || size.Y != 84 {
+			t.Errorf("got image size %v, expected 84x84", size)
+		}
+
+		// The finder pattern reaches the image corner.
+		if r, _, _, _ := img.At(0, 0).RGBA(); r != 0 {
+			t.Error("expected a dark module at the image corner")
+		}
+	}
+}
+
+func TestQuietZoneEdges(t *testing.T) {
+	q, err := New("hello", QuietZone(8, 2, 4, 2), Scale(3))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// The symbol is 21 modules, with 2 modules all round and 6 more at the
+	// top and 2 more at the bottom.
+	bitmap := q.Bitmap()
+	if len(bitmap) != 33 || len(bitmap[0]) != 25 {
+		t.Fatalf("got bitmap %dx%d, expected 25x33", len(bitmap[0]), len(bitmap))
+	}
+	if !bitmap[8][2] || bitmap[7][2] || bitmap[8][1] {
+		t.Error("finder pattern is not at the top left of the quiet zone")
+	}
+	if width, rows := q.PackedBitmap(); width != 25 || len(rows) != 33 {
+		t.Errorf("got packed bitmap %dx%d, expected 25x33", width, len(rows))
+	}
+
+	img := q.Image()
+	if size := img.Bounds().Size(); size.X != 75 || size.Y != 99 {
+		t.Errorf("got image size %v, expected 75x99", size)
+	}
+	if got, expected := q.symbolRect(img.Bounds()), image.Rect(6, 24, 69, 87); got != expected {
+		t.Errorf("got symbol at %v, expected %v", got, expected)
+	}
+	if r, _, _, _ := img.At(6, 24).RGBA(); r != 0 {
+		t.Error("expected a dark module at the top left of the symbol")
+	}
+
+	svg, err := q.Render("svg", RenderOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(string(svg), `viewBox="0 -6 25 33"`) {
+		t.Errorf("svg viewBox does not include the quiet zone: %s", svg[:120])
+	}
+
+	// Margin replaces QuietZone.
+	q, err = New("hello", QuietZone(8, 2, 4, 2), Margin(1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := len(q.Bitmap()); got != 23 {
+		t.Errorf("got bitmap size %d, expected 23", got)
+	}
+
+	if _, err := New("hello", QuietZone(4, -1, 4, 4)); err == nil {
+		t.Error("expected error for a negative quiet zone")
+	}
+}
+
+func TestQuietZoneEdgesPointType(t *testing.T) {
+	q, err := New("hello", QuietZone(8, 2, 4, 2))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, test := range []struct {
+		x, y     int
+		expected PointType
+	}{
+		{2, 7, QuietZonePoint},
+		{2, 8, FinderPatternPoint},
+		{1, 8, QuietZonePoint},
+		{22, 28, DataPoint},
+	} {
+		if got := q.PointType(test.x, test.y); got != test.expected {
+			t.Errorf("PointType(%d, %d) = %s, expected %s", test.x, test.y, got, test.expected)
+		}
+	}
+}
+
+func TestPalette(t *testing.T) {
+	halo := color.RGBA{0xff, 0xcc, 0, 0xff}
+	eyes := color.RGBA{0x80, 0, 0, 0xff}
+	p := color.Palette{color.Black, eyes, color.White, halo}
+
+	q, err := New("https://example.org", Palette(p), Scale(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, ok := q.Image().(*image.Paletted)
+	if !ok {
+		t.Fatal("Image() is not paletted")
+	}
+	if len(img.Palette) != 4 {
+		t.Fatalf("got %d palette entries, expected 4", len(img.Palette))
+	}
+
+	// Dark modules use index 0 and light modules index 2.
+	bitmap := q.Bitmap()
+	for y, row := range bitmap {
+		for x, v := range row {
+			expected := uint8(2)
+			if v {
+				expected = 0
+			}
+			if got := img.ColorIndexAt(2*x, 2*y); got != expected {
+				t.Fatalf("module (%d, %d): got index %d, expected %d", x, y, got, expected)
+			}
+		}
+	}
+
+	for _, bad := range []color.Palette{{color.Black}, {color.Black, halo}} {
+		if _, err := New("https://example.org", Palette(bad)); err == nil {
+			t.Errorf("Palette(%v) succeeded, expected an error", bad)
+		}
+	}
+
+	// A nil palette restores the default order.
+	q, err = New("https://example.org", Palette(p), Palette(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Image().(*image.Paletted).Palette; len(got) != 2 || got[0] != q.BackgroundColor {
+		t.Errorf("got palette %v, expected the default", got)
+	}
+}
+
+func TestQuietZoneSize(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		opts     []Option
+		expected int
+	}{
+		{"QuietZoneSize", []Option{QuietZoneSize(3)}, 27},
+		{"QuitZoneSize", []Option{QuitZoneSize(3)}, 27},
+		{"field", []Option{func(q *QRCode) { q.QuitZoneSize = 3 }}, 27},
+		// Later options win, whichever spelling is used.
+		{"field then Margin", []Option{func(q *QRCode) { q.QuitZoneSize = 3 }, Margin(1)}, 23},
+		{"Margin then field", []Option{Margin(1), func(q *QRCode) { q.QuitZoneSize = 3 }}, 27},
+		{"QuitZoneSize then NoQuietZone", []Option{QuitZoneSize(3), NoQuietZone()}, 21},
+		{"QuietZone then QuietZoneSize", []Option{QuietZone(8, 2, 4, 2), QuietZoneSize(3)}, 27},
+	} {
+		q, err := New("hello", test.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		bitmap := q.Bitmap()
+		if len(bitmap) != test.expected || len(bitmap[0]) != test.expected {
+			t.Errorf("%s: got bitmap %dx%d, expected %dx%d", test.name,
+				len(bitmap[0]), len(bitmap), test.expected, test.expected)
+		}
+		if _, err := VerifyBitmap(bitmap); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+	}
+
+	// The quiet zone is part of the symbol, so With rejects it.
+	q, err := New("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.With(QuitZoneSize(8)); err == nil {
+		t.Error("With(QuitZoneSize(8)) succeeded, expected an error")
+	}
+}
@@ -0,0 +1,94 @@
+package qrcode
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minModuleMM is the smallest module size, in millimetres, which phone
+	// cameras reliably read at a normal scanning distance.
+	minModuleMM = 0.4
+
+	// largeModuleMM is the module size, in millimetres, above which a larger
+	// symbol is cheap, and extra error correction is worth having against
+	// dirt and damage.
+	largeModuleMM = 1.0
+
+	// longContentBytes is the content length above which symbol density
+	// dominates, and the lowest level is preferred.
+	longContentBytes = 300
+)
+
+// RecommendLevel suggests a RecoveryLevel for content, instead of always
+// choosing Highest and making the symbol much larger than it needs to be.
+//
+// The heuristics are:
+//   - Short text and URLs use Medium, a good default.
+//   - Long or binary content uses Low, keeping the symbol readable.
+//   - A logo covers modules, so needs at least High.
+//   - physicalSizeMM is the printed width of the symbol, excluding the quiet
+//     zone, or 0 if unknown. The level is lowered while modules would be too
+//     small to scan, and raised for large prints with large modules.
+//
+// Low is returned if content is too long to encode.
+func RecommendLevel(content string, hasLogo bool, physicalSizeMM float64) RecoveryLevel {
+	floor := Low
+	if hasLogo {
+		floor = High
+	}
+
+	level := Medium
+	if len(content) > longContentBytes || isBinary(content) {
+		level = Low
+	}
+	if level < floor {
+		level = floor
+	}
+
+	modules := recommendModules(content, level)
+	for modules == 0 && level > Low {
+		level--
+		modules = recommendModules(content, level)
+	}
+	if modules == 0 || physicalSizeMM <= 0 {
+		return level
+	}
+
+	for physicalSizeMM/float64(modules) < minModuleMM && level > floor {
+		level--
+		modules = recommendModules(content, level)
+	}
+
+	if level < Highest {
+		if m := recommendModules(content, level+1); m > 0 && physicalSizeMM/float64(m) >= largeModuleMM {
+			level++
+		}
+	}
+
+	return level
+}
+
+// recommendModules returns the width in modules, excluding the quiet zone, of
+// content encoded at level, or 0 if it is too long.
+func recommendModules(content string, level RecoveryLevel) int {
+	q, err := New(content, Level(level))
+	if err != nil {
+		return 0
+	}
+	return q.symbol.size - 2*q.symbol.quietZoneSize
+}
+
+// isBinary reports whether content looks like binary data rather than text.
+func isBinary(content string) bool {
+	if !utf8.ValidString(content) {
+		return true
+	}
+
+	for _, r := range content {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecommendLevel(t *testing.T) {
+	tests := []struct {
+		content        string
+		hasLogo        bool
+		physicalSizeMM float64
+		expected       RecoveryLevel
+	}{
+		// Unknown print size.
+		{"https://example.org", false, 0, Medium},
+		{"https://example.org", true, 0, High},
+		{strings.Repeat("a", 500), false, 0, Low},
+		{"\x00\x01\x02\xff", false, 0, Low},
+		{"line one\nline two", false, 0, Medium},
+
+		// Modules too small to scan at the default level are made larger by
+		// lowering it, down to Low.
+		{"https://example.org", false, 10, Medium},
+		{"https://example.org", false, 8, Low},
+
+		// Large prints get an extra level.
+		{"https://example.org", false, 200, High},
+		{"https://example.org", true, 200, Highest},
+
+		// A logo keeps at least High even when modules are small.
+		{"https://example.org", true, 5, High},
+
+		// Too long for anything but Low.
+		{strings.Repeat("#", 2900), false, 0, Low},
+		{strings.Repeat("#", 2900), true, 0, Low},
+	}
+
+	for _, test := range tests {
+		got := RecommendLevel(test.content, test.hasLogo, test.physicalSizeMM)
+		if got != test.expected {
+			t.Errorf("RecommendLevel(%q, %t, %g) = %s, expected %s", test.content, test.hasLogo,
+				test.physicalSizeMM, got, test.expected)
+		}
+	}
+}
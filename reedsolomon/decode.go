@@ -0,0 +1,172 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package reedsolomon
+
+import (
+	"errors"
+
+	"github.com/yougg/go-qrcode/bitset"
+)
+
+// ErrTooManyErrors is returned by Decode when a block has more errors than
+// its error correction bytes can correct.
+var ErrTooManyErrors = errors.New("reedsolomon: too many errors to correct")
+
+// Decode corrects errors in a block produced by Encode, and returns the
+// corrected data with the error correction bytes removed.
+//
+// numECBytes is the number of error correction bytes at the end of block. Up
+// to numECBytes/2 erroneous bytes are corrected; numErrors is the number
+// corrected. ErrTooManyErrors is returned if the block cannot be corrected.
+//
+// The steps are: syndrome computation, Berlekamp-Massey to find the error
+// locator polynomial, Chien search for the error positions, and Forney's
+// algorithm for the error values.
+func Decode(block *bitset.Bitset, numECBytes int) (data *bitset.Bitset, numErrors int, err error) {
+	if block.Len()%8 != 0 || numECBytes < 0 || block.Len()/8 < numECBytes || block.Len()/8 > 255 {
+		return nil, 0, errors.New("reedsolomon: invalid block size")
+	}
+
+	numBytes := block.Len() / 8
+	codeword := newGFPolyFromData(block)
+
+	syndromes, ok := rsSyndromes(codeword, numECBytes)
+	if !ok {
+		locator := rsErrorLocator(syndromes)
+		numErrors = locator.numTerms() - 1
+
+		if 2*numErrors > numECBytes {
+			return nil, 0, ErrTooManyErrors
+		}
+
+		positions := rsErrorPositions(locator, numBytes)
+		if len(positions) != numErrors {
+			return nil, 0, ErrTooManyErrors
+		}
+
+		for _, p := range positions {
+			codeword.term[p] = gfAdd(codeword.term[p], rsErrorValue(syndromes, locator, p))
+		}
+
+		if _, ok := rsSyndromes(codeword, numECBytes); !ok {
+			return nil, 0, ErrTooManyErrors
+		}
+	}
+
+	data = bitset.New()
+	for i := numBytes - 1; i >= numECBytes; i-- {
+		data.AppendByte(byte(codeword.term[i]), 8)
+	}
+
+	return data, numErrors, nil
+}
+
+// rsSyndromes returns the syndromes S_j = codeword(a^j) for j = 0 to
+// numECBytes-1, and whether they are all zero (i.e. no errors were detected).
+func rsSyndromes(codeword gfPoly, numECBytes int) (gfPoly, bool) {
+	syndromes := gfPoly{term: make([]gfElement, numECBytes)}
+
+	ok := true
+	for j := 0; j < numECBytes; j++ {
+		syndromes.term[j] = codeword.evaluate(gfExpTable[j])
+		if syndromes.term[j] != gfZero {
+			ok = false
+		}
+	}
+
+	return syndromes, ok
+}
+
+// rsErrorLocator returns the error locator polynomial, found using the
+// Berlekamp-Massey algorithm. Its degree is the number of errors.
+func rsErrorLocator(syndromes gfPoly) gfPoly {
+	locator := gfPoly{term: []gfElement{gfOne}}
+	previous := gfPoly{term: []gfElement{gfOne}}
+	previousDiscrepancy := gfOne
+
+	numErrors := 0
+	shift := 1
+
+	for n := 0; n < syndromes.numTerms(); n++ {
+		discrepancy := syndromes.term[n]
+		for i := 1; i <= numErrors && i < locator.numTerms(); i++ {
+			discrepancy = gfAdd(discrepancy, gfMultiply(locator.term[i], syndromes.term[n-i]))
+		}
+
+		if discrepancy == gfZero {
+			shift++
+			continue
+		}
+
+		correction := gfPolyMultiply(previous,
+			newGFPolyMonomial(gfDivide(discrepancy, previousDiscrepancy), shift))
+		next := gfPolyAdd(locator, correction)
+
+		if 2*numErrors <= n {
+			previous = locator
+			previousDiscrepancy = discrepancy
+			numErrors = n + 1 - numErrors
+			shift = 1
+		} else {
+			shift++
+		}
+
+		locator = next
+	}
+
+	return locator
+}
+
+// rsErrorPositions returns the indexes of the codeword terms in error: the
+// powers p where locator(a^-p) is zero (the Chien search).
+func rsErrorPositions(locator gfPoly, numBytes int) []int {
+	var positions []int
+
+	for p := 0; p < numBytes; p++ {
+		if locator.evaluate(gfInverse(gfExpTable[p])) == gfZero {
+			positions = append(positions, p)
+		}
+	}
+
+	return positions
+}
+
+// rsErrorValue returns the value of the error at codeword term p, using
+// Forney's algorithm.
+func rsErrorValue(syndromes, locator gfPoly, p int) gfElement {
+	// The error evaluator polynomial, syndromes * locator mod x^numECBytes.
+	evaluator := gfPolyMultiply(syndromes, locator)
+	if evaluator.numTerms() > syndromes.numTerms() {
+		evaluator.term = evaluator.term[:syndromes.numTerms()]
+	}
+
+	// The formal derivative of the locator. In GF(2^8) the even powers vanish.
+	derivative := gfPoly{term: make([]gfElement, max(locator.numTerms()-1, 0))}
+	for i := 1; i < locator.numTerms(); i += 2 {
+		derivative.term[i-1] = locator.term[i]
+	}
+
+	x := gfExpTable[p]
+	xInverse := gfInverse(x)
+
+	return gfMultiply(x, gfDivide(evaluator.evaluate(xInverse), derivative.evaluate(xInverse)))
+}
+
+// evaluate returns the value of e at x.
+func (e gfPoly) evaluate(x gfElement) gfElement {
+	result := gfZero
+	for i := e.numTerms() - 1; i >= 0; i-- {
+		result = gfAdd(gfMultiply(result, x), e.term[i])
+	}
+
+	return result
+}
+
+// max returns the larger of a and b.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,106 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+package reedsolomon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/yougg/go-qrcode/bitset"
+)
+
+func TestDecode(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, test := range []struct {
+		numDataBytes int
+		numECBytes   int
+	}{
+		{19, 7},
+		{16, 10},
+		{13, 13},
+		{9, 17},
+		{115, 30},
+		{1, 2},
+	} {
+		for numErrors := 0; numErrors <= test.numECBytes/2; numErrors++ {
+			data := bitset.New()
+			for i := 0; i < test.numDataBytes; i++ {
+				data.AppendByte(byte(rng.Intn(256)), 8)
+			}
+
+			block := Encode(data, test.numECBytes).Bytes()
+
+			// Corrupt numErrors distinct bytes, data or error correction.
+			for _, i := range rng.Perm(len(block))[:numErrors] {
+				block[i] ^= byte(1 + rng.Intn(255))
+			}
+
+			corrupted := bitset.New()
+			corrupted.AppendBytes(block)
+
+			result, corrected, err := Decode(corrupted, test.numECBytes)
+			if err != nil {
+				t.Fatalf("%d+%d bytes with %d errors: %s", test.numDataBytes, test.numECBytes,
+					numErrors, err.Error())
+			}
+
+			if corrected != numErrors {
+				t.Errorf("%d+%d bytes: corrected %d errors, expected %d", test.numDataBytes,
+					test.numECBytes, corrected, numErrors)
+			}
+
+			if !result.Equals(data) {
+				t.Errorf("%d+%d bytes with %d errors: got %s, expected %s", test.numDataBytes,
+					test.numECBytes, numErrors, result.String(), data.String())
+			}
+		}
+	}
+}
+
+func TestDecodeTooManyErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	failures := 0
+	for n := 0; n < 100; n++ {
+		data := bitset.New()
+		for i := 0; i < 19; i++ {
+			data.AppendByte(byte(rng.Intn(256)), 8)
+		}
+
+		block := Encode(data, 7).Bytes()
+		for _, i := range rng.Perm(len(block))[:6] {
+			block[i] ^= byte(1 + rng.Intn(255))
+		}
+
+		corrupted := bitset.New()
+		corrupted.AppendBytes(block)
+
+		result, _, err := Decode(corrupted, 7)
+		switch {
+		case err == ErrTooManyErrors:
+			failures++
+		case err != nil:
+			t.Fatal(err.Error())
+		case result.Equals(data):
+			t.Fatal("6 errors corrected with 7 error correction bytes")
+		}
+	}
+
+	// Six errors are usually detected, but may be miscorrected to another
+	// valid codeword.
+	if failures < 90 {
+		t.Errorf("detected %d of 100 uncorrectable blocks, expected at least 90", failures)
+	}
+}
+
+func TestDecodeInvalidBlock(t *testing.T) {
+	if _, _, err := Decode(bitset.New(true), 2); err == nil {
+		t.Error("partial byte block decoded, expected error")
+	}
+
+	if _, _, err := Decode(bitset.New(), 2); err == nil {
+		t.Error("block shorter than numECBytes decoded, expected error")
+	}
+}
@@ -4,6 +4,8 @@
 package qrcode
 
 import (
+	"fmt"
+
 	"github.com/yougg/go-qrcode/bitset"
 )
 
@@ -105,6 +107,15 @@ var (
 )
 
 func buildRegularSymbol(version qrCodeVersion, mask int, data *bitset.Bitset, margin int) (*symbol, error) {
+	switch {
+	case version.level < Low || version.level > Highest:
+		return nil, fmt.Errorf("invalid recovery level %d", version.level)
+	case mask < 0 || mask > 7:
+		return nil, fmt.Errorf("invalid mask pattern %d (expected 0-7 inclusive)", mask)
+	case margin < 0:
+		return nil, fmt.Errorf("invalid margin %d (must not be negative)", margin)
+	}
+
 	m := &regularSymbol{
 		version: version,
 		mask:    mask,
@@ -125,6 +136,10 @@ func buildRegularSymbol(version qrCodeVersion, mask int, data *bitset.Bitset, ma
 		return nil, err
 	}
 
+	if n := m.symbol.numEmptyModules(); n != 0 {
+		return nil, fmt.Errorf("%d modules left empty (version=%d)", n, version.version)
+	}
+
 	return m.symbol, nil
 }
 
@@ -174,57 +189,91 @@ func (m *regularSymbol) addTimingPatterns() {
 }
 
 func (m *regularSymbol) addFormatInfo() {
+	setFormatInfo(m.symbol, m.size, m.version.formatInfo(m.mask))
+}
+
+// setFormatInfo writes the 15-bit Format Information f into both copies'
+// modules of s, a symbol size modules wide (excluding the quiet zone).
+func setFormatInfo(s *symbol, size int, f *bitset.Bitset) {
 	fpSize := finderPatternSize
 	l := formatInfoLengthBits - 1
 
-	f := m.version.formatInfo(m.mask)
-
 	// Bits 0-7, under the top right finder pattern.
 	for i := 0; i <= 7; i++ {
-		m.symbol.set(m.size-i-1, fpSize+1, f.At(l-i))
+		s.set(size-i-1, fpSize+1, f.At(l-i))
 	}
 
 	// Bits 0-5, right of the top left finder pattern.
 	for i := 0; i <= 5; i++ {
-		m.symbol.set(fpSize+1, i, f.At(l-i))
+		s.set(fpSize+1, i, f.At(l-i))
 	}
 
 	// Bits 6-8 on the corner of the top left finder pattern.
-	m.symbol.set(fpSize+1, fpSize, f.At(l-6))
-	m.symbol.set(fpSize+1, fpSize+1, f.At(l-7))
-	m.symbol.set(fpSize, fpSize+1, f.At(l-8))
+	s.set(fpSize+1, fpSize, f.At(l-6))
+	s.set(fpSize+1, fpSize+1, f.At(l-7))
+	s.set(fpSize, fpSize+1, f.At(l-8))
 
 	// Bits 9-14 on the underside of the top left finder pattern.
 	for i := 9; i <= 14; i++ {
-		m.symbol.set(14-i, fpSize+1, f.At(l-i))
+		s.set(14-i, fpSize+1, f.At(l-i))
 	}
 
 	// Bits 8-14 on the right side of the bottom left finder pattern.
 	for i := 8; i <= 14; i++ {
-		m.symbol.set(fpSize+1, m.size-fpSize+i-8, f.At(l-i))
+		s.set(fpSize+1, size-fpSize+i-8, f.At(l-i))
 	}
 
 	// Always dark symbol.
-	m.symbol.set(fpSize+1, m.size-fpSize-1, true)
+	s.set(fpSize+1, size-fpSize-1, true)
 }
 
 func (m *regularSymbol) addVersionInfo() {
-	fpSize := finderPatternSize
-
 	v := m.version.versionInfo()
-	l := versionInfoLengthBits - 1
-
 	if v == nil {
 		return
 	}
 
+	setVersionInfo(m.symbol, m.size, v)
+}
+
+// setVersionInfo writes the 18-bit Version Information v into both copies'
+// modules of s, a symbol size modules wide (excluding the quiet zone).
+func setVersionInfo(s *symbol, size int, v *bitset.Bitset) {
+	fpSize := finderPatternSize
+	l := versionInfoLengthBits - 1
+
 	for i := 0; i < v.Len(); i++ {
 		// Above the bottom left finder pattern.
-		m.symbol.set(i/3, m.size-fpSize-4+i%3, v.At(l-i))
+		s.set(i/3, size-fpSize-4+i%3, v.At(l-i))
 
 		// Left of the top right finder pattern.
-		m.symbol.set(m.size-fpSize-4+i%3, i/3, v.At(l-i))
+		s.set(size-fpSize-4+i%3, i/3, v.At(l-i))
+	}
+}
+
+// dataMask returns true if the data module at (x, y) is inverted by data mask
+// pattern mask.
+func dataMask(mask int, x int, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+((y*x)%3))%2 == 0
+	case 7:
+		return ((y+x)%2+((y*x)%3))%2 == 0
 	}
+
+	return false
 }
 
 type direction uint8
@@ -242,25 +291,7 @@ func (m *regularSymbol) addData() (bool, error) {
 	y := m.size - 1
 
 	for i := 0; i < m.data.Len(); i++ {
-		var mask bool
-		switch m.mask {
-		case 0:
-			mask = (y+x+xOffset)%2 == 0
-		case 1:
-			mask = y%2 == 0
-		case 2:
-			mask = (x+xOffset)%3 == 0
-		case 3:
-			mask = (y+x+xOffset)%3 == 0
-		case 4:
-			mask = (y/2+(x+xOffset)/3)%2 == 0
-		case 5:
-			mask = (y*(x+xOffset))%2+(y*(x+xOffset))%3 == 0
-		case 6:
-			mask = ((y*(x+xOffset))%2+((y*(x+xOffset))%3))%2 == 0
-		case 7:
-			mask = ((y+x+xOffset)%2+((y*(x+xOffset))%3))%2 == 0
-		}
+		mask := dataMask(m.mask, x+xOffset, y)
 
 		// != is equivalent to XOR.
 		m.symbol.set(x+xOffset, y, mask != m.data.At(i))
@@ -298,6 +329,10 @@ func (m *regularSymbol) addData() (bool, error) {
 				x--
 			}
 
+			if x < 0 {
+				return false, fmt.Errorf("data is %d bits, exceeds symbol capacity (version=%d)", m.data.Len(), m.version.version)
+			}
+
 			if m.symbol.empty(x+xOffset, y) {
 				break
 			}
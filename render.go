@@ -0,0 +1,359 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Encode a QR Code and return a raw PNG image.
+//
+// width and height are the image size in pixels, and margin the quiet zone in
+// modules. If the size is too small then a larger image is silently returned.
+// Negative sizes give each module a fixed number of pixels: See the
+// documentation for Image().
+//
+// To serve over HTTP, remember to send a Content-Type: image/png header.
+func Encode(content string, level RecoveryLevel, width, height, margin int) ([]byte, error) {
+	return EncodeWithOptions(content, Level(level), Width(width), Height(height), Margin(margin))
+}
+
+// EncodeWithOptions encodes a QR Code with the given options and returns a raw
+// PNG image.
+func EncodeWithOptions(content string, opts ...Option) ([]byte, error) {
+	q, err := New(content, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PNG()
+}
+
+// EncodeEncrypted encrypts content with key, see Encrypt, then encodes it as
+// a QR Code with the given options and returns a raw PNG image. Use Decrypt
+// to read the scanned content.
+func EncodeEncrypted(content string, key []byte, opts ...Option) ([]byte, error) {
+	q, err := NewEncrypted(content, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PNG()
+}
+
+// WriteFile encodes, then writes a QR Code to the given filename in PNG format.
+//
+// size is both the image width and height in pixels, and margin the quiet zone
+// in modules. If size is too small then a larger image is silently written.
+// Negative values for size cause a variable sized image to be written: See the
+// documentation for Image().
+func WriteFile(content string, level RecoveryLevel, size int, filename string, margin int) error {
+	return WriteFileWithOptions(content, filename, Level(level), Width(size), Height(size), Margin(margin))
+}
+
+// WriteColorFile encodes, then writes a QR Code to the given filename in PNG format.
+// With WriteColorFile you can also specify the background and foreground colors
+// you want to use.
+//
+// size is both the image width and height in pixels, and margin the quiet zone
+// in modules. If size is too small then a larger image is silently written.
+// Negative values for size cause a variable sized image to be written: See the
+// documentation for Image().
+func WriteColorFile(content string, level RecoveryLevel, size int, background, foreground color.Color, filename string, margin int) error {
+	return WriteFileWithOptions(content, filename,
+		Level(level),
+		Width(size),
+		Height(size),
+		Margin(margin),
+		BackgroundColor(background),
+		ForegroundColor(foreground))
+}
+
+// WriteFileWithOptions encodes a QR Code with the given options, then writes
+// it to the given filename in PNG format.
+func WriteFileWithOptions(content string, filename string, opts ...Option) error {
+	q, err := New(content, opts...)
+	if err != nil {
+		return err
+	}
+
+	return q.WriteFile(filename)
+}
+
+// WriteWithOptions encodes a QR Code with the given options, then writes it to
+// w in PNG format.
+func WriteWithOptions(content string, w io.Writer, opts ...Option) error {
+	q, err := New(content, opts...)
+	if err != nil {
+		return err
+	}
+
+	return q.Write(w)
+}
+
+// EncodeWithLogo encodes a QR Code with logo drawn over its center and returns
+// it as a PNG image.
+//
+// The logo keeps its own size and aspect ratio, and is only scaled down if it
+// is larger than logoMaxRatio of the QR Code. Transparent areas of the logo
+// let the QR Code show through. See AddLogo for more placement and styling
+// choices.
+func EncodeWithLogo(level RecoveryLevel, str string, logo image.Image, margin int) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	var opts = []Option{
+		Level(level),
+		Margin(margin),
+	}
+	code, err := New(str, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	img := code.AddLogo(logo)
+
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// Image returns the QR Code as an image.Image.
+//
+// The image size is set by the Width and Height options. A positive size sets
+// a fixed image width and height (e.g. 256 yields an 256x256px image).
+//
+// Depending on the amount of data encoded, fixed size images can have different
+// amounts of padding (white space around the QR Code). As an alternative, a
+// variable sized image can be generated instead:
+//
+// A negative size causes a variable sized image to be returned. The image
+// returned is the minimum size required for the QR Code. Choose a larger
+// negative number to increase the scale of the image. e.g. a size of -5 causes
+// each module (QR Code "pixel") to be 5px in size. Different negative widths
+// and heights give non-square modules, see ModulePixelSize.
+//
+// If a Caption is set, the image is taller than the requested height.
+//
+// A size smaller than the QR Code is enlarged to one pixel per module, unless
+// Downscale is set.
+//
+// Use RenderImage to draw the same QR Code at other sizes or colors.
+func (q *QRCode) Image() image.Image {
+	if width, height, ok := q.thumbnailSize(); ok {
+		return q.thumbnail(width, height)
+	}
+
+	pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY, width, height := q.layout()
+
+	// Deeper edges of QuietZone enlarge the image.
+	top, right, bottom, left := q.quietZoneExtra()
+	offsetX += left * pixelsPerModuleX
+	offsetY += top * pixelsPerModuleY
+	width += (left + right) * pixelsPerModuleX
+	height += (top + bottom) * pixelsPerModuleY
+
+	rect := image.Rectangle{Min: image.Point{0, 0}, Max: image.Point{X: width, Y: height}}
+
+	img := image.NewPaletted(rect, q.palette())
+
+	for i := 0; i < width; i++ {
+		for j := 0; j < height; j++ {
+			img.Set(i, j, q.BackgroundColor)
+		}
+	}
+
+	bitmap := q.symbol.bitmap()
+	for y, row := range bitmap {
+		for x, v := range row {
+			if v && !q.isEye(x, y) {
+				startX := x*pixelsPerModuleX + offsetX
+				startY := y*pixelsPerModuleY + offsetY
+				q.drawModule(img, image.Rect(startX, startY, startX+pixelsPerModuleX, startY+pixelsPerModuleY))
+			}
+		}
+	}
+	q.drawEyes(img, pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY)
+
+	q.drawQuietZone(img)
+	q.drawStamp(img)
+	q.drawOrientationMarker(img, pixelsPerModuleX, pixelsPerModuleY)
+
+	if text := q.captionText(); text != "" {
+		img = q.addCaption(img, text)
+	}
+
+	if q.transform != nil {
+		img = q.applyTransform(img)
+	}
+
+	return img
+}
+
+// layout resolves the image size as described for Image(), and returns the
+// size of each module, the position of the symbol within the image, and the
+// image size. q is not modified, so images can be drawn concurrently.
+func (q *QRCode) layout() (pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY, width, height int) {
+	// Minimum pixels (both width and height) required.
+	realSize := q.symbol.size
+	width, height = q.width, q.height
+
+	// Exact module size support.
+	if q.scale > 0 {
+		width = q.scale * realSize
+		height = q.scale * realSize
+	}
+
+	// Variable size support.
+	if width < 0 {
+		width = width * -1 * realSize
+	}
+	if height < 0 {
+		height = height * -1 * realSize
+	}
+
+	// Actual pixels available to draw the symbol. Automatically increase the
+	// image size if it's not large enough.
+	if width < realSize {
+		width = realSize
+	}
+	if height < realSize {
+		height = realSize
+	}
+
+	// Size of each module drawn.
+	pixelsPerModuleX = width / realSize
+	pixelsPerModuleY = height / realSize
+
+	// Grow the image to keep modules legible, see MinModulePixels.
+	if pixelsPerModuleX < q.minModulePixels {
+		pixelsPerModuleX = q.minModulePixels
+		width = realSize * pixelsPerModuleX
+	}
+	if pixelsPerModuleY < q.minModulePixels {
+		pixelsPerModuleY = q.minModulePixels
+		height = realSize * pixelsPerModuleY
+	}
+
+	// Shrink the image to a whole number of modules, see SnapToModule and
+	// NoQuietZone.
+	if q.snapToModule || q.noQuietZone {
+		width = realSize * pixelsPerModuleX
+		height = realSize * pixelsPerModuleY
+	}
+
+	// Center the symbol within the image. Any remaining pixels widen the
+	// quiet zone.
+	offsetX = (width - realSize*pixelsPerModuleX) / 2
+	offsetY = (height - realSize*pixelsPerModuleY) / 2
+
+	return
+}
+
+// PNG returns the QR Code as a PNG image. The image is tagged as sRGB, unless
+// an ICCProfile is given.
+//
+// The image is sized as set by the Width, Height and Scale options: See the
+// documentation for Image().
+func (q *QRCode) PNG() ([]byte, error) {
+	var b bytes.Buffer
+	if err := q.EncodePNG(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// EncodePNG writes the QR Code as a PNG image to w, as PNG() does. The image
+// is streamed to w as it is compressed, rather than built in memory first.
+func (q *QRCode) EncodePNG(w io.Writer) error {
+	start := time.Now()
+
+	n, err := q.encodePNGImage(w, q.Image())
+	if err != nil {
+		return err
+	}
+
+	if q.metrics != nil {
+		q.metrics.Rendered("png", time.Since(start), n)
+	}
+
+	return nil
+}
+
+// encodePNGImage writes img to w as a PNG image with q's color profile and
+// metadata chunks, returning the number of bytes written.
+func (q *QRCode) encodePNGImage(w io.Writer, img image.Image) (int, error) {
+	chunk, err := q.colorProfileChunk()
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &colorProfileWriter{w: w, chunk: append(chunk, q.metadataChunks()...)}
+	if q.reproducible {
+		err = encodeReproduciblePNG(cw, img)
+	} else {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(cw, img)
+	}
+
+	return cw.n, err
+}
+
+// Write writes the QR Code as a PNG image to io.Writer, as PNG() returns it.
+func (q *QRCode) Write(out io.Writer) error {
+	return q.EncodePNG(out)
+}
+
+// WriteFile writes the QR Code as a PNG image to the specified file, as PNG()
+// returns it. The image is written to a temporary file in the same directory,
+// which replaces the file once it is complete, so an encoding or write error
+// leaves any existing file unchanged.
+func (q *QRCode) WriteFile(filename string) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+
+	if err := q.writeTempFile(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
+
+// writeTempFile writes the QR Code as a PNG image to f, and closes it.
+func (q *QRCode) writeTempFile(f *os.File) error {
+	w := bufio.NewWriter(f)
+	if err := q.EncodePNG(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	// CreateTemp makes the file private; use the usual permissions.
+	if err := f.Chmod(0644); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,190 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/yougg/go-qrcode/styles"
+	"golang.org/x/image/math/f64"
+)
+
+// RenderConfig is the drawing settings of a QRCode, separate from its encoded
+// symbol. Read it with QRCode.RenderConfig, change it freely, then draw with
+// it using QRCode.WithRenderConfig.
+type RenderConfig struct {
+	// Colors of the dark and light modules.
+	ForegroundColor, BackgroundColor color.Color
+
+	// Image size in pixels, and pixels per module, as set by the Width,
+	// Height and Scale options.
+	Width, Height, Scale int
+
+	// Image size adjustments, see SnapToModule and MinModulePixels.
+	SnapToModule    bool
+	MinModulePixels int
+
+	// Thumbnail drawing of images too small for the symbol, see Downscale.
+	Downscale          bool
+	DownscaleThreshold float64
+
+	// Shapes of the data modules and finder patterns.
+	ModuleShape, EyeShape styles.Shape
+
+	// Quiet zone of each edge in modules: top, right, bottom, left, see
+	// QuietZone. The narrowest edge is part of the encoded symbol, and can't
+	// be changed.
+	QuietZone [4]int
+
+	// Quiet zone styling, see QuietZoneColor, QuietZoneRadius and
+	// QuietZoneOutline. A nil color is not drawn.
+	QuietZoneColor  color.Color
+	QuietZoneRadius int
+	OutlineWidth    int
+	OutlineColor    color.Color
+
+	// Text printed under the symbol, see Caption and CaptionContent.
+	Caption        string
+	CaptionContent bool
+
+	// Label printed in the quiet zone, see Stamp and StampSequence.
+	Stamp         string
+	StampSequence bool
+
+	// Edge of the quiet zone with an arrow, see OrientationMarker.
+	OrientationMarker Edge
+
+	// Affine transform of images, see Transform. Nil is none.
+	Transform *f64.Aff3
+
+	// Nearest neighbor scaling of logos and backgrounds, see NoSmoothing.
+	NoSmoothing bool
+
+	// Color profile of PNG images, see ICCProfile. Without ICCProfileSet,
+	// images are tagged as sRGB.
+	ICCProfileSet  bool
+	ICCProfileName string
+	ICCProfile     []byte
+
+	// PNG metadata chunks and encoding, see PNGMetadata and Reproducible.
+	PNGMetadata  bool
+	Reproducible bool
+
+	// Palette of paletted images, see Palette. Nil is the default.
+	Palette color.Palette
+}
+
+// RenderConfig returns the drawing settings of q.
+func (q *QRCode) RenderConfig() RenderConfig {
+	c := RenderConfig{
+		ForegroundColor: q.ForegroundColor,
+		BackgroundColor: q.BackgroundColor,
+		Width:           q.width,
+		Height:          q.height,
+		Scale:           q.scale,
+		SnapToModule:    q.snapToModule,
+		MinModulePixels: q.minModulePixels,
+		ModuleShape:     q.moduleShape,
+		EyeShape:        q.eyeShape,
+		QuietZoneColor:  q.quietZoneColor,
+		QuietZoneRadius: q.quietZoneRadius,
+		OutlineWidth:    q.outlineWidth,
+		OutlineColor:    q.outlineColor,
+		Caption:         q.caption,
+		CaptionContent:  q.captionContent,
+		Palette:         append(color.Palette(nil), q.customPalette...),
+
+		Downscale:          q.downscale,
+		DownscaleThreshold: q.downscaleThreshold,
+		Stamp:              q.stamp,
+		StampSequence:      q.stampSequence,
+		OrientationMarker:  q.orientationMarker,
+		NoSmoothing:        q.noSmoothing,
+		ICCProfileSet:      q.iccProfileSet,
+		ICCProfileName:     q.iccProfileName,
+		ICCProfile:         append([]byte(nil), q.iccProfile...),
+		PNGMetadata:        q.pngMetadata,
+		Reproducible:       q.reproducible,
+	}
+
+	if q.quietZoneEdges != nil {
+		c.QuietZone = *q.quietZoneEdges
+	} else {
+		m := q.quietZone()
+		c.QuietZone = [4]int{m, m, m, m}
+	}
+	if q.transform != nil {
+		t := *q.transform
+		c.Transform = &t
+	}
+
+	return c
+}
+
+// WithRenderConfig returns a copy of q drawn with c. The encoded symbol is
+// shared, and q is not modified.
+func (q *QRCode) WithRenderConfig(c RenderConfig) (*QRCode, error) {
+	r := *q
+	r.ForegroundColor, r.BackgroundColor = c.ForegroundColor, c.BackgroundColor
+	r.width, r.height, r.scale = c.Width, c.Height, c.Scale
+	r.moduleShape, r.eyeShape = c.ModuleShape, c.EyeShape
+	r.quietZoneColor, r.quietZoneRadius = c.QuietZoneColor, c.QuietZoneRadius
+	r.outlineWidth, r.outlineColor = c.OutlineWidth, c.OutlineColor
+	r.caption, r.captionContent = c.Caption, c.CaptionContent
+	r.snapToModule, r.minModulePixels = c.SnapToModule, c.MinModulePixels
+	r.downscale, r.downscaleThreshold = c.Downscale, c.DownscaleThreshold
+	r.stamp, r.stampSequence = c.Stamp, c.StampSequence
+	r.orientationMarker = c.OrientationMarker
+	r.noSmoothing = c.NoSmoothing
+	r.iccProfileSet, r.iccProfileName = c.ICCProfileSet, c.ICCProfileName
+	r.iccProfile = nil
+	if c.ICCProfile != nil {
+		r.iccProfile = append([]byte{}, c.ICCProfile...)
+	}
+	r.pngMetadata, r.reproducible = c.PNGMetadata, c.Reproducible
+	r.transform = nil
+	if c.Transform != nil {
+		t := *c.Transform
+		r.transform = &t
+	}
+	r.customPalette = nil
+	if c.Palette != nil {
+		r.customPalette = append(color.Palette{}, c.Palette...)
+	}
+
+	e := c.QuietZone
+	r.quietZoneEdges = nil
+	if e[0] != e[1] || e[1] != e[2] || e[2] != e[3] {
+		r.quietZoneEdges = &e
+	}
+
+	if r.ForegroundColor == nil || r.BackgroundColor == nil {
+		return nil, errors.New("render config: foreground and background colors must be set")
+	}
+	if m := min(min(e[0], e[1]), min(e[2], e[3])); m != q.quietZone() && !q.noQuietZone {
+		return nil, fmt.Errorf("render config: narrowest quiet zone edge is %d modules, expected %d (use New)", m, q.quietZone())
+	}
+	if err := r.checkPalette(); err != nil {
+		return nil, err
+	}
+	if err := r.checkConflicts(); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// With returns a copy of q with opts applied, sharing the encoded symbol; q is
+// not modified. Options that would change the symbol, such as Level, Margin
+// or PadCodewords, are an error: encode a new QRCode with New instead.
+func (q *QRCode) With(opts ...Option) (*QRCode, error) {
+	r := *q
+	if err := r.Set(opts...); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,205 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/yougg/go-qrcode/styles"
+	"golang.org/x/image/math/f64"
+)
+
+func TestWith(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(2), PadCodewords(0xec, 0x11))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitmap := q.Bitmap()
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	r, err := q.With(ForegroundColor(red), Scale(3), ModuleShape(styles.Circle), Caption("hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ForegroundColor != red || r.scale != 3 || r.moduleShape != styles.Circle {
+		t.Error("With did not apply the options")
+	}
+	if q.ForegroundColor == red || q.scale == 3 || q.caption != "" {
+		t.Error("With modified the original QR Code")
+	}
+	if !sameBitmap(r.Bitmap(), bitmap) {
+		t.Error("With changed the symbol")
+	}
+
+	// Options leaving the symbol settings as they are are allowed.
+	if _, err := q.With(Margin(2), Level(Medium)); err != nil {
+		t.Errorf("With(Margin(2), Level(Medium)) = %v, expected no error", err)
+	}
+
+	for name, opt := range map[string]Option{
+		"Level":           Level(Highest),
+		"Margin":          Margin(4),
+		"NoQuietZone":     NoQuietZone(),
+		"MinVersion":      MinVersion(10),
+		"PadCodewords":    PadCodewords(1),
+		"Normalize":       Normalize(URLTrim),
+		"Style":           Style(styles.Style{QuietZone: 4}),
+		"Preprocess":      Preprocess(UppercaseURL),
+		"CompressPayload": CompressPayload(),
+	} {
+		if _, err := q.With(opt); err == nil {
+			t.Errorf("With(%s) succeeded, expected an error", name)
+		}
+	}
+
+	if _, err := q.With(Palette(color.Palette{color.White, red})); err == nil {
+		t.Error("With an invalid Palette succeeded, expected an error")
+	}
+}
+
+func TestRenderConfig(t *testing.T) {
+	q, err := New("https://example.org", Scale(2), QuietZoneOutline(1, color.Black), CaptionContent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := q.RenderConfig()
+	if c.Scale != 2 || c.OutlineWidth != 1 || !c.CaptionContent {
+		t.Fatalf("got %+v", c)
+	}
+
+	// Round trip.
+	same, err := q.WithRenderConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameImage(same.Image(), q.Image()) {
+		t.Error("WithRenderConfig(RenderConfig()) draws a different image")
+	}
+
+	c.Scale, c.CaptionContent, c.OutlineWidth = 5, false, 0
+	c.BackgroundColor = color.RGBA{0xee, 0xee, 0xee, 0xff}
+	r, err := q.WithRenderConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := r.Image().Bounds(), image.Rect(0, 0, 5*q.symbol.size, 5*q.symbol.size); got != expected {
+		t.Errorf("got bounds %v, expected %v", got, expected)
+	}
+	if q.RenderConfig().Scale != 2 {
+		t.Error("WithRenderConfig modified the original QR Code")
+	}
+
+	c.ForegroundColor = nil
+	if _, err := q.WithRenderConfig(c); err == nil {
+		t.Error("WithRenderConfig without a foreground color succeeded, expected an error")
+	}
+}
+
+func TestRenderConfigOptions(t *testing.T) {
+	aff := f64.Aff3{-1, 0, 0, 0, 1, 0}
+	q, err := New("https://example.org", Width(-4), QuietZone(6, 4, 4, 4), OrientationMarker(TopEdge),
+		Stamp("1/2"), Transform(aff), ICCProfile("none", nil), PNGMetadata(), Reproducible(),
+		NoSmoothing(), SnapToModule(), MinModulePixels(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := q.RenderConfig()
+	if c.QuietZone != [4]int{6, 4, 4, 4} || c.OrientationMarker != TopEdge || c.Stamp != "1/2" ||
+		c.Transform == nil || *c.Transform != aff || !c.ICCProfileSet || !c.PNGMetadata || !c.Reproducible ||
+		!c.NoSmoothing || !c.SnapToModule || c.MinModulePixels != 2 {
+		t.Fatalf("got %+v", c)
+	}
+
+	same, err := q.WithRenderConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameImage(same.Image(), q.Image()) || same.Fingerprint() != q.Fingerprint() {
+		t.Error("WithRenderConfig(RenderConfig()) draws a different image")
+	}
+
+	// The config does not share the transform with q.
+	c.Transform[0] = 1
+	if q.transform[0] != -1 {
+		t.Error("changing RenderConfig().Transform modified the QR Code")
+	}
+
+	c = q.RenderConfig()
+	c.QuietZone = [4]int{4, 4, 4, 4}
+	plain, err := q.WithRenderConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.quietZoneEdges != nil {
+		t.Error("equal quiet zone edges were kept")
+	}
+
+	c.QuietZone = [4]int{8, 8, 8, 8}
+	if _, err := q.WithRenderConfig(c); err == nil {
+		t.Error("WithRenderConfig changing the narrowest quiet zone edge succeeded, expected an error")
+	}
+}
+
+func TestSet(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := q.Fingerprint()
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	if err := q.Set(ForegroundColor(red), Level(High)); err == nil {
+		t.Error("Set(Level(High)) succeeded, expected an error")
+	}
+	if err := q.Set(ForegroundColor(red), Palette(color.Palette{color.White})); err == nil {
+		t.Error("Set with an invalid Palette succeeded, expected an error")
+	}
+	if q.Fingerprint() != fingerprint || q.level != Medium {
+		t.Error("a failed Set modified the QR Code")
+	}
+
+	if err := q.Set(ForegroundColor(red)); err != nil {
+		t.Fatal(err)
+	}
+	if q.Foreground() != red {
+		t.Error("Set did not apply the options")
+	}
+
+	// Version has no effect, so it is not a change to the symbol.
+	version := q.Version()
+	if err := q.Set(Version(version + 3)); err != nil {
+		t.Errorf("Set(Version(%d)) = %v, expected no error", version+3, err)
+	}
+	if q.Version() != version {
+		t.Errorf("got version %d after Set(Version(%d)), expected %d", q.Version(), version+3, version)
+	}
+
+	// The deprecated fields are the same settings as the getters.
+	q.ForegroundColor = color.White
+	if q.Foreground() != color.White || q.EncodedContent() != q.Content {
+		t.Error("the deprecated fields do not match the getters")
+	}
+}
+
+// sameBitmap returns true if a and b are equal.
+func sameBitmap(a, b [][]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for y := range a {
+		if len(a[y]) != len(b[y]) {
+			return false
+		}
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,400 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeWithOptions(t *testing.T) {
+	legacy, err := Encode("options", High, 120, 120, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []Option{Level(High), Width(120), Height(120), Margin(2)}
+
+	data, err := EncodeWithOptions("options", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, legacy) {
+		t.Error("EncodeWithOptions differs from Encode with the equivalent parameters")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWithOptions("options", &buf, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), legacy) {
+		t.Error("WriteWithOptions differs from Encode with the equivalent parameters")
+	}
+
+	filename := filepath.Join(t.TempDir(), "qr.png")
+	if err := WriteFileWithOptions("options", filename, opts...); err != nil {
+		t.Fatal(err)
+	}
+	written, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, legacy) {
+		t.Error("WriteFileWithOptions differs from Encode with the equivalent parameters")
+	}
+
+	if _, err := EncodeWithOptions("options", Margin(-1)); err == nil {
+		t.Error("EncodeWithOptions succeeded with a negative margin, expected error")
+	}
+}
+
+func TestWriteFileError(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "qr.png")
+	if err := os.WriteFile(filename, []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid ICC profile name fails while encoding.
+	q, err := New("options", ICCProfile("", []byte("profile")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.WriteFile(filename); err == nil {
+		t.Fatal("WriteFile succeeded with an invalid ICC profile, expected an error")
+	}
+
+	if data, err := os.ReadFile(filename); err != nil || string(data) != "existing" {
+		t.Errorf("got file %q %v after a failed WriteFile, expected it unchanged", data, err)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("got %d files after a failed WriteFile, expected the temporary file removed", len(entries))
+	}
+}
+
+func TestEncodeWithLogo(t *testing.T) {
+	// A logo which is opaque red on the left half and fully transparent on the
+	// right half.
+	logo := image.NewNRGBA(image.Rect(0, 0, 6, 4))
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 4; y++ {
+			logo.Set(x, y, color.NRGBA{0xff, 0, 0, 0xff})
+		}
+	}
+
+	buf, err := EncodeWithLogo(Highest, "https://example.org", logo, 4)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	offsetX := (img.Bounds().Dx() - 6) / 2
+	offsetY := (img.Bounds().Dy() - 4) / 2
+
+	for x := 0; x < 6; x++ {
+		for y := 0; y < 4; y++ {
+			c := color.RGBAModel.Convert(img.At(offsetX+x, offsetY+y)).(color.RGBA)
+
+			switch {
+			case x < 3 && c != (color.RGBA{0xff, 0, 0, 0xff}):
+				t.Errorf("logo pixel (%d, %d) is %v, expected red", x, y, c)
+			case x >= 3 && c != (color.RGBA{0, 0, 0, 0xff}) && c != (color.RGBA{0xff, 0xff, 0xff, 0xff}):
+				t.Errorf("transparent logo pixel (%d, %d) is %v, expected black or white", x, y, c)
+			}
+		}
+	}
+}
+
+func TestQRCodeScale(t *testing.T) {
+	for _, n := range []int{1, 2, 3} {
+		q, err := New("https://example.org", Width(1000), Height(1000), Scale(n), Margin(4))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		img := q.Image()
+		bitmap := q.Bitmap()
+
+		if img.Bounds().Dx() != len(bitmap)*n || img.Bounds().Dy() != len(bitmap)*n {
+			t.Fatalf("Scale(%d) image is %v, expected %dx%d", n, img.Bounds(), len(bitmap)*n, len(bitmap)*n)
+		}
+
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			for y := 0; y < img.Bounds().Dy(); y++ {
+				expected := color.RGBAModel.Convert(color.White)
+				if bitmap[y/n][x/n] {
+					expected = color.RGBAModel.Convert(color.Black)
+				}
+
+				if got := color.RGBAModel.Convert(img.At(x, y)); got != expected {
+					t.Fatalf("Scale(%d) pixel (%d, %d) is %v, expected %v", n, x, y, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestModulePixelSize(t *testing.T) {
+	for _, test := range []struct {
+		w, h int
+		opts []Option
+	}{
+		{2, 1, []Option{ModulePixelSize(2, 1)}},
+		{1, 3, []Option{ModulePixelSize(1, 3)}},
+		{4, 4, []Option{Scale(4), ModulePixelSize(4, 4)}},
+		{1, 1, []Option{ModulePixelSize(0, -2)}},
+		// Later options win.
+		{3, 3, []Option{ModulePixelSize(2, 1), Scale(3)}},
+		{5, 5, []Option{ModulePixelSize(2, 1), Width(-5), Height(-5)}},
+	} {
+		q, err := New("https://example.org", test.opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		img := q.Image()
+		bitmap := q.Bitmap()
+		if size := img.Bounds().Size(); size.X != len(bitmap)*test.w || size.Y != len(bitmap)*test.h {
+			t.Fatalf("%dx%d pixel modules: image is %v, expected %dx%d", test.w, test.h,
+				size, len(bitmap)*test.w, len(bitmap)*test.h)
+		}
+
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			for y := 0; y < img.Bounds().Dy(); y++ {
+				expected := color.RGBAModel.Convert(color.White)
+				if bitmap[y/test.h][x/test.w] {
+					expected = color.RGBAModel.Convert(color.Black)
+				}
+
+				if got := color.RGBAModel.Convert(img.At(x, y)); got != expected {
+					t.Fatalf("%dx%d pixel modules: pixel (%d, %d) is %v, expected %v",
+						test.w, test.h, x, y, got, expected)
+				}
+			}
+		}
+	}
+
+	// The deeper edges of QuietZone keep the pixel aspect.
+	q, err := New("hello", QuietZone(2, 1, 1, 1), ModulePixelSize(3, 1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if size := q.Image().Bounds().Size(); size.X != 23*3 || size.Y != 24 {
+		t.Errorf("got image size %v, expected 69x24", size)
+	}
+}
+
+func TestQRCodeExactSize(t *testing.T) {
+	tests := []struct {
+		opts     []Option
+		expected func(realSize int) int
+	}{
+		{
+			[]Option{Width(100), Height(100)},
+			func(int) int { return 100 },
+		},
+		{
+			[]Option{Width(100), Height(100), ExactSize()},
+			func(int) int { return 100 },
+		},
+		{
+			[]Option{Width(100), Height(100), SnapToModule()},
+			func(realSize int) int { return realSize * (100 / realSize) },
+		},
+	}
+
+	for i, test := range tests {
+		q, err := New("https://example.org", test.opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		expected := test.expected(len(q.Bitmap()))
+
+		img := q.Image()
+		if img.Bounds().Dx() != expected || img.Bounds().Dy() != expected {
+			t.Errorf("test %d: image is %v, expected %dx%d", i, img.Bounds(), expected, expected)
+		}
+
+		art := ImageGenerator(q, image.NewRGBA(image.Rect(0, 0, 10, 10)), 100)
+		if art.Bounds().Dx() != expected || art.Bounds().Dy() != expected {
+			t.Errorf("test %d: artistic image is %v, expected %dx%d", i, art.Bounds(), expected, expected)
+		}
+	}
+}
+
+func TestImageGeneratorNoSmoothing(t *testing.T) {
+	q, err := New("https://example.org", Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// 100px does not divide evenly into modules.
+	img := ImageGenerator(q, image.NewRGBA(image.Rect(0, 0, 10, 10)), 100)
+
+	// Finder patterns are drawn at full module size, so every pixel of the
+	// symbol's top left module is exactly black.
+	pixelsPerModule := 100 / len(q.Bitmap())
+	offset := (100-len(q.Bitmap())*pixelsPerModule)/2 + 4*pixelsPerModule
+
+	for x := offset; x < offset+pixelsPerModule; x++ {
+		for y := offset; y < offset+pixelsPerModule; y++ {
+			if got := color.RGBAModel.Convert(img.At(x, y)); got != color.RGBAModel.Convert(color.Black) {
+				t.Fatalf("pixel (%d, %d) is %v, expected black", x, y, got)
+			}
+		}
+	}
+}
+
+// oneByteWriter accepts a single byte per Write call.
+type oneByteWriter struct {
+	bytes.Buffer
+}
+
+func (w *oneByteWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return w.Buffer.Write(p[:1])
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestQRCodeEncodePNG(t *testing.T) {
+	q, err := New("https://example.org", Width(256), Height(256))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected, err := q.PNG()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var b bytes.Buffer
+	if err := q.EncodePNG(&b); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !bytes.Equal(b.Bytes(), expected) {
+		t.Error("EncodePNG output differs from PNG()")
+	}
+
+	if _, err := png.Decode(&b); err != nil {
+		t.Error(err.Error())
+	}
+
+	// Short writes are an error, as with io.Writer.
+	if err := q.EncodePNG(&oneByteWriter{}); err == nil {
+		t.Error("EncodePNG to a short writer succeeded, expected error")
+	}
+
+	if err := q.EncodePNG(failingWriter{}); err == nil {
+		t.Error("EncodePNG to a failing writer succeeded, expected error")
+	}
+}
+
+func TestNoSmoothing(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	// Hard edged artwork: red on the left, blue on the right.
+	art := image.NewRGBA(image.Rect(0, 0, 30, 30))
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			if x < 15 {
+				art.Set(x, y, red)
+			} else {
+				art.Set(x, y, blue)
+			}
+		}
+	}
+
+	allowed := map[color.RGBA]bool{
+		red:                      true,
+		blue:                     true,
+		{0, 0, 0, 0xff}:          true,
+		{0xff, 0xff, 0xff, 0xff}: true,
+	}
+
+	// countOther returns the number of pixels of img not in allowed.
+	countOther := func(img image.Image) int {
+		n := 0
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if !allowed[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)] {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	for _, noSmoothing := range []bool{false, true} {
+		opts := []Option{Level(Highest), Width(100), Height(100)}
+		if noSmoothing {
+			opts = append(opts, NoSmoothing())
+		}
+
+		q, err := New("https://example.org", opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		generated := countOther(ImageGenerator(q, art, 100))
+		logo := countOther(q.AddLogo(art))
+
+		switch {
+		case noSmoothing && (generated != 0 || logo != 0):
+			t.Errorf("NoSmoothing: %d and %d smoothed pixels, expected none", generated, logo)
+		case !noSmoothing && (generated == 0 || logo == 0):
+			t.Errorf("smoothing: %d and %d smoothed pixels, expected some", generated, logo)
+		}
+	}
+}
+
+func TestMinModulePixels(t *testing.T) {
+	q, err := New("min module pixels", Width(50), Height(50), MinModulePixels(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := q.symbol.size
+
+	if b := q.Image().Bounds(); b.Dx() != 3*n || b.Dy() != 3*n {
+		t.Errorf("got image size %v, expected %d", b.Size(), 3*n)
+	}
+
+	q, err = New("min module pixels", Width(500), Height(500), MinModulePixels(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := q.Image().Bounds(); b.Dx() != 500 {
+		t.Errorf("got image width %d, expected large enough image to be unchanged", b.Dx())
+	}
+
+	if _, err := New("min module pixels", Width(50), Height(50), MinModulePixels(3), Strict()); err == nil {
+		t.Error("New succeeded under Strict with small modules, expected error")
+	}
+	if _, err := New("min module pixels", Scale(3), MinModulePixels(3), Strict()); err != nil {
+		t.Errorf("New failed under Strict with large enough modules: %s", err)
+	}
+}
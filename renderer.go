@@ -0,0 +1,291 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Renderer writes a QR Code in an output format. Renderers are registered by
+// name with RegisterRenderer, and used by QRCode.Render and Theme.Write.
+type Renderer interface {
+	Render(sym SymbolView, opts RenderOptions) ([]byte, error)
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(sym SymbolView, opts RenderOptions) ([]byte, error)
+
+// Render calls f(sym, opts).
+func (f RendererFunc) Render(sym SymbolView, opts RenderOptions) ([]byte, error) {
+	return f(sym, opts)
+}
+
+// RenderOptions are the output settings passed to a Renderer. The zero value
+// renders with the QR Code's own settings.
+type RenderOptions struct {
+	// Colors of the dark and light modules, replacing the QR Code's
+	// ForegroundColor and BackgroundColor if not nil.
+	ForegroundColor, BackgroundColor color.Color
+
+	// Image width and height in pixels, replacing the QR Code's Width and
+	// Height if not zero; see Image() for negative sizes. A Scale, pixels per
+	// module, replaces both.
+	Width, Height, Scale int
+
+	// Ink printed by the tiff and pdf renderers. The zero value is BlackInk.
+	Ink Ink
+
+	// Printed size of pdf, dxf, stl and scad output in millimetres,
+	// including the quiet zone. Defaults to 30.
+	SizeMM float64
+
+	// Thickness of the base plate and height of the raised dark modules of
+	// stl and scad output, in millimetres. Defaults to 2 and 1. A negative
+	// BaseMM leaves out the base plate.
+	BaseMM, ReliefMM float64
+
+	// Size of a module in svg output, in pixels. 0 writes a scalable image
+	// with only a viewBox.
+	ModuleSize int
+
+	// Accessible name of svg output, written as its <title> and aria-label.
+	// Defaults to the Caption, or "QR Code".
+	Title string
+
+	// Leave the content out of the <desc> of svg output, e.g. for codes
+	// carrying secrets.
+	RedactContent bool
+}
+
+// SymbolView is a read-only view of an encoded QR Code, passed to Renderers.
+type SymbolView struct {
+	q *QRCode
+}
+
+// Size returns the width and height of the symbol in modules, including the
+// quiet zone.
+func (v SymbolView) Size() int {
+	return v.q.symbol.size
+}
+
+// QuietZoneSize returns the width of the quiet zone in modules.
+func (v SymbolView) QuietZoneSize() int {
+	return v.q.symbol.quietZoneSize
+}
+
+// QuietZoneExtra returns the quiet zone of each edge beyond QuietZoneSize,
+// set by QuietZone, which renderers add around the Size x Size modules.
+func (v SymbolView) QuietZoneExtra() (top, right, bottom, left int) {
+	return v.q.quietZoneExtra()
+}
+
+// Dark reports whether the module at (x, y) is dark. The quiet zone starts
+// at (0, 0). Modules outside the Size x Size symbol are light.
+func (v SymbolView) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= v.q.symbol.size || y >= v.q.symbol.size {
+		return false
+	}
+
+	return v.q.symbol.module[y][x]
+}
+
+// Bitmap returns a copy of the modules, see QRCode.Bitmap.
+func (v SymbolView) Bitmap() [][]bool {
+	return v.q.Bitmap()
+}
+
+// Content returns the encoded content.
+func (v SymbolView) Content() string {
+	return v.q.Content
+}
+
+// VersionNumber returns the QR Code version (1-40 inclusive).
+func (v SymbolView) VersionNumber() int {
+	return v.q.VersionNumber
+}
+
+// Level returns the error recovery level.
+func (v SymbolView) Level() RecoveryLevel {
+	return v.q.level
+}
+
+// ForegroundColor returns the color of the dark modules.
+func (v SymbolView) ForegroundColor() color.Color {
+	return v.q.ForegroundColor
+}
+
+// BackgroundColor returns the color of the light modules.
+func (v SymbolView) BackgroundColor() color.Color {
+	return v.q.BackgroundColor
+}
+
+// Image returns the QR Code as drawn by QRCode.Image, with all of its styling
+// options.
+func (v SymbolView) Image() image.Image {
+	return v.q.Image()
+}
+
+// renderers holds the registered Renderers by lower case name.
+var renderers = struct {
+	sync.RWMutex
+	m map[string]Renderer
+}{m: map[string]Renderer{}}
+
+// RegisterRenderer makes r available under name (case insensitive), replacing
+// any Renderer previously registered with that name. The png, svg, pdf, tiff,
+// ico, bmp, txt, heatmap, dxf, stl and scad renderers are built in.
+func RegisterRenderer(name string, r Renderer) {
+	renderers.Lock()
+	defer renderers.Unlock()
+
+	renderers.m[strings.ToLower(name)] = r
+}
+
+// LookupRenderer returns the Renderer registered under name.
+func LookupRenderer(name string) (Renderer, bool) {
+	renderers.RLock()
+	defer renderers.RUnlock()
+
+	r, ok := renderers.m[strings.ToLower(name)]
+	return r, ok
+}
+
+// RendererNames returns the names of the registered Renderers, sorted.
+func RendererNames() []string {
+	renderers.RLock()
+	defer renderers.RUnlock()
+
+	var names []string
+	for name := range renderers.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Render returns the QR Code in the named output format, e.g. "svg", using
+// the Renderer registered under that name.
+func (q *QRCode) Render(format string, opts RenderOptions) ([]byte, error) {
+	r, ok := LookupRenderer(format)
+	if !ok {
+		return nil, fmt.Errorf("no renderer for format %q", format)
+	}
+
+	return r.Render(SymbolView{q.withRenderOptions(opts)}, opts)
+}
+
+// RenderImage returns the QR Code as Image() does, with the colors and size
+// of opts. The symbol is not encoded again and q is not modified, so one
+// QRCode can be drawn at many sizes, concurrently.
+func (q *QRCode) RenderImage(opts RenderOptions) image.Image {
+	return q.withRenderOptions(opts).Image()
+}
+
+// withRenderOptions returns q, or a copy of q with the colors and size of
+// opts.
+func (q *QRCode) withRenderOptions(opts RenderOptions) *QRCode {
+	if opts.ForegroundColor == nil && opts.BackgroundColor == nil &&
+		opts.Width == 0 && opts.Height == 0 && opts.Scale == 0 {
+		return q
+	}
+
+	c := *q
+	if opts.ForegroundColor != nil {
+		c.ForegroundColor = opts.ForegroundColor
+	}
+	if opts.BackgroundColor != nil {
+		c.BackgroundColor = opts.BackgroundColor
+	}
+	if opts.Width != 0 || opts.Height != 0 {
+		c.scale = 0
+		if opts.Width != 0 {
+			c.width = opts.Width
+		}
+		if opts.Height != 0 {
+			c.height = opts.Height
+		}
+	}
+	if opts.Scale != 0 {
+		c.scale = opts.Scale
+	}
+
+	return &c
+}
+
+func init() {
+	RegisterRenderer("png", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.PNG()
+	}))
+	RegisterRenderer("svg", RendererFunc(renderSVG))
+	RegisterRenderer("pdf", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		size := opts.SizeMM
+		if size == 0 {
+			size = 30
+		}
+		return sym.q.PDF(opts.ink(), size)
+	}))
+	RegisterRenderer("tiff", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.TIFF(opts.ink())
+	}))
+	RegisterRenderer("ico", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.ICO()
+	}))
+	RegisterRenderer("bmp", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.BMP()
+	}))
+	RegisterRenderer("txt", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return []byte(sym.q.ToString(false)), nil
+	}))
+	RegisterRenderer("heatmap", RendererFunc(renderHeatmap))
+	RegisterRenderer("dxf", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		size := opts.SizeMM
+		if size == 0 {
+			size = 30
+		}
+		return sym.q.DXF(size)
+	}))
+	RegisterRenderer("stl", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.STL(opts.model())
+	}))
+	RegisterRenderer("scad", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return sym.q.OpenSCAD(opts.model())
+	}))
+}
+
+// ink returns opts.Ink, or BlackInk if it is not set.
+func (opts RenderOptions) ink() Ink {
+	if opts.Ink == (Ink{}) {
+		return BlackInk
+	}
+
+	return opts.Ink
+}
+
+// model returns opts.SizeMM, opts.BaseMM and opts.ReliefMM, or their
+// defaults if not set. A negative BaseMM gives no base plate.
+func (opts RenderOptions) model() (sizeMM, baseMM, reliefMM float64) {
+	sizeMM, baseMM, reliefMM = opts.SizeMM, opts.BaseMM, opts.ReliefMM
+	if sizeMM == 0 {
+		sizeMM = 30
+	}
+	if baseMM == 0 {
+		baseMM = 2
+	} else if baseMM < 0 {
+		baseMM = 0
+	}
+	if reliefMM == 0 {
+		reliefMM = 1
+	}
+
+	return
+}
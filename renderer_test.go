@@ -0,0 +1,284 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRendererNames(t *testing.T) {
+	expected := []string{"bmp", "dxf", "heatmap", "ico", "pdf", "png", "scad", "stl", "svg", "tiff", "txt"}
+	if got := RendererNames(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got renderers %v, expected %v", got, expected)
+	}
+}
+
+func TestRender(t *testing.T) {
+	q, err := New("render", Width(64), Height(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	png, err := q.Render("PNG", RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(png, expected) {
+		t.Error("png renderer differs from PNG()")
+	}
+
+	txt, err := q.Render("txt", RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(txt) != q.ToString(false) {
+		t.Error("txt renderer differs from ToString(false)")
+	}
+
+	pdf, err := q.Render("pdf", RenderOptions{SizeMM: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Error("pdf renderer did not return a PDF")
+	}
+
+	if _, err := q.Render("jpeg", RenderOptions{}); err == nil {
+		t.Error("Render succeeded with an unregistered format, expected error")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	q, err := New("svg", Margin(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	data, err := q.Render("svg", RenderOptions{ForegroundColor: red, ModuleSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.ForegroundColor != color.Black {
+		t.Error("Render changed the QR Code's foreground color")
+	}
+
+	var svg struct {
+		ViewBox string `xml:"viewBox,attr"`
+		Width   int    `xml:"width,attr"`
+		Rect    struct {
+			Fill string `xml:"fill,attr"`
+		} `xml:"rect"`
+		Path struct {
+			Fill string `xml:"fill,attr"`
+			D    string `xml:"d,attr"`
+		} `xml:"path"`
+	}
+	if err := xml.Unmarshal(data, &svg); err != nil {
+		t.Fatal(err)
+	}
+
+	n := len(q.Bitmap())
+	if svg.ViewBox != fmt.Sprintf("0 0 %d %d", n, n) || svg.Width != 3*n {
+		t.Errorf("got viewBox %q width %d", svg.ViewBox, svg.Width)
+	}
+	if svg.Rect.Fill != "#ffffff" || svg.Path.Fill != "#ff0000" {
+		t.Errorf("got fills %q and %q", svg.Rect.Fill, svg.Path.Fill)
+	}
+
+	// Replay the path to check it covers exactly the dark modules.
+	var dark int
+	for _, row := range q.Bitmap() {
+		for _, v := range row {
+			if v {
+				dark++
+			}
+		}
+	}
+
+	var covered int
+	for _, cmd := range strings.Split(strings.TrimSuffix(svg.Path.D, "z"), "z") {
+		var x, y, w, w2 int
+		if _, err := fmt.Sscanf(cmd, "M%d %dh%dv1h-%d", &x, &y, &w, &w2); err != nil {
+			t.Fatalf("path command %q: %s", cmd, err)
+		}
+		for i := x; i < x+w; i++ {
+			if !q.Bitmap()[y][i] {
+				t.Fatalf("path covers light module (%d, %d)", i, y)
+			}
+		}
+		covered += w
+	}
+	if covered != dark {
+		t.Errorf("path covers %d modules, expected %d", covered, dark)
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	RegisterRenderer("Version", RendererFunc(func(sym SymbolView, opts RenderOptions) ([]byte, error) {
+		return []byte(fmt.Sprintf("%d-%s %s", sym.VersionNumber(), sym.Level(), sym.Content())), nil
+	}))
+	defer func() {
+		renderers.Lock()
+		delete(renderers.m, "version")
+		renderers.Unlock()
+	}()
+
+	q, err := New("custom", Level(High))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := q.Render("version", RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1-Q custom" {
+		t.Errorf("got %q", data)
+	}
+
+	theme, err := LoadTheme(strings.NewReader("format: version\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := theme.Write(q, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "1-Q custom" {
+		t.Errorf("theme wrote %q", buf.String())
+	}
+}
+
+func TestRenderSVGAccessibility(t *testing.T) {
+	q, err := New("https://example.org/?a=1&b=<2>", Caption("Scan me"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type svg struct {
+		Role      string `xml:"role,attr"`
+		AriaLabel string `xml:"aria-label,attr"`
+		Title     string `xml:"title"`
+		Desc      string `xml:"desc"`
+	}
+
+	tests := []struct {
+		opts     RenderOptions
+		expected svg
+	}{
+		{
+			RenderOptions{},
+			svg{"img", "Scan me", "Scan me", "QR Code encoding: https://example.org/?a=1&b=<2>"},
+		},
+		{
+			RenderOptions{Title: "Payment \"link\"", RedactContent: true},
+			svg{"img", "Payment \"link\"", "Payment \"link\"", "QR Code (content redacted)"},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := q.Render("svg", test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got svg
+		if err := xml.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != test.expected {
+			t.Errorf("got %+v, expected %+v", got, test.expected)
+		}
+		if test.opts.RedactContent && bytes.Contains(data, []byte("example.org")) {
+			t.Error("redacted SVG contains the content")
+		}
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	q, err := New("https://example.org", Width(-2), Height(-2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := q.symbol.size
+	before := *q
+
+	tests := []struct {
+		opts     RenderOptions
+		expected int
+	}{
+		{RenderOptions{}, 2 * n},
+		{RenderOptions{Scale: 5}, 5 * n},
+		{RenderOptions{Width: 3 * n, Height: 3 * n}, 3 * n},
+		{RenderOptions{Width: -4, Height: -4}, 4 * n},
+		{RenderOptions{}, 2 * n},
+	}
+
+	done := make(chan bool)
+	for _, test := range tests {
+		test := test
+		go func() {
+			defer func() { done <- true }()
+
+			img := q.RenderImage(test.opts)
+			if got := img.Bounds().Dx(); got != test.expected {
+				t.Errorf("%+v: got width %d, expected %d", test.opts, got, test.expected)
+			}
+		}()
+	}
+	for range tests {
+		<-done
+	}
+
+	if q.width != before.width || q.height != before.height || q.scale != before.scale {
+		t.Errorf("RenderImage changed the QR Code size to %dx%d scale %d", q.width, q.height, q.scale)
+	}
+
+	// Colors apply per call too. The top left module is dark.
+	img := q.RenderImage(RenderOptions{BackgroundColor: color.Black, ForegroundColor: color.White})
+	if r, _, _, _ := img.At(0, 0).RGBA(); r != 0xffff {
+		t.Errorf("got foreground %v, expected white", img.At(0, 0))
+	}
+	if q.BackgroundColor != before.BackgroundColor {
+		t.Error("RenderImage changed the BackgroundColor")
+	}
+}
+
+func TestSymbolViewReadOnly(t *testing.T) {
+	q, err := New("read only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := SymbolView{q}
+
+	for _, p := range [][2]int{{-1, 0}, {0, -1}, {v.Size(), 0}, {0, v.Size()}} {
+		if v.Dark(p[0], p[1]) {
+			t.Errorf("Dark(%d, %d) outside the symbol is true", p[0], p[1])
+		}
+	}
+
+	// The finder pattern's top left module is dark.
+	x := v.QuietZoneSize()
+	bitmap := v.Bitmap()
+	bitmap[x][x] = false
+	if !v.Dark(x, x) || !q.Bitmap()[x][x] {
+		t.Error("modifying Bitmap changed the symbol")
+	}
+}
@@ -0,0 +1,199 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"encoding/binary"
+	"hash/adler32"
+	"image"
+	"image/color"
+	"io"
+)
+
+// encodeReproduciblePNG writes img to w as a PNG with the fixed encoding of
+// Reproducible: every row filtered with Up, then compressed as a single fixed
+// Huffman block using only runs of repeated bytes.
+func encodeReproduciblePNG(w io.Writer, img image.Image) error {
+	e := &pngChunkWriter{w: w}
+	if _, e.err = io.WriteString(w, pngSignature); e.err != nil {
+		return e.err
+	}
+
+	b := img.Bounds()
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(b.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(b.Dy()))
+
+	var raw []byte
+	if p, ok := img.(*image.Paletted); ok && len(p.Palette) <= 256 {
+		depth := 8
+		switch {
+		case len(p.Palette) <= 2:
+			depth = 1
+		case len(p.Palette) <= 4:
+			depth = 2
+		case len(p.Palette) <= 16:
+			depth = 4
+		}
+		ihdr[8] = byte(depth)
+		ihdr[9] = 3 // Color type: palette.
+		e.writeChunk("IHDR", ihdr)
+
+		var plte, trns []byte
+		for _, c := range p.Palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			plte = append(plte, n.R, n.G, n.B)
+			trns = append(trns, n.A)
+		}
+		e.writeChunk("PLTE", plte)
+
+		// Trailing opaque entries are left out of tRNS.
+		for len(trns) > 0 && trns[len(trns)-1] == 0xff {
+			trns = trns[:len(trns)-1]
+		}
+		if len(trns) > 0 {
+			e.writeChunk("tRNS", trns)
+		}
+
+		rowBytes := (b.Dx()*depth + 7) / 8
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := make([]byte, rowBytes)
+			for x := 0; x < b.Dx(); x++ {
+				bit := x * depth
+				row[bit/8] |= p.ColorIndexAt(b.Min.X+x, y) << uint(8-depth-bit%8)
+			}
+			raw = append(raw, row...)
+		}
+	} else {
+		ihdr[8] = 8 // Bit depth.
+		ihdr[9] = 6 // Color type: RGBA.
+		e.writeChunk("IHDR", ihdr)
+
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				n := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				raw = append(raw, n.R, n.G, n.B, n.A)
+			}
+		}
+	}
+
+	// Filter each row with Up, the difference from the row above.
+	height := b.Dy()
+	rowBytes := 0
+	if height > 0 {
+		rowBytes = len(raw) / height
+	}
+	filtered := make([]byte, 0, len(raw)+height)
+	for y := 0; y < height; y++ {
+		filtered = append(filtered, 2)
+		for x := 0; x < rowBytes; x++ {
+			v := raw[y*rowBytes+x]
+			if y > 0 {
+				v -= raw[(y-1)*rowBytes+x]
+			}
+			filtered = append(filtered, v)
+		}
+	}
+
+	e.writeChunk("IDAT", fixedZlib(filtered))
+	e.writeChunk("IEND", nil)
+
+	return e.err
+}
+
+// Fixed Huffman length codes 257-285: the shortest length of each, and its
+// number of extra bits (RFC 1951 section 3.2.5).
+var (
+	deflateLengthBase = [29]int{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 13, 15, 17, 19, 23, 27, 31,
+		35, 43, 51, 59, 67, 83, 99, 115, 131, 163, 195, 227, 258,
+	}
+	deflateLengthExtra = [29]uint{
+		0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2,
+		3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 0,
+	}
+)
+
+// fixedZlib returns data in zlib format, compressed by an encoder that never
+// changes: a single fixed Huffman block of literals and runs of the previous
+// byte. Unlike compress/zlib, the output is the same with every Go release.
+func fixedZlib(data []byte) []byte {
+	// Deflate, no preset dictionary, fastest compression.
+	bw := &deflateBitWriter{buf: []byte{0x78, 0x01}}
+
+	bw.write(1, 1) // Final block.
+	bw.write(1, 2) // Fixed Huffman codes.
+
+	for i := 0; i < len(data); {
+		run := 0
+		if i > 0 {
+			for run < 258 && i+run < len(data) && data[i+run] == data[i-1] {
+				run++
+			}
+		}
+
+		if run < 3 {
+			bw.symbol(int(data[i]))
+			i++
+			continue
+		}
+
+		code := len(deflateLengthBase) - 1
+		for deflateLengthBase[code] > run {
+			code--
+		}
+		bw.symbol(257 + code)
+		bw.write(uint32(run-deflateLengthBase[code]), deflateLengthExtra[code])
+		bw.code(0, 5) // Distance 1.
+		i += run
+	}
+	bw.symbol(256) // End of block.
+
+	if bw.n > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+	}
+
+	return binary.BigEndian.AppendUint32(bw.buf, adler32.Checksum(data))
+}
+
+// deflateBitWriter packs deflate bits, least significant first.
+type deflateBitWriter struct {
+	buf []byte
+	acc uint32
+	n   uint
+}
+
+// write writes the n low bits of v.
+func (w *deflateBitWriter) write(v uint32, n uint) {
+	w.acc |= v << w.n
+	w.n += n
+	for w.n >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+// code writes the n bit Huffman code c, most significant bit first.
+func (w *deflateBitWriter) code(c uint32, n uint) {
+	var reversed uint32
+	for i := uint(0); i < n; i++ {
+		reversed = reversed<<1 | c>>i&1
+	}
+	w.write(reversed, n)
+}
+
+// symbol writes literal/length symbol s with the fixed Huffman codes.
+func (w *deflateBitWriter) symbol(s int) {
+	switch {
+	case s < 144:
+		w.code(uint32(0x30+s), 8)
+	case s < 256:
+		w.code(uint32(0x190+s-144), 9)
+	case s < 280:
+		w.code(uint32(s-256), 7)
+	default:
+		w.code(uint32(0xc0+s-280), 8)
+	}
+}
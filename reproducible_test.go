@@ -0,0 +1,116 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestFixedZlib(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	inputs := [][]byte{nil, {7}, bytes.Repeat([]byte{0}, 1000), bytes.Repeat([]byte("ab"), 300)}
+	random := make([]byte, 5000)
+	for i := range random {
+		// Mostly runs, as in filtered images.
+		if rng.Intn(10) == 0 {
+			random[i] = byte(rng.Intn(256))
+		} else if i > 0 {
+			random[i] = random[i-1]
+		}
+	}
+	inputs = append(inputs, random)
+
+	for i, data := range inputs {
+		r, err := zlib.NewReader(bytes.NewReader(fixedZlib(data)))
+		if err != nil {
+			t.Fatalf("input %d: %s", i, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("input %d: %s", i, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("input %d: round trip differs", i)
+		}
+	}
+}
+
+func TestReproducible(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"plain", nil},
+		{"palette", []Option{Margin(4), QuietZoneRadius(6)}},
+		{"rgba", []Option{Width(20), Height(20), Downscale(0)}},
+		{"icc", []Option{ICCProfile("Custom", bytes.Repeat([]byte("profile "), 20))}},
+	}
+
+	for _, test := range tests {
+		q, err := New("https://example.org", append(test.opts, Reproducible())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := q.PNG()
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := q.PNG()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("%s: output differs between calls", test.name)
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if !sameImage(img, q.Image()) {
+			t.Errorf("%s: decoded image differs from Image()", test.name)
+		}
+	}
+}
+
+// The fixed encoding must never change: a new hash here breaks users'
+// content-addressed stores.
+func TestReproducibleGolden(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(4), Scale(4), Reproducible())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got, expected := hex.EncodeToString(sum[:]), "fb023e03826f1d990d06d4fb9f90acc395ce1e9489579d81e924a3581f3f95e6"; got != expected {
+		t.Errorf("got sha256 %s, expected %s", got, expected)
+	}
+}
+
+// sameImage returns true if a and b have the same bounds and pixels.
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	na, nb := image.NewNRGBA(a.Bounds()), image.NewNRGBA(b.Bounds())
+	draw.Draw(na, na.Bounds(), a, a.Bounds().Min, draw.Src)
+	draw.Draw(nb, nb.Bounds(), b, b.Bounds().Min, draw.Src)
+	return bytes.Equal(na.Pix, nb.Pix)
+}
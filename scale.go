@@ -0,0 +1,55 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+)
+
+// scaleNearest returns src scaled to width x height pixels by nearest
+// neighbor sampling. Unlike smoothing filters, every output pixel is a color
+// of src, so the edges of modules and other hard edged artwork stay sharp.
+//
+// Output rows sampling the same source row are copied rather than sampled
+// again, so integer upscaling is a block copy.
+func scaleNearest(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	b := src.Bounds()
+	if b.Empty() {
+		return dst
+	}
+
+	columns := make([]int, width)
+	for x := range columns {
+		columns[x] = b.Min.X + x*b.Dx()/width
+	}
+
+	lastY := -1
+	for y := 0; y < height; y++ {
+		sy := b.Min.Y + y*b.Dy()/height
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+4*width]
+
+		if sy == lastY {
+			copy(row, dst.Pix[(y-1)*dst.Stride:])
+			continue
+		}
+		lastY = sy
+
+		for x, sx := range columns {
+			if x > 0 && sx == columns[x-1] {
+				copy(row[4*x:4*x+4], row[4*x-4:4*x])
+				continue
+			}
+			c := color.RGBAModel.Convert(src.At(sx, sy)).(color.RGBA)
+			row[4*x+0] = c.R
+			row[4*x+1] = c.G
+			row[4*x+2] = c.B
+			row[4*x+3] = c.A
+		}
+	}
+
+	return dst
+}
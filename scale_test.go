@@ -0,0 +1,76 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestScaleNearest(t *testing.T) {
+	// A 2x2 checkerboard, offset from the origin.
+	src := image.NewPaletted(image.Rect(10, 10, 12, 12), color.Palette{color.White, color.Black})
+	src.SetColorIndex(10, 10, 1)
+	src.SetColorIndex(11, 11, 1)
+
+	tests := []struct {
+		width, height int
+	}{
+		{2, 2},
+		{6, 6},
+		{8, 4},
+		{5, 3},
+	}
+
+	for _, test := range tests {
+		dst := scaleNearest(src, test.width, test.height)
+
+		if dst.Bounds() != image.Rect(0, 0, test.width, test.height) {
+			t.Fatalf("image is %v, expected %dx%d", dst.Bounds(), test.width, test.height)
+		}
+
+		for y := 0; y < test.height; y++ {
+			for x := 0; x < test.width; x++ {
+				expected := color.RGBAModel.Convert(src.At(10+x*2/test.width, 10+y*2/test.height))
+				if got := dst.At(x, y); got != expected {
+					t.Fatalf("%dx%d: pixel (%d, %d) is %v, expected %v",
+						test.width, test.height, x, y, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestFitLogoPalettedIsSharp(t *testing.T) {
+	logo := image.NewPaletted(image.Rect(0, 0, 100, 100), color.Palette{color.White, color.Black})
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 50; x++ {
+			logo.SetColorIndex(x, y, 1)
+		}
+	}
+
+	fit := fitLogo(logo, 30, 30, true)
+	b := fit.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(fit.At(x, y)).(color.RGBA)
+			if c != (color.RGBA{0, 0, 0, 0xff}) && c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+				t.Fatalf("pixel (%d, %d) is %v, expected black or white", x, y, c)
+			}
+		}
+	}
+}
+
+func BenchmarkScaleNearest(b *testing.B) {
+	q, err := New("https://example.org", Width(-1), Height(-1))
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+	img := q.Image()
+
+	for n := 0; n < b.N; n++ {
+		scaleNearest(img, 1024, 1024)
+	}
+}
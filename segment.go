@@ -0,0 +1,153 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yougg/go-qrcode/bitset"
+)
+
+// Mode is the data encoding mode of a Segment.
+type Mode int
+
+const (
+	// Numeric encodes the digits 0-9, 3 per 10 bits.
+	Numeric Mode = iota + 1
+
+	// Alphanumeric encodes 0-9, A-Z, space and $%*+-./:, 2 per 11 bits.
+	Alphanumeric
+
+	// Byte encodes any bytes, 8 bits each.
+	Byte
+
+	// Kanji encodes Shift JIS double byte characters in the ranges
+	// 0x8140-0x9FFC and 0xE040-0xEBBF, 13 bits each.
+	Kanji
+)
+
+// String returns the name of the mode, e.g. "numeric".
+func (m Mode) String() string {
+	if d, ok := m.dataMode(); ok {
+		return dataModeString(d)
+	}
+	return "unknown"
+}
+
+// dataMode returns the encoder data mode for m.
+func (m Mode) dataMode() (dataMode, bool) {
+	switch m {
+	case Numeric:
+		return dataModeNumeric, true
+	case Alphanumeric:
+		return dataModeAlphanumeric, true
+	case Byte:
+		return dataModeByte, true
+	case Kanji:
+		return dataModeKanji, true
+	}
+
+	return dataModeNone, false
+}
+
+// Segment is a run of data encoded in a single mode.
+type Segment struct {
+	Mode Mode
+
+	// Data to encode. Kanji data is in Shift JIS.
+	Data string
+}
+
+// NewSegments constructs a QRCode from explicitly segmented data, for callers
+// that perform their own mode optimisation, or need Kanji mode. The segments
+// are encoded in order, without merging or splitting, and the smallest version
+// which fits them is chosen.
+//
+//	q, err := qrcode.NewSegments([]qrcode.Segment{
+//		{Mode: qrcode.Numeric, Data: "123"},
+//		{Mode: qrcode.Byte, Data: "x"},
+//	})
+//
+// An error occurs if a segment's data is not valid in its mode, or the
+// segments are too long. The Content of the QR Code is the concatenated data.
+func NewSegments(segments []Segment, opts ...Option) (*QRCode, error) {
+	var content []byte
+	for i, s := range segments {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("segment %d: %s", i, err.Error())
+		}
+		content = append(content, s.Data...)
+	}
+	if len(content) == 0 {
+		return nil, errors.New("no data to encode")
+	}
+
+	q := &QRCode{
+		Content: string(content),
+	}
+	q.apply(withDefaults(opts)...)
+
+	err := q.build(func(encoder *dataEncoder) (*bitset.Bitset, error) {
+		encoded := bitset.New()
+		for i, s := range segments {
+			mode, _ := s.Mode.dataMode()
+
+			n := len(s.Data)
+			if mode == dataModeKanji {
+				n /= 2
+			}
+			if _, err := encoder.encodedLength(mode, n); err != nil {
+				return nil, fmt.Errorf("segment %d: %s", i, err.Error())
+			}
+
+			encoder.encodeDataRaw([]byte(s.Data), mode, encoded)
+		}
+		return encoded, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// validate returns an error if s's data cannot be encoded in its mode.
+func (s Segment) validate() error {
+	if _, ok := s.Mode.dataMode(); !ok {
+		return fmt.Errorf("unknown mode %d", s.Mode)
+	}
+
+	for i := 0; i < len(s.Data); i++ {
+		c := s.Data[i]
+
+		switch s.Mode {
+		case Numeric:
+			if c < '0' || c > '9' {
+				return fmt.Errorf("byte %d (0x%02x) is not numeric", i, c)
+			}
+		case Alphanumeric:
+			if c >= 0x80 || alphanumericIndex[c] < 0 {
+				return fmt.Errorf("byte %d (0x%02x) is not alphanumeric", i, c)
+			}
+		case Kanji:
+			if i+1 >= len(s.Data) || !isKanji(c, s.Data[i+1]) {
+				return fmt.Errorf("byte %d is not a Shift JIS kanji character", i)
+			}
+			i++
+		}
+	}
+
+	return nil
+}
+
+// alphanumericIndex maps ASCII characters to their Alphanumeric mode value,
+// or -1.
+var alphanumericIndex = func() [128]int {
+	var index [128]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < len(alphanumericCharacters); i++ {
+		index[alphanumericCharacters[i]] = i
+	}
+	return index
+}()
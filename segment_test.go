@@ -0,0 +1,80 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewSegments(t *testing.T) {
+	tests := []struct {
+		segments []Segment
+		bits     string
+	}{
+		{
+			[]Segment{{Mode: Numeric, Data: "123"}, {Mode: Byte, Data: "x"}},
+			// Numeric: mode 0001, count 3, 123. Byte: mode 0100, count 1, 'x'.
+			"0001" + "0000000011" + "0001111011" + "0100" + "00000001" + "01111000",
+		},
+		{
+			// Kept as two segments, although one would be shorter.
+			[]Segment{{Mode: Alphanumeric, Data: "AB"}, {Mode: Alphanumeric, Data: "C"}},
+			"0010" + "000000010" + "00111001101" + "0010" + "000000001" + "001100",
+		},
+		{
+			// ISO/IEC 18004 Kanji example: 0x935F and 0xE4AA.
+			[]Segment{{Mode: Kanji, Data: "\x93\x5f\xe4\xaa"}},
+			"1000" + "00000010" + "0110110011111" + "1101010101010",
+		},
+	}
+
+	for _, test := range tests {
+		q, err := NewSegments(test.segments, Level(Low))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		var got string
+		for i := 0; i < len(test.bits); i++ {
+			if q.data.At(i) {
+				got += "1"
+			} else {
+				got += "0"
+			}
+		}
+		if got != test.bits {
+			t.Errorf("%v encoded as\n%s\nexpected\n%s", test.segments, got, test.bits)
+		}
+
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if string(info.Content) != q.Content {
+			t.Errorf("decoded %q, expected %q", info.Content, q.Content)
+		}
+	}
+}
+
+func TestNewSegmentsErrors(t *testing.T) {
+	tests := [][]Segment{
+		nil,
+		{{Mode: Numeric, Data: "12a"}},
+		{{Mode: Alphanumeric, Data: "abc"}},
+		{{Mode: Kanji, Data: "\x93"}},
+		{{Mode: Kanji, Data: "ab"}},
+		{{Mode: Mode(0), Data: "1"}},
+		{{Mode: Byte, Data: strings.Repeat("#", 3000)}},
+		// Valid alone, too long with the overhead of a second segment.
+		{{Mode: Numeric, Data: strings.Repeat("1", 7089)}, {Mode: Numeric, Data: "1"}},
+	}
+
+	for _, segments := range tests {
+		if _, err := NewSegments(segments, Level(Low)); err == nil {
+			t.Errorf("NewSegments(%.40v) succeeded, expected error", segments)
+		}
+	}
+
+	if _, err := NewSegments([]Segment{{Mode: Numeric, Data: strings.Repeat("1", 7089)}}, Level(Low)); err != nil {
+		t.Errorf("7089 digits: %s, expected success", err.Error())
+	}
+}
@@ -0,0 +1,175 @@
+package qrcode
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// shamirPrefix starts the content of the share codes produced by
+// SplitSecret. Like the Base45 that follows, it only uses alphanumeric mode
+// characters.
+const shamirPrefix = "QRS1:"
+
+// shamirChecksumSize is the number of bytes of the SHA-256 digest of the
+// secret split with it, so CombineShares detects shares from different
+// secrets or corrupted shares.
+const shamirChecksumSize = 4
+
+// SplitSecret splits secret, e.g. a wallet seed or backup key, into n QR
+// Codes using Shamir's secret sharing: any k of the codes recover the secret
+// with CombineShares, while fewer reveal nothing about it. 1 <= k <= n <= 255.
+//
+// Each code's content is "QRS1:" followed by the Base45 encoded share, so it
+// is encoded in alphanumeric mode. The options apply to every code.
+func SplitSecret(secret []byte, k, n int, opts ...Option) ([]*QRCode, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, errors.New("secret is empty")
+	case k < 1 || n < k || n > 255:
+		return nil, fmt.Errorf("invalid %d of %d shares (need 1 <= k <= n <= 255)", k, n)
+	}
+
+	// The checksum is split with the secret.
+	sum := sha256.Sum256(secret)
+	data := append(append([]byte{}, secret...), sum[:shamirChecksumSize]...)
+
+	// shares[i] is the share with x coordinate i+1: k, x, then one y value
+	// per byte of data.
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = append(make([]byte, 0, 2+len(data)), byte(k), byte(i+1))
+	}
+
+	// Each byte is the constant term of a random polynomial of degree k-1.
+	coefficients := make([]byte, k)
+	for _, b := range data {
+		coefficients[0] = b
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		for i := range shares {
+			shares[i] = append(shares[i], gf256Eval(coefficients, byte(i+1)))
+		}
+	}
+
+	codes := make([]*QRCode, n)
+	for i, share := range shares {
+		q, err := NewSegments([]Segment{{Mode: Alphanumeric, Data: shamirPrefix + base45Encode(share)}}, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %v", i+1, err)
+		}
+		codes[i] = q
+	}
+
+	return codes, nil
+}
+
+// CombineShares returns the secret split by SplitSecret, from the contents of
+// at least k of its codes, e.g. as read by a scanner. Duplicate shares are
+// ignored.
+func CombineShares(contents []string) ([]byte, error) {
+	var xs []byte
+	var ys [][]byte
+	k := 0
+
+	for i, content := range contents {
+		content = strings.TrimSpace(content)
+		if !strings.HasPrefix(content, shamirPrefix) {
+			return nil, fmt.Errorf("share %d is not a secret share (expected prefix %q)", i+1, shamirPrefix)
+		}
+		share, err := base45Decode(content[len(shamirPrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %v", i+1, err)
+		}
+		if len(share) < 2+shamirChecksumSize+1 || share[0] == 0 || share[1] == 0 {
+			return nil, fmt.Errorf("share %d is malformed", i+1)
+		}
+
+		if i == 0 {
+			k = int(share[0])
+		} else if int(share[0]) != k || len(share)-2 != len(ys[0]) {
+			return nil, fmt.Errorf("share %d is from a different secret", i+1)
+		}
+
+		if j := bytes.IndexByte(xs, share[1]); j >= 0 {
+			if !bytes.Equal(ys[j], share[2:]) {
+				return nil, fmt.Errorf("share %d conflicts with another share", i+1)
+			}
+			continue
+		}
+		xs = append(xs, share[1])
+		ys = append(ys, share[2:])
+	}
+
+	if len(xs) == 0 || len(xs) < k {
+		return nil, fmt.Errorf("%d shares given, %d needed", len(xs), k)
+	}
+	xs, ys = xs[:k], ys[:k]
+
+	// Lagrange interpolation at x = 0 of each byte.
+	data := make([]byte, len(ys[0]))
+	for i := range data {
+		var b byte
+		for j, xj := range xs {
+			// The basis polynomial of share j at 0: prod xm / (xm - xj).
+			basis := byte(1)
+			for m, xm := range xs {
+				if m != j {
+					basis = gf256Mul(basis, gf256Mul(xm, gf256Inv(xm^xj)))
+				}
+			}
+			b ^= gf256Mul(ys[j][i], basis)
+		}
+		data[i] = b
+	}
+
+	secret, checksum := data[:len(data)-shamirChecksumSize], data[len(data)-shamirChecksumSize:]
+	if sum := sha256.Sum256(secret); !bytes.Equal(sum[:shamirChecksumSize], checksum) {
+		return nil, errors.New("shares do not combine to a valid secret: shares are from different secrets or corrupted")
+	}
+
+	return secret, nil
+}
+
+// gf256Eval returns the polynomial with coefficients c (constant term first)
+// evaluated at x in GF(2^8).
+func gf256Eval(c []byte, x byte) byte {
+	var y byte
+	for i := len(c) - 1; i >= 0; i-- {
+		y = gf256Mul(y, x) ^ c[i]
+	}
+	return y
+}
+
+// gf256Mul returns a*b in GF(2^8) with the AES polynomial
+// x^8 + x^4 + x^3 + x + 1.
+func gf256Mul(a, b byte) byte {
+	var p byte
+	for b != 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// gf256Inv returns the multiplicative inverse of a (non-zero) in GF(2^8):
+// a^254.
+func gf256Inv(a byte) byte {
+	r := byte(1)
+	for i := 0; i < 254; i++ {
+		r = gf256Mul(r, a)
+	}
+	return r
+}
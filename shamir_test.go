@@ -0,0 +1,82 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSplitSecret(t *testing.T) {
+	secret := []byte("correct horse battery staple wallet seed")
+
+	codes, err := SplitSecret(secret, 3, 5, Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 5 {
+		t.Fatalf("got %d codes, expected 5", len(codes))
+	}
+
+	contents := make([]string, len(codes))
+	for i, q := range codes {
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[i] = string(info.Content)
+		if !strings.HasPrefix(contents[i], "QRS1:") || strings.Contains(contents[i], "horse") {
+			t.Fatalf("share %d content is %q", i, contents[i])
+		}
+	}
+
+	// Any 3 of the 5 shares, in any order.
+	for _, pick := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}, {3, 3, 1, 2}} {
+		var shares []string
+		for _, i := range pick {
+			shares = append(shares, contents[i])
+		}
+
+		got, err := CombineShares(shares)
+		if err != nil || !bytes.Equal(got, secret) {
+			t.Errorf("shares %v: got %q, %v", pick, got, err)
+		}
+	}
+
+	if _, err := CombineShares(contents[:2]); err == nil {
+		t.Error("CombineShares succeeded with 2 of 3 shares")
+	}
+	if _, err := CombineShares([]string{contents[0], contents[0], contents[1]}); err == nil {
+		t.Error("CombineShares succeeded with a duplicated share")
+	}
+
+	others, err := SplitSecret(bytes.ToUpper(secret), 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineShares([]string{contents[0], contents[1], others[2].Content}); err == nil {
+		t.Error("CombineShares succeeded with shares of different secrets")
+	}
+
+	if _, err := CombineShares([]string{"hello"}); err == nil {
+		t.Error("CombineShares succeeded with a non-share")
+	}
+
+	for _, kn := range [][2]int{{0, 3}, {4, 3}, {2, 256}} {
+		if _, err := SplitSecret(secret, kn[0], kn[1]); err == nil {
+			t.Errorf("SplitSecret(%d of %d) succeeded, expected error", kn[0], kn[1])
+		}
+	}
+}
+
+func TestGF256(t *testing.T) {
+	// FIPS 197 section 4.2 example.
+	if got := gf256Mul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("0x57 * 0x83 = 0x%02x, expected 0xc1", got)
+	}
+
+	for a := 1; a < 256; a++ {
+		if gf256Mul(byte(a), gf256Inv(byte(a))) != 1 {
+			t.Fatalf("0x%02x * inverse != 1", a)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+// PNGBytes encodes content as a size x size pixel PNG image:
+//
+//	png, err := qrcode.PNGBytes("https://example.org", 256)
+//
+// opts, e.g. Level(Medium), are applied after the size, so e.g. Scale
+// overrides it.
+func PNGBytes(content string, size int, opts ...Option) ([]byte, error) {
+	return EncodeWithOptions(content, append([]Option{Width(size), Height(size)}, opts...)...)
+}
+
+// SVGBytes encodes content as a scalable SVG image, see the "svg" Renderer.
+func SVGBytes(content string, opts ...Option) ([]byte, error) {
+	return renderContent(content, "svg", RenderOptions{}, opts)
+}
+
+// SVGString encodes content as a scalable SVG image, returned as a string
+// for embedding in HTML templates.
+func SVGString(content string, opts ...Option) (string, error) {
+	b, err := SVGBytes(content, opts...)
+	return string(b), err
+}
+
+// PDFBytes encodes content as a PDF document of a sizeMM millimetre square
+// symbol, including the quiet zone, printed in BlackInk.
+func PDFBytes(content string, sizeMM float64, opts ...Option) ([]byte, error) {
+	return renderContent(content, "pdf", RenderOptions{SizeMM: sizeMM}, opts)
+}
+
+// TIFFBytes encodes content as a CMYK TIFF image printed in BlackInk, see
+// QRCode.TIFF.
+func TIFFBytes(content string, opts ...Option) ([]byte, error) {
+	return renderContent(content, "tiff", RenderOptions{}, opts)
+}
+
+// ICOBytes encodes content as a Windows icon of 16, 32 and 64 pixel images,
+// see QRCode.ICO.
+func ICOBytes(content string, opts ...Option) ([]byte, error) {
+	return renderContent(content, "ico", RenderOptions{}, opts)
+}
+
+// BMPBytes encodes content as a monochrome Windows bitmap, see QRCode.BMP.
+func BMPBytes(content string, size int, opts ...Option) ([]byte, error) {
+	return renderContent(content, "bmp", RenderOptions{}, append([]Option{Width(size), Height(size)}, opts...))
+}
+
+// TextString encodes content as text for terminals, see QRCode.ToString.
+func TextString(content string, opts ...Option) (string, error) {
+	b, err := renderContent(content, "txt", RenderOptions{}, opts)
+	return string(b), err
+}
+
+// renderContent encodes content with opts, and returns it in the named
+// output format.
+func renderContent(content, format string, ro RenderOptions, opts []Option) ([]byte, error) {
+	q, err := New(content, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Render(format, ro)
+}
@@ -0,0 +1,76 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPNGBytes(t *testing.T) {
+	b, err := PNGBytes("https://example.org", 256, Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 256 || size.Y != 256 {
+		t.Errorf("got size %v, expected 256x256", size)
+	}
+
+	if _, err := PNGBytes(strings.Repeat("a", 8000), 256); err == nil {
+		t.Error("expected error for content too long")
+	}
+}
+
+func TestFormatShortcuts(t *testing.T) {
+	q, err := New("https://example.org", Level(High))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format string
+		got    func() ([]byte, error)
+	}{
+		{"svg", func() ([]byte, error) { return SVGBytes("https://example.org", Level(High)) }},
+		{"svg", func() ([]byte, error) {
+			s, err := SVGString("https://example.org", Level(High))
+			return []byte(s), err
+		}},
+		{"tiff", func() ([]byte, error) { return TIFFBytes("https://example.org", Level(High)) }},
+		{"ico", func() ([]byte, error) { return ICOBytes("https://example.org", Level(High)) }},
+		{"txt", func() ([]byte, error) {
+			s, err := TextString("https://example.org", Level(High))
+			return []byte(s), err
+		}},
+	}
+
+	for _, test := range tests {
+		expected, err := q.Render(test.format, RenderOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := test.got()
+		if err != nil {
+			t.Errorf("%s: %v", test.format, err)
+		} else if !bytes.Equal(got, expected) {
+			t.Errorf("%s: output differs from Render", test.format)
+		}
+	}
+
+	pdf, err := PDFBytes("https://example.org", 25, Level(High))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("got %q, expected a PDF document", pdf[:min(len(pdf), 8)])
+	}
+}
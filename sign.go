@@ -0,0 +1,301 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Signed payloads let scanners check that a QR Code was issued by the holder
+// of a private key, e.g. for tickets and certificates. Two envelopes are
+// supported, both signed with ES256 (ECDSA using P-256 and SHA-256):
+//
+//   - compact JWS (RFC 7515), for web based verifiers.
+//   - COSE_Sign1 (RFC 9052), zlib compressed and Base45 encoded after a
+//     prefix, as in EU Digital COVID Certificates ("HC1:"). The envelope
+//     only contains alphanumeric mode characters, so it is encoded compactly.
+
+// maxSignedPayload is the largest decompressed COSE payload VerifyCOSE
+// accepts, well beyond the capacity of any QR Code.
+const maxSignedPayload = 64 * 1024
+
+// SignJWS returns payload signed with key as a compact JWS, with kid
+// identifying the key in the header if not empty.
+func SignJWS(payload []byte, key *ecdsa.PrivateKey, kid string) (string, error) {
+	if err := checkES256PrivateKey(key); err != nil {
+		return "", err
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid,omitempty"`
+	}{"ES256", kid}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(h) + "." + enc.EncodeToString(payload)
+
+	sig, err := signES256(key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// VerifyJWS returns the payload of a compact JWS produced by SignJWS, or an
+// error if it was not signed by the private key of pub.
+func VerifyJWS(token string, pub *ecdsa.PublicKey) ([]byte, error) {
+	if err := checkES256Key(pub); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("JWS is not in compact serialization")
+	}
+
+	enc := base64.RawURLEncoding
+	h, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS header: %v", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(h, &header); err != nil {
+		return nil, fmt.Errorf("invalid JWS header: %v", err)
+	}
+	if header.Alg != "ES256" {
+		return nil, fmt.Errorf("unsupported JWS algorithm %q (expected ES256)", header.Alg)
+	}
+
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS payload: %v", err)
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS signature: %v", err)
+	}
+
+	if !verifyES256(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, errors.New("JWS signature does not verify")
+	}
+
+	return payload, nil
+}
+
+// SignCOSE returns payload signed with key as a COSE_Sign1 message, with kid
+// identifying the key in the protected header if not empty. The message is
+// zlib compressed, Base45 encoded and appended to prefix, e.g. "HC1:".
+func SignCOSE(payload []byte, key *ecdsa.PrivateKey, kid []byte, prefix string) (string, error) {
+	if err := checkES256PrivateKey(key); err != nil {
+		return "", err
+	}
+
+	// Protected header {1 (alg): -7 (ES256), 4 (kid): kid}.
+	var protected []byte
+	if len(kid) > 0 {
+		protected = cborHead(cborMap, 2)
+	} else {
+		protected = cborHead(cborMap, 1)
+	}
+	protected = append(protected, cborInt(1)...)
+	protected = append(protected, cborInt(-7)...)
+	if len(kid) > 0 {
+		protected = append(protected, cborInt(4)...)
+		protected = append(protected, cborBytes(kid)...)
+	}
+
+	sig, err := signES256(key, coseSigStructure(protected, payload))
+	if err != nil {
+		return "", err
+	}
+
+	msg := cborHead(cborTag, 18)
+	msg = append(msg, cborHead(cborArray, 4)...)
+	msg = append(msg, cborBytes(protected)...)
+	msg = append(msg, cborHead(cborMap, 0)...)
+	msg = append(msg, cborBytes(payload)...)
+	msg = append(msg, cborBytes(sig)...)
+
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	zw.Write(msg)
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	return prefix + base45Encode(buf.Bytes()), nil
+}
+
+// VerifyCOSE returns the payload of a message produced by SignCOSE, or an
+// error if it does not start with prefix or was not signed by the private
+// key of pub.
+func VerifyCOSE(message string, pub *ecdsa.PublicKey, prefix string) ([]byte, error) {
+	if err := checkES256Key(pub); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(message, prefix) {
+		return nil, fmt.Errorf("COSE message does not start with %q", prefix)
+	}
+
+	compressed, err := base45Decode(message[len(prefix):])
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid COSE message compression: %v", err)
+	}
+	msg, err := io.ReadAll(io.LimitReader(zr, maxSignedPayload+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid COSE message compression: %v", err)
+	}
+	if len(msg) > maxSignedPayload {
+		return nil, errors.New("COSE message is too large")
+	}
+
+	r := &cborReader{data: msg}
+	if r.peekMajor() == cborTag {
+		if tag, err := r.head(cborTag); err != nil || tag != 18 {
+			return nil, errors.New("COSE message is not a COSE_Sign1")
+		}
+	}
+	if n, err := r.head(cborArray); err != nil || n != 4 {
+		return nil, errors.New("COSE message is not a COSE_Sign1")
+	}
+
+	protected, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.skip(); err != nil {
+		return nil, err
+	}
+	payload, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := coseAlgorithm(protected)
+	if err != nil {
+		return nil, err
+	}
+	if alg != -7 {
+		return nil, fmt.Errorf("unsupported COSE algorithm %d (expected -7, ES256)", alg)
+	}
+
+	if !verifyES256(pub, coseSigStructure(protected, payload), sig) {
+		return nil, errors.New("COSE signature does not verify")
+	}
+
+	return payload, nil
+}
+
+// coseSigStructure returns the data signed for a COSE_Sign1 message:
+// ["Signature1", protected, external_aad (empty), payload].
+func coseSigStructure(protected, payload []byte) []byte {
+	b := cborHead(cborArray, 4)
+	b = append(b, cborHead(cborTextString, 10)...)
+	b = append(b, "Signature1"...)
+	b = append(b, cborBytes(protected)...)
+	b = append(b, cborBytes(nil)...)
+	b = append(b, cborBytes(payload)...)
+
+	return b
+}
+
+// coseAlgorithm returns the alg (1) parameter of a COSE protected header.
+func coseAlgorithm(protected []byte) (int64, error) {
+	r := &cborReader{data: protected}
+	n, err := r.head(cborMap)
+	if err != nil {
+		return 0, errors.New("invalid COSE protected header")
+	}
+
+	for i := uint64(0); i < n; i++ {
+		label, err := r.int()
+		if err != nil {
+			return 0, errors.New("invalid COSE protected header")
+		}
+		if label == 1 {
+			return r.int()
+		}
+		if err := r.skip(); err != nil {
+			return 0, err
+		}
+	}
+
+	return 0, errors.New("COSE protected header has no algorithm")
+}
+
+// checkES256Key returns an error if pub is not a P-256 key.
+func checkES256Key(pub *ecdsa.PublicKey) error {
+	if pub == nil || pub.Curve != elliptic.P256() {
+		return errors.New("ES256 requires a P-256 ECDSA key")
+	}
+	return nil
+}
+
+// checkES256PrivateKey is checkES256Key for a private key.
+func checkES256PrivateKey(key *ecdsa.PrivateKey) error {
+	if key == nil {
+		return checkES256Key(nil)
+	}
+	return checkES256Key(&key.PublicKey)
+}
+
+// signES256 returns the ES256 signature of data: r and s as 32 byte big
+// endian values.
+func signES256(key *ecdsa.PrivateKey, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return sig, nil
+}
+
+// verifyES256 returns true if sig is a valid ES256 signature of data.
+func verifyES256(pub *ecdsa.PublicKey, data, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+
+	digest := sha256.Sum256(data)
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+
+	return ecdsa.Verify(pub, digest[:], r, s)
+}
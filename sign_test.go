@@ -0,0 +1,149 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestSignJWS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"ticket":"A-42","seat":"17C"}`)
+
+	token, err := SignJWS(payload, key, "issuer-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	if string(header) != `{"alg":"ES256","kid":"issuer-1"}` {
+		t.Errorf("got header %s", header)
+	}
+
+	got, err := VerifyJWS(token, &key.PublicKey)
+	if err != nil || string(got) != string(payload) {
+		t.Errorf("VerifyJWS = %q, %v, expected %q", got, err, payload)
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if _, err := VerifyJWS(token, &other.PublicKey); err == nil {
+		t.Error("VerifyJWS succeeded with another key")
+	}
+
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"ticket":"A-43"}`)) + "." + parts[2]
+	if _, err := VerifyJWS(forged, &key.PublicKey); err == nil {
+		t.Error("VerifyJWS succeeded with a modified payload")
+	}
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	if _, err := VerifyJWS(none, &key.PublicKey); err == nil {
+		t.Error("VerifyJWS accepted alg none")
+	}
+
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if _, err := SignJWS(payload, p384, ""); err == nil {
+		t.Error("SignJWS succeeded with a P-384 key")
+	}
+	if _, err := SignJWS(payload, nil, ""); err == nil {
+		t.Error("SignJWS succeeded with a nil key")
+	}
+}
+
+func TestSignCOSE(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("ticket A-42, seat 17C")
+
+	for _, kid := range [][]byte{nil, []byte("issuer-1")} {
+		msg, err := SignCOSE(payload, key, kid, "HC1:")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The message is encoded in alphanumeric mode.
+		for _, c := range msg {
+			if !strings.ContainsRune(base45Alphabet, c) {
+				t.Fatalf("message %q contains non alphanumeric mode character %q", msg, c)
+			}
+		}
+
+		q, err := New(msg, Level(Medium))
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := VerifyCOSE(string(info.Content), &key.PublicKey, "HC1:")
+		if err != nil || string(got) != string(payload) {
+			t.Errorf("VerifyCOSE = %q, %v, expected %q", got, err, payload)
+		}
+
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if _, err := VerifyCOSE(msg, &other.PublicKey, "HC1:"); err == nil {
+			t.Error("VerifyCOSE succeeded with another key")
+		}
+		if _, err := VerifyCOSE(msg, &key.PublicKey, "HC2:"); err == nil {
+			t.Error("VerifyCOSE succeeded with the wrong prefix")
+		}
+	}
+
+	for _, invalid := range []string{"HC1:", "HC1:ABC", "HC1:" + base45Encode([]byte("not zlib"))} {
+		if _, err := VerifyCOSE(invalid, &key.PublicKey, "HC1:"); err == nil {
+			t.Errorf("VerifyCOSE(%q) succeeded, expected error", invalid)
+		}
+	}
+
+	if _, err := SignCOSE(payload, nil, nil, "HC1:"); err == nil {
+		t.Error("SignCOSE succeeded with a nil key")
+	}
+}
+
+func TestCBOR(t *testing.T) {
+	// RFC 8949 Appendix A examples.
+	tests := []struct {
+		got      []byte
+		expected string
+	}{
+		{cborInt(0), "\x00"},
+		{cborInt(23), "\x17"},
+		{cborInt(24), "\x18\x18"},
+		{cborInt(1000), "\x19\x03\xe8"},
+		{cborInt(1000000), "\x1a\x00\x0f\x42\x40"},
+		{cborInt(-1), "\x20"},
+		{cborInt(-1000), "\x39\x03\xe7"},
+		{cborBytes([]byte{1, 2, 3, 4}), "\x44\x01\x02\x03\x04"},
+		{cborHead(cborTag, 18), "\xd2"},
+	}
+
+	for i, test := range tests {
+		if string(test.got) != test.expected {
+			t.Errorf("#%d: got % x, expected % x", i, test.got, test.expected)
+		}
+	}
+
+	r := &cborReader{data: []byte("\x83\x01\x82\x02\x03\xa1\x61a\x61b\x39\x03\xe7")}
+	if err := r.skip(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := r.int(); err != nil || v != -1000 {
+		t.Errorf("got %d, %v, expected -1000", v, err)
+	}
+	if err := (&cborReader{data: []byte("\x5a\xff\xff\xff\xff")}).skip(); err == nil {
+		t.Error("skip succeeded on truncated data")
+	}
+}
@@ -0,0 +1,123 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Sink stores encoded QR Codes by name, e.g. as files or as objects in a
+// cloud storage bucket.
+type Sink interface {
+	// Create returns a writer for the named object. The object is complete
+	// when the writer is closed without error.
+	Create(name, contentType string) (io.WriteCloser, error)
+}
+
+// SinkFunc is a Sink calling a function with each complete object, e.g. to
+// upload it with a cloud storage client.
+type SinkFunc func(name, contentType string, data []byte) error
+
+// Create returns a writer buffering the object, which calls f when closed.
+func (f SinkFunc) Create(name, contentType string) (io.WriteCloser, error) {
+	return &bufferedObject{close: func(data []byte) error {
+		return f(name, contentType, data)
+	}}, nil
+}
+
+// DirSink returns a Sink writing each object to a file named by the object
+// name in dir. Missing directories are created. Names must be local to dir:
+// absolute names and names with ".." elements are rejected.
+func DirSink(dir string) Sink {
+	return dirSink(dir)
+}
+
+type dirSink string
+
+func (d dirSink) Create(name, contentType string) (io.WriteCloser, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return nil, fmt.Errorf("sink: name %q is not local to the directory", name)
+	}
+
+	filename := filepath.Join(string(d), local)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(filename)
+}
+
+// WriterSink returns a Sink writing every object, one after another, to w.
+// Objects are written whole when closed, so concurrent objects never
+// interleave.
+func WriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *writerSink) Create(name, contentType string) (io.WriteCloser, error) {
+	return &bufferedObject{close: func(data []byte) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		_, err := s.w.Write(data)
+		return err
+	}}, nil
+}
+
+// bufferedObject holds an object in memory until it is closed.
+type bufferedObject struct {
+	bytes.Buffer
+	close func(data []byte) error
+}
+
+func (o *bufferedObject) Close() error {
+	return o.close(o.Bytes())
+}
+
+// WriteToSink writes the QR Code to sink as the named object. The output
+// format is chosen by the name's extension from the registered Renderers,
+// e.g. "codes/a.svg" is written as svg. Names without a registered extension
+// are written as png.
+func (q *QRCode) WriteToSink(sink Sink, name string) error {
+	format := strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")
+	if _, ok := LookupRenderer(format); !ok {
+		format = "png"
+	}
+
+	data, err := q.Render(format, RenderOptions{})
+	if err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension("." + format)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w, err := sink.Create(name, contentType)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,146 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteToSink(t *testing.T) {
+	q, err := New("sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type object struct {
+		contentType string
+		data        []byte
+	}
+	objects := map[string]object{}
+	sink := SinkFunc(func(name, contentType string, data []byte) error {
+		objects[name] = object{contentType, data}
+		return nil
+	})
+
+	for _, name := range []string{"a.png", "b.svg", "c.pdf", "d"} {
+		if err := q.WriteToSink(sink, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	png, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		prefix      string
+	}{
+		{"a.png", "image/png", "\x89PNG"},
+		{"b.svg", "image/svg+xml", "<svg"},
+		{"c.pdf", "application/pdf", "%PDF-"},
+		{"d", "image/png", "\x89PNG"},
+	}
+	for _, test := range tests {
+		o := objects[test.name]
+		if o.contentType != test.contentType {
+			t.Errorf("%s: got content type %q, expected %q", test.name, o.contentType, test.contentType)
+		}
+		if !bytes.HasPrefix(o.data, []byte(test.prefix)) {
+			t.Errorf("%s: unexpected data %.8q", test.name, o.data)
+		}
+	}
+	if !bytes.Equal(objects["a.png"].data, png) {
+		t.Error("png object differs from PNG()")
+	}
+
+	failing := SinkFunc(func(name, contentType string, data []byte) error {
+		return errors.New("upload failed")
+	})
+	if err := q.WriteToSink(failing, "e.png"); err == nil {
+		t.Error("WriteToSink succeeded with a failing sink, expected error")
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := New("dir sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.WriteToSink(DirSink(dir), "sub/code.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sub", "code.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png, err := q.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, png) {
+		t.Error("file differs from PNG()")
+	}
+
+	for _, name := range []string{"../escape.png", "sub/../../escape.png", "/abs.png", ""} {
+		if err := q.WriteToSink(DirSink(dir), name); err == nil {
+			t.Errorf("name %q: got nil error", name)
+		}
+	}
+}
+
+func TestGenerateAllTo(t *testing.T) {
+	var items []Item
+	for i := 0; i < 20; i++ {
+		items = append(items, Item{Content: fmt.Sprint(i), Filename: fmt.Sprintf("%d.txt", i)})
+	}
+
+	var mu sync.Mutex
+	got := map[string]string{}
+	sink := SinkFunc(func(name, contentType string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got[name] = string(data)
+		return nil
+	})
+
+	if err := GenerateAllTo(sink, items, 4, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range items {
+		q, err := New(item.Content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[item.Filename] != q.ToString(false) {
+			t.Errorf("%s: unexpected content", item.Filename)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateAllTo(WriterSink(&buf), items, 4, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected int
+	for _, data := range got {
+		expected += len(data)
+	}
+	if buf.Len() != expected {
+		t.Errorf("writer sink got %d bytes, expected %d", buf.Len(), expected)
+	}
+}
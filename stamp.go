@@ -0,0 +1,40 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/draw"
+)
+
+// drawStamp draws the Stamp text into the bottom right corner of the quiet
+// zone of img. The stamp is omitted if the quiet zone is too small to hold it
+// without touching the symbol.
+func (q *QRCode) drawStamp(img draw.Image) {
+	if q.stamp == "" {
+		return
+	}
+
+	b := img.Bounds()
+	symbolRect := q.symbolRect(b)
+
+	// Keep a gap of at least one pixel from both the symbol and the edge.
+	space := b.Max.Y - symbolRect.Max.Y - 2
+	if space < captionFace.Height {
+		return
+	}
+
+	textScale := max(1, min(space/captionFace.Height, b.Dx()/captionPixelsPerScale))
+	padding := (space - captionFace.Height*textScale) / 2
+
+	text := elide(q.stamp, (b.Dx()-2)/(captionGlyphWidth*textScale))
+	width := len([]rune(text)) * captionGlyphWidth * textScale
+
+	at := image.Pt(b.Max.X-1-padding-width, symbolRect.Max.Y+1+padding)
+	if at.X < b.Min.X+1 {
+		at.X = b.Min.X + 1
+	}
+
+	drawText(img, text, at, textScale, image.NewUniform(q.ForegroundColor))
+}
@@ -0,0 +1,109 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSequenceStamp(t *testing.T) {
+	tests := []struct {
+		i, n     int
+		expected string
+	}{
+		{0, 1, "1/1"},
+		{41, 500, "042/500"},
+		{499, 500, "500/500"},
+		{8, 10, "09/10"},
+	}
+
+	for _, test := range tests {
+		if got := sequenceStamp(test.i, test.n); got != test.expected {
+			t.Errorf("sequenceStamp(%d, %d) = %q, expected %q", test.i, test.n, got, test.expected)
+		}
+	}
+}
+
+func TestNewBatchStampSequence(t *testing.T) {
+	codes, err := NewBatch([]string{"a", "b", "c"}, StampSequence())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for i, q := range codes {
+		if expected := sequenceStamp(i, 3); q.stamp != expected {
+			t.Errorf("code %d has stamp %q, expected %q", i, q.stamp, expected)
+		}
+	}
+
+	codes, err = NewBatch([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if codes[0].stamp != "" {
+		t.Errorf("got stamp %q without StampSequence, expected none", codes[0].stamp)
+	}
+}
+
+func TestStamp(t *testing.T) {
+	plain, err := New("https://example.org", Width(256), Height(256), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	stamped, err := New("https://example.org", Width(256), Height(256), Margin(4), Stamp("042/500"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	a := plain.Image()
+	b := stamped.Image()
+	if a.Bounds() != b.Bounds() {
+		t.Fatalf("stamped image is %v, expected %v", b.Bounds(), a.Bounds())
+	}
+
+	symbolRect := stamped.symbolRect(b.Bounds())
+	corner := image.Rect(b.Bounds().Dx()/2, symbolRect.Max.Y, b.Bounds().Dx(), b.Bounds().Dy())
+
+	changed := 0
+	for y := 0; y < b.Bounds().Dy(); y++ {
+		for x := 0; x < b.Bounds().Dx(); x++ {
+			if color.RGBAModel.Convert(a.At(x, y)) == color.RGBAModel.Convert(b.At(x, y)) {
+				continue
+			}
+
+			if !image.Pt(x, y).In(corner) {
+				t.Fatalf("stamp changed pixel (%d, %d) outside the bottom right quiet zone", x, y)
+			}
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		t.Error("stamp not drawn")
+	}
+}
+
+func TestStampNoQuietZone(t *testing.T) {
+	plain, err := New("https://example.org", Width(256), Height(256))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	stamped, err := New("https://example.org", Width(256), Height(256), Stamp("1/2"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	a := plain.Image()
+	b := stamped.Image()
+
+	for y := 0; y < b.Bounds().Dy(); y++ {
+		for x := 0; x < b.Bounds().Dx(); x++ {
+			if color.RGBAModel.Convert(a.At(x, y)) != color.RGBAModel.Convert(b.At(x, y)) {
+				t.Fatalf("stamp drawn at (%d, %d) without room in the quiet zone", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package qrcode
+
+// Stats describes a generated QR Code, for diagnostics.
+type Stats struct {
+	// Version number (1-40 inclusive).
+	Version int
+
+	// Error recovery level.
+	Level RecoveryLevel
+
+	// Data mask pattern (0-7 inclusive).
+	MaskPattern int
+
+	// Penalty score of the chosen data mask. Lower is better.
+	Penalty int
+
+	// Width/height of the symbol in modules, excluding the quiet zone.
+	ModuleCount int
+
+	// Width of a single quiet zone in modules.
+	QuietZoneSize int
+
+	// Number of blocks the data is split into for error correction.
+	NumBlocks int
+
+	// Number of data and error correction codewords.
+	DataCodewords            int
+	ErrorCorrectionCodewords int
+
+	// Number of data bits used by the content, and the number of data bits
+	// left unused (filled with padding).
+	ContentBits       int
+	RemainingDataBits int
+
+	// Number of erroneous codewords which can be corrected, if spread evenly
+	// across the blocks. Twice as many erasures (codewords known to be
+	// damaged, e.g. covered by a logo) can be corrected.
+	CorrectableCodewords int
+}
+
+// PenaltyBreakdown is the penalty score of a data mask, split into the four
+// ISO/IEC 18004 rules. The mask with the lowest total is chosen.
+type PenaltyBreakdown struct {
+	// Data mask pattern (0-7 inclusive).
+	Mask int
+
+	// Rule 1: runs of 5 or more modules of the same color in a row or column.
+	Rule1 int
+	// Rule 2: 2x2 blocks of modules of the same color.
+	Rule2 int
+	// Rule 3: finder-like 1:1:3:1:1 patterns next to a light area.
+	Rule3 int
+	// Rule 4: deviation of the proportion of dark modules from 50%.
+	Rule4 int
+
+	// Sum of the four rules.
+	Total int
+
+	// Whether the mask was chosen.
+	Chosen bool
+}
+
+// MaskPenalties returns the penalty scores of all eight data masks, as
+// evaluated when the QR Code was encoded, to diagnose mask selection.
+func (q *QRCode) MaskPenalties() [8]PenaltyBreakdown {
+	return q.maskPenalties
+}
+
+// MaskPattern returns the data mask pattern (0-7 inclusive) used.
+func (q *QRCode) MaskPattern() int {
+	return q.mask
+}
+
+// RecoveryLevel returns the error recovery level used.
+func (q *QRCode) RecoveryLevel() RecoveryLevel {
+	return q.level
+}
+
+// ModuleCount returns the width/height of the symbol in modules, excluding
+// the quiet zone.
+func (q *QRCode) ModuleCount() int {
+	return q.version.symbolSize()
+}
+
+// DataCodewords returns the number of data codewords in the symbol.
+func (q *QRCode) DataCodewords() int {
+	return q.version.numDataBits() / 8
+}
+
+// Stats returns diagnostics about the QR Code.
+func (q *QRCode) Stats() Stats {
+	s := Stats{
+		Version:     q.VersionNumber,
+		Level:       q.level,
+		MaskPattern: q.mask,
+		Penalty:     q.penalty,
+
+		ModuleCount:   q.ModuleCount(),
+		QuietZoneSize: q.symbol.quietZoneSize,
+
+		NumBlocks:     q.version.numBlocks(),
+		DataCodewords: q.DataCodewords(),
+
+		ContentBits:       q.contentBits,
+		RemainingDataBits: q.version.numDataBits() - q.contentBits,
+	}
+
+	p := q.version.numMisdecodeProtectionCodewords()
+	for _, b := range q.version.block {
+		numErrorCodewords := b.numCodewords - b.numDataCodewords
+
+		s.ErrorCorrectionCodewords += b.numBlocks * numErrorCodewords
+		s.CorrectableCodewords += b.numBlocks * ((numErrorCodewords - p) / 2)
+	}
+
+	return s
+}
@@ -0,0 +1,102 @@
+package qrcode
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	// ISO/IEC 18004 Annex I example: version 1-M, mask 2.
+	q, err := New("01234567", Level(Medium), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	s := q.Stats()
+
+	expected := Stats{
+		Version:                  1,
+		Level:                    Medium,
+		MaskPattern:              2,
+		Penalty:                  q.penalty,
+		ModuleCount:              21,
+		QuietZoneSize:            4,
+		NumBlocks:                1,
+		DataCodewords:            16,
+		ErrorCorrectionCodewords: 10,
+		// Mode indicator (4) + character count (10) + 8 digits (27).
+		ContentBits:       41,
+		RemainingDataBits: 16*8 - 41,
+		// (10 - 2 misdecode protection codewords) / 2.
+		CorrectableCodewords: 4,
+	}
+
+	if s != expected {
+		t.Errorf("got %+v, expected %+v", s, expected)
+	}
+
+	if q.MaskPattern() != 2 || q.RecoveryLevel() != Medium || q.ModuleCount() != 21 || q.DataCodewords() != 16 {
+		t.Errorf("getters disagree with Stats %+v", s)
+	}
+}
+
+func TestStatsCorrectableCodewords(t *testing.T) {
+	tests := []struct {
+		version  int
+		level    RecoveryLevel
+		expected int
+	}{
+		// 1 block of 26 codewords, 7 error correction, 3 misdecode protection.
+		{1, Low, 2},
+		// 1 block of 26 codewords, 17 error correction, 1 misdecode protection.
+		{1, Highest, 8},
+		// 2 blocks of 11+22 and 2 of 12+22 codewords.
+		{5, Highest, 4 * 11},
+		// 19 blocks of 118+30 and 6 of 119+30.
+		{40, Low, 25 * 15},
+	}
+
+	for _, test := range tests {
+		q, err := newWithForcedVersion("A", test.version, test.level)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if got := q.Stats().CorrectableCodewords; got != test.expected {
+			t.Errorf("version %d-%s: got %d correctable codewords, expected %d",
+				test.version, test.level, got, test.expected)
+		}
+	}
+}
+
+func TestMaskPenalties(t *testing.T) {
+	q, err := New("01234567", Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chosen := 0
+	for mask, b := range q.MaskPenalties() {
+		if b.Mask != mask {
+			t.Errorf("mask %d: got Mask %d", mask, b.Mask)
+		}
+		if b.Total != b.Rule1+b.Rule2+b.Rule3+b.Rule4 {
+			t.Errorf("mask %d: total %d is not the sum of %+v", mask, b.Total, b)
+		}
+		if b.Total < q.penalty {
+			t.Errorf("mask %d: total %d is lower than the chosen penalty %d", mask, b.Total, q.penalty)
+		}
+		if b.Chosen {
+			chosen++
+			if mask != q.MaskPattern() || b.Total != q.penalty {
+				t.Errorf("mask %d chosen, expected mask %d penalty %d", mask, q.MaskPattern(), q.penalty)
+			}
+		}
+	}
+	if chosen != 1 {
+		t.Errorf("%d masks chosen, expected 1", chosen)
+	}
+
+	// The ISO/IEC 18004 Annex I example, mask 2.
+	b := q.MaskPenalties()[2]
+	if b.Rule1 != 128 || b.Rule2 != 111 || b.Rule3 != 720 || b.Rule4 != 0 {
+		t.Errorf("got %+v", b)
+	}
+}
@@ -0,0 +1,128 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// StitchGrid is the Bitmap of a QR Code as a chart for embroidery and
+// cross-stitch, one stitch per module. Export it with JSON or CSV.
+type StitchGrid struct {
+	// Size of the grid in stitches, including the quiet zone.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// Stitches per inch, e.g. 14 for 14 count Aida, and the finished size of
+	// the grid in millimetres.
+	ModulesPerInch float64 `json:"modules_per_inch"`
+	WidthMM        float64 `json:"width_mm"`
+	HeightMM       float64 `json:"height_mm"`
+
+	// Threads used by the grid: the background, then the foreground.
+	Threads []Thread `json:"threads"`
+
+	// Rows of the grid, top to bottom, each a string of one Thread Symbol
+	// per stitch.
+	Rows []string `json:"rows"`
+}
+
+// Thread is a thread color of a StitchGrid.
+type Thread struct {
+	// Symbol marking the thread's stitches in the grid: "." for the
+	// background and "X" for the foreground.
+	Symbol string `json:"symbol"`
+
+	// "background" or "foreground".
+	Role string `json:"role"`
+
+	// Color hint, "#rrggbb", from the BackgroundColor and ForegroundColor.
+	Color string `json:"color"`
+
+	// Number of stitches in the thread, to estimate the thread needed.
+	Stitches int `json:"stitches"`
+}
+
+// StitchGrid returns the Bitmap of the QR Code as a chart stitched at
+// modulesPerInch. The quiet zone is included, so set a Margin unless the
+// fabric around the code is left plain.
+func (q *QRCode) StitchGrid(modulesPerInch float64) (StitchGrid, error) {
+	if modulesPerInch <= 0 {
+		return StitchGrid{}, errors.New("stitch: modules per inch must be positive")
+	}
+
+	width, rows := q.PackedBitmap()
+	g := StitchGrid{
+		Width:          width,
+		Height:         len(rows),
+		ModulesPerInch: modulesPerInch,
+		Threads: []Thread{
+			{Symbol: ".", Role: "background", Color: hexColor(q.BackgroundColor)},
+			{Symbol: "X", Role: "foreground", Color: hexColor(q.ForegroundColor)},
+		},
+	}
+	g.WidthMM = mmRound(float64(g.Width) * 25.4 / modulesPerInch)
+	g.HeightMM = mmRound(float64(g.Height) * 25.4 / modulesPerInch)
+
+	for _, row := range rows {
+		var b strings.Builder
+		for x := 0; x < width; x++ {
+			thread := &g.Threads[0]
+			if row[x/8]&(0x80>>uint(x%8)) != 0 {
+				thread = &g.Threads[1]
+			}
+			thread.Stitches++
+			b.WriteString(thread.Symbol)
+		}
+		g.Rows = append(g.Rows, b.String())
+	}
+
+	return g, nil
+}
+
+// JSON returns g as indented JSON.
+func (g StitchGrid) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// CSV returns g as CSV: a record of the Thread Symbol of each stitch for each
+// row, as read by spreadsheets and charting software. The records follow a
+// header of "#" comment lines giving the size, spacing and threads, which
+// csv.Reader skips with Comment set to '#'.
+func (g StitchGrid) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %dx%d stitches, %g per inch, %gx%gmm\n",
+		g.Width, g.Height, g.ModulesPerInch, g.WidthMM, g.HeightMM)
+	for _, t := range g.Threads {
+		fmt.Fprintf(&buf, "# %s %s %s, %d stitches\n", t.Symbol, t.Role, t.Color, t.Stitches)
+	}
+
+	w := csv.NewWriter(&buf)
+	for _, row := range g.Rows {
+		if err := w.Write(strings.Split(row, "")); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// hexColor returns c as "#rrggbb", ignoring its alpha.
+func hexColor(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B)
+}
+
+// mmRound returns v rounded to 0.01mm.
+func mmRound(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,114 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestStitchGrid(t *testing.T) {
+	q, err := New("https://example.org", Margin(4), ForegroundColor(color.RGBA{0x12, 0x34, 0x56, 0xff}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := q.StitchGrid(14)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitmap := q.Bitmap()
+	size := len(bitmap)
+	if g.Width != size || g.Height != size || len(g.Rows) != size {
+		t.Fatalf("got %dx%d grid with %d rows, expected %dx%d", g.Width, g.Height, len(g.Rows), size, size)
+	}
+	if expected := mmRound(float64(size) * 25.4 / 14); g.WidthMM != expected || g.HeightMM != expected {
+		t.Errorf("got %vx%vmm, expected %vmm square", g.WidthMM, g.HeightMM, expected)
+	}
+
+	if g.Threads[0].Color != "#ffffff" || g.Threads[1].Color != "#123456" {
+		t.Errorf("got thread colors %s and %s", g.Threads[0].Color, g.Threads[1].Color)
+	}
+	if total := g.Threads[0].Stitches + g.Threads[1].Stitches; total != size*size {
+		t.Errorf("got %d stitches, expected %d", total, size*size)
+	}
+
+	for y, row := range bitmap {
+		for x, v := range row {
+			expected := "."
+			if v {
+				expected = "X"
+			}
+			if got := g.Rows[y][x : x+1]; got != expected {
+				t.Fatalf("stitch (%d, %d): got %q, expected %q", x, y, got, expected)
+			}
+		}
+	}
+
+	if _, err := q.StitchGrid(0); err == nil {
+		t.Error("StitchGrid(0) succeeded, expected an error")
+	}
+}
+
+func TestStitchGridExport(t *testing.T) {
+	q, err := New("0", Margin(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := q.StitchGrid(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded StitchGrid
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ModulesPerInch != 11 || decoded.Rows[0] != g.Rows[0] || decoded.Threads[1].Role != "foreground" {
+		t.Errorf("JSON round trip lost data:\n%s", data)
+	}
+
+	data, err = g.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	header := []string{
+		"# 23x23 stitches, 11 per inch, 53.11x53.11mm",
+		fmt.Sprintf("# . background #ffffff, %d stitches", g.Threads[0].Stitches),
+		fmt.Sprintf("# X foreground #000000, %d stitches", g.Threads[1].Stitches),
+	}
+	if len(lines) != len(header)+g.Height {
+		t.Fatalf("got %d CSV lines, expected %d", len(lines), len(header)+g.Height)
+	}
+	for i, expected := range header {
+		if lines[i] != expected {
+			t.Errorf("got CSV header line %q, expected %q", lines[i], expected)
+		}
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != g.Height {
+		t.Fatalf("got %d CSV records, expected %d", len(records), g.Height)
+	}
+	if got := strings.Join(records[1], ""); got != g.Rows[1] {
+		t.Errorf("got CSV record %q, expected %q", got, g.Rows[1])
+	}
+}
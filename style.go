@@ -0,0 +1,133 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+
+	"github.com/yougg/go-qrcode/styles"
+)
+
+// Style applies the preset s, from the styles package: its colors, quiet zone
+// and module and finder pattern shapes. Options given after Style override
+// its settings.
+func Style(s styles.Style) Option {
+	return func(q *QRCode) {
+		if s.Foreground != nil {
+			q.ForegroundColor = s.Foreground
+		}
+		if s.Background != nil {
+			q.BackgroundColor = s.Background
+		}
+		q.margin = s.QuietZone
+		q.moduleShape = s.Modules
+		q.eyeShape = s.Eyes
+	}
+}
+
+// ModuleShape draws the dark data modules in shape s.
+func ModuleShape(s styles.Shape) Option {
+	return func(q *QRCode) {
+		q.moduleShape = s
+	}
+}
+
+// EyeShape draws the three finder patterns in shape s.
+func EyeShape(s styles.Shape) Option {
+	return func(q *QRCode) {
+		q.eyeShape = s
+	}
+}
+
+// logoShape returns the shapeMask shape for s.
+func logoShape(s styles.Shape) LogoShape {
+	switch s {
+	case styles.RoundedSquare:
+		return LogoRoundedRect
+	case styles.Circle:
+		return LogoCircle
+	}
+	return LogoSquare
+}
+
+// drawModule draws a dark module occupying r in img, in q.moduleShape.
+func (q *QRCode) drawModule(img *image.Paletted, r image.Rectangle) {
+	mask := &shapeMask{r, logoShape(q.moduleShape), min(r.Dx(), r.Dy()) / 3}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if q.moduleShape == styles.Square || mask.contains(x, y) {
+				img.Set(x, y, q.ForegroundColor)
+			}
+		}
+	}
+}
+
+// isEye returns true if the module at (x, y) of the symbol bitmap is part of a
+// finder pattern drawn by drawEyes.
+func (q *QRCode) isEye(x, y int) bool {
+	if q.eyeShape == styles.Square {
+		return false
+	}
+
+	for _, origin := range q.eyeOrigins() {
+		if (image.Point{x, y}).In(image.Rect(origin.X, origin.Y, origin.X+finderPatternSize, origin.Y+finderPatternSize)) {
+			return true
+		}
+	}
+	return false
+}
+
+// eyeOrigins returns the top left module of each finder pattern in the symbol
+// bitmap.
+func (q *QRCode) eyeOrigins() []image.Point {
+	qz := q.symbol.quietZoneSize
+	far := qz + q.version.symbolSize() - finderPatternSize
+
+	return []image.Point{{qz, qz}, {far, qz}, {qz, far}}
+}
+
+// drawEyes draws the finder patterns in q.eyeShape, as a dark 7x7 outline
+// around a dark 3x3 center. Nothing is drawn for square eyes, which are
+// drawn as ordinary modules.
+func (q *QRCode) drawEyes(img *image.Paletted, pixelsPerModuleX, pixelsPerModuleY, offsetX, offsetY int) {
+	if q.eyeShape == styles.Square {
+		return
+	}
+
+	shape := logoShape(q.eyeShape)
+	module := min(pixelsPerModuleX, pixelsPerModuleY)
+
+	for _, origin := range q.eyeOrigins() {
+		outer := image.Rect(
+			offsetX+origin.X*pixelsPerModuleX,
+			offsetY+origin.Y*pixelsPerModuleY,
+			offsetX+(origin.X+finderPatternSize)*pixelsPerModuleX,
+			offsetY+(origin.Y+finderPatternSize)*pixelsPerModuleY,
+		)
+		ring := image.Rect(outer.Min.X+pixelsPerModuleX, outer.Min.Y+pixelsPerModuleY,
+			outer.Max.X-pixelsPerModuleX, outer.Max.Y-pixelsPerModuleY)
+		center := image.Rect(ring.Min.X+pixelsPerModuleX, ring.Min.Y+pixelsPerModuleY,
+			ring.Max.X-pixelsPerModuleX, ring.Max.Y-pixelsPerModuleY)
+
+		masks := []*shapeMask{
+			{outer, shape, 2 * module},
+			{ring, shape, 3 * module / 2},
+			{center, shape, module},
+		}
+
+		for y := outer.Min.Y; y < outer.Max.Y; y++ {
+			for x := outer.Min.X; x < outer.Max.X; x++ {
+				switch {
+				case masks[2].contains(x, y):
+					img.Set(x, y, q.ForegroundColor)
+				case masks[1].contains(x, y):
+					img.Set(x, y, q.BackgroundColor)
+				case masks[0].contains(x, y):
+					img.Set(x, y, q.ForegroundColor)
+				}
+			}
+		}
+	}
+}
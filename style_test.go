@@ -0,0 +1,113 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/yougg/go-qrcode/styles"
+)
+
+func TestStyleClassic(t *testing.T) {
+	plain, err := New("https://example.org", Width(132), Height(132), Margin(4))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	styled, err := New("https://example.org", Width(132), Height(132), Style(styles.Classic))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if n := countDifferentPixels(plain.Image(), styled.Image()); n != 0 {
+		t.Errorf("Classic style changed %d pixels", n)
+	}
+}
+
+func TestStyleInverted(t *testing.T) {
+	q, err := New("https://example.org", Style(styles.Inverted), Width(100), Height(100))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// The quiet zone is the background color.
+	if got := color.RGBAModel.Convert(q.Image().At(0, 0)); got != color.RGBAModel.Convert(color.Black) {
+		t.Errorf("quiet zone is %v, expected black", got)
+	}
+}
+
+func TestStyleShapes(t *testing.T) {
+	const size = 33 * 8
+
+	classic, err := New("https://example.org", Style(styles.Classic), Width(size), Height(size))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(classic.Bitmap()) != 33 {
+		t.Fatalf("symbol is %d modules, expected 33", len(classic.Bitmap()))
+	}
+
+	for _, s := range []styles.Style{styles.Rounded, styles.Dots, styles.SocialBadge} {
+		q, err := New("https://example.org", Style(s), Width(size), Height(size), Margin(4),
+			ForegroundColor(color.Black))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		img := q.Image()
+		dark := color.RGBAModel.Convert(color.Black)
+
+		// Shaped modules cover less than square modules, but every dark
+		// module's center is still dark. Eyes are checked below.
+		if countDark(img) >= countDark(classic.Image()) {
+			t.Errorf("%s: shaped modules are no smaller than squares", s.Name)
+		}
+
+		for y, row := range q.Bitmap() {
+			for x, v := range row {
+				if q.isEye(x, y) {
+					continue
+				}
+
+				center := color.RGBAModel.Convert(img.At(x*8+4, y*8+4))
+				if v != (center == dark) {
+					t.Fatalf("%s: module (%d, %d) center is %v, expected dark=%t", s.Name, x, y, center, v)
+				}
+			}
+		}
+
+		// The eyes are rounded: the top left corner of the top left finder
+		// pattern is light.
+		if got := color.RGBAModel.Convert(img.At(4*8, 4*8)); got == dark {
+			t.Errorf("%s: eye corner is dark, expected rounded", s.Name)
+		}
+
+		// The eye occupies modules 4-10: its inner ring is light and its
+		// center dark.
+		for _, m := range []struct {
+			x, y int
+			dark bool
+		}{{5, 7, false}, {9, 7, false}, {7, 7, true}, {6, 8, true}} {
+			got := color.RGBAModel.Convert(img.At(m.x*8+4, m.y*8+4))
+			if (got == dark) != m.dark {
+				t.Errorf("%s: eye module (%d, %d) is %v, expected dark=%t", s.Name, m.x, m.y, got, m.dark)
+			}
+		}
+	}
+}
+
+// countDark returns the number of black pixels in img.
+func countDark(img image.Image) int {
+	n := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == color.RGBAModel.Convert(color.Black) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,149 @@
+// Package styles provides named rendering presets for QR Codes, bundling
+// module shape, finder pattern ("eye") shape, colors and quiet zone.
+//
+// Select a preset with the qrcode.Style option:
+//
+//	q, err := qrcode.New("https://example.org", qrcode.Style(styles.Rounded))
+package styles
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Shape is the shape modules or finder patterns are drawn with.
+type Shape int
+
+const (
+	// Square is the traditional shape.
+	Square Shape = iota
+
+	// RoundedSquare has rounded corners.
+	RoundedSquare
+
+	// Circle draws modules as dots, and finder patterns as concentric
+	// circles.
+	Circle
+)
+
+// shapeNames are the names of each Shape, as used by String and ParseShape.
+var shapeNames = []string{"square", "rounded", "circle"}
+
+// String returns the name of s: square, rounded or circle.
+func (s Shape) String() string {
+	if s < 0 || int(s) >= len(shapeNames) {
+		return fmt.Sprintf("Shape(%d)", int(s))
+	}
+	return shapeNames[s]
+}
+
+// ParseShape returns the Shape named name (square, rounded or circle),
+// ignoring case.
+func ParseShape(name string) (Shape, error) {
+	for i, n := range shapeNames {
+		if strings.EqualFold(n, name) {
+			return Shape(i), nil
+		}
+	}
+	return Square, fmt.Errorf("unknown shape %q (expected square, rounded or circle)", name)
+}
+
+// Style is a rendering preset.
+type Style struct {
+	// Name of the preset, as accepted by ByName.
+	Name string
+
+	// Shape of each dark data module.
+	Modules Shape
+
+	// Shape of the three finder patterns.
+	Eyes Shape
+
+	// Colors of the dark and light modules.
+	Foreground color.Color
+	Background color.Color
+
+	// Width of the quiet zone, in modules.
+	QuietZone int
+}
+
+var (
+	// Classic is black square modules on white, as in ISO/IEC 18004.
+	Classic = Style{
+		Name:       "Classic",
+		Modules:    Square,
+		Eyes:       Square,
+		Foreground: color.Black,
+		Background: color.White,
+		QuietZone:  4,
+	}
+
+	// Rounded softens the modules and eyes with rounded corners.
+	Rounded = Style{
+		Name:       "Rounded",
+		Modules:    RoundedSquare,
+		Eyes:       RoundedSquare,
+		Foreground: color.Black,
+		Background: color.White,
+		QuietZone:  4,
+	}
+
+	// Dots draws the data modules as dots, keeping rounded eyes for reliable
+	// scanning.
+	Dots = Style{
+		Name:       "Dots",
+		Modules:    Circle,
+		Eyes:       RoundedSquare,
+		Foreground: color.Black,
+		Background: color.White,
+		QuietZone:  4,
+	}
+
+	// Inverted is white on black, for dark designs. Not every scanner reads
+	// inverted codes.
+	Inverted = Style{
+		Name:       "Inverted",
+		Modules:    Square,
+		Eyes:       Square,
+		Foreground: color.White,
+		Background: color.Black,
+		QuietZone:  4,
+	}
+
+	// HighContrastPrint is black square modules on white with a generous
+	// quiet zone, to survive trimming and poor print conditions.
+	HighContrastPrint = Style{
+		Name:       "HighContrastPrint",
+		Modules:    Square,
+		Eyes:       Square,
+		Foreground: color.Black,
+		Background: color.White,
+		QuietZone:  6,
+	}
+
+	// SocialBadge is blue dots with rounded eyes and a narrow quiet zone, for
+	// profile badges and share cards.
+	SocialBadge = Style{
+		Name:       "SocialBadge",
+		Modules:    Circle,
+		Eyes:       RoundedSquare,
+		Foreground: color.NRGBA{0x1a, 0x73, 0xe8, 0xff},
+		Background: color.White,
+		QuietZone:  2,
+	}
+)
+
+// All lists every preset.
+var All = []Style{Classic, Rounded, Dots, Inverted, HighContrastPrint, SocialBadge}
+
+// ByName returns the preset named name, ignoring case.
+func ByName(name string) (Style, bool) {
+	for _, s := range All {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+
+	return Style{}, false
+}
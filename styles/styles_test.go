@@ -0,0 +1,47 @@
+package styles
+
+import (
+	"testing"
+)
+
+func TestByName(t *testing.T) {
+	for _, s := range All {
+		for _, name := range []string{s.Name, "  " + s.Name, ""} {
+			got, ok := ByName(name)
+			if name == s.Name {
+				if !ok || got.Name != s.Name {
+					t.Errorf("ByName(%q) = %q, %t, expected %q", name, got.Name, ok, s.Name)
+				}
+			} else if ok {
+				t.Errorf("ByName(%q) succeeded, expected failure", name)
+			}
+		}
+
+		if s.Foreground == nil || s.Background == nil || s.QuietZone < 1 {
+			t.Errorf("preset %q is incomplete", s.Name)
+		}
+	}
+
+	if got, ok := ByName("socialbadge"); !ok || got.Name != "SocialBadge" {
+		t.Errorf("ByName is case sensitive")
+	}
+}
+
+func TestParseShape(t *testing.T) {
+	for _, s := range []Shape{Square, RoundedSquare, Circle} {
+		got, err := ParseShape(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseShape(%q) = %v, %v, expected %v", s.String(), got, err, s)
+		}
+	}
+
+	if got, err := ParseShape("CIRCLE"); err != nil || got != Circle {
+		t.Errorf("ParseShape is case sensitive")
+	}
+	if _, err := ParseShape("hexagon"); err == nil {
+		t.Error("ParseShape(\"hexagon\") succeeded, expected error")
+	}
+	if got := Shape(7).String(); got != "Shape(7)" {
+		t.Errorf("got %q, expected Shape(7)", got)
+	}
+}
@@ -0,0 +1,110 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"time"
+)
+
+// renderSVG is the svg Renderer. The dark modules are drawn as a single path
+// of horizontal runs, in a viewBox of one unit per module.
+//
+// For accessibility the image has role="img", and a <title> and <desc>
+// describing the code and its content for screen readers.
+func renderSVG(sym SymbolView, opts RenderOptions) ([]byte, error) {
+	start := time.Now()
+
+	n := sym.Size()
+
+	// Deeper edges of QuietZone extend the viewBox beyond the symbol.
+	top, right, bottom, left := sym.QuietZoneExtra()
+	width, height := left+n+right, top+n+bottom
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%d %d %d %d"`, -left, -top, width, height)
+	if opts.ModuleSize > 0 {
+		fmt.Fprintf(&buf, ` width="%d" height="%d"`, width*opts.ModuleSize, height*opts.ModuleSize)
+	}
+	title := opts.Title
+	if title == "" {
+		title = sym.q.caption
+	}
+	if title == "" {
+		title = "QR Code"
+	}
+	desc := "QR Code encoding: " + sym.Content()
+	if opts.RedactContent {
+		desc = "QR Code (content redacted)"
+	}
+
+	fmt.Fprintf(&buf, ` role="img" aria-label="%s" shape-rendering="crispEdges">`+"\n", svgEscape(title))
+	fmt.Fprintf(&buf, "<title>%s</title>\n<desc>%s</desc>\n", svgEscape(title), svgEscape(desc))
+
+	if bg := svgFill(sym.BackgroundColor()); bg != "" {
+		if left > 0 || top > 0 {
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d"%s/>`+"\n", -left, -top, width, height, bg)
+		} else {
+			fmt.Fprintf(&buf, `<rect width="%d" height="%d"%s/>`+"\n", width, height, bg)
+		}
+	}
+
+	fg := svgFill(sym.ForegroundColor())
+	if fg == "" {
+		fg = ` fill="none"`
+	}
+	fmt.Fprintf(&buf, `<path%s d="`, fg)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if !sym.Dark(x, y) {
+				continue
+			}
+
+			run := x
+			for run < n && sym.Dark(run, y) {
+				run++
+			}
+
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", x, y, run-x, run-x)
+			x = run
+		}
+	}
+	buf.WriteString(`"/>` + "\n</svg>\n")
+
+	if sym.q.metrics != nil {
+		sym.q.metrics.Rendered("svg", time.Since(start), buf.Len())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// svgEscape returns s escaped for use in XML text and attribute values.
+func svgEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+// svgFill returns the fill attributes for c, or "" if c is fully
+// transparent.
+func svgFill(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nrgba.A == 0 {
+		return ""
+	}
+
+	fill := fmt.Sprintf(` fill="#%02x%02x%02x"`, nrgba.R, nrgba.G, nrgba.B)
+	if nrgba.A != 0xff {
+		fill += fmt.Sprintf(` fill-opacity="%s"`, pdfNumber(float64(nrgba.A)/0xff))
+	}
+
+	return fill
+}
@@ -110,7 +110,7 @@ func (m *symbol) bitmap() [][]bool {
 	module := make([][]bool, len(m.module))
 
 	for i := range m.module {
-		module[i] = m.module[i][:]
+		module[i] = append([]bool(nil), m.module[i]...)
 	}
 
 	return module
@@ -152,6 +152,24 @@ func (m *symbol) penaltyScore() int {
 	return m.penalty1() + m.penalty2() + m.penalty3() + m.penalty4()
 }
 
+// penaltyBreakdown returns the individual penalty scores of the symbol, using
+// penalty3Legacy if legacy is set. The Mask field is not set.
+func (m *symbol) penaltyBreakdown(legacy bool) PenaltyBreakdown {
+	b := PenaltyBreakdown{
+		Rule1: m.penalty1(),
+		Rule2: m.penalty2(),
+		Rule4: m.penalty4(),
+	}
+	if legacy {
+		b.Rule3 = m.penalty3Legacy()
+	} else {
+		b.Rule3 = m.penalty3()
+	}
+	b.Total = b.Rule1 + b.Rule2 + b.Rule3 + b.Rule4
+
+	return b
+}
+
 // penalty1 returns the penalty score for "adjacent modules in row/column with
 // same colour".
 //
@@ -232,10 +250,63 @@ func (m *symbol) penalty2() int {
 // (dark:light:dark:light:dark) pattern in row/column, preceded or followed by
 // light area 4 modules wide".
 //
-// Existence of the pattern scores penaltyWeight3.
+// The pattern may be any multiple n of the ratio: dark runs of n, 3n and n
+// modules separated by light runs of n modules. Each pattern with a light area
+// at least 4n modules wide on either side scores penaltyWeight3, and the area
+// outside the symbol counts as light.
 func (m *symbol) penalty3() int {
 	penalty := 0
 
+	for i := 0; i < m.symbolSize; i++ {
+		penalty += finderLikePatterns(m.symbolSize, func(j int) bool { return m.get(j, i) })
+		penalty += finderLikePatterns(m.symbolSize, func(j int) bool { return m.get(i, j) })
+	}
+
+	return penalty * penaltyWeight3
+}
+
+// finderLikePatterns returns the number of penalty3 patterns in a row or
+// column of size modules.
+func finderLikePatterns(size int, get func(i int) bool) int {
+	// runs are the lengths of alternating light and dark runs of modules,
+	// starting and ending with light runs extended by the area outside the
+	// symbol.
+	runs := []int{size}
+	for i := 0; i < size; i++ {
+		if get(i) != (len(runs)%2 == 0) {
+			runs = append(runs, 0)
+		}
+		runs[len(runs)-1]++
+	}
+	if len(runs)%2 == 0 {
+		runs = append(runs, 0)
+	}
+	runs[len(runs)-1] += size
+
+	count := 0
+	for i := 1; i+5 < len(runs); i += 2 {
+		n := runs[i]
+		if runs[i+1] != n || runs[i+2] != 3*n || runs[i+3] != n || runs[i+4] != n {
+			continue
+		}
+
+		before, after := runs[i-1], runs[i+5]
+		if (before >= 4*n && after >= n) || (after >= 4*n && before >= n) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// penalty3Legacy returns the rule 3 penalty score as computed before penalty3
+// was corrected, see LegacyMaskPenalty. Only single module wide patterns are
+// found, a pattern with light areas on both sides scores once, and the light
+// area may only extend past the edge of the symbol before the pattern, or
+// directly after it.
+func (m *symbol) penalty3Legacy() int {
+	penalty := 0
+
 	for y := 0; y < m.symbolSize; y++ {
 		var bitBuffer int16 = 0x00
 
@@ -332,3 +332,101 @@ func TestSymbolPenalties(t *testing.T) {
 		}
 	}
 }
+
+func TestPenalty3Patterns(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected int
+	}{
+		// Light area before, after, or both: each pattern scores once.
+		{"00001011101", 1},
+		{"10111010000", 1},
+		{"000010111010000", 1},
+		{"0000101110100001011101", 2},
+
+		// The area outside the symbol is light.
+		{"1011101", 1},
+		{"10111010", 1},
+		{"010111010", 1},
+		{"101110100", 1},
+		{"1011101000", 1},
+
+		// Fewer than 4 light modules on both sides.
+		{"1110111010001", 0},
+		{"10001011101000101", 0},
+
+		// Not a 1:1:3:1:1 ratio.
+		{"000010011010000", 0},
+		{"000011011101", 0},
+		{"0000101111010000", 0},
+		{"1100000000", 0},
+
+		// Any multiple of the ratio, with a light area of 4n.
+		{"00000000110011111100110", 1},
+		{"1100111111001100000000", 1},
+		{"111000111111111000111000000000000", 1},
+		{"100000011001111110011000000100", 0},
+		{"0000000110011111100111", 0},
+	}
+
+	for _, test := range tests {
+		line := make([]bool, len(test.line))
+		for i, c := range test.line {
+			line[i] = c == '1'
+		}
+
+		got := finderLikePatterns(len(line), func(i int) bool { return line[i] })
+		if got != test.expected {
+			t.Errorf("%s: got %d patterns, expected %d", test.line, got, test.expected)
+		}
+
+		// The same line as a single row and column of a symbol.
+		s := newSymbol(len(line), 4)
+		for i, v := range line {
+			s.set(i, 0, v)
+		}
+		if p := s.penalty3(); p < got*penaltyWeight3 {
+			t.Errorf("%s: penalty3() = %d, expected at least %d", test.line, p, got*penaltyWeight3)
+		}
+	}
+}
+
+func TestLegacyMaskPenalty(t *testing.T) {
+	// Trailing light modules extending past the edge of the symbol were not
+	// counted.
+	s := newSymbol(9, 4)
+	s.set2dPattern(0, 0, [][]bool{{b0, b0, b1, b0, b1, b1, b1, b0, b1}})
+	if p := s.penalty3Legacy(); p != penaltyWeight3 {
+		t.Errorf("penalty3Legacy() = %d, expected %d", p, penaltyWeight3)
+	}
+	s = newSymbol(11, 4)
+	s.set2dPattern(0, 0, [][]bool{{b1, b0, b1, b0, b1, b1, b1, b0, b1, b0, b0}})
+	if p := s.penalty3Legacy(); p != 0 {
+		t.Errorf("penalty3Legacy() = %d, expected 0", p)
+	}
+	if p := s.penalty3(); p != penaltyWeight3 {
+		t.Errorf("penalty3() = %d, expected %d", p, penaltyWeight3)
+	}
+
+	// "HELLO WORLD" at 1-M is one of the inputs whose mask changed.
+	q, err := New("HELLO WORLD", Level(Medium))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := New("HELLO WORLD", Level(Medium), LegacyMaskPenalty())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.mask != 0 || legacy.mask != 4 {
+		t.Errorf("got mask %d, legacy mask %d, expected 0 and 4", q.mask, legacy.mask)
+	}
+	if legacy.penalty != legacy.symbol.legacyPenaltyScore() {
+		t.Errorf("legacy penalty %d, expected %d", legacy.penalty, legacy.symbol.legacyPenaltyScore())
+	}
+}
+
+// legacyPenaltyScore returns the penalty score of the symbol using
+// penalty3Legacy, see LegacyMaskPenalty.
+func (m *symbol) legacyPenaltyScore() int {
+	return m.penalty1() + m.penalty2() + m.penalty3Legacy() + m.penalty4()
+}
@@ -0,0 +1,65 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// minTemplateQuietZone is the quiet zone width in modules PlaceInTemplate keeps around
+// the symbol, as required by ISO/IEC 18004.
+const minTemplateQuietZone = 4
+
+// PlaceInTemplate returns a copy of template, e.g. a ticket, badge or invoice, with the
+// QR Code drawn into the region at. The code is scaled to the largest whole
+// number of pixels per module that fits, and centered in at.
+//
+// A busy template would make the code unscannable, so PlaceInTemplate enforces a quiet
+// zone of at least 4 modules, filled with the opaque background color (white
+// if the background is transparent). Captions and transforms are not drawn.
+func (q *QRCode) PlaceInTemplate(template image.Image, at image.Rectangle) (image.Image, error) {
+	if at.Empty() || !at.In(template.Bounds()) {
+		return nil, fmt.Errorf("region %v is not within the template %v", at, template.Bounds())
+	}
+
+	extra := max(0, minTemplateQuietZone-q.symbol.quietZoneSize)
+	modules := q.symbol.size + 2*extra
+
+	pixelsPerModule := min(at.Dx(), at.Dy()) / modules
+	if pixelsPerModule < 1 {
+		return nil, errors.New("template region is too small for the QR Code")
+	}
+
+	c := *q
+	c.scale = 0
+	c.width = q.symbol.size * pixelsPerModule
+	c.height = c.width
+	c.caption, c.captionContent = "", false
+	c.transform = nil
+	c.quietZoneEdges = nil
+
+	side := modules * pixelsPerModule
+	origin := at.Min.Add(image.Pt((at.Dx()-side)/2, (at.Dy()-side)/2))
+	area := image.Rectangle{Min: origin, Max: origin.Add(image.Pt(side, side))}
+
+	img := image.NewRGBA(template.Bounds())
+	draw.Draw(img, img.Bounds(), template, template.Bounds().Min, draw.Src)
+
+	bg := color.NRGBAModel.Convert(q.BackgroundColor).(color.NRGBA)
+	if bg.A == 0 {
+		bg = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	bg.A = 0xff
+	draw.Draw(img, area, image.NewUniform(bg), image.Point{}, draw.Src)
+
+	code := c.Image()
+	inset := extra * pixelsPerModule
+	draw.Draw(img, area.Inset(inset), code, code.Bounds().Min, draw.Over)
+
+	return img, nil
+}
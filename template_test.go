@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPlaceInTemplate(t *testing.T) {
+	// A busy template: a fine checkerboard of dark and mid grey.
+	template := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			v := uint8(0x20)
+			if (x+y)%2 == 0 {
+				v = 0x80
+			}
+			template.Set(x, y, color.RGBA{v, v, v, 0xff})
+		}
+	}
+
+	q, err := New("https://example.org/ticket/42", Margin(0), BackgroundColor(color.Transparent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := image.Rect(200, 50, 380, 250)
+	img, err := q.PlaceInTemplate(template, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.At(10, 10) != template.At(10, 10) {
+		t.Error("template outside the region was changed")
+	}
+
+	// The symbol with a 4 module quiet zone on each side, in 180 pixels.
+	modules := q.symbol.size + 2*4
+	pixelsPerModule := 180 / modules
+	side := modules * pixelsPerModule
+	origin := image.Pt(at.Min.X+(at.Dx()-side)/2, at.Min.Y+(at.Dy()-side)/2)
+
+	bitmap := make([][]bool, modules)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, modules)
+		for x := range bitmap[y] {
+			px := origin.X + x*pixelsPerModule + pixelsPerModule/2
+			py := origin.Y + y*pixelsPerModule + pixelsPerModule/2
+
+			r, g, b, _ := img.At(px, py).RGBA()
+			switch {
+			case r == 0 && g == 0 && b == 0:
+				bitmap[y][x] = true
+			case r == 0xffff && g == 0xffff && b == 0xffff:
+			default:
+				t.Fatalf("module (%d, %d) is %v, expected black or white", x, y, img.At(px, py))
+			}
+		}
+	}
+
+	info, err := VerifyBitmap(bitmap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.QuietZoneSize != 4 || string(info.Content) != q.Content {
+		t.Errorf("got quiet zone %d content %q", info.QuietZoneSize, info.Content)
+	}
+
+	if _, err := q.PlaceInTemplate(template, image.Rect(0, 0, 20, 20)); err == nil {
+		t.Error("PlaceInTemplate succeeded in a region too small, expected error")
+	}
+	if _, err := q.PlaceInTemplate(template, image.Rect(300, 200, 500, 400)); err == nil {
+		t.Error("PlaceInTemplate succeeded in a region outside the template, expected error")
+	}
+}
@@ -0,0 +1,298 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	// Logo formats.
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"github.com/yougg/go-qrcode/styles"
+)
+
+// Theme is a set of rendering options loaded from a file with LoadTheme, so
+// brand templates can be maintained without code changes. Empty fields keep
+// their defaults.
+type Theme struct {
+	// Preset from the styles package, e.g. "Rounded", applied first.
+	Style string `json:"style"`
+
+	// Colors, in any syntax accepted by ParseColor.
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+
+	// Module and finder pattern shapes: square, rounded or circle.
+	Modules string `json:"modules"`
+	Eyes    string `json:"eyes"`
+
+	// Quiet zone width, in modules.
+	Margin *int `json:"margin"`
+
+	// Image width and height in pixels. See Image() for negative sizes.
+	Size int `json:"size"`
+
+	// Error recovery level: L, M, Q or H.
+	Level *RecoveryLevel `json:"level"`
+
+	// Text printed under the symbol, see Caption.
+	Caption string `json:"caption"`
+
+	// Path of a PNG, JPEG or GIF logo drawn over the symbol by Write. Only
+	// png output can carry a logo.
+	Logo string `json:"logo"`
+
+	// Output format used by Write: png (the default), or the name of any
+	// registered Renderer, e.g. svg, tiff, pdf or txt.
+	Format string `json:"format"`
+
+	// Printed size of pdf output in millimetres. Defaults to 30.
+	PrintSizeMM float64 `json:"print_size_mm"`
+}
+
+// LoadTheme reads a Theme from r, in JSON or in a YAML subset of
+// "key: value" lines, with # comments. Unknown keys are an error.
+//
+//	style: Rounded
+//	foreground: "#1a73e8"
+//	margin: 2
+//	level: H
+func LoadTheme(r io.Reader) (*Theme, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var t Theme
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&t); err != nil {
+		return nil, fmt.Errorf("theme: %s", err.Error())
+	}
+
+	if _, err := t.Options(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := LookupRenderer(t.Format); !ok && t.Format != "" {
+		return nil, fmt.Errorf("theme: unknown format %q", t.Format)
+	}
+
+	return &t, nil
+}
+
+// Options returns the Options the Theme describes, for use with New.
+func (t *Theme) Options() ([]Option, error) {
+	var opts []Option
+
+	if t.Style != "" {
+		s, ok := styles.ByName(t.Style)
+		if !ok {
+			return nil, fmt.Errorf("theme: unknown style %q", t.Style)
+		}
+		opts = append(opts, Style(s))
+	}
+
+	for _, c := range []struct {
+		value string
+		opt   func(color.Color) Option
+	}{
+		{t.Foreground, ForegroundColor},
+		{t.Background, BackgroundColor},
+	} {
+		if c.value == "" {
+			continue
+		}
+		parsed, err := ParseColor(c.value)
+		if err != nil {
+			return nil, fmt.Errorf("theme: %s", err.Error())
+		}
+		opts = append(opts, c.opt(parsed))
+	}
+
+	for _, s := range []struct {
+		value string
+		opt   func(styles.Shape) Option
+	}{
+		{t.Modules, ModuleShape},
+		{t.Eyes, EyeShape},
+	} {
+		if s.value == "" {
+			continue
+		}
+		shape, err := styles.ParseShape(s.value)
+		if err != nil {
+			return nil, fmt.Errorf("theme: %s", err.Error())
+		}
+		opts = append(opts, s.opt(shape))
+	}
+
+	if t.Margin != nil {
+		opts = append(opts, Margin(*t.Margin))
+	}
+	if t.Size != 0 {
+		opts = append(opts, Width(t.Size), Height(t.Size))
+	}
+	if t.Level != nil {
+		opts = append(opts, Level(*t.Level))
+	}
+	if t.Caption != "" {
+		opts = append(opts, Caption(t.Caption))
+	}
+
+	return opts, nil
+}
+
+// Write writes q to w in the Theme's Format, with its Logo. A Logo with any
+// Format other than png is an error, rather than being left out.
+func (t *Theme) Write(q *QRCode, w io.Writer) error {
+	if format := strings.ToLower(t.Format); format != "" && format != "png" {
+		if t.Logo != "" {
+			return fmt.Errorf("theme: logo is not supported in %s output", format)
+		}
+		return writeBytes(w)(q.Render(format, RenderOptions{SizeMM: t.PrintSizeMM}))
+	}
+
+	if t.Logo == "" {
+		return q.Write(w)
+	}
+
+	f, err := os.Open(t.Logo)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	logo, _, err := image.Decode(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("theme: logo %s: %s", t.Logo, err.Error())
+	}
+
+	_, err = q.encodePNGImage(w, q.AddLogo(logo))
+	return err
+}
+
+// writeBytes returns a function writing the result of an encoder to w.
+func writeBytes(w io.Writer) func([]byte, error) error {
+	return func(data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+}
+
+// yamlToJSON converts flat "key: value" YAML to a JSON object, using the
+// Theme field types to decide which values are numbers. Fields decoded from
+// text, such as the recovery level, are kept as strings.
+func yamlToJSON(data []byte) ([]byte, error) {
+	textType := reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	numeric := map[string]bool{}
+	tt := reflect.TypeOf(Theme{})
+	for i := 0; i < tt.NumField(); i++ {
+		f := tt.Field(i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		kind := ft.Kind()
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		numeric[name] = kind >= reflect.Int && kind <= reflect.Float64 &&
+			!reflect.PtrTo(ft).Implements(textType)
+	}
+
+	fields := map[string]interface{}{}
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(stripYAMLComment(line))
+		if line == "" || line == "---" {
+			continue
+		}
+
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			return nil, fmt.Errorf("theme: line %d: expected \"key: value\"", n+1)
+		}
+		key := strings.TrimSpace(line[:colon])
+		value := unquoteYAML(strings.TrimSpace(line[colon+1:]))
+
+		if _, ok := fields[key]; ok {
+			return nil, fmt.Errorf("theme: line %d: duplicate key %q", n+1, key)
+		}
+
+		if numeric[key] {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("theme: line %d: %s is not a number", n+1, key)
+			}
+			fields[key] = v
+		} else {
+			fields[key] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// stripYAMLComment removes a trailing # comment, which must follow
+// whitespace. Unlike YAML, a # starting a value is kept, so colors such as
+// #1a73e8 need not be quoted.
+func stripYAMLComment(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+
+	inQuote := byte(0)
+	for i := 1; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '\'':
+			inQuote = c
+		case c == '#' && (line[i-1] == ' ' || line[i-1] == '\t'):
+			if colon := strings.IndexByte(line, ':'); colon >= 0 && strings.TrimSpace(line[colon+1:i]) == "" {
+				continue
+			}
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+// unquoteYAML removes matching single or double quotes around s.
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if u, err := strconv.Unquote(s); err == nil {
+				return u
+			}
+		}
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1)
+	}
+	return s
+}
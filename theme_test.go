@@ -0,0 +1,173 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yougg/go-qrcode/styles"
+)
+
+func TestLoadTheme(t *testing.T) {
+	const jsonTheme = `{
+		"style": "Rounded",
+		"foreground": "#1a73e8",
+		"background": "rgb(255, 255, 240)",
+		"eyes": "square",
+		"margin": 2,
+		"size": 200,
+		"level": "H",
+		"caption": "Scan me"
+	}`
+
+	const yamlTheme = `
+# Brand template.
+style: Rounded
+foreground: #1a73e8   # brand blue
+background: "rgb(255, 255, 240)"
+eyes: square
+margin: 2
+size: 200
+level: H
+caption: 'Scan me'
+`
+
+	for _, s := range []string{jsonTheme, yamlTheme} {
+		theme, err := LoadTheme(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		opts, err := theme.Options()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		q, err := New("https://example.org", opts...)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if q.ForegroundColor != (color.NRGBA{0x1a, 0x73, 0xe8, 0xff}) {
+			t.Errorf("foreground is %v", q.ForegroundColor)
+		}
+		if q.BackgroundColor != (color.NRGBA{0xff, 0xff, 0xf0, 0xff}) {
+			t.Errorf("background is %v", q.BackgroundColor)
+		}
+		if q.moduleShape != styles.RoundedSquare || q.eyeShape != styles.Square {
+			t.Errorf("shapes are %v and %v, expected rounded and square", q.moduleShape, q.eyeShape)
+		}
+		if q.margin != 2 || q.width != 200 || q.RecoveryLevel() != Highest || q.caption != "Scan me" {
+			t.Errorf("got margin %d, size %d, level %s, caption %q", q.margin, q.width, q.RecoveryLevel(), q.caption)
+		}
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	for _, s := range []string{
+		`{"colour": "red"}`,
+		`colour: red`,
+		`foreground: notacolor`,
+		`modules: hexagon`,
+		`style: Fancy`,
+		`level: X`,
+		`margin: wide`,
+		`format: jpeg`,
+		"size: 1\nsize: 2",
+		`just text`,
+	} {
+		if _, err := LoadTheme(strings.NewReader(s)); err == nil {
+			t.Errorf("LoadTheme(%q) succeeded, expected error", s)
+		}
+	}
+}
+
+func TestThemeWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "theme")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	logo := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := range logo.Pix {
+		logo.Pix[i] = 0xff
+	}
+	logoPath := filepath.Join(dir, "logo.png")
+	f, err := os.Create(logoPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	png.Encode(f, logo)
+	f.Close()
+
+	tests := []struct {
+		theme  string
+		prefix string
+	}{
+		{"format: png", pngSignature},
+		{"logo: " + logoPath, pngSignature},
+		{"format: tiff", "II*\x00"},
+		{"format: pdf", "%PDF-"},
+		{"format: txt", "█"},
+	}
+
+	for _, test := range tests {
+		theme, err := LoadTheme(strings.NewReader(test.theme))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		q, err := New("https://example.org", Margin(1))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		var b bytes.Buffer
+		if err := theme.Write(q, &b); err != nil {
+			t.Fatalf("%s: %s", test.theme, err.Error())
+		}
+
+		if !strings.HasPrefix(b.String(), test.prefix) {
+			t.Errorf("%s: output starts %q, expected %q", test.theme, b.String()[:4], test.prefix)
+		}
+	}
+	// The logo path writes the same chunks as EncodePNG.
+	theme, err := LoadTheme(strings.NewReader("logo: " + logoPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	q, err := New("https://example.org", PNGMetadata())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var b bytes.Buffer
+	if err := theme.Write(q, &b); err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, chunk := range []string{"sRGB", "tEXtSoftware"} {
+		if !strings.Contains(b.String(), chunk) {
+			t.Errorf("logo output is missing the %s chunk", chunk)
+		}
+	}
+
+	// Other formats can't carry the logo.
+	theme, err = LoadTheme(strings.NewReader("format: svg\nlogo: " + logoPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	b.Reset()
+	if err := theme.Write(q, &b); err == nil || b.Len() != 0 {
+		t.Errorf("svg output with a logo returned %v, expected an error and no output", err)
+	}
+}
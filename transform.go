@@ -0,0 +1,63 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+// RotationTransform returns the transform rotating by degrees clockwise
+// around the point (cx, cy), for use with Transform.
+func RotationTransform(degrees, cx, cy float64) f64.Aff3 {
+	p := newunits()
+	p.rotate(degrees, cx, cy)
+	return p.getAff3()
+}
+
+// SkewTransform returns the transform shearing x by sx per pixel of y, and y
+// by sy per pixel of x, for use with Transform.
+func SkewTransform(sx, sy float64) f64.Aff3 {
+	return f64.Aff3{1, sx, 0, sy, 1, 0}
+}
+
+// applyTransform returns img transformed by q.transform, enlarged to fit the
+// result and with a transparent background.
+func (q *QRCode) applyTransform(img *image.Paletted) *image.Paletted {
+	aff := *q.transform
+	if aff[0]*aff[4]-aff[1]*aff[3] == 0 {
+		return img
+	}
+
+	b := img.Bounds()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range []image.Point{b.Min, {b.Max.X, b.Min.Y}, {b.Min.X, b.Max.Y}, b.Max} {
+		x := aff[0]*float64(p.X) + aff[1]*float64(p.Y) + aff[2]
+		y := aff[3]*float64(p.X) + aff[4]*float64(p.Y) + aff[5]
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	// Move the result to the origin.
+	minX, minY = math.Floor(minX), math.Floor(minY)
+	aff[2] -= minX
+	aff[5] -= minY
+
+	p := append(color.Palette{}, img.Palette...)
+	p = append(p, color.Transparent)
+
+	out := image.NewPaletted(image.Rect(0, 0, int(math.Ceil(maxX-minX)), int(math.Ceil(maxY-minY))), p)
+	for i := range out.Pix {
+		out.Pix[i] = uint8(len(p) - 1)
+	}
+
+	draw.NearestNeighbor.Transform(out, aff, img, b, draw.Src, nil)
+
+	return out
+}
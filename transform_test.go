@@ -0,0 +1,94 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+func TestTransformIdentity(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	straight := q.Image()
+
+	q.Set(Transform(f64.Aff3{1, 0, 0, 0, 1, 0}))
+	img := q.Image()
+
+	if img.Bounds() != straight.Bounds() {
+		t.Fatalf("identity transform image is %v, expected %v", img.Bounds(), straight.Bounds())
+	}
+	if n := countDifferentPixels(straight, img); n != 0 {
+		t.Errorf("identity transform changed %d pixels", n)
+	}
+}
+
+func TestTransformRotation(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100), Margin(2))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	straight := q.Image()
+
+	// A quarter turn clockwise: (x, y) moves to (99-y, x).
+	q.Set(Transform(RotationTransform(90, 50, 50)))
+	img := q.Image()
+
+	if img.Bounds().Dx() != 100 || img.Bounds().Dy() != 100 {
+		t.Fatalf("rotated image is %v, expected 100x100", img.Bounds())
+	}
+
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			got := color.RGBAModel.Convert(img.At(99-y, x))
+			expected := color.RGBAModel.Convert(straight.At(x, y))
+			if got != expected {
+				t.Fatalf("pixel (%d, %d) moved to (%d, %d) is %v, expected %v", x, y, 99-y, x, got, expected)
+			}
+		}
+	}
+}
+
+func TestTransformRotationEnlarges(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100), Transform(RotationTransform(45, 50, 50)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	img := q.Image()
+	if img.Bounds().Dx() < 141 || img.Bounds().Dy() < 141 {
+		t.Fatalf("45 degree rotated image is %v, expected at least 141x141", img.Bounds())
+	}
+
+	// The corners are outside the rotated square.
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("corner pixel alpha is %d, expected transparent", a)
+	}
+}
+
+func TestTransformSkew(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100), Transform(SkewTransform(0.5, 0)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if b := q.Image().Bounds(); b.Dx() != 150 || b.Dy() != 100 {
+		t.Errorf("skewed image is %v, expected 150x100", b)
+	}
+}
+
+func TestTransformSingular(t *testing.T) {
+	q, err := New("https://example.org", Width(100), Height(100), Transform(f64.Aff3{}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if b := q.Image().Bounds(); b.Dx() != 100 || b.Dy() != 100 {
+		t.Errorf("singular transform image is %v, expected the straight 100x100 image", b)
+	}
+}
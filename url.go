@@ -0,0 +1,230 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// normalizeURL returns content normalized according to mode.
+func normalizeURL(content string, mode URLMode) (string, error) {
+	if mode == 0 {
+		return content, nil
+	}
+
+	content = strings.TrimSpace(content)
+	if mode == URLTrim {
+		return content, nil
+	}
+
+	if content == "" {
+		return "", errors.New("url is empty")
+	}
+	for _, r := range content {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return "", fmt.Errorf("url %q contains white space or control characters", content)
+		}
+	}
+
+	if mode&URLAddScheme != 0 && !hasScheme(content) {
+		if mode&URLRequireHTTPS != 0 {
+			content = "https://" + content
+		} else {
+			content = "http://" + content
+		}
+	}
+
+	u, err := url.Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %s", err.Error())
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch {
+	case u.Scheme == "":
+		return "", fmt.Errorf("url %q has no scheme", content)
+	case mode&URLRequireHTTPS != 0 && u.Scheme != "https":
+		return "", fmt.Errorf("url %q is not https", content)
+	case (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() == "":
+		return "", fmt.Errorf("url %q has no host", content)
+	}
+
+	if u.Host != "" {
+		host, err := punycodeHost(u.Hostname())
+		if err != nil {
+			return "", err
+		}
+		if port := u.Port(); port != "" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			// An IPv6 literal keeps its brackets.
+			host = "[" + host + "]"
+		}
+		u.Host = host
+	}
+
+	return u.String(), nil
+}
+
+// hasScheme reports whether content starts with a URL scheme. A host and
+// port, e.g. "example.org:8080" or "example.org:8080/page", is not a scheme.
+func hasScheme(content string) bool {
+	u, err := url.Parse(content)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+
+	// A port is digits ending the content or followed by a path, query or
+	// fragment.
+	rest := content[len(u.Scheme)+1:]
+	port := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+	if port > 0 && (port == len(rest) || strings.ContainsRune("/?#", rune(rest[port]))) {
+		return false
+	}
+
+	return true
+}
+
+// punycodeHost returns host lower cased, with each non-ASCII label converted
+// to its "xn--" punycode form (RFC 3492).
+func punycodeHost(host string) (string, error) {
+	labels := strings.Split(strings.ToLower(host), ".")
+
+	for i, label := range labels {
+		ascii := true
+		for _, r := range label {
+			if r >= 0x80 {
+				ascii = false
+				break
+			}
+		}
+		if ascii {
+			continue
+		}
+
+		encoded, err := punycode(label)
+		if err != nil {
+			return "", fmt.Errorf("host %q: %s", host, err.Error())
+		}
+		labels[i] = "xn--" + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// Punycode parameters, see RFC 3492 section 5.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+)
+
+// punycode encodes label, see RFC 3492 section 6.3.
+func punycode(label string) (string, error) {
+	runes := []rune(label)
+
+	var out []byte
+	for _, r := range runes {
+		if r < 0x80 {
+			out = append(out, byte(r))
+		}
+	}
+
+	b := len(out)
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+
+	for h < len(runes) {
+		m := rune(unicode.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+
+		delta += int(m-n) * (h + 1)
+		if delta < 0 {
+			return "", errors.New("punycode overflow")
+		}
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+				continue
+			} else if r > n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := k - bias
+				if t < punycodeTMin {
+					t = punycodeTMin
+				} else if t > punycodeTMax {
+					t = punycodeTMax
+				}
+				if q < t {
+					break
+				}
+
+				out = append(out, punycodeDigit(t+(q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			out = append(out, punycodeDigit(q))
+
+			bias = punycodeAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(out), nil
+}
+
+// punycodeAdapt is the bias adaptation function of RFC 3492 section 6.1.
+func punycodeAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeDigit returns the basic code point for the digit d (0-35).
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
@@ -0,0 +1,95 @@
+// go-qrcode
+// Copyright 2014 Tom Harwood
+
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		content  string
+		mode     URLMode
+		expected string
+		ok       bool
+	}{
+		{" example.org ", 0, " example.org ", true},
+		{" example.org \n", URLTrim, "example.org", true},
+		{" https://example.org/page ", URLValidate, "https://example.org/page", true},
+		{"HTTP://Example.ORG:8080/Path", URLValidate, "http://example.org:8080/Path", true},
+		{"https://bücher.example/", URLValidate, "https://xn--bcher-kva.example/", true},
+		{"https://münchen.de", URLValidate, "https://xn--mnchen-3ya.de", true},
+		{"mailto:user@example.org", URLValidate, "mailto:user@example.org", true},
+		{"example.org/page", URLValidate, "", false},
+		{"http:///page", URLValidate, "", false},
+		{"https://exa mple.org", URLValidate, "", false},
+		{"", URLValidate, "", false},
+		{"http://example.org", URLRequireHTTPS, "", false},
+		{"https://example.org", URLRequireHTTPS, "https://example.org", true},
+		{"example.org/page", URLAddScheme, "http://example.org/page", true},
+		{"example.org:8080", URLAddScheme, "http://example.org:8080", true},
+		{"example.org:8080/page?a=1", URLAddScheme, "http://example.org:8080/page?a=1", true},
+		{"localhost:8080", URLAddScheme, "http://localhost:8080", true},
+		{"tel:+15550100", URLAddScheme, "tel:+15550100", true},
+		{"http://[2001:db8::1]:8080/page", URLValidate, "http://[2001:db8::1]:8080/page", true},
+		{"http://[2001:DB8::1]/page", URLValidate, "http://[2001:db8::1]/page", true},
+		{"example.org", URLAddScheme | URLRequireHTTPS, "https://example.org", true},
+		{"https://example.org", URLAddScheme, "https://example.org", true},
+	}
+
+	for _, test := range tests {
+		got, err := normalizeURL(test.content, test.mode)
+
+		if test.ok != (err == nil) {
+			t.Errorf("normalizeURL(%q, %d) error %v, expected ok=%t", test.content, test.mode, err, test.ok)
+		} else if got != test.expected {
+			t.Errorf("normalizeURL(%q, %d) = %q, expected %q", test.content, test.mode, got, test.expected)
+		}
+	}
+}
+
+func TestPunycode(t *testing.T) {
+	// Examples from RFC 3492 section 7.1.
+	tests := []struct {
+		label    string
+		expected string
+	}{
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+		{"3年b組金八先生", "3b-ww4c5e180e575a65lsy2b"},
+		{"bücher", "bcher-kva"},
+	}
+
+	for _, test := range tests {
+		got, err := punycode(test.label)
+		if err != nil {
+			t.Errorf("punycode(%q): %s", test.label, err)
+		} else if got != test.expected {
+			t.Errorf("punycode(%q) = %q, expected %q", test.label, got, test.expected)
+		}
+	}
+}
+
+func TestNewNormalize(t *testing.T) {
+	q, err := New("  example.org/a ", Normalize(URLAddScheme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Content != "http://example.org/a" {
+		t.Errorf("got content %q", q.Content)
+	}
+
+	info, err := VerifyBitmap(q.Bitmap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(info.Content) != q.Content {
+		t.Errorf("encoded %q, expected %q", info.Content, q.Content)
+	}
+
+	if _, err := New("not a url", Normalize(URLValidate)); err == nil {
+		t.Error("New succeeded with an invalid URL, expected error")
+	}
+}
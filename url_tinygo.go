@@ -0,0 +1,17 @@
+//go:build tinygo
+// +build tinygo
+
+package qrcode
+
+import "errors"
+
+// normalizeURL returns content unchanged for mode 0. URL normalization needs
+// the net packages, which are excluded from TinyGo builds, so other modes are
+// an error.
+func normalizeURL(content string, mode URLMode) (string, error) {
+	if mode == 0 {
+		return content, nil
+	}
+
+	return "", errors.New("URL normalization is not available in TinyGo builds")
+}
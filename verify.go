@@ -0,0 +1,524 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yougg/go-qrcode/bitset"
+	"github.com/yougg/go-qrcode/reedsolomon"
+)
+
+// Info describes a QR Code symbol read back by VerifyBitmap.
+type Info struct {
+	// Version number (1-40 inclusive).
+	Version int
+
+	// Error recovery level.
+	Level RecoveryLevel
+
+	// Data mask pattern (0-7 inclusive).
+	MaskPattern int
+
+	// Width of the narrowest edge of the quiet zone in modules.
+	QuietZoneSize int
+
+	// The decoded content.
+	Content []byte
+}
+
+// VerifyBitmap strictly checks that bitmap, as returned by Bitmap(), is an
+// internally consistent QR Code symbol, and returns its decoded content.
+//
+// Unlike a scanner, VerifyBitmap corrects nothing: any deviation is an error.
+// This makes it suitable for fuzz tests and CI pipelines validating generated
+// codes. The checks are:
+//
+//   - the quiet zone is empty, and the symbol has a valid version size. The
+//     edges of the quiet zone may differ, and the bitmap need not be square.
+//   - the finder and timing patterns, and the dark module, are intact.
+//   - both copies of the format information are identical valid BCH codes.
+//   - both copies of the version information (versions 7+) are identical
+//     valid BCH codes matching the symbol size.
+//   - every block's error correction codewords match its data codewords.
+//   - the data segments, terminator and padding are well formed.
+func VerifyBitmap(bitmap [][]bool) (Info, error) {
+	var info Info
+
+	height := len(bitmap)
+	if height == 0 {
+		return info, errors.New("bitmap is empty")
+	}
+	width := len(bitmap[0])
+	for _, row := range bitmap {
+		if len(row) != width {
+			return info, errors.New("bitmap is not rectangular")
+		}
+	}
+
+	// The symbol is the bounding box of the dark modules, so the quiet zone
+	// is empty by construction. Its edges may differ, see QuietZone.
+	top, left, bottom, right := height, width, -1, -1
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				top, left = min(top, y), min(left, x)
+				bottom, right = max(bottom, y), max(right, x)
+			}
+		}
+	}
+	if bottom < 0 {
+		return info, errors.New("bitmap is empty")
+	}
+
+	symbolSize := right - left + 1
+	if bottom-top+1 != symbolSize {
+		return info, fmt.Errorf("symbol is %dx%d modules, expected a square", symbolSize, bottom-top+1)
+	}
+	if symbolSize < 21 || (symbolSize-17)%4 != 0 || (symbolSize-17)/4 > 40 {
+		return info, fmt.Errorf("symbol size %d is not a valid version size", symbolSize)
+	}
+
+	info.QuietZoneSize = min(min(top, left), min(height-1-bottom, width-1-right))
+	info.Version = (symbolSize - 17) / 4
+
+	s := newSymbol(symbolSize, info.QuietZoneSize)
+	for y := 0; y < symbolSize; y++ {
+		for x := 0; x < symbolSize; x++ {
+			s.set(x, y, bitmap[y+top][x+left])
+		}
+	}
+
+	if err := verifyFunctionPatterns(s); err != nil {
+		return info, err
+	}
+
+	level, mask, err := readFormatInfo(s)
+	if err != nil {
+		return info, err
+	}
+	info.Level = level
+	info.MaskPattern = mask
+
+	if err = verifyVersionInfo(s, info.Version); err != nil {
+		return info, err
+	}
+
+	v := getQRCodeVersion(level, info.Version)
+	if v == nil {
+		return info, errors.New("cannot find QR Code version")
+	}
+
+	data, err := readCodewords(s, *v, mask)
+	if err != nil {
+		return info, err
+	}
+
+	info.Content, err = decodeSegments(data, *v)
+	if err != nil {
+		return info, err
+	}
+
+	return info, nil
+}
+
+// functionPatterns returns a regularSymbol with only the function patterns
+// (finder, alignment, timing, format and version information) of version v
+// set. Every empty module is a data module.
+func functionPatterns(v qrCodeVersion) *regularSymbol {
+	m := &regularSymbol{
+		version: v,
+		symbol:  newSymbol(v.symbolSize(), 0),
+		size:    v.symbolSize(),
+	}
+
+	m.addFinderPatterns()
+	m.addAlignmentPatterns()
+	m.addTimingPatterns()
+	m.addFormatInfo()
+	m.addVersionInfo()
+
+	return m
+}
+
+// verifyFunctionPatterns checks the finder, alignment and timing patterns,
+// and the dark module, of s.
+func verifyFunctionPatterns(s *symbol) error {
+	v := getQRCodeVersion(Low, (s.symbolSize-17)/4)
+	expected := functionPatterns(*v)
+
+	// Skip the format and version information, which are checked separately.
+	format := &regularSymbol{version: *v, symbol: newSymbol(s.symbolSize, 0), size: s.symbolSize}
+	format.addFormatInfo()
+	format.addVersionInfo()
+
+	for y := 0; y < s.symbolSize; y++ {
+		for x := 0; x < s.symbolSize; x++ {
+			if expected.symbol.empty(x, y) || !format.symbol.empty(x, y) {
+				continue
+			}
+
+			if s.get(x, y) != expected.symbol.get(x, y) {
+				return fmt.Errorf("function pattern module (%d, %d) is incorrect", x, y)
+			}
+		}
+	}
+
+	// The always dark module is part of the format information area.
+	if !s.get(finderPatternSize+1, s.symbolSize-finderPatternSize-1) {
+		return errors.New("dark module is not set")
+	}
+
+	return nil
+}
+
+// readFormatInfo reads and checks both copies of the format information.
+func readFormatInfo(s *symbol) (RecoveryLevel, int, error) {
+	fpSize := finderPatternSize
+	size := s.symbolSize
+
+	var first, second uint32
+	bit := func(v bool, i int) uint32 {
+		if v {
+			return 1 << uint(i)
+		}
+		return 0
+	}
+
+	// See regularSymbol.addFormatInfo for the module positions.
+	for i := 0; i <= 5; i++ {
+		first |= bit(s.get(fpSize+1, i), i)
+	}
+	first |= bit(s.get(fpSize+1, fpSize), 6)
+	first |= bit(s.get(fpSize+1, fpSize+1), 7)
+	first |= bit(s.get(fpSize, fpSize+1), 8)
+	for i := 9; i <= 14; i++ {
+		first |= bit(s.get(14-i, fpSize+1), i)
+	}
+
+	for i := 0; i <= 7; i++ {
+		second |= bit(s.get(size-i-1, fpSize+1), i)
+	}
+	for i := 8; i <= 14; i++ {
+		second |= bit(s.get(fpSize+1, size-fpSize+i-8), i)
+	}
+
+	if first != second {
+		return 0, 0, fmt.Errorf("format information copies differ (0x%04x, 0x%04x)", first, second)
+	}
+
+	for formatID, f := range formatBitSequence {
+		if f.regular != first {
+			continue
+		}
+
+		var level RecoveryLevel
+		switch formatID >> 3 {
+		case 0x1: // 0b01
+			level = Low
+		case 0x0: // 0b00
+			level = Medium
+		case 0x3: // 0b11
+			level = High
+		case 0x2: // 0b10
+			level = Highest
+		}
+
+		return level, formatID & 0x7, nil
+	}
+
+	return 0, 0, fmt.Errorf("format information 0x%04x is not a valid code", first)
+}
+
+// verifyVersionInfo checks both copies of the version information, if
+// version requires it.
+func verifyVersionInfo(s *symbol, version int) error {
+	if version < 7 {
+		return nil
+	}
+
+	fpSize := finderPatternSize
+	size := s.symbolSize
+	expected := versionBitSequence[version]
+
+	var first, second uint32
+	for i := 0; i < versionInfoLengthBits; i++ {
+		if s.get(i/3, size-fpSize-4+i%3) {
+			first |= 1 << uint(i)
+		}
+		if s.get(size-fpSize-4+i%3, i/3) {
+			second |= 1 << uint(i)
+		}
+	}
+
+	if first != expected || second != expected {
+		return fmt.Errorf("version information (0x%05x, 0x%05x) does not match version %d (0x%05x)",
+			first, second, version, expected)
+	}
+
+	return nil
+}
+
+// readCodewords reads the data modules of s, removes the data mask, checks
+// the error correction of every block, and returns the data codewords.
+func readCodewords(s *symbol, v qrCodeVersion, mask int) (*bitset.Bitset, error) {
+	template := functionPatterns(v)
+	size := s.symbolSize
+
+	// Read the data modules in the order written by regularSymbol.addData:
+	// two module wide columns, right to left, alternately upwards and
+	// downwards, skipping the vertical timing pattern.
+	raw := bitset.New()
+	up := true
+	for x := size - 1; x > 0; x -= 2 {
+		if x == finderPatternSize-1 {
+			x--
+		}
+
+		for i := 0; i < size; i++ {
+			y := i
+			if up {
+				y = size - 1 - i
+			}
+
+			for _, dx := range []int{0, 1} {
+				if !template.symbol.empty(x-dx, y) {
+					continue
+				}
+
+				raw.AppendBools(s.get(x-dx, y) != dataMask(mask, x-dx, y))
+			}
+		}
+
+		up = !up
+	}
+
+	numCodewords := 0
+	for _, b := range v.block {
+		numCodewords += b.numBlocks * b.numCodewords
+	}
+	if raw.Len() != numCodewords*8+v.numRemainderBits {
+		return nil, fmt.Errorf("read %d data bits, expected %d", raw.Len(), numCodewords*8+v.numRemainderBits)
+	}
+
+	// De-interleave the blocks, see QRCode.encodeBlocks.
+	type dataBlock struct {
+		data             []byte
+		ec               []byte
+		numDataCodewords int
+		numECCodewords   int
+	}
+
+	var blocks []dataBlock
+	for _, b := range v.block {
+		for j := 0; j < b.numBlocks; j++ {
+			blocks = append(blocks, dataBlock{
+				numDataCodewords: b.numDataCodewords,
+				numECCodewords:   b.numCodewords - b.numDataCodewords,
+			})
+		}
+	}
+
+	pos := 0
+	for i := 0; ; i++ {
+		done := true
+		for j := range blocks {
+			if i < blocks[j].numDataCodewords {
+				blocks[j].data = append(blocks[j].data, raw.ByteAt(pos))
+				pos += 8
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+	for i := 0; ; i++ {
+		done := true
+		for j := range blocks {
+			if i < blocks[j].numECCodewords {
+				blocks[j].ec = append(blocks[j].ec, raw.ByteAt(pos))
+				pos += 8
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	for i := pos; i < raw.Len(); i++ {
+		if raw.At(i) {
+			return nil, errors.New("remainder bits are not zero")
+		}
+	}
+
+	data := bitset.New()
+	for i, b := range blocks {
+		d := bitset.New()
+		d.AppendBytes(b.data)
+
+		encoded := reedsolomon.Encode(d, b.numECCodewords)
+		for j, c := range b.ec {
+			if encoded.ByteAt((b.numDataCodewords+j)*8) != c {
+				return nil, fmt.Errorf("block %d error correction codeword %d is incorrect", i, j)
+			}
+		}
+
+		data.Append(d)
+	}
+
+	return data, nil
+}
+
+// decodeSegments decodes the data segments, terminator and padding of data.
+func decodeSegments(data *bitset.Bitset, v qrCodeVersion) ([]byte, error) {
+	var encoder *dataEncoder
+	switch {
+	case v.version <= 9:
+		encoder = newDataEncoder(dataEncoderType1To9)
+	case v.version <= 26:
+		encoder = newDataEncoder(dataEncoderType10To26)
+	default:
+		encoder = newDataEncoder(dataEncoderType27To40)
+	}
+
+	var content []byte
+	pos := 0
+
+	read := func(n int) (uint32, error) {
+		if pos+n > data.Len() {
+			return 0, errors.New("data segment truncated")
+		}
+
+		var v uint32
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if data.At(pos + i) {
+				v |= 1
+			}
+		}
+		pos += n
+
+		return v, nil
+	}
+
+	for data.Len()-pos >= 4 {
+		mode, _ := read(4)
+
+		var dataMode dataMode
+		switch mode {
+		case 0x0:
+			// Terminator.
+		case 0x1:
+			dataMode = dataModeNumeric
+		case 0x2:
+			dataMode = dataModeAlphanumeric
+		case 0x4:
+			dataMode = dataModeByte
+		case 0x8:
+			dataMode = dataModeKanji
+		default:
+			return nil, fmt.Errorf("unsupported mode indicator 0x%x", mode)
+		}
+
+		if mode == 0x0 {
+			break
+		}
+
+		n, err := read(encoder.charCountBits(dataMode))
+		if err != nil {
+			return nil, err
+		}
+
+		switch dataMode {
+		case dataModeNumeric:
+			for i := 0; i < int(n); i += 3 {
+				digits := min(int(n)-i, 3)
+
+				value, err := read(1 + 3*digits)
+				if err != nil {
+					return nil, err
+				}
+
+				s := fmt.Sprintf("%0*d", digits, value)
+				if len(s) != digits {
+					return nil, fmt.Errorf("numeric value %d too large", value)
+				}
+				content = append(content, s...)
+			}
+		case dataModeAlphanumeric:
+			for i := 0; i < int(n); i += 2 {
+				numBits, numChars := 11, 2
+				if int(n)-i == 1 {
+					numBits, numChars = 6, 1
+				}
+
+				value, err := read(numBits)
+				if err != nil {
+					return nil, err
+				}
+
+				chars := make([]byte, numChars)
+				for j := numChars - 1; j >= 0; j-- {
+					c := value % 45
+					value /= 45
+
+					chars[j] = alphanumericCharacters[c]
+				}
+				if value != 0 {
+					return nil, errors.New("alphanumeric value too large")
+				}
+				content = append(content, chars...)
+			}
+		case dataModeByte:
+			for i := 0; i < int(n); i++ {
+				b, err := read(8)
+				if err != nil {
+					return nil, err
+				}
+				content = append(content, byte(b))
+			}
+		case dataModeKanji:
+			for i := 0; i < int(n); i++ {
+				value, err := read(13)
+				if err != nil {
+					return nil, err
+				}
+
+				// Reverse encodeKanjiCharacter.
+				c := value/0xc0<<8 | value%0xc0
+				if c+0x8140 <= 0x9ffc {
+					c += 0x8140
+				} else {
+					c += 0xc140
+				}
+				content = append(content, byte(c>>8), byte(c))
+			}
+		}
+	}
+
+	// The remaining bits are zero up to a codeword boundary, then alternating
+	// pad codewords.
+	for ; pos%8 != 0 && pos < data.Len(); pos++ {
+		if data.At(pos) {
+			return nil, errors.New("padding bits are not zero")
+		}
+	}
+	for i := 0; pos < data.Len(); i++ {
+		expected := byte(0xec)
+		if i%2 == 1 {
+			expected = 0x11
+		}
+
+		if b := data.ByteAt(pos); b != expected {
+			return nil, fmt.Errorf("pad codeword is 0x%02x, expected 0x%02x", b, expected)
+		}
+		pos += 8
+	}
+
+	return content, nil
+}
+
+// alphanumericCharacters maps alphanumeric mode values to characters, see
+// encodeAlphanumericCharacter.
+const alphanumericCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
@@ -0,0 +1,141 @@
+package qrcode
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyBitmapAllVersionLevels(t *testing.T) {
+	for version := 1; version <= 40; version++ {
+		for _, level := range []RecoveryLevel{Low, Medium, High, Highest} {
+			content := fmt.Sprintf("v-%d l-%d", version, level)
+
+			q, err := newWithForcedVersion(content, version, level)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			info, err := VerifyBitmap(q.Bitmap())
+			if err != nil {
+				t.Fatalf("Version=%d Level=%s: %s", version, level, err.Error())
+			}
+
+			if info.Version != version || info.Level != level || info.MaskPattern != q.mask {
+				t.Errorf("got version %d level %s mask %d, expected %d %s %d", info.Version,
+					info.Level, info.MaskPattern, version, level, q.mask)
+			}
+			if string(info.Content) != content {
+				t.Errorf("got content %q, expected %q", info.Content, content)
+			}
+		}
+	}
+}
+
+func TestVerifyBitmapContent(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	tests := []string{
+		"0",
+		"01234567",
+		"A",
+		"HELLO WORLD",
+		"123ZZ#!#!",
+		"https://example.org/?q=1",
+		"\x00\xff",
+	}
+
+	for i := 0; i < 32; i++ {
+		b := make([]byte, 1+r.Intn(200))
+		for j := range b {
+			// Mostly digits and upper case, to mix segment modes.
+			b[j] = "0123456789ABC xyz#"[r.Intn(18)]
+		}
+		tests = append(tests, string(b))
+	}
+
+	for _, content := range tests {
+		for _, margin := range []int{0, 4} {
+			q, err := New(content, Level(Medium), Margin(margin))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			info, err := VerifyBitmap(q.Bitmap())
+			if err != nil {
+				t.Fatalf("%q: %s", content, err.Error())
+			}
+
+			if string(info.Content) != content || info.QuietZoneSize != margin {
+				t.Errorf("got %q quiet zone %d, expected %q quiet zone %d", info.Content,
+					info.QuietZoneSize, content, margin)
+			}
+		}
+	}
+}
+
+func TestVerifyBitmapDamaged(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Margin(4), MinVersion(7))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	size := len(q.Bitmap())
+
+	tests := []struct {
+		name string
+		x, y int
+	}{
+		{"quiet zone", 1, 1},
+		{"finder pattern", 4 + 3, 4 + 3},
+		{"timing pattern", 4 + 10, 4 + 6},
+		{"format information", 4 + 8, 4 + 2},
+		{"version information", 4 + 1, size - 4 - 10},
+		{"data", size - 4 - 1, size - 4 - 1},
+	}
+
+	for _, test := range tests {
+		bitmap := q.Bitmap()
+		damaged := make([][]bool, len(bitmap))
+		for i := range bitmap {
+			damaged[i] = append([]bool{}, bitmap[i]...)
+		}
+		damaged[test.y][test.x] = !damaged[test.y][test.x]
+
+		if _, err := VerifyBitmap(damaged); err == nil {
+			t.Errorf("damaged %s verified, expected error", test.name)
+		}
+	}
+}
+
+func TestVerifyBitmapInvalid(t *testing.T) {
+	tests := [][][]bool{
+		{},
+		{{false}},
+		{{true, false}, {false}},
+		make([][]bool, 22),
+	}
+
+	for i, bitmap := range tests {
+		if _, err := VerifyBitmap(bitmap); err == nil {
+			t.Errorf("test %d verified, expected error", i)
+		}
+	}
+}
+
+func TestVerifyBitmapQuietZone(t *testing.T) {
+	for _, edges := range [][4]int{{8, 4, 4, 4}, {1, 2, 3, 4}} {
+		q, err := New("quiet zone edges", QuietZone(edges[0], edges[1], edges[2], edges[3]))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		info, err := VerifyBitmap(q.Bitmap())
+		if err != nil {
+			t.Fatalf("QuietZone%v: %s", edges, err.Error())
+		}
+		if string(info.Content) != "quiet zone edges" || info.QuietZoneSize != min(min(edges[0], edges[1]), min(edges[2], edges[3])) {
+			t.Errorf("QuietZone%v: got %q quiet zone %d", edges, info.Content, info.QuietZoneSize)
+		}
+	}
+}
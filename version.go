@@ -4,7 +4,8 @@
 package qrcode
 
 import (
-	"log"
+	"fmt"
+	"strings"
 
 	"github.com/yougg/go-qrcode/bitset"
 )
@@ -30,6 +31,65 @@ const (
 	Highest
 )
 
+// ParseRecoveryLevel returns the RecoveryLevel named by s. Both the ISO/IEC
+// 18004 letters ("L", "M", "Q", "H") and the constant names ("Low", "Medium",
+// "High", "Highest") are accepted, case insensitively.
+func ParseRecoveryLevel(s string) (RecoveryLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "L", "LOW":
+		return Low, nil
+	case "M", "MEDIUM":
+		return Medium, nil
+	case "Q", "HIGH":
+		return High, nil
+	case "H", "HIGHEST":
+		return Highest, nil
+	}
+
+	return Low, fmt.Errorf("invalid recovery level %q (expected L, M, Q or H)", s)
+}
+
+// String returns the ISO/IEC 18004 letter of the level: L, M, Q or H.
+func (l RecoveryLevel) String() string {
+	switch l {
+	case Low:
+		return "L"
+	case Medium:
+		return "M"
+	case High:
+		return "Q"
+	case Highest:
+		return "H"
+	}
+
+	return fmt.Sprintf("RecoveryLevel(%d)", int(l))
+}
+
+// Set implements flag.Value.
+func (l *RecoveryLevel) Set(s string) error {
+	level, err := ParseRecoveryLevel(s)
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l RecoveryLevel) MarshalText() ([]byte, error) {
+	if l < Low || l > Highest {
+		return nil, fmt.Errorf("invalid recovery level %d", int(l))
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *RecoveryLevel) UnmarshalText(text []byte) error {
+	return l.Set(string(text))
+}
+
 // qrCodeVersion describes the data length and encoding order of a single QR
 // Code version. There are 40 versions numbers x 4 recovery levels == 160
 // possible qrCodeVersion structures.
@@ -51,9 +111,6 @@ type qrCodeVersion struct {
 	// Number of bits required to pad the combined data & error correction bit
 	// stream up to the symbol's full capacity.
 	numRemainderBits int
-
-	// quietZoneSize,white space bit
-	blankAreaSize int
 }
 
 type block struct {
@@ -80,7 +137,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			1,
@@ -94,7 +150,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			1,
@@ -108,7 +163,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			1,
@@ -122,7 +176,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			2,
@@ -136,7 +189,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			2,
@@ -150,7 +202,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			2,
@@ -164,7 +215,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			2,
@@ -178,7 +228,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			3,
@@ -192,7 +241,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			3,
@@ -206,7 +254,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			3,
@@ -220,7 +267,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			3,
@@ -234,7 +280,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			4,
@@ -248,7 +293,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			4,
@@ -262,7 +306,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			4,
@@ -276,7 +319,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			4,
@@ -290,7 +332,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			5,
@@ -304,7 +345,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			5,
@@ -318,7 +358,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			5,
@@ -337,7 +376,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			5,
@@ -356,7 +394,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			6,
@@ -370,7 +407,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			6,
@@ -384,7 +420,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			6,
@@ -398,7 +433,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			6,
@@ -412,7 +446,6 @@ var (
 				},
 			},
 			7,
-			4,
 		},
 		{
 			7,
@@ -426,7 +459,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			7,
@@ -440,7 +472,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			7,
@@ -459,7 +490,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			7,
@@ -478,7 +508,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			8,
@@ -492,7 +521,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			8,
@@ -511,7 +539,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			8,
@@ -530,7 +557,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			8,
@@ -549,7 +575,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			9,
@@ -563,7 +588,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			9,
@@ -582,7 +606,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			9,
@@ -601,7 +624,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			9,
@@ -620,7 +642,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			10,
@@ -639,7 +660,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			10,
@@ -658,7 +678,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			10,
@@ -677,7 +696,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			10,
@@ -696,7 +714,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			11,
@@ -710,7 +727,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			11,
@@ -729,7 +745,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			11,
@@ -748,7 +763,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			11,
@@ -767,7 +781,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			12,
@@ -786,7 +799,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			12,
@@ -805,7 +817,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			12,
@@ -824,7 +835,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			12,
@@ -843,7 +853,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			13,
@@ -857,7 +866,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			13,
@@ -876,7 +884,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			13,
@@ -895,7 +902,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			13,
@@ -914,7 +920,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			14,
@@ -933,7 +938,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			14,
@@ -952,7 +956,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			14,
@@ -971,7 +974,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			14,
@@ -990,7 +992,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			15,
@@ -1009,7 +1010,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			15,
@@ -1028,7 +1028,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			15,
@@ -1047,7 +1046,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			15,
@@ -1066,7 +1064,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			16,
@@ -1085,7 +1082,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			16,
@@ -1104,7 +1100,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			16,
@@ -1123,7 +1118,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			16,
@@ -1142,7 +1136,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			17,
@@ -1161,7 +1154,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			17,
@@ -1180,7 +1172,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			17,
@@ -1199,7 +1190,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			17,
@@ -1218,7 +1208,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			18,
@@ -1237,7 +1226,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			18,
@@ -1256,7 +1244,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			18,
@@ -1275,7 +1262,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			18,
@@ -1294,7 +1280,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			19,
@@ -1313,7 +1298,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			19,
@@ -1332,7 +1316,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			19,
@@ -1351,7 +1334,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			19,
@@ -1370,7 +1352,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			20,
@@ -1389,7 +1370,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			20,
@@ -1408,7 +1388,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			20,
@@ -1427,7 +1406,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			20,
@@ -1446,7 +1424,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			21,
@@ -1465,7 +1442,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			21,
@@ -1479,7 +1455,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			21,
@@ -1498,7 +1473,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			21,
@@ -1517,7 +1491,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			22,
@@ -1536,7 +1509,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			22,
@@ -1550,7 +1522,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			22,
@@ -1569,7 +1540,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			22,
@@ -1583,7 +1553,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			23,
@@ -1602,7 +1571,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			23,
@@ -1621,7 +1589,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			23,
@@ -1640,7 +1607,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			23,
@@ -1659,7 +1625,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			24,
@@ -1678,7 +1643,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			24,
@@ -1697,7 +1661,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			24,
@@ -1716,7 +1679,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			24,
@@ -1735,7 +1697,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			25,
@@ -1754,7 +1715,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			25,
@@ -1773,7 +1733,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			25,
@@ -1792,7 +1751,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			25,
@@ -1811,7 +1769,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			26,
@@ -1830,7 +1787,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			26,
@@ -1849,7 +1805,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			26,
@@ -1868,7 +1823,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			26,
@@ -1887,7 +1841,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			27,
@@ -1906,7 +1859,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			27,
@@ -1925,7 +1877,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			27,
@@ -1944,7 +1895,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			27,
@@ -1963,7 +1913,6 @@ var (
 				},
 			},
 			4,
-			4,
 		},
 		{
 			28,
@@ -1982,7 +1931,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			28,
@@ -2001,7 +1949,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			28,
@@ -2020,7 +1967,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			28,
@@ -2039,7 +1985,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			29,
@@ -2058,7 +2003,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			29,
@@ -2077,7 +2021,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			29,
@@ -2096,7 +2039,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			29,
@@ -2115,7 +2057,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			30,
@@ -2134,7 +2075,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			30,
@@ -2153,7 +2093,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			30,
@@ -2172,7 +2111,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			30,
@@ -2191,7 +2129,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			31,
@@ -2210,7 +2147,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			31,
@@ -2229,7 +2165,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			31,
@@ -2248,7 +2183,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			31,
@@ -2267,7 +2201,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			32,
@@ -2281,7 +2214,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			32,
@@ -2300,7 +2232,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			32,
@@ -2319,7 +2250,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			32,
@@ -2338,7 +2268,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			33,
@@ -2357,7 +2286,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			33,
@@ -2376,7 +2304,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			33,
@@ -2395,7 +2322,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			33,
@@ -2414,7 +2340,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			34,
@@ -2433,7 +2358,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			34,
@@ -2452,7 +2376,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			34,
@@ -2471,7 +2394,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			34,
@@ -2490,7 +2412,6 @@ var (
 				},
 			},
 			3,
-			4,
 		},
 		{
 			35,
@@ -2509,7 +2430,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			35,
@@ -2528,7 +2448,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			35,
@@ -2547,7 +2466,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			35,
@@ -2566,7 +2484,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			36,
@@ -2585,7 +2502,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			36,
@@ -2604,7 +2520,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			36,
@@ -2623,7 +2538,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			36,
@@ -2642,7 +2556,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			37,
@@ -2661,7 +2574,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			37,
@@ -2680,7 +2592,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			37,
@@ -2699,7 +2610,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			37,
@@ -2718,7 +2628,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			38,
@@ -2737,7 +2646,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			38,
@@ -2756,7 +2664,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			38,
@@ -2775,7 +2682,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			38,
@@ -2794,7 +2700,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			39,
@@ -2813,7 +2718,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			39,
@@ -2832,7 +2736,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			39,
@@ -2851,7 +2754,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			39,
@@ -2870,7 +2772,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			40,
@@ -2889,7 +2790,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			40,
@@ -2908,7 +2808,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			40,
@@ -2927,7 +2826,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 		{
 			40,
@@ -2946,7 +2844,6 @@ var (
 				},
 			},
 			0,
-			4,
 		},
 	}
 )
@@ -3078,11 +2975,11 @@ func (v qrCodeVersion) formatInfo(maskPattern int) *bitset.Bitset {
 	case Highest:
 		formatID = 0x10 // 0b10000
 	default:
-		log.Panicf("Invalid level %d", v.level)
+		panic(fmt.Sprintf("Invalid level %d", v.level))
 	}
 
 	if maskPattern < 0 || maskPattern > 7 {
-		log.Panicf("Invalid maskPattern %d", maskPattern)
+		panic(fmt.Sprintf("Invalid maskPattern %d", maskPattern))
 	}
 
 	formatID |= maskPattern & 0x7
@@ -3123,17 +3020,18 @@ func (v qrCodeVersion) numDataBits() int {
 // data length in bits, the error recovery level required, and the data encoder
 // used.
 //
-// The chosen QR Code version is the smallest version able to fit numDataBits
-// and the optional terminator bits required by the specified encoder.
+// The chosen QR Code version is the smallest version, no smaller than
+// minVersion, able to fit numDataBits and the optional terminator bits
+// required by the specified encoder.
 //
 // On success the chosen QR Code version is returned.
-func chooseQRCodeVersion(level RecoveryLevel, encoder *dataEncoder, numDataBits int) *qrCodeVersion {
+func chooseQRCodeVersion(level RecoveryLevel, encoder *dataEncoder, numDataBits int, minVersion int) *qrCodeVersion {
 	var chosenVersion *qrCodeVersion
 
 	for _, v := range versions {
 		if v.level != level {
 			continue
-		} else if v.version < encoder.minVersion {
+		} else if v.version < encoder.minVersion || v.version < minVersion {
 			continue
 		} else if v.version > encoder.maxVersion {
 			break
@@ -3165,6 +3063,26 @@ func (v qrCodeVersion) numTerminatorBitsRequired(numDataBits int) int {
 	return numTerminatorBits
 }
 
+// numMisdecodeProtectionCodewords returns the number of error correction
+// codewords per block reserved for detecting (rather than correcting) errors.
+// Only the smallest versions reserve any, see ISO/IEC 18004 table 9.
+func (v qrCodeVersion) numMisdecodeProtectionCodewords() int {
+	switch {
+	case v.version == 1 && v.level == Low:
+		return 3
+	case v.version == 1 && v.level == Medium:
+		return 2
+	case v.version == 1 && (v.level == High || v.level == Highest):
+		return 1
+	case v.version == 2 && v.level == Low:
+		return 2
+	case v.version == 3 && v.level == Low:
+		return 1
+	}
+
+	return 0
+}
+
 // numBlocks returns the number of blocks.
 func (v qrCodeVersion) numBlocks() int {
 	numBlocks := 0
@@ -3194,16 +3112,6 @@ func (v qrCodeVersion) symbolSize() int {
 	return 21 + (v.version-1)*4
 }
 
-// quietZoneSize returns the number of pixels of border space on each side of
-// the QR Code. The quiet space assists with decoding.
-func (v qrCodeVersion) quietZoneSize() int {
-	return v.blankAreaSize
-}
-
-func (v *qrCodeVersion) setQuietZoneSize(i int) {
-	v.blankAreaSize = i
-}
-
 // getQRCodeVersion returns the QR Code version by version number and recovery
 // level. Returns nil if the requested combination is not defined.
 func getQRCodeVersion(level RecoveryLevel, version int) *qrCodeVersion {
@@ -4,6 +4,8 @@
 package qrcode
 
 import (
+	"encoding/json"
+	"flag"
 	"testing"
 
 	"github.com/yougg/go-qrcode/bitset"
@@ -112,6 +114,33 @@ func TestVersionInfo(t *testing.T) {
 	}
 }
 
+func TestNumMisdecodeProtectionCodewords(t *testing.T) {
+	// ISO/IEC 18004 table 9.
+	tests := []struct {
+		version  int
+		level    RecoveryLevel
+		expected int
+	}{
+		{1, Low, 3},
+		{1, Medium, 2},
+		{1, High, 1},
+		{1, Highest, 1},
+		{2, Low, 2},
+		{2, Medium, 0},
+		{3, Low, 1},
+		{3, Highest, 0},
+		{4, Low, 0},
+	}
+
+	for _, test := range tests {
+		v := getQRCodeVersion(test.level, test.version)
+		if got := v.numMisdecodeProtectionCodewords(); got != test.expected {
+			t.Errorf("version %d-%s: got %d misdecode protection codewords, expected %d",
+				test.version, test.level, got, test.expected)
+		}
+	}
+}
+
 func TestNumBitsToPadToCodeoword(t *testing.T) {
 	tests := []struct {
 		level   RecoveryLevel
@@ -156,3 +185,81 @@ func TestNumBitsToPadToCodeoword(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRecoveryLevel(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected RecoveryLevel
+	}{
+		{"L", Low},
+		{"m", Medium},
+		{"Q", High},
+		{"H", Highest},
+		{"low", Low},
+		{"Medium", Medium},
+		{"HIGH", High},
+		{" highest ", Highest},
+	}
+
+	for _, test := range tests {
+		got, err := ParseRecoveryLevel(test.s)
+		if err != nil {
+			t.Errorf("ParseRecoveryLevel(%q) failed: %s", test.s, err.Error())
+		} else if got != test.expected {
+			t.Errorf("ParseRecoveryLevel(%q) = %s, expected %s", test.s, got, test.expected)
+		}
+	}
+
+	for _, s := range []string{"", "X", "30%"} {
+		if _, err := ParseRecoveryLevel(s); err == nil {
+			t.Errorf("ParseRecoveryLevel(%q) succeeded, expected error", s)
+		}
+	}
+
+	for _, l := range []RecoveryLevel{Low, Medium, High, Highest} {
+		got, err := ParseRecoveryLevel(l.String())
+		if err != nil || got != l {
+			t.Errorf("ParseRecoveryLevel(%q) = %s, %v, expected %s", l.String(), got, err, l)
+		}
+	}
+}
+
+func TestRecoveryLevelText(t *testing.T) {
+	type config struct {
+		Level RecoveryLevel
+	}
+
+	b, err := json.Marshal(config{High})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(b) != `{"Level":"Q"}` {
+		t.Errorf("got %s, expected {\"Level\":\"Q\"}", b)
+	}
+
+	var c config
+	if err = json.Unmarshal([]byte(`{"Level":"highest"}`), &c); err != nil {
+		t.Fatal(err.Error())
+	}
+	if c.Level != Highest {
+		t.Errorf("got %s, expected %s", c.Level, Highest)
+	}
+
+	if _, err = json.Marshal(config{RecoveryLevel(7)}); err == nil {
+		t.Error("marshalling an invalid level succeeded, expected error")
+	}
+}
+
+func TestRecoveryLevelFlag(t *testing.T) {
+	level := Medium
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "recovery level")
+
+	if err := fs.Parse([]string{"-level", "L"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if level != Low {
+		t.Errorf("got %s, expected %s", level, Low)
+	}
+}
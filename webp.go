@@ -0,0 +1,299 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+// maxVP8LSize is the largest width or height of a lossless WebP frame.
+const maxVP8LSize = 1 << 14
+
+// EncodeWebP writes the Animation a to w in animated WebP format.
+//
+// Every frame is stored losslessly (VP8L), so no colors are lost to a
+// palette. All frames must have the same dimensions as the first frame.
+func EncodeWebP(w io.Writer, a *Animation) error {
+	if len(a.Image) == 0 {
+		return errors.New("webp: no frames to encode")
+	}
+
+	bounds := a.Image[0].Bounds()
+	if bounds.Dx() > maxVP8LSize || bounds.Dy() > maxVP8LSize {
+		return errors.New("webp: image too large to encode")
+	}
+
+	hasAlpha := false
+	frames := make([][]byte, len(a.Image))
+	for i, m := range a.Image {
+		if m.Bounds().Dx() != bounds.Dx() || m.Bounds().Dy() != bounds.Dy() {
+			return errors.New("webp: frames have different sizes")
+		}
+
+		var alpha bool
+		frames[i], alpha = encodeVP8L(m)
+		hasAlpha = hasAlpha || alpha
+	}
+
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x02 // Animation flag.
+	if hasAlpha {
+		vp8x[0] |= 0x10
+	}
+	putUint24(vp8x[4:7], uint32(bounds.Dx()-1))
+	putUint24(vp8x[7:10], uint32(bounds.Dy()-1))
+	writeRIFFChunk(&body, "VP8X", vp8x)
+
+	anim := make([]byte, 6)
+	// Background color (BGRA) is transparent black; every frame covers the
+	// whole canvas anyway.
+	binary.LittleEndian.PutUint16(anim[4:6], uint16(a.numPlays()))
+	writeRIFFChunk(&body, "ANIM", anim)
+
+	for i, f := range frames {
+		var anmf bytes.Buffer
+
+		header := make([]byte, 16)
+		// Frame X and Y offsets are zero.
+		putUint24(header[6:9], uint32(bounds.Dx()-1))
+		putUint24(header[9:12], uint32(bounds.Dy()-1))
+		putUint24(header[12:15], uint32(a.delay(i)*10))
+		header[15] = 0x02 // Do not blend, do not dispose.
+		anmf.Write(header)
+		writeRIFFChunk(&anmf, "VP8L", f)
+
+		writeRIFFChunk(&body, "ANMF", anmf.Bytes())
+	}
+
+	var riff bytes.Buffer
+	writeRIFFChunk(&riff, "RIFF", body.Bytes())
+
+	_, err := w.Write(riff.Bytes())
+	return err
+}
+
+// writeRIFFChunk appends a RIFF chunk to buf, padding it to an even length.
+func writeRIFFChunk(buf *bytes.Buffer, name string, data []byte) {
+	header := make([]byte, 8)
+	copy(header[0:4], name)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(data)))
+
+	buf.Write(header)
+	buf.Write(data)
+
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+// putUint24 stores v in b as a 24-bit little endian value.
+func putUint24(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// vp8lWriter writes the least significant bit first bit stream used by
+// lossless WebP.
+type vp8lWriter struct {
+	buf   bytes.Buffer
+	bits  uint64
+	nBits uint
+}
+
+// write appends the n least significant bits of v.
+func (w *vp8lWriter) write(v uint32, n uint) {
+	w.bits |= uint64(v) << w.nBits
+	w.nBits += n
+
+	for w.nBits >= 8 {
+		w.buf.WriteByte(byte(w.bits))
+		w.bits >>= 8
+		w.nBits -= 8
+	}
+}
+
+// writeCode appends a Huffman code, which is read back one bit at a time
+// starting with its most significant bit.
+func (w *vp8lWriter) writeCode(code uint32, length uint) {
+	for i := length; i > 0; i-- {
+		w.write((code>>(i-1))&1, 1)
+	}
+}
+
+// bytes flushes any partial byte and returns the bit stream.
+func (w *vp8lWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf.WriteByte(byte(w.bits))
+		w.bits = 0
+		w.nBits = 0
+	}
+
+	return w.buf.Bytes()
+}
+
+// vp8lChannel is the prefix code used for one ARGB channel.
+//
+// QR Codes rarely use more than two values in a channel, in which case a
+// "simple" code of zero or one bit per pixel is used. Otherwise every value is
+// stored with a fixed 8-bit code.
+type vp8lChannel struct {
+	// Distinct values in ascending order, if there are at most two.
+	symbols []byte
+
+	// True if more than two values are used.
+	full bool
+}
+
+// add records the use of value v.
+func (c *vp8lChannel) add(v byte) {
+	if c.full {
+		return
+	}
+
+	for _, s := range c.symbols {
+		if s == v {
+			return
+		}
+	}
+
+	if len(c.symbols) == 2 {
+		c.full = true
+		c.symbols = nil
+		return
+	}
+
+	c.symbols = append(c.symbols, v)
+	if len(c.symbols) == 2 && c.symbols[0] > c.symbols[1] {
+		c.symbols[0], c.symbols[1] = c.symbols[1], c.symbols[0]
+	}
+}
+
+// writeCode writes the prefix code definition for an alphabet of
+// alphabetSize symbols, of which only the first 256 are used.
+func (c *vp8lChannel) writeCode(w *vp8lWriter, alphabetSize int) {
+	if !c.full {
+		w.write(1, 1) // Simple code.
+		w.write(uint32(len(c.symbols)-1), 1)
+
+		if c.symbols[0] < 2 {
+			w.write(0, 1)
+			w.write(uint32(c.symbols[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(c.symbols[0]), 8)
+		}
+
+		if len(c.symbols) == 2 {
+			w.write(uint32(c.symbols[1]), 8)
+		}
+		return
+	}
+
+	// Normal code. The code length code only uses lengths 0 and 8, each with
+	// a 1-bit code. The lengths are sent in the order
+	// 17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, so 12 are needed to reach 8.
+	w.write(0, 1)
+	w.write(12-4, 4)
+	for i := 0; i < 12; i++ {
+		if i == 2 || i == 11 {
+			w.write(1, 3)
+		} else {
+			w.write(0, 3)
+		}
+	}
+
+	w.write(0, 1) // Code lengths are given for the whole alphabet.
+	for i := 0; i < alphabetSize; i++ {
+		if i < 256 {
+			w.write(1, 1) // Length 8.
+		} else {
+			w.write(0, 1) // Length 0.
+		}
+	}
+}
+
+// writeSymbol writes the code for value v.
+func (c *vp8lChannel) writeSymbol(w *vp8lWriter, v byte) {
+	switch {
+	case c.full:
+		// Every literal has length 8, so its canonical code is its value.
+		w.writeCode(uint32(v), 8)
+	case len(c.symbols) == 2:
+		if v == c.symbols[1] {
+			w.write(1, 1)
+		} else {
+			w.write(0, 1)
+		}
+	}
+}
+
+// encodeVP8L returns m as a lossless WebP bit stream, and whether m has any
+// transparent pixels.
+func encodeVP8L(m image.Image) ([]byte, bool) {
+	b := m.Bounds()
+
+	pix := make([]color.NRGBA, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			pix = append(pix, color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA))
+		}
+	}
+
+	// Channels in the order the prefix codes are sent: green, red, blue,
+	// alpha.
+	var channels [4]vp8lChannel
+	hasAlpha := false
+	for _, p := range pix {
+		channels[0].add(p.G)
+		channels[1].add(p.R)
+		channels[2].add(p.B)
+		channels[3].add(p.A)
+		hasAlpha = hasAlpha || p.A != 0xff
+	}
+
+	w := &vp8lWriter{}
+	w.write(0x2f, 8) // Signature.
+	w.write(uint32(b.Dx()-1), 14)
+	w.write(uint32(b.Dy()-1), 14)
+	if hasAlpha {
+		w.write(1, 1)
+	} else {
+		w.write(0, 1)
+	}
+	w.write(0, 3) // Version.
+
+	w.write(0, 1) // No transforms.
+	w.write(0, 1) // No color cache.
+	w.write(0, 1) // No meta prefix codes.
+
+	// Green shares its alphabet with 24 backward reference length codes.
+	channels[0].writeCode(w, 256+24)
+	channels[1].writeCode(w, 256)
+	channels[2].writeCode(w, 256)
+	channels[3].writeCode(w, 256)
+
+	// Distance codes are unused: a simple code with the single symbol 0.
+	w.write(1, 1)
+	w.write(0, 1)
+	w.write(0, 1)
+	w.write(0, 1)
+
+	for _, p := range pix {
+		channels[0].writeSymbol(w, p.G)
+		channels[1].writeSymbol(w, p.R)
+		channels[2].writeSymbol(w, p.B)
+		channels[3].writeSymbol(w, p.A)
+	}
+
+	return w.bytes(), hasAlpha
+}
@@ -0,0 +1,89 @@
+//go:build !tinygo
+// +build !tinygo
+
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/vp8l"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	q, err := New("https://example.org", Level(Medium), Width(64), Height(64))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	gradient := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			gradient.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 0x80, 0xff})
+		}
+	}
+
+	a := &Animation{Image: []image.Image{q.Image(), gradient}, Delay: []int{50, 50}}
+
+	var buf bytes.Buffer
+	if err = EncodeWebP(&buf, a); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	frames := webpFrames(t, buf.Bytes())
+	if len(frames) != len(a.Image) {
+		t.Fatalf("got %d frames, expected %d", len(frames), len(a.Image))
+	}
+
+	for i, f := range frames {
+		m, err := vp8l.Decode(bytes.NewReader(f))
+		if err != nil {
+			t.Fatalf("frame %d: %s", i, err.Error())
+		}
+
+		expected := a.Image[i]
+		if m.Bounds().Dx() != expected.Bounds().Dx() || m.Bounds().Dy() != expected.Bounds().Dy() {
+			t.Fatalf("frame %d has bounds %v, expected %v", i, m.Bounds(), expected.Bounds())
+		}
+
+		for x := 0; x < m.Bounds().Dx(); x++ {
+			for y := 0; y < m.Bounds().Dy(); y++ {
+				got := color.NRGBAModel.Convert(m.At(x, y))
+				want := color.NRGBAModel.Convert(expected.At(x, y))
+				if got != want {
+					t.Fatalf("frame %d pixel (%d, %d) is %v, expected %v", i, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+// webpFrames returns the VP8L bit stream of every ANMF chunk in an animated
+// WebP file.
+func webpFrames(t *testing.T, b []byte) [][]byte {
+	if string(b[0:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		t.Fatal("not a WebP file")
+	}
+
+	var frames [][]byte
+	for p := 12; p+8 <= len(b); {
+		name := string(b[p : p+4])
+		size := int(binary.LittleEndian.Uint32(b[p+4 : p+8]))
+		data := b[p+8 : p+8+size]
+
+		if name == "ANMF" {
+			if string(data[16:20]) != "VP8L" {
+				t.Fatalf("frame chunk is %s, expected VP8L", data[16:20])
+			}
+			frameSize := int(binary.LittleEndian.Uint32(data[20:24]))
+			frames = append(frames, data[24:24+frameSize])
+		}
+
+		p += 8 + size + size%2
+	}
+
+	return frames
+}